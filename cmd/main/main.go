@@ -27,7 +27,7 @@ import (
 
 func main() {
 	libpak.Main(
-		native.Detect{},
+		native.Detect{Logger: bard.NewLogger(os.Stdout)},
 		native.Build{Logger: bard.NewLogger(os.Stdout)},
 	)
 }
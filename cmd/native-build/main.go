@@ -0,0 +1,260 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// native-build builds a native image from an exploded application outside of the buildpack lifecycle. It resolves the
+// native-image arguments the buildpack would from the same buildpack.toml defaults, $BP_NATIVE_IMAGE_* configuration
+// and bindings in $SERVICE_BINDING_ROOT, prints them and compiles the image with the same invoker, installing the
+// native-image component if needed. Unlike the buildpack, it leaves the application unchanged.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
+
+	"github.com/BurntSushi/toml"
+	"github.com/buildpacks/libcnb"
+	"github.com/paketo-buildpacks/libpak/bard"
+	"github.com/paketo-buildpacks/libpak/sherpa"
+
+	nativeimage "github.com/paketo-buildpacks/native-image/v5"
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func main() {
+	var (
+		application = flag.String("application", ".", "the exploded application directory")
+		buildpack   = flag.String("buildpack", "", "a buildpack.toml providing configuration defaults and denied arguments, defaults to the one of this buildpack")
+		dryRun      = flag.Bool("dry-run", false, "print the native-image arguments without running native-image")
+		output      = flag.String("output", "", "the directory to write the native image to, defaults to a temporary directory")
+		stack       = flag.String("stack", "", "the stack the native image runs on, e.g. io.paketo.stacks.tiny")
+	)
+	flag.Parse()
+
+	sherpa.Execute(func() error {
+		return build(*application, *buildpack, *output, *stack, *dryRun)
+	})
+}
+
+func build(application string, buildpackFile string, output string, stack string, dryRun bool) error {
+	logger := bard.NewLogger(os.Stdout)
+
+	application, err := filepath.Abs(application)
+	if err != nil {
+		return fmt.Errorf("unable to resolve application %s\n%w", application, err)
+	}
+
+	if buildpackFile == "" {
+		dir, err := ioutil.TempDir("", "native-build-buildpack")
+		if err != nil {
+			return fmt.Errorf("unable to create buildpack directory\n%w", err)
+		}
+		defer os.RemoveAll(dir)
+
+		if err := extract(nativeimage.Buildpack, dir); err != nil {
+			return err
+		}
+		buildpackFile = filepath.Join(dir, "buildpack.toml")
+	}
+
+	layers, err := ioutil.TempDir("", "native-build-layers")
+	if err != nil {
+		return fmt.Errorf("unable to create layers directory\n%w", err)
+	}
+	defer os.RemoveAll(layers)
+
+	if output == "" {
+		if output, err = ioutil.TempDir("", "native-build"); err != nil {
+			return fmt.Errorf("unable to create output directory\n%w", err)
+		}
+		if dryRun {
+			defer os.RemoveAll(output)
+		}
+	} else if err := os.MkdirAll(output, 0755); err != nil {
+		return fmt.Errorf("unable to create %s\n%w", output, err)
+	}
+
+	r, err := resolve(logger, application, buildpackFile, layers, output, stack)
+	if err != nil {
+		return err
+	}
+
+	logger.Headerf("Resolved native-image arguments for %s", r.StartClass)
+	for _, c := range r.Compilations {
+		logger.Body(strings.Join(append([]string{r.NativeImage.Command}, c.Arguments...), " \\\n  "))
+	}
+	if dryRun {
+		return nil
+	}
+
+	if r.Installer != nil {
+		installation := &libcnb.Layers{Path: layers}
+		layer, err := installation.Layer(r.Installer.Name())
+		if err != nil {
+			return fmt.Errorf("unable to create layer %s\n%w", r.Installer.Name(), err)
+		}
+		if _, err := r.Installer.Contribute(layer); err != nil {
+			return err
+		}
+	}
+
+	var env []string
+	if r.NativeImage.Environment != nil {
+		env = native.Environment(r.NativeImage.Environment, os.Environ())
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+	defer stop()
+
+	for _, c := range r.Compilations {
+		execution, err := c.Invoker.Execution(output, c.Binary, c.Arguments, env)
+		if err != nil {
+			return err
+		}
+		execution.Stdout, execution.Stderr = logger.InfoWriter(), logger.InfoWriter()
+
+		logger.Bodyf("Executing %s %s", filepath.Base(execution.Command), strings.Join(execution.Args, " "))
+		if err := (native.ProcessGroupExecutor{}).ExecuteContext(ctx, execution); err != nil {
+			return fmt.Errorf("error running native-image\n%w", err)
+		}
+		if err := c.Invoker.Collect(output, c.Binary); err != nil {
+			return err
+		}
+	}
+
+	logger.Headerf("Native image written to %s", output)
+	return nil
+}
+
+// resolution is what the buildpack would compile for an application
+type resolution struct {
+	NativeImage  native.NativeImage
+	Installer    *native.ComponentInstaller
+	StartClass   string
+	Compilations []compilation
+}
+
+// compilation is the compilation of one binary, the main binary first followed by the auxiliary binaries
+type compilation struct {
+	Invoker   native.Invoker
+	Binary    string
+	Arguments []string
+}
+
+// resolve runs the build of the buildpack against the application and returns the compilations it contributes
+func resolve(logger bard.Logger, application string, buildpackFile string, layers string, output string, stack string) (resolution, error) {
+	var buildpack libcnb.Buildpack
+	_, err := toml.DecodeFile(buildpackFile, &buildpack)
+	if err != nil {
+		return resolution{}, fmt.Errorf("unable to decode buildpack %s\n%w", buildpackFile, err)
+	}
+	// the buildpack resources, such as the configuration of logging backends, are read relative to the buildpack
+	if buildpack.Path, err = filepath.Abs(filepath.Dir(buildpackFile)); err != nil {
+		return resolution{}, fmt.Errorf("unable to resolve buildpack %s\n%w", buildpackFile, err)
+	}
+
+	bindings, err := libcnb.NewBindingsForLaunch()
+	if err != nil {
+		return resolution{}, fmt.Errorf("unable to read bindings\n%w", err)
+	}
+
+	result, err := native.Build{Logger: logger, SBOMScanner: noSBOM{}}.Build(libcnb.BuildContext{
+		Application: libcnb.Application{Path: application},
+		Buildpack:   buildpack,
+		Layers:      libcnb.Layers{Path: layers},
+		Platform:    libcnb.Platform{Bindings: bindings},
+		StackID:     stack,
+	})
+	if err != nil {
+		return resolution{}, err
+	}
+
+	var r resolution
+	for _, l := range result.Layers {
+		switch c := l.(type) {
+		case native.NativeImage:
+			r.NativeImage = c
+		case native.ComponentInstaller:
+			r.Installer = &c
+		}
+	}
+
+	layer := libcnb.Layer{Path: output}
+	arguments, startClass, err := r.NativeImage.ProcessArguments(layer)
+	if err != nil {
+		return resolution{}, err
+	}
+	auxiliary, err := r.NativeImage.ProcessAuxiliaryArguments(layer)
+	if err != nil {
+		return resolution{}, err
+	}
+	r.StartClass = startClass
+
+	invoker, takesArguments := r.NativeImage.ResolveInvoker()
+	r.Compilations = append(r.Compilations, compilation{
+		Invoker:   invoker,
+		Binary:    native.BinaryName(startClass, runtime.GOOS),
+		Arguments: arguments,
+	})
+	// like the buildpack, auxiliary binaries are compiled directly when the invoker decides the arguments itself
+	for _, a := range auxiliary {
+		c := compilation{Invoker: invoker, Arguments: a}
+		if !takesArguments {
+			c.Invoker = native.DirectInvoker{Command: r.NativeImage.Command}
+		}
+		r.Compilations = append(r.Compilations, c)
+	}
+
+	return r, nil
+}
+
+// extract writes the files of fsys to dir
+func extract(fsys fs.FS, dir string) error {
+	return fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(dir, filepath.FromSlash(path))
+		if d.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+
+		b, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			return fmt.Errorf("unable to read %s\n%w", path, err)
+		}
+		if err := ioutil.WriteFile(target, b, 0644); err != nil {
+			return fmt.Errorf("unable to write %s\n%w", target, err)
+		}
+		return nil
+	})
+}
+
+// noSBOM skips the SBOM of the application, which is only needed in an image
+type noSBOM struct{}
+
+func (noSBOM) ScanLayer(libcnb.Layer, string, ...libcnb.SBOMFormat) error { return nil }
+func (noSBOM) ScanBuild(string, ...libcnb.SBOMFormat) error               { return nil }
+func (noSBOM) ScanLaunch(string, ...libcnb.SBOMFormat) error              { return nil }
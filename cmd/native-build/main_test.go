@@ -0,0 +1,122 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/buildpacks/libcnb"
+	. "github.com/onsi/gomega"
+	"github.com/paketo-buildpacks/libpak/bard"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	nativeimage "github.com/paketo-buildpacks/native-image/v5"
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func TestUnit(t *testing.T) {
+	suite := spec.New("native-build", spec.Report(report.Terminal{}))
+	suite("NativeBuild", testNativeBuild)
+	suite.Run(t)
+}
+
+func testNativeBuild(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		application string
+		buildpack   string
+		layers      string
+		output      string
+		logger      = bard.NewLogger(io.Discard)
+	)
+
+	it.Before(func() {
+		application = t.TempDir()
+		layers = t.TempDir()
+		output = t.TempDir()
+
+		dir := t.TempDir()
+		Expect(extract(nativeimage.Buildpack, dir)).To(Succeed())
+		buildpack = filepath.Join(dir, "buildpack.toml")
+
+		Expect(os.MkdirAll(filepath.Join(application, "META-INF"), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(application, "META-INF", "MANIFEST.MF"), []byte(`
+Start-Class: test-start-class
+`), 0644)).To(Succeed())
+	})
+
+	it("resolves the arguments the build of the buildpack does", func() {
+		r, err := resolve(logger, application, buildpack, layers, output, "")
+		Expect(err).NotTo(HaveOccurred())
+
+		var bp libcnb.Buildpack
+		_, err = toml.DecodeFile(buildpack, &bp)
+		Expect(err).NotTo(HaveOccurred())
+		bp.Path = filepath.Dir(buildpack)
+
+		result, err := native.Build{Logger: logger, SBOMScanner: noSBOM{}}.Build(libcnb.BuildContext{
+			Application: libcnb.Application{Path: application},
+			Buildpack:   bp,
+			Layers:      libcnb.Layers{Path: t.TempDir()},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		var n native.NativeImage
+		for _, l := range result.Layers {
+			if c, ok := l.(native.NativeImage); ok {
+				n = c
+			}
+		}
+		arguments, startClass, err := n.ProcessArguments(libcnb.Layer{Path: output})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(r.StartClass).To(Equal(startClass))
+		Expect(r.Compilations).To(HaveLen(1))
+		Expect(r.Compilations[0].Arguments).To(Equal(arguments))
+		Expect(r.Compilations[0].Binary).To(Equal(native.BinaryName(startClass, runtime.GOOS)))
+	})
+
+	it("applies the defaults of the embedded buildpack.toml", func() {
+		r, err := resolve(logger, application, buildpack, layers, output, "")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(r.Compilations[0].Arguments).To(ContainElements("-H:+ReportExceptionStackTraces", "--install-exit-handlers"))
+		Expect(r.Compilations[0].Invoker).To(Equal(native.DirectInvoker{Command: "native-image"}))
+		Expect(r.Installer).NotTo(BeNil())
+	})
+
+	context("$BP_NATIVE_IMAGE_INVOKER", func() {
+		it.Before(func() {
+			t.Setenv("BP_NATIVE_IMAGE_INVOKER", "argfile")
+		})
+
+		it("compiles with the invoker of the buildpack", func() {
+			r, err := resolve(logger, application, buildpack, layers, output, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(r.Compilations[0].Invoker).To(Equal(native.ArgfileInvoker{Command: "native-image"}))
+		})
+	})
+}
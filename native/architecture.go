@@ -0,0 +1,147 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// ArchitectureMetadataKey is the key of the target architecture in the native image layer metadata
+const ArchitectureMetadataKey = "architecture"
+
+const (
+	// MarchCompatibility is the -march value of the most portable machine code for the target architecture
+	MarchCompatibility = "compatibility"
+
+	// MarchNative is the -march value of machine code for the CPU of the builder, which may crash with SIGILL on
+	// older hosts
+	MarchNative = "native"
+)
+
+// releaseArchitectures maps the OS_ARCH of a JDK release file to the Go architecture name
+var releaseArchitectures = map[string]string{
+	"x86_64":  "amd64",
+	"amd64":   "amd64",
+	"aarch64": "arm64",
+	"arm64":   "arm64",
+}
+
+// graalVMVersionPattern matches the version of GraalVM releases versioned independently of the JDK, e.g.
+// `GraalVM 22.3.0 Java 17 CE`, `GraalVM Version 20.3.0 CE` or `native-image 22.3.1.0-Final Mandrel Distribution`
+var graalVMVersionPattern = regexp.MustCompile(`^(?:GraalVM (?:Version )?|native-image )(\d+)\.(\d+)\.(\d+)(?:\.\d+)?(?:-Final Mandrel| Java| CE| EE|\s*$)`)
+
+// ValidateArchitecture checks that the JDK in javaHome, which provides native-image, builds for goarch. JDKs without
+// a release file are not checked.
+func ValidateArchitecture(javaHome string, goarch string) error {
+	if javaHome == "" {
+		return nil
+	}
+
+	file := filepath.Join(javaHome, "release")
+	in, err := os.Open(file)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("unable to open %s\n%w", file, err)
+	}
+	defer in.Close()
+
+	s := bufio.NewScanner(in)
+	for s.Scan() {
+		parts := strings.SplitN(s.Text(), "=", 2)
+		if len(parts) != 2 || parts[0] != "OS_ARCH" {
+			continue
+		}
+
+		arch := strings.Trim(parts[1], `"`)
+		if a, ok := releaseArchitectures[arch]; ok && a != goarch {
+			return fmt.Errorf("native-image in %s builds for %s, but the target architecture is %s", javaHome, arch, goarch)
+		}
+		return nil
+	}
+	if err := s.Err(); err != nil {
+		return fmt.Errorf("unable to read %s\n%w", file, err)
+	}
+
+	return nil
+}
+
+// SupportsMarch returns whether the native-image of the version output supports -march, which GraalVM added in
+// 22.3. Releases versioned like the JDK, and versions that cannot be parsed, are assumed to support it.
+func SupportsMarch(version string) bool {
+	v, ok := ParseGraalVMVersion(version)
+	if !ok {
+		return true
+	}
+
+	return v.Major() > 22 || (v.Major() == 22 && v.Minor() >= 3)
+}
+
+// ParseGraalVMVersion parses the version of GraalVM releases versioned independently of the JDK from the first line
+// of the output of native-image --version
+func ParseGraalVMVersion(version string) (*semver.Version, bool) {
+	m := graalVMVersionPattern.FindStringSubmatch(version)
+	if m == nil {
+		return nil, false
+	}
+
+	v, err := semver.NewVersion(fmt.Sprintf("%s.%s.%s", m[1], m[2], m[3]))
+	return v, err == nil
+}
+
+// ArchitectureArguments selects the machine code generated for the target architecture. Images are commonly built
+// on newer hardware than they run on, so amd64 and arm64 default to the most portable machine code.
+type ArchitectureArguments struct {
+	Architecture string
+
+	// March is native, compatibility or an explicit micro-architecture such as x86-64-v3, overriding the default
+	March string
+}
+
+// Configure appends -march to inputArgs as configured, or -march=compatibility for known architectures
+func (a ArchitectureArguments) Configure(inputArgs []string) ([]string, string, error) {
+	if a.March != "" {
+		if strings.ContainsAny(a.March, " \t=") {
+			return []string{}, "", fmt.Errorf("invalid micro-architecture %q", a.March)
+		}
+		return append(inputArgs, fmt.Sprintf("-march=%s", a.March)), "", nil
+	}
+
+	if a.Architecture == "amd64" || a.Architecture == "arm64" {
+		inputArgs = append(inputArgs, fmt.Sprintf("-march=%s", MarchCompatibility))
+	}
+
+	return inputArgs, "", nil
+}
+
+// removeMarch removes -march arguments, for versions of native-image that do not support them
+func removeMarch(arguments []string) []string {
+	var filtered []string
+	for _, a := range arguments {
+		if !strings.HasPrefix(a, "-march=") {
+			filtered = append(filtered, a)
+		}
+	}
+	return filtered
+}
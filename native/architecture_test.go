@@ -0,0 +1,108 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testArchitecture(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		javaHome string
+	)
+
+	it.Before(func() {
+		var err error
+
+		javaHome, err = ioutil.TempDir("", "architecture-java-home")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(javaHome)).To(Succeed())
+	})
+
+	context("ValidateArchitecture", func() {
+		it("accepts a matching architecture", func() {
+			Expect(ioutil.WriteFile(filepath.Join(javaHome, "release"), []byte("JAVA_VERSION=\"17.0.7\"\nOS_ARCH=\"aarch64\"\n"), 0644)).
+				To(Succeed())
+
+			Expect(native.ValidateArchitecture(javaHome, "arm64")).To(Succeed())
+		})
+
+		it("rejects a different architecture", func() {
+			Expect(ioutil.WriteFile(filepath.Join(javaHome, "release"), []byte("OS_ARCH=\"x86_64\"\n"), 0644)).To(Succeed())
+
+			Expect(native.ValidateArchitecture(javaHome, "arm64")).
+				To(MatchError("native-image in " + javaHome + " builds for x86_64, but the target architecture is arm64"))
+		})
+
+		it("does not check without a release file", func() {
+			Expect(native.ValidateArchitecture(javaHome, "arm64")).To(Succeed())
+			Expect(native.ValidateArchitecture("", "arm64")).To(Succeed())
+		})
+	})
+
+	context("SupportsMarch", func() {
+		it("supports -march from GraalVM 22.3", func() {
+			Expect(native.SupportsMarch("GraalVM 22.3.0 Java 17 CE")).To(BeTrue())
+			Expect(native.SupportsMarch("native-image 22.3.1.0-Final Mandrel Distribution (Java Version 17.0.6+10)")).To(BeTrue())
+			Expect(native.SupportsMarch("native-image 17.0.7 2023-04-18")).To(BeTrue())
+		})
+
+		it("does not support -march before GraalVM 22.3", func() {
+			Expect(native.SupportsMarch("GraalVM 22.2.0 Java 17 CE")).To(BeFalse())
+			Expect(native.SupportsMarch("GraalVM Version 20.3.0 CE")).To(BeFalse())
+			Expect(native.SupportsMarch("native-image 21.3.0.0-Final Mandrel Distribution (Java Version 11.0.13+8)")).To(BeFalse())
+		})
+	})
+
+	context("ArchitectureArguments", func() {
+		it("selects portable machine code", func() {
+			Expect(native.ArchitectureArguments{Architecture: "arm64"}.Configure([]string{"test"})).
+				To(Equal([]string{"test", "-march=compatibility"}))
+		})
+
+		it("uses the configured micro-architecture", func() {
+			Expect(native.ArchitectureArguments{Architecture: "amd64", March: "x86-64-v3"}.Configure([]string{"test"})).
+				To(Equal([]string{"test", "-march=x86-64-v3"}))
+			Expect(native.ArchitectureArguments{Architecture: "riscv64", March: "native"}.Configure([]string{"test"})).
+				To(Equal([]string{"test", "-march=native"}))
+		})
+
+		it("fails on an invalid micro-architecture", func() {
+			_, _, err := native.ArchitectureArguments{March: "x86-64 -O0"}.Configure([]string{"test"})
+			Expect(err).To(MatchError(`invalid micro-architecture "x86-64 -O0"`))
+		})
+
+		it("does not add arguments for other architectures", func() {
+			args, _, err := native.ArchitectureArguments{Architecture: "riscv64"}.Configure([]string{"test"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(args).To(Equal([]string{"test"}))
+		})
+	})
+}
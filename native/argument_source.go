@@ -0,0 +1,145 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package native
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/paketo-buildpacks/libpak/bard"
+)
+
+// The names of the argument sources of the buildpack, in order of increasing precedence
+const (
+	SourceStackDefaults     = "stack defaults"
+	SourceLibraryProperties = "library properties"
+	SourceMetadata          = "metadata repository"
+	SourceUserEnvironment   = "user environment"
+	SourceUserFile          = "user file"
+)
+
+// ArgumentSource contributes native-image arguments. Sources are applied in order of increasing precedence, each one
+// given the arguments of the sources before it, so that a later source may replace the arguments of an earlier one.
+type ArgumentSource interface {
+	Name() string
+	Arguments(inputArgs []string) ([]string, error)
+}
+
+// ConfigurerSource is an ArgumentSource applying a number of argument configurers in order
+type ConfigurerSource struct {
+	SourceName  string
+	Configurers []Arguments
+}
+
+// Name returns the name of the source
+func (c ConfigurerSource) Name() string {
+	return c.SourceName
+}
+
+// Arguments returns the inputArgs as changed by each of the configurers
+func (c ConfigurerSource) Arguments(inputArgs []string) ([]string, error) {
+	arguments := inputArgs
+	for _, configurer := range c.Configurers {
+		var err error
+		if arguments, _, err = configurer.Configure(arguments); err != nil {
+			return []string{}, fmt.Errorf("unable to set %s arguments\n%w", c.SourceName, err)
+		}
+	}
+
+	return arguments, nil
+}
+
+// ArgumentChain applies argument sources in order of increasing precedence
+type ArgumentChain struct {
+	Sources []ArgumentSource
+}
+
+// Apply returns the arguments of all sources and a trace recording the source contributing each of them
+func (c ArgumentChain) Apply() ([]string, ArgumentTrace, error) {
+	trace := ArgumentTrace{Origins: map[string]string{}}
+
+	var arguments []string
+	for _, s := range c.Sources {
+		trace.Sources = append(trace.Sources, s.Name())
+
+		var err error
+		if arguments, err = s.Arguments(arguments); err != nil {
+			return []string{}, ArgumentTrace{}, err
+		}
+
+		for _, arg := range arguments {
+			if _, ok := trace.Origins[arg]; !ok {
+				trace.Origins[arg] = s.Name()
+				trace.Arguments = append(trace.Arguments, arg)
+			}
+		}
+	}
+
+	return arguments, trace, nil
+}
+
+// ArgumentTrace records the source contributing each native-image argument
+type ArgumentTrace struct {
+	Sources   []string
+	Arguments []string
+	Origins   map[string]string
+}
+
+// Source returns the name of the source contributing an argument
+func (a ArgumentTrace) Source(argument string) (string, bool) {
+	s, ok := a.Origins[argument]
+	return s, ok
+}
+
+// Log logs the precedence of the sources, the source of each of the final arguments and the arguments that were
+// replaced or removed, at debug level
+func (a ArgumentTrace) Log(logger bard.Logger, final []string) {
+	if !logger.IsDebugEnabled() {
+		return
+	}
+
+	logger.Debugf("Native-image argument precedence: %s", strings.Join(a.Sources, " < "))
+	for _, arg := range final {
+		if s, ok := a.Source(arg); ok {
+			logger.Debugf("  %s (from %s)", arg, s)
+		} else {
+			logger.Debugf("  %s", arg)
+		}
+	}
+
+	for _, arg := range a.Arguments {
+		if !containsString(final, arg) {
+			logger.Debugf("  %s (from %s, replaced or removed)", arg, a.Origins[arg])
+		}
+	}
+}
+
+// reportingSource is an ArgumentSource reporting the arguments of the sources before it that it replaces
+type reportingSource struct {
+	ArgumentSource
+	Resolver Resolver
+}
+
+// Arguments returns the arguments of the wrapped source and reports the replaced inputArgs
+func (r reportingSource) Arguments(inputArgs []string) ([]string, error) {
+	arguments, err := r.ArgumentSource.Arguments(inputArgs)
+	if err != nil {
+		return []string{}, err
+	}
+
+	r.Resolver.ReportOverrides(inputArgs, arguments)
+	return arguments, nil
+}
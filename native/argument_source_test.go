@@ -0,0 +1,114 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package native_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/paketo-buildpacks/libpak/bard"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testArgumentSource(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+	)
+
+	it("applies the sources in order of increasing precedence", func() {
+		arguments, trace, err := native.ArgumentChain{Sources: []native.ArgumentSource{
+			native.ConfigurerSource{
+				SourceName:  native.SourceStackDefaults,
+				Configurers: []native.Arguments{native.BaselineArguments{}, native.AssertionArguments{Enabled: true}},
+			},
+			native.ConfigurerSource{
+				SourceName:  native.SourceUserEnvironment,
+				Configurers: []native.Arguments{native.UserArguments{Arguments: "--no-fallback -ea"}},
+			},
+		}}.Apply()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(arguments).To(Equal([]string{"--no-fallback", "-ea"}))
+		Expect(trace.Sources).To(Equal([]string{native.SourceStackDefaults, native.SourceUserEnvironment}))
+		Expect(trace.Origins).To(HaveKeyWithValue("--no-fallback", native.SourceUserEnvironment))
+		Expect(trace.Origins).To(HaveKeyWithValue("-ea", native.SourceStackDefaults))
+	})
+
+	it("records the source contributing each argument", func() {
+		_, trace, err := native.ArgumentChain{Sources: []native.ArgumentSource{
+			native.ConfigurerSource{
+				SourceName:  native.SourceStackDefaults,
+				Configurers: []native.Arguments{native.BaselineArguments{}},
+			},
+			native.ConfigurerSource{
+				SourceName:  native.SourceUserEnvironment,
+				Configurers: []native.Arguments{native.UserArguments{Arguments: "-H:+ReportExceptionStackTraces"}},
+			},
+		}}.Apply()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(trace.Origins).To(HaveKeyWithValue("-H:+ReportExceptionStackTraces", native.SourceUserEnvironment))
+		_, ok := trace.Source("-H:+StaticExecutable")
+		Expect(ok).To(BeFalse())
+	})
+
+	it("fails with the name of the failing source", func() {
+		_, _, err := native.ArgumentChain{Sources: []native.ArgumentSource{
+			native.ConfigurerSource{
+				SourceName:  native.SourceUserEnvironment,
+				Configurers: []native.Arguments{native.UserArguments{Arguments: "'unterminated"}},
+			},
+		}}.Apply()
+		Expect(err).To(MatchError(ContainSubstring("unable to set user environment arguments")))
+	})
+
+	context("trace", func() {
+		var trace native.ArgumentTrace
+
+		it.Before(func() {
+			trace = native.ArgumentTrace{
+				Sources:   []string{native.SourceStackDefaults, native.SourceUserEnvironment},
+				Arguments: []string{"--no-fallback", "-J-Xmx4g", "-J-Xmx8g"},
+				Origins: map[string]string{
+					"--no-fallback": native.SourceStackDefaults,
+					"-J-Xmx4g":      native.SourceStackDefaults,
+					"-J-Xmx8g":      native.SourceUserEnvironment,
+				},
+			}
+		})
+
+		it("logs the precedence and the source of each argument in debug mode", func() {
+			b := &bytes.Buffer{}
+			trace.Log(bard.NewLoggerWithOptions(b, bard.WithDebug(b)), []string{"--no-fallback", "-J-Xmx8g"})
+
+			Expect(b.String()).To(ContainSubstring("Native-image argument precedence: stack defaults < user environment"))
+			Expect(b.String()).To(ContainSubstring("--no-fallback (from stack defaults)"))
+			Expect(b.String()).To(ContainSubstring("-J-Xmx8g (from user environment)"))
+			Expect(b.String()).To(ContainSubstring("-J-Xmx4g (from stack defaults, replaced or removed)"))
+		})
+
+		it("does not log without debug mode", func() {
+			b := &bytes.Buffer{}
+			trace.Log(bard.NewLogger(b), []string{"--no-fallback", "-J-Xmx8g"})
+
+			Expect(b.String()).To(BeEmpty())
+		})
+	})
+}
@@ -22,6 +22,7 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/magiconair/properties"
@@ -106,6 +107,188 @@ func (u UserFileArguments) Configure(inputArgs []string) ([]string, string, erro
 
 }
 
+// AssertionArguments enables Java assertions in the generated image
+type AssertionArguments struct {
+	Enabled bool
+}
+
+// Configure appends -ea to inputArgs when assertions are enabled
+func (a AssertionArguments) Configure(inputArgs []string) ([]string, string, error) {
+	if a.Enabled {
+		inputArgs = append(inputArgs, "-ea")
+	}
+
+	return inputArgs, "", nil
+}
+
+// DeterministicArguments requests that native-image lays out the image heap deterministically, so that repeated
+// builds from the same inputs produce identical image heaps. native-image has no seed option to set, the layout
+// depends only on the inputs of the build once the option is enabled.
+type DeterministicArguments struct {
+	Enabled bool
+}
+
+// Configure appends -H:+DeterministicImageHeap to inputArgs when enabled
+func (d DeterministicArguments) Configure(inputArgs []string) ([]string, string, error) {
+	if d.Enabled {
+		inputArgs = append(inputArgs, "-H:+DeterministicImageHeap")
+	}
+
+	return inputArgs, "", nil
+}
+
+// SupportsDeterministicImageHeap returns whether the native-image of the version output supports
+// -H:+DeterministicImageHeap, which GraalVM added in 23.0. Releases versioned like the JDK, and versions that cannot
+// be parsed, are assumed to support it.
+func SupportsDeterministicImageHeap(version string) bool {
+	v, ok := ParseGraalVMVersion(version)
+	if !ok {
+		return true
+	}
+
+	return v.Major() >= 23
+}
+
+// removeDeterministicImageHeap removes -H:+DeterministicImageHeap, for versions of native-image that do not support it
+func removeDeterministicImageHeap(arguments []string) []string {
+	var filtered []string
+	for _, a := range arguments {
+		if a != "-H:+DeterministicImageHeap" {
+			filtered = append(filtered, a)
+		}
+	}
+	return filtered
+}
+
+// StackTraceArguments reports the stack traces of exceptions thrown while building the image, rather than only their
+// messages
+type StackTraceArguments struct {
+	Enabled bool
+}
+
+// Configure appends -H:+ReportExceptionStackTraces to inputArgs when enabled
+func (s StackTraceArguments) Configure(inputArgs []string) ([]string, string, error) {
+	if s.Enabled {
+		inputArgs = append(inputArgs, "-H:+ReportExceptionStackTraces")
+	}
+
+	return inputArgs, "", nil
+}
+
+// ContainerArguments prepares the image for running in a container. Without exit handlers the binary, running as PID
+// 1, ignores the SIGTERM a platform stops the container with.
+type ContainerArguments struct {
+	ExitHandlers          bool
+	HeapDumpOnOutOfMemory bool
+}
+
+// Configure appends --install-exit-handlers, and the options writing a heap dump on an out of memory error, to
+// inputArgs as requested
+func (c ContainerArguments) Configure(inputArgs []string) ([]string, string, error) {
+	if c.ExitHandlers {
+		inputArgs = append(inputArgs, "--install-exit-handlers")
+	}
+
+	if c.HeapDumpOnOutOfMemory {
+		inputArgs = append(inputArgs, "--enable-monitoring=heapdump", "-R:+HeapDumpOnOutOfMemoryError")
+	}
+
+	return inputArgs, "", nil
+}
+
+// LocaleArguments includes locales and charsets in the generated image. Without them an internationalized application
+// silently falls back to the locale and charsets of the build at runtime.
+type LocaleArguments struct {
+	// Locales is "all" or a comma separated list of locales such as "en,fr-CA"
+	Locales     string
+	AllCharsets bool
+}
+
+// Configure appends -H:+IncludeAllLocales or -H:IncludeLocales, and -H:+AddAllCharsets, to inputArgs as requested
+func (l LocaleArguments) Configure(inputArgs []string) ([]string, string, error) {
+	var locales []string
+	for _, locale := range strings.Split(l.Locales, ",") {
+		if locale = strings.TrimSpace(locale); locale != "" {
+			locales = append(locales, locale)
+		}
+	}
+
+	if len(locales) == 1 && strings.EqualFold(locales[0], "all") {
+		inputArgs = append(inputArgs, "-H:+IncludeAllLocales")
+	} else if len(locales) > 0 {
+		inputArgs = append(inputArgs, fmt.Sprintf("-H:IncludeLocales=%s", strings.Join(locales, ",")))
+	}
+
+	if l.AllCharsets {
+		inputArgs = append(inputArgs, "-H:+AddAllCharsets")
+	}
+
+	return inputArgs, "", nil
+}
+
+// ReduceResourceArguments returns arguments for a retry after native-image ran out of memory and whether the heap was
+// reduced. The --parallelism is halved, starting from cpus when not set, and any -J-Xmx is reduced by a quarter to leave
+// headroom for native memory. Without -J-Xmx the heap is left to native-image, which sizes it from the available memory.
+func ReduceResourceArguments(arguments []string, cpus int) ([]string, bool) {
+	parallelism := cpus
+	heap := false
+	var reduced []string
+
+	for _, arg := range arguments {
+		if strings.HasPrefix(arg, "--parallelism=") {
+			if p, err := strconv.Atoi(strings.TrimPrefix(arg, "--parallelism=")); err == nil {
+				parallelism = p
+			}
+			continue
+		}
+
+		if strings.HasPrefix(arg, "-J-Xmx") {
+			if b, ok := parseMemorySize(strings.TrimPrefix(arg, "-J-Xmx")); ok && b*3/4 >= MinimumReducedHeap {
+				arg, heap = fmt.Sprintf("-J-Xmx%s", formatMemorySize(b*3/4)), true
+			}
+		}
+
+		reduced = append(reduced, arg)
+	}
+
+	if parallelism /= 2; parallelism < 1 {
+		parallelism = 1
+	}
+
+	// options must precede the main class, so prepend
+	return append([]string{fmt.Sprintf("--parallelism=%d", parallelism)}, reduced...), heap
+}
+
+// MinimumReducedHeap is the smallest -J-Xmx a retry reduces the heap to, below it the JVM would not start
+const MinimumReducedHeap = 2 * 1024 * 1024
+
+// formatMemorySize formats bytes as a JVM memory size in whole mebibytes, or kibibytes when not a whole number of them
+func formatMemorySize(b int64) string {
+	if kib := b / 1024; kib%1024 != 0 {
+		return fmt.Sprintf("%dk", kib)
+	}
+
+	return fmt.Sprintf("%dm", b/(1024*1024))
+}
+
+// parseMemorySize parses a JVM memory size such as 8g, 512m or 1024k into bytes
+func parseMemorySize(size string) (int64, bool) {
+	multipliers := map[string]int64{"k": 1024, "m": 1024 * 1024, "g": 1024 * 1024 * 1024}
+
+	multiplier := int64(1)
+	if len(size) > 0 {
+		if m, ok := multipliers[strings.ToLower(size[len(size)-1:])]; ok {
+			multiplier, size = m, size[:len(size)-1]
+		}
+	}
+
+	v, err := strconv.ParseInt(size, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return v * multiplier, true
+}
 
 // containsArg checks if needle is found in haystack
 //
@@ -125,9 +308,16 @@ func containsArg(needle string, haystack []string) bool {
 
 // ExplodedJarArguments provides a set of arguments specific to building from an exploded jar directory
 type ExplodedJarArguments struct {
-	ApplicationPath string
-	LayerPath       string
-	Manifest        *properties.Properties
+	AdditionalClasspath []string
+	ApplicationPath     string
+	ClasspathResolver   ClasspathResolver
+	Excluded            []string
+	LayerPath           string
+	Manifest            *properties.Properties
+	// StartClass overrides the Start-Class and Main-Class of the manifest
+	StartClass string
+	// LegacyMainClass passes the start class as the trailing argument instead of with -H:Class
+	LegacyMainClass bool
 }
 
 // NoStartOrMainClass is an error returned when a start or main class cannot be found
@@ -139,61 +329,115 @@ func (e NoStartOrMainClass) Error() string {
 
 // Configure appends arguments to inputArgs for building from an exploded JAR directory
 func (e ExplodedJarArguments) Configure(inputArgs []string) ([]string, string, error) {
-	startClass, ok := e.Manifest.Get("Start-Class")
-	if !ok {
-		startClass, ok = e.Manifest.Get("Main-Class")
-		if !ok {
-			return []string{}, "", NoStartOrMainClass{}
+	startClass := e.StartClass
+	if startClass == "" {
+		var ok bool
+		if startClass, ok = e.Manifest.Get("Start-Class"); !ok {
+			if startClass, ok = e.Manifest.Get("Main-Class"); !ok {
+				return []string{}, "", NoStartOrMainClass{}
+			}
 		}
 	}
 
-	cp := os.Getenv("CLASSPATH")
-	if cp == "" {
-		// CLASSPATH should have been done by upstream buildpacks, but just in case
-		cp = e.ApplicationPath
-		if v, ok := e.Manifest.Get("Class-Path"); ok {
-			cp = strings.Join([]string{cp, v}, string(filepath.ListSeparator))
-		}
+	cp, err := explodedClasspath(e.ClasspathResolver, e.ApplicationPath, e.Manifest, e.Excluded)
+	if err != nil {
+		return []string{}, "", err
 	}
 
 	inputArgs = append(inputArgs,
 		fmt.Sprintf("-H:Name=%s", filepath.Join(e.LayerPath, startClass)),
-		"-cp", cp,
-		startClass,
+		"-cp", appendClasspath(cp, e.AdditionalClasspath),
 	)
+	inputArgs = append(inputArgs, EntryPointArguments(startClass, e.LegacyMainClass)...)
 
 	return inputArgs, startClass, nil
 }
 
+// explodedClasspath returns the classpath of an exploded JAR directory, without the excluded entries. The resolver
+// defaults to the ManifestClasspathResolver.
+func explodedClasspath(resolver ClasspathResolver, applicationPath string, manifest *properties.Properties, excluded []string) (string, error) {
+	cp := os.Getenv("CLASSPATH")
+	if cp == "" {
+		// CLASSPATH should have been done by upstream buildpacks, but just in case
+		if resolver == nil {
+			resolver = ManifestClasspathResolver{}
+		}
+
+		entries, err := resolver.Resolve(applicationPath, manifest)
+		if err != nil {
+			return "", fmt.Errorf("unable to resolve classpath\n%w", err)
+		}
+		cp = strings.Join(ClasspathPaths(entries), string(filepath.ListSeparator))
+	}
+
+	var entries []string
+	for _, entry := range NormalizeClasspath(filepath.SplitList(cp)) {
+		if !containsPath(excluded, entry) {
+			entries = append(entries, entry)
+		}
+	}
+
+	return strings.Join(entries, string(filepath.ListSeparator)), nil
+}
+
+// appendClasspath appends additional entries to a classpath
+func appendClasspath(cp string, additional []string) string {
+	return strings.Join(append([]string{cp}, additional...), string(filepath.ListSeparator))
+}
+
+func containsPath(paths []string, path string) bool {
+	for _, p := range paths {
+		if filepath.Clean(p) == filepath.Clean(path) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // JarArguments provides a set of arguments specific to building from a jar file
 type JarArguments struct {
-	ApplicationPath string
-	JarFilePattern  string
+	AdditionalClasspath []string
+	ApplicationPath     string
+	JarFilePattern      string
 }
 
 func (j JarArguments) Configure(inputArgs []string) ([]string, string, error) {
-	file := filepath.Join(j.ApplicationPath, j.JarFilePattern)
-	candidates, err := filepath.Glob(file)
+	jar, err := findJar(j.ApplicationPath, j.JarFilePattern)
 	if err != nil {
-		return []string{}, "", fmt.Errorf("unable to find JAR with %s\n%w", j.JarFilePattern, err)
+		return []string{}, "", err
 	}
 
-	if len(candidates) != 1 {
-		sort.Strings(candidates)
-		return []string{}, "", fmt.Errorf("unable to find single JAR in %s, candidates: %s", j.JarFilePattern, candidates)
-	}
-
-	jarFileName := filepath.Base(candidates[0])
+	jarFileName := filepath.Base(jar)
 	startClass := strings.TrimSuffix(jarFileName, ".jar")
 
 	if containsArg("-jar", inputArgs) {
 		inputArgs = replaceJarArguments(inputArgs)
 	}
-	inputArgs = append(inputArgs, "-jar", candidates[0])
+	if len(j.AdditionalClasspath) > 0 {
+		inputArgs = append(inputArgs, "-cp", strings.Join(j.AdditionalClasspath, string(filepath.ListSeparator)))
+	}
+	inputArgs = append(inputArgs, "-jar", jar)
 
 	return inputArgs, startClass, nil
 }
 
+// findJar returns the single JAR matching jarFilePattern in the application
+func findJar(applicationPath string, jarFilePattern string) (string, error) {
+	file := filepath.Join(applicationPath, jarFilePattern)
+	candidates, err := filepath.Glob(file)
+	if err != nil {
+		return "", fmt.Errorf("unable to find JAR with %s\n%w", jarFilePattern, err)
+	}
+
+	if len(candidates) != 1 {
+		sort.Strings(candidates)
+		return "", fmt.Errorf("unable to find single JAR in %s, candidates: %s", jarFilePattern, candidates)
+	}
+
+	return candidates[0], nil
+}
+
 func replaceJarArguments(fileArgs []string) []string {
 	var tmpArgs, modifiedArgs []string
 	var skip, skipTillQuote bool
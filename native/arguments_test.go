@@ -178,6 +178,123 @@ func testArguments(t *testing.T, context spec.G, it spec.S) {
 		})
 	})
 
+	context("assertion arguments", func() {
+		it("does nothing when disabled", func() {
+			args, startClass, err := native.AssertionArguments{}.Configure([]string{"one"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(startClass).To(Equal(""))
+			Expect(args).To(Equal([]string{"one"}))
+		})
+
+		it("enables assertions", func() {
+			args, _, err := native.AssertionArguments{Enabled: true}.Configure([]string{"one"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(args).To(Equal([]string{"one", "-ea"}))
+		})
+	})
+
+	context("deterministic arguments", func() {
+		it("does nothing when disabled", func() {
+			args, _, err := native.DeterministicArguments{}.Configure([]string{"one"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(args).To(Equal([]string{"one"}))
+		})
+
+		it("requests a deterministic image heap", func() {
+			args, _, err := native.DeterministicArguments{Enabled: true}.Configure([]string{"one"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(args).To(Equal([]string{"one", "-H:+DeterministicImageHeap"}))
+		})
+
+		it("supports a deterministic image heap from GraalVM 23.0", func() {
+			Expect(native.SupportsDeterministicImageHeap("GraalVM 23.0.1 Java 17 CE")).To(BeTrue())
+			Expect(native.SupportsDeterministicImageHeap("native-image 17.0.7 2023-04-18")).To(BeTrue())
+		})
+
+		it("does not support a deterministic image heap before GraalVM 23.0", func() {
+			Expect(native.SupportsDeterministicImageHeap("GraalVM 22.3.0 Java 17 CE")).To(BeFalse())
+			Expect(native.SupportsDeterministicImageHeap("native-image 22.3.1.0-Final Mandrel Distribution (Java Version 17.0.6+10)")).To(BeFalse())
+		})
+	})
+
+	context("stack trace arguments", func() {
+		it("does nothing when disabled", func() {
+			args, _, err := native.StackTraceArguments{}.Configure([]string{"one"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(args).To(Equal([]string{"one"}))
+		})
+
+		it("reports exception stack traces", func() {
+			args, _, err := native.StackTraceArguments{Enabled: true}.Configure([]string{"one"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(args).To(Equal([]string{"one", "-H:+ReportExceptionStackTraces"}))
+		})
+	})
+
+	context("container arguments", func() {
+		it("does nothing when disabled", func() {
+			args, _, err := native.ContainerArguments{}.Configure([]string{"one"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(args).To(Equal([]string{"one"}))
+		})
+
+		it("installs exit handlers and writes a heap dump on out of memory", func() {
+			args, _, err := native.ContainerArguments{ExitHandlers: true, HeapDumpOnOutOfMemory: true}.Configure([]string{"one"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(args).To(Equal([]string{"one", "--install-exit-handlers", "--enable-monitoring=heapdump", "-R:+HeapDumpOnOutOfMemoryError"}))
+		})
+	})
+
+	context("locale arguments", func() {
+		it("does nothing by default", func() {
+			args, _, err := native.LocaleArguments{}.Configure([]string{"one"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(args).To(Equal([]string{"one"}))
+		})
+
+		it("includes all locales and charsets", func() {
+			args, _, err := native.LocaleArguments{Locales: "ALL", AllCharsets: true}.Configure([]string{"one"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(args).To(Equal([]string{"one", "-H:+IncludeAllLocales", "-H:+AddAllCharsets"}))
+		})
+
+		it("includes specific locales", func() {
+			args, _, err := native.LocaleArguments{Locales: "en, fr-CA,"}.Configure([]string{"one"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(args).To(Equal([]string{"one", "-H:IncludeLocales=en,fr-CA"}))
+		})
+	})
+
+	context("reduce resource arguments", func() {
+		it("halves the number of cpus", func() {
+			arguments, heap := native.ReduceResourceArguments([]string{"one"}, 8)
+			Expect(arguments).To(Equal([]string{"--parallelism=4", "one"}))
+			Expect(heap).To(BeFalse())
+		})
+
+		it("halves the parallelism and reduces the heap", func() {
+			arguments, heap := native.ReduceResourceArguments([]string{"--parallelism=3", "-J-Xmx8g", "one"}, 8)
+			Expect(arguments).To(Equal([]string{"--parallelism=1", "-J-Xmx6144m", "one"}))
+			Expect(heap).To(BeTrue())
+		})
+
+		it("reduces the heap in kibibytes", func() {
+			arguments, _ := native.ReduceResourceArguments([]string{"-J-Xmx5m"}, 2)
+			Expect(arguments).To(Equal([]string{"--parallelism=1", "-J-Xmx3840k"}))
+		})
+
+		it("does not reduce the heap below the minimum", func() {
+			arguments, heap := native.ReduceResourceArguments([]string{"-J-Xmx2m"}, 2)
+			Expect(arguments).To(Equal([]string{"--parallelism=1", "-J-Xmx2m"}))
+			Expect(heap).To(BeFalse())
+		})
+
+		it("keeps at least one thread", func() {
+			arguments, _ := native.ReduceResourceArguments(nil, 1)
+			Expect(arguments).To(Equal([]string{"--parallelism=1"}))
+		})
+	})
+
 	context("exploded jar arguments", func() {
 		var layer libcnb.Layer
 
@@ -208,8 +325,19 @@ func testArguments(t *testing.T, context spec.G, it spec.S) {
 				"stuff",
 				fmt.Sprintf("-H:Name=%s/test-start-class", layer.Path),
 				"-cp",
-				fmt.Sprintf("%s:%s", ctx.Application.Path, "manifest-class-path"),
-				"test-start-class"}))
+				fmt.Sprintf("%s:%s", ctx.Application.Path, filepath.Join(ctx.Application.Path, "manifest-class-path")),
+				"-H:Class=test-start-class"}))
+		})
+
+		it("appends additional classpath entries", func() {
+			args, _, err := native.ExplodedJarArguments{
+				AdditionalClasspath: []string{"/extra/classes", "/extra/lib.jar"},
+				ApplicationPath:     ctx.Application.Path,
+				LayerPath:           layer.Path,
+				Manifest:            props,
+			}.Configure(nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(args[2]).To(Equal(fmt.Sprintf("%[1]s:%[1]s/manifest-class-path:/extra/classes:/extra/lib.jar", ctx.Application.Path)))
 		})
 
 		it("fails to find start or main class", func() {
@@ -246,7 +374,31 @@ func testArguments(t *testing.T, context spec.G, it spec.S) {
 					fmt.Sprintf("-H:Name=%s/test-start-class", layer.Path),
 					"-cp",
 					"some-classpath",
-					"test-start-class"}))
+					"-H:Class=test-start-class"}))
+			})
+
+			it("passes the start class as the trailing argument in legacy mode", func() {
+				args, _, err := native.ExplodedJarArguments{
+					ApplicationPath: ctx.Application.Path,
+					LayerPath:       layer.Path,
+					Manifest:        props,
+					LegacyMainClass: true,
+				}.Configure(nil)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(args[len(args)-1]).To(Equal("test-start-class"))
+			})
+
+			it("removes excluded entries", func() {
+				Expect(os.Setenv("CLASSPATH", "/workspace/BOOT-INF/classes:/workspace/BOOT-INF/lib/testcontainers-1.18.3.jar:/workspace/BOOT-INF/lib/spring-boot-3.1.0.jar")).To(Succeed())
+
+				args, _, err := native.ExplodedJarArguments{
+					ApplicationPath: ctx.Application.Path,
+					Excluded:        []string{"/workspace/BOOT-INF/lib/testcontainers-1.18.3.jar"},
+					LayerPath:       layer.Path,
+					Manifest:        props,
+				}.Configure([]string{"stuff"})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(args[3]).To(Equal("/workspace/BOOT-INF/classes:/workspace/BOOT-INF/lib/spring-boot-3.1.0.jar"))
 			})
 		})
 	})
@@ -275,6 +427,20 @@ func testArguments(t *testing.T, context spec.G, it spec.S) {
 			}))
 		})
 
+		it("adds additional classpath entries", func() {
+			args, _, err := native.JarArguments{
+				AdditionalClasspath: []string{"/extra/classes", "/extra/lib.jar"},
+				ApplicationPath:     ctx.Application.Path,
+				JarFilePattern:      "target/*.jar",
+			}.Configure([]string{"stuff"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(args).To(Equal([]string{
+				"stuff",
+				"-cp", "/extra/classes:/extra/lib.jar",
+				"-jar", filepath.Join(ctx.Application.Path, "target", "found.jar"),
+			}))
+		})
+
 		it("overrides -jar arguments", func() {
 			inputArgs := []string{"stuff", "-jar", "no-where"}
 			args, startClass, err := native.JarArguments{
@@ -0,0 +1,108 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// processTypePattern matches valid CNB process types
+var processTypePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// AuxiliaryBinary is an additional entrypoint of the application, compiled into its own binary and contributed as a
+// non-default process. The entrypoint is either a main class or a module JAR whose manifest names the main class.
+type AuxiliaryBinary struct {
+	Name  string `toml:"name"`
+	Class string `toml:"class"`
+	Jar   string `toml:"jar"`
+}
+
+// ParseAuxiliaryBinaries parses a comma separated list of name=class or name=path/to/module.jar pairs
+func ParseAuxiliaryBinaries(value string) ([]AuxiliaryBinary, error) {
+	var binaries []AuxiliaryBinary
+
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 || parts[1] == "" || !processTypePattern.MatchString(parts[0]) {
+			return nil, fmt.Errorf("invalid auxiliary binary %q, expected name=fully.qualified.MainClass or name=path/to/module.jar", entry)
+		}
+
+		entrypoint := strings.TrimSpace(parts[1])
+		if strings.HasSuffix(entrypoint, ".jar") {
+			binaries = append(binaries, AuxiliaryBinary{Name: parts[0], Jar: entrypoint})
+		} else {
+			binaries = append(binaries, AuxiliaryBinary{Name: parts[0], Class: entrypoint})
+		}
+	}
+
+	return binaries, nil
+}
+
+// AuxiliaryArguments appends the arguments for compiling an auxiliary binary with the classpath of the application
+type AuxiliaryArguments struct {
+	ApplicationPath string
+	Binary          AuxiliaryBinary
+	Classpath       string
+	LayerPath       string
+	// LegacyMainClass passes the main class as the trailing argument instead of with -H:Class
+	LegacyMainClass bool
+}
+
+// Configure appends arguments to inputArgs for building the auxiliary binary. The module JAR of a binary is put ahead
+// of the application classpath.
+func (a AuxiliaryArguments) Configure(inputArgs []string) ([]string, string, error) {
+	class, cp := a.Binary.Class, a.Classpath
+
+	if a.Binary.Jar != "" {
+		jar := a.Binary.Jar
+		if !filepath.IsAbs(jar) {
+			jar = filepath.Join(a.ApplicationPath, jar)
+		}
+
+		manifest, err := NewManifestFromJAR(jar)
+		if err != nil {
+			return []string{}, "", err
+		}
+
+		var ok bool
+		if class, ok = manifest.Get("Start-Class"); !ok {
+			if class, ok = manifest.Get("Main-Class"); !ok {
+				return []string{}, "", fmt.Errorf("unable to find the main class of %s\n%w", jar, NoStartOrMainClass{})
+			}
+		}
+
+		if !containsPath(filepath.SplitList(cp), jar) {
+			cp = strings.Join(append([]string{jar}, filepath.SplitList(cp)...), string(filepath.ListSeparator))
+		}
+	}
+
+	inputArgs = append(inputArgs,
+		fmt.Sprintf("-H:Name=%s", filepath.Join(a.LayerPath, a.Binary.Name)),
+		"-cp", cp,
+	)
+	inputArgs = append(inputArgs, EntryPointArguments(class, a.LegacyMainClass)...)
+
+	return inputArgs, a.Binary.Name, nil
+}
@@ -0,0 +1,153 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"archive/zip"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testAuxiliary(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+	)
+
+	context("ParseAuxiliaryBinaries", func() {
+		it("parses name=class pairs", func() {
+			Expect(native.ParseAuxiliaryBinaries(" migrate=com.example.Migrate, seed=com.example.Seed ")).To(Equal([]native.AuxiliaryBinary{
+				{Name: "migrate", Class: "com.example.Migrate"},
+				{Name: "seed", Class: "com.example.Seed"},
+			}))
+		})
+
+		it("parses module JARs", func() {
+			Expect(native.ParseAuxiliaryBinaries("orders=BOOT-INF/lib/orders.jar")).To(Equal([]native.AuxiliaryBinary{
+				{Name: "orders", Jar: "BOOT-INF/lib/orders.jar"},
+			}))
+		})
+
+		it("ignores empty entries", func() {
+			Expect(native.ParseAuxiliaryBinaries(",")).To(BeEmpty())
+		})
+
+		it("rejects malformed entries", func() {
+			_, err := native.ParseAuxiliaryBinaries("com.example.Migrate")
+			Expect(err).To(MatchError(ContainSubstring(`invalid auxiliary binary "com.example.Migrate"`)))
+
+			_, err = native.ParseAuxiliaryBinaries("mi grate=com.example.Migrate")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	it("appends auxiliary arguments", func() {
+		args, name, err := native.AuxiliaryArguments{
+			Binary:    native.AuxiliaryBinary{Name: "migrate", Class: "com.example.Migrate"},
+			Classpath: "/workspace",
+			LayerPath: "/layers/native-image",
+		}.Configure([]string{"test-argument"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(name).To(Equal("migrate"))
+		Expect(args).To(Equal([]string{
+			"test-argument",
+			"-H:Name=/layers/native-image/migrate",
+			"-cp", "/workspace",
+			"-H:Class=com.example.Migrate",
+		}))
+	})
+
+	context("module JARs", func() {
+		var appPath string
+
+		it.Before(func() {
+			var err error
+
+			appPath, err = ioutil.TempDir("", "auxiliary")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(os.MkdirAll(filepath.Join(appPath, "BOOT-INF", "lib"), 0755)).To(Succeed())
+		})
+
+		it.After(func() {
+			Expect(os.RemoveAll(appPath)).To(Succeed())
+		})
+
+		writeJAR := func(manifest string) {
+			out, err := os.Create(filepath.Join(appPath, "BOOT-INF", "lib", "orders.jar"))
+			Expect(err).NotTo(HaveOccurred())
+			defer out.Close()
+
+			z := zip.NewWriter(out)
+			w, err := z.Create("META-INF/MANIFEST.MF")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = w.Write([]byte(manifest))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(z.Close()).To(Succeed())
+		}
+
+		it("compiles the main class of the module JAR ahead of the application classpath", func() {
+			writeJAR("Main-Class: com.example.orders.Orders\n")
+
+			args, name, err := native.AuxiliaryArguments{
+				ApplicationPath: appPath,
+				Binary:          native.AuxiliaryBinary{Name: "orders", Jar: "BOOT-INF/lib/orders.jar"},
+				Classpath:       appPath,
+				LayerPath:       "/layers/native-image",
+			}.Configure(nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(name).To(Equal("orders"))
+			Expect(args).To(Equal([]string{
+				"-H:Name=/layers/native-image/orders",
+				"-cp", strings.Join([]string{filepath.Join(appPath, "BOOT-INF", "lib", "orders.jar"), appPath}, string(filepath.ListSeparator)),
+				"-H:Class=com.example.orders.Orders",
+			}))
+		})
+
+		it("does not repeat a module JAR already on the classpath", func() {
+			writeJAR("Start-Class: com.example.orders.Orders\nMain-Class: org.springframework.boot.loader.JarLauncher\n")
+			jar := filepath.Join(appPath, "BOOT-INF", "lib", "orders.jar")
+
+			args, _, err := native.AuxiliaryArguments{
+				ApplicationPath: appPath,
+				Binary:          native.AuxiliaryBinary{Name: "orders", Jar: "BOOT-INF/lib/orders.jar"},
+				Classpath:       appPath + string(filepath.ListSeparator) + jar,
+				LayerPath:       "/layers/native-image",
+			}.Configure(nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(args[2]).To(Equal(appPath + string(filepath.ListSeparator) + jar))
+			Expect(args[3]).To(Equal("-H:Class=com.example.orders.Orders"))
+		})
+
+		it("fails without a main class", func() {
+			writeJAR("Manifest-Version: 1.0\n")
+
+			_, _, err := native.AuxiliaryArguments{
+				ApplicationPath: appPath,
+				Binary:          native.AuxiliaryBinary{Name: "orders", Jar: "BOOT-INF/lib/orders.jar"},
+			}.Configure(nil)
+			Expect(errors.Is(err, native.NoStartOrMainClass{})).To(BeTrue())
+		})
+	})
+}
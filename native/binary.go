@@ -0,0 +1,92 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/buildpacks/libcnb"
+	"github.com/paketo-buildpacks/libpak"
+	"github.com/paketo-buildpacks/libpak/bard"
+)
+
+// BinaryLayer contributes a launch layer holding only the binaries, copied from the cached native image layer that
+// holds the argument files, tracing agent configuration and reports of the build. The layer is reused from the
+// previous image while the binaries are unchanged, so that registries store it once rather than with every change of
+// the application.
+type BinaryLayer struct {
+	// Binaries are the names of the binaries, and of the native libraries they load, in the native image layer
+	Binaries []string
+	Logger   bard.Logger
+	// Outputs are the glob patterns of the other files of the native image layer to copy next to the binaries, such
+	// as the shared libraries the binaries load
+	Outputs         []string
+	SourceDateEpoch time.Time
+	// SourcePath is the path of the native image layer
+	SourcePath string
+}
+
+func (b BinaryLayer) Contribute(layer libcnb.Layer) (libcnb.Layer, error) {
+	expected := map[string]interface{}{}
+	for _, name := range b.Binaries {
+		digest, err := FileDigest(filepath.Join(b.SourcePath, name))
+		if err != nil {
+			return libcnb.Layer{}, fmt.Errorf("unable to compute checksum of %s\n%w", name, err)
+		}
+		expected[name] = digest
+	}
+	if len(b.Outputs) > 0 {
+		expected["outputs"] = b.Outputs
+	}
+
+	contributor := libpak.NewLayerContributor("Native Image Binary", expected, libcnb.LayerTypes{
+		Launch: true,
+	})
+	contributor.Logger = b.Logger
+
+	return contributor.Contribute(layer, func() (libcnb.Layer, error) {
+		for _, name := range b.Binaries {
+			dst := filepath.Join(layer.Path, name)
+			if err := copyBinary(filepath.Join(b.SourcePath, name), dst); err != nil {
+				return libcnb.Layer{}, err
+			}
+
+			if !b.SourceDateEpoch.IsZero() {
+				if err := os.Chtimes(dst, b.SourceDateEpoch, b.SourceDateEpoch); err != nil {
+					return libcnb.Layer{}, fmt.Errorf("unable to set modification time of %s\n%w", dst, err)
+				}
+			}
+		}
+
+		outputs, err := CopyOutputs(b.SourcePath, layer.Path, b.Outputs)
+		if err != nil {
+			return libcnb.Layer{}, err
+		}
+		for _, o := range outputs {
+			b.Logger.Bodyf("Copying %s next to the native image", o)
+		}
+
+		return layer, nil
+	})
+}
+
+func (BinaryLayer) Name() string {
+	return "native-image-binary"
+}
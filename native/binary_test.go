@@ -0,0 +1,97 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/buildpacks/libcnb"
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testBinary(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		layers libcnb.Layers
+		source string
+	)
+
+	it.Before(func() {
+		layers = libcnb.Layers{Path: t.TempDir()}
+		source = filepath.Join(layers.Path, "native-image")
+
+		Expect(os.MkdirAll(source, 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(source, "app"), []byte("app"), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(source, "migrate"), []byte("migrate"), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(source, "app.args"), []byte("args"), 0644)).To(Succeed())
+	})
+
+	it("contributes a launch layer holding only the binaries", func() {
+		epoch := time.Unix(1700000000, 0)
+		b := native.BinaryLayer{Binaries: []string{"app", "migrate"}, SourceDateEpoch: epoch, SourcePath: source}
+
+		layer, err := layers.Layer(b.Name())
+		Expect(err).NotTo(HaveOccurred())
+
+		layer, err = b.Contribute(layer)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(layer.LayerTypes).To(Equal(libcnb.LayerTypes{Launch: true}))
+		Expect(ioutil.ReadFile(filepath.Join(layer.Path, "app"))).To(Equal([]byte("app")))
+		Expect(filepath.Join(layer.Path, "migrate")).To(BeARegularFile())
+		Expect(filepath.Join(layer.Path, "app.args")).NotTo(BeAnExistingFile())
+		Expect(layer.Metadata).To(HaveKey("app"))
+
+		fi, err := os.Stat(filepath.Join(layer.Path, "app"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fi.Mode().Perm()).To(Equal(os.FileMode(0755)))
+		Expect(fi.ModTime().Equal(epoch)).To(BeTrue())
+	})
+
+	it("copies the outputs next to the binaries", func() {
+		Expect(ioutil.WriteFile(filepath.Join(source, "libawt.so"), []byte("lib"), 0644)).To(Succeed())
+		b := native.BinaryLayer{Binaries: []string{"app"}, Outputs: []string{"*.so"}, SourcePath: source}
+
+		layer, err := layers.Layer(b.Name())
+		Expect(err).NotTo(HaveOccurred())
+
+		layer, err = b.Contribute(layer)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(filepath.Join(layer.Path, "libawt.so")).To(BeARegularFile())
+		Expect(filepath.Join(layer.Path, "migrate")).NotTo(BeAnExistingFile())
+		Expect(layer.Metadata).To(HaveKey("outputs"))
+	})
+
+	it("fails without the binary", func() {
+		b := native.BinaryLayer{Binaries: []string{"missing"}, SourcePath: source}
+
+		layer, err := layers.Layer(b.Name())
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = b.Contribute(layer)
+		Expect(err).To(MatchError(ContainSubstring("unable to compute checksum of missing")))
+	})
+}
@@ -0,0 +1,203 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Heuristics for estimating a build without a previous build to compare with. They are deliberately pessimistic and
+// were taken from Spring Boot applications built on a 4 CPU runner.
+const (
+	baseDuration    = 45 * time.Second
+	durationByClass = 4 * time.Millisecond
+	baseMemory      = int64(1024 * 1024 * 1024)
+	memoryByClass   = int64(96 * 1024)
+)
+
+// Estimate is the expected duration and peak memory use of a native-image build
+type Estimate struct {
+	Classes    int64
+	Duration   time.Duration
+	Memory     int64
+	Disk       int64
+	Historical bool
+}
+
+const (
+	BudgetFail = "fail"
+	BudgetWarn = "warn"
+)
+
+// Budget is the maximum duration and peak memory use allowed for a native-image build, and the maximum size of the
+// native image. Zero values are not limited.
+type Budget struct {
+	Duration time.Duration
+	Memory   int64
+	Size     int64
+
+	// SizeAction is fail or warn, the action taken when the native image exceeds Size
+	SizeAction string
+
+	// Phases are the thresholds on the durations of the phases of the build and on its total duration
+	Phases []PhaseThreshold
+
+	// PhaseAction is fail or warn, the action taken when a phase exceeds its threshold
+	PhaseAction string
+}
+
+// CountClasses returns the number of classes in the classpath entries, which may be JARs or directories
+func CountClasses(classpath []string) (int64, error) {
+	var count int64
+
+	for _, entry := range classpath {
+		fi, err := os.Stat(entry)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return 0, fmt.Errorf("unable to stat %s\n%w", entry, err)
+		}
+
+		if fi.IsDir() {
+			if err := filepath.Walk(entry, func(_ string, info os.FileInfo, err error) error {
+				if err != nil {
+					return err
+				}
+				if !info.IsDir() && strings.HasSuffix(info.Name(), ".class") {
+					count++
+				}
+				return nil
+			}); err != nil {
+				return 0, fmt.Errorf("unable to walk %s\n%w", entry, err)
+			}
+			continue
+		}
+
+		z, err := openJAR(entry)
+		if err != nil {
+			return 0, err
+		} else if z == nil {
+			continue
+		}
+		for _, f := range z.File {
+			if strings.HasSuffix(f.Name, ".class") {
+				count++
+			}
+		}
+		z.Close()
+	}
+
+	return count, nil
+}
+
+// EstimateBuild estimates a build of classes classes. The previous build is scaled by the change in the number of
+// classes when it is known, otherwise heuristics are used.
+func EstimateBuild(classes int64, previous Metrics) Estimate {
+	e := Estimate{
+		Classes:  classes,
+		Duration: baseDuration + time.Duration(classes)*durationByClass,
+		Memory:   baseMemory + classes*memoryByClass,
+	}
+
+	if previous.ClassCount > 0 && previous.Duration > 0 {
+		factor := float64(classes) / float64(previous.ClassCount)
+		e.Duration = time.Duration(float64(previous.Duration) * factor)
+		if previous.PeakRSS > 0 {
+			e.Memory = int64(float64(previous.PeakRSS) * factor)
+		}
+		e.Historical = true
+	}
+
+	return e
+}
+
+func (e Estimate) String() string {
+	source := "heuristics"
+	if e.Historical {
+		source = "the previous build"
+	}
+
+	return fmt.Sprintf("%s and %s for %d classes, based on %s", e.Duration.Round(time.Second), formatBytes(e.Memory), e.Classes, source)
+}
+
+// Check returns an error if the estimate exceeds the budget
+func (b Budget) Check(e Estimate) error {
+	if b.Duration > 0 && e.Duration > b.Duration {
+		return fmt.Errorf("estimated duration %s exceeds the budget of %s set with $%s",
+			e.Duration.Round(time.Second), b.Duration, ConfigNativeImageDurationBudget)
+	}
+
+	if b.Memory > 0 && e.Memory > b.Memory {
+		return fmt.Errorf("estimated peak memory %s exceeds the budget of %s set with $%s",
+			formatBytes(e.Memory), formatBytes(b.Memory), ConfigNativeImageMemoryBudget)
+	}
+
+	return nil
+}
+
+// CheckSize returns an ImageSizeError if the native image exceeds the maximum size
+func (b Budget) CheckSize(image string, size int64) error {
+	if b.Size > 0 && size > b.Size {
+		return ImageSizeError{Image: image, Size: size, MaxSize: b.Size}
+	}
+
+	return nil
+}
+
+// ParseSizeBudget parses the maximum size of the native image, such as 80m
+func ParseSizeBudget(value string) (int64, error) {
+	size, ok := parseMemorySize(strings.TrimSpace(value))
+	if !ok || size <= 0 {
+		return 0, fmt.Errorf("unable to parse $%s value %q as a memory size", ConfigNativeImageMaxSize, value)
+	}
+
+	return size, nil
+}
+
+// ParseBudgetAction parses the action taken when the budget of config is exceeded, fail or warn, defaulting to def
+func ParseBudgetAction(config string, value string, def string) (string, error) {
+	switch value = strings.ToLower(strings.TrimSpace(value)); value {
+	case "":
+		return def, nil
+	case BudgetFail, BudgetWarn:
+		return value, nil
+	default:
+		return "", fmt.Errorf("unknown $%s %q, must be %s or %s", config, value, BudgetFail, BudgetWarn)
+	}
+}
+
+// ParseMemoryBudget parses a memory size such as 8g, in the form accepted by -Xmx
+func ParseMemoryBudget(value string) (int64, error) {
+	size, ok := parseMemorySize(strings.TrimSpace(value))
+	if !ok {
+		return 0, fmt.Errorf("unable to parse $%s value %q as a memory size", ConfigNativeImageMemoryBudget, value)
+	}
+
+	return size, nil
+}
+
+func formatBytes(b int64) string {
+	return fmt.Sprintf("%.1f GB", float64(b)/(1024*1024*1024))
+}
+
+func formatMegabytes(b int64) string {
+	return fmt.Sprintf("%.1f MB", float64(b)/(1024*1024))
+}
@@ -0,0 +1,126 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"archive/zip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testBudget(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		appPath string
+	)
+
+	it.Before(func() {
+		var err error
+
+		appPath, err = ioutil.TempDir("", "budget-application")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(appPath)).To(Succeed())
+	})
+
+	it("counts classes in directories and JARs", func() {
+		Expect(os.MkdirAll(filepath.Join(appPath, "classes", "com", "example"), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(appPath, "classes", "com", "example", "A.class"), []byte{}, 0644)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(appPath, "classes", "application.properties"), []byte{}, 0644)).To(Succeed())
+
+		out, err := os.Create(filepath.Join(appPath, "library.jar"))
+		Expect(err).NotTo(HaveOccurred())
+		z := zip.NewWriter(out)
+		for _, n := range []string{"com/example/B.class", "com/example/C.class", "META-INF/MANIFEST.MF"} {
+			_, err := z.Create(n)
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(z.Close()).To(Succeed())
+		Expect(out.Close()).To(Succeed())
+
+		Expect(native.CountClasses([]string{
+			filepath.Join(appPath, "classes"),
+			filepath.Join(appPath, "library.jar"),
+			filepath.Join(appPath, "missing.jar"),
+		})).To(Equal(int64(3)))
+	})
+
+	it("estimates from heuristics", func() {
+		e := native.EstimateBuild(1000, native.Metrics{})
+		Expect(e.Historical).To(BeFalse())
+		Expect(e.Duration).To(Equal(49 * time.Second))
+		Expect(e.String()).To(Equal("49s and 1.1 GB for 1000 classes, based on heuristics"))
+	})
+
+	it("estimates from the previous build", func() {
+		e := native.EstimateBuild(2000, native.Metrics{ClassCount: 1000, Duration: time.Minute, PeakRSS: 2 * 1024 * 1024 * 1024})
+		Expect(e.Historical).To(BeTrue())
+		Expect(e.Duration).To(Equal(2 * time.Minute))
+		Expect(e.Memory).To(Equal(int64(4 * 1024 * 1024 * 1024)))
+	})
+
+	it("checks the budget", func() {
+		e := native.Estimate{Duration: 10 * time.Minute, Memory: 8 * 1024 * 1024 * 1024}
+
+		Expect(native.Budget{}.Check(e)).To(Succeed())
+		Expect(native.Budget{Duration: 15 * time.Minute, Memory: 16 * 1024 * 1024 * 1024}.Check(e)).To(Succeed())
+		Expect(native.Budget{Duration: 5 * time.Minute}.Check(e)).
+			To(MatchError("estimated duration 10m0s exceeds the budget of 5m0s set with $BP_NATIVE_IMAGE_DURATION_BUDGET"))
+		Expect(native.Budget{Memory: 4 * 1024 * 1024 * 1024}.Check(e)).
+			To(MatchError("estimated peak memory 8.0 GB exceeds the budget of 4.0 GB set with $BP_NATIVE_IMAGE_MEMORY_BUDGET"))
+	})
+
+	it("parses a memory budget", func() {
+		Expect(native.ParseMemoryBudget("8g")).To(Equal(int64(8 * 1024 * 1024 * 1024)))
+
+		_, err := native.ParseMemoryBudget("lots")
+		Expect(err).To(MatchError(`unable to parse $BP_NATIVE_IMAGE_MEMORY_BUDGET value "lots" as a memory size`))
+	})
+
+	it("checks the size of the native image", func() {
+		Expect(native.Budget{}.CheckSize("app", 100*1024*1024)).To(Succeed())
+		Expect(native.Budget{Size: 80 * 1024 * 1024}.CheckSize("app", 80*1024*1024)).To(Succeed())
+		Expect(native.Budget{Size: 80 * 1024 * 1024}.CheckSize("app", 100*1024*1024)).
+			To(MatchError("native image app of 100.0 MB exceeds the maximum size of 80.0 MB set with $BP_NATIVE_IMAGE_MAX_SIZE"))
+	})
+
+	it("parses a size budget", func() {
+		Expect(native.ParseSizeBudget("80m")).To(Equal(int64(80 * 1024 * 1024)))
+
+		_, err := native.ParseSizeBudget("small")
+		Expect(err).To(MatchError(`unable to parse $BP_NATIVE_IMAGE_MAX_SIZE value "small" as a memory size`))
+	})
+
+	it("parses a budget action", func() {
+		Expect(native.ParseBudgetAction("BP_NATIVE_IMAGE_MAX_SIZE_ACTION", "", "fail")).To(Equal("fail"))
+		Expect(native.ParseBudgetAction("BP_NATIVE_IMAGE_MAX_SIZE_ACTION", " WARN ", "fail")).To(Equal("warn"))
+
+		_, err := native.ParseBudgetAction("BP_NATIVE_IMAGE_MAX_SIZE_ACTION", "ignore", "fail")
+		Expect(err).To(MatchError(`unknown $BP_NATIVE_IMAGE_MAX_SIZE_ACTION "ignore", must be fail or warn`))
+	})
+}
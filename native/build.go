@@ -17,10 +17,15 @@
 package native
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/paketo-buildpacks/libpak/sherpa"
+	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
 
 	"github.com/paketo-buildpacks/libpak/effect"
 	"github.com/paketo-buildpacks/libpak/sbom"
@@ -28,7 +33,6 @@ import (
 	"github.com/buildpacks/libcnb"
 	"github.com/heroku/color"
 	"github.com/magiconair/properties"
-	"github.com/paketo-buildpacks/libjvm"
 	"github.com/paketo-buildpacks/libpak"
 	"github.com/paketo-buildpacks/libpak/bard"
 )
@@ -36,46 +40,127 @@ import (
 const (
 	ConfigNativeImageArgs           = "BP_NATIVE_IMAGE_BUILD_ARGUMENTS"
 	DeprecatedConfigNativeImageArgs = "BP_BOOT_NATIVE_IMAGE_BUILD_ARGUMENTS"
+	ConfigNativeImageBuildTimeout   = "BP_NATIVE_IMAGE_BUILD_TIMEOUT"
+	ConfigNativeImageTempDirectory  = "BP_NATIVE_IMAGE_TEMP_DIRECTORY"
+	ConfigNativeImageKeepTempFiles  = "BP_NATIVE_IMAGE_KEEP_TEMP_FILES"
+	ConfigNativeImageMinDiskSpace   = "BP_NATIVE_IMAGE_MIN_DISK_SPACE"
+	ConfigNativeImageDiskCheck      = "BP_NATIVE_IMAGE_DISK_SPACE_CHECK"
+	ConfigNativeImageAssertions     = "BP_NATIVE_IMAGE_ENABLE_ASSERTIONS"
+	ConfigNativeImageDeterministic  = "BP_NATIVE_IMAGE_DETERMINISTIC"
+	ConfigNativeImageExitHandlers   = "BP_NATIVE_IMAGE_INSTALL_EXIT_HANDLERS"
+	ConfigNativeImageHeapDump       = "BP_NATIVE_IMAGE_HEAP_DUMP_ON_OOM"
+	ConfigNativeImageStackTraces    = "BP_NATIVE_IMAGE_REPORT_STACK_TRACES"
+	ConfigNativeImageAnalysis       = "BP_NATIVE_IMAGE_ANALYSIS_REPORTS"
+	ConfigNativeImageForbiddenTypes = "BP_NATIVE_IMAGE_FORBIDDEN_TYPES"
+	ConfigNativeImageRunImageCheck  = "BP_NATIVE_IMAGE_RUN_IMAGE_CHECK"
+	ConfigNativeImagePIE            = "BP_NATIVE_IMAGE_PIE"
+	ConfigNativeImageRELRO          = "BP_NATIVE_IMAGE_RELRO"
+	ConfigNativeImageRetryOnOOM     = "BP_NATIVE_IMAGE_RETRY_ON_OOM"
+	ConfigNativeImageAuxiliary      = "BP_NATIVE_IMAGE_AUXILIARY_BINARIES"
+	ConfigNativeImageDevServices    = "BP_NATIVE_IMAGE_EXCLUDE_DEV_SERVICES"
+	ConfigNativeImageBuildOnly      = "BP_NATIVE_IMAGE_BUILD_ONLY_ARTIFACTS"
+	ConfigNativeImageRecordArgs     = "BP_NATIVE_IMAGE_RECORD_ARGUMENTS"
+	ConfigNativeImageBuildEnv       = "BP_NATIVE_IMAGE_BUILD_ENV"
+	ConfigNativeImageVerify         = "BP_NATIVE_IMAGE_VERIFY_REPRODUCIBLE"
+	ConfigNativeImageCompare        = "BP_NATIVE_IMAGE_COMPARE_BUILDS"
+	ConfigNativeImageLibraryArgs    = "BP_NATIVE_IMAGE_LIBRARY_ARGUMENTS"
+	ConfigNativeImageDurationBudget = "BP_NATIVE_IMAGE_DURATION_BUDGET"
+	ConfigNativeImageMemoryBudget   = "BP_NATIVE_IMAGE_MEMORY_BUDGET"
+	ConfigNativeImageMaxSize        = "BP_NATIVE_IMAGE_MAX_SIZE"
+	ConfigNativeImageMaxSizeAction  = "BP_NATIVE_IMAGE_MAX_SIZE_ACTION"
+	ConfigNativeImagePhaseLimits    = "BP_NATIVE_IMAGE_PHASE_THRESHOLDS"
+	ConfigNativeImagePhaseAction    = "BP_NATIVE_IMAGE_PHASE_THRESHOLD_ACTION"
+	ConfigNativeImageAgent          = "BP_NATIVE_IMAGE_TRACING_AGENT"
+	ConfigNativeImageAgentDuration  = "BP_NATIVE_IMAGE_TRACING_AGENT_DURATION"
+	ConfigNativeImageAgentReadyURL  = "BP_NATIVE_IMAGE_TRACING_AGENT_READY_URL"
+	ConfigNativeImageUsageStats     = "BP_NATIVE_IMAGE_USAGE_STATISTICS"
+	ConfigNativeImageResources      = "BP_NATIVE_IMAGE_INCLUDE_RESOURCES"
+	ConfigNativeImageLocales        = "BP_NATIVE_IMAGE_INCLUDE_LOCALES"
+	ConfigNativeImageAllCharsets    = "BP_NATIVE_IMAGE_ADD_ALL_CHARSETS"
+	ConfigNativeImageURLProtocols   = "BP_NATIVE_IMAGE_URL_PROTOCOLS"
+	ConfigNativeImageSecurity       = "BP_NATIVE_IMAGE_ENABLE_ALL_SECURITY_SERVICES"
+	ConfigNativeImageProviders      = "BP_NATIVE_IMAGE_SECURITY_PROVIDERS"
+	ConfigNativeImageBuildTimeInit  = "BP_NATIVE_IMAGE_INITIALIZE_AT_BUILD_TIME"
+	ConfigNativeImageRunTimeInit    = "BP_NATIVE_IMAGE_INITIALIZE_AT_RUN_TIME"
+	ConfigNativeImageExcluded       = "BP_NATIVE_IMAGE_EXCLUDED_ARTIFACTS"
+	ConfigNativeImageClasspath      = "BP_NATIVE_IMAGE_ADDITIONAL_CLASSPATH"
+	ConfigNativeImagePreserve       = "BP_NATIVE_IMAGE_PRESERVE_APP"
+	ConfigNativeImageStaticContent  = "BP_NATIVE_IMAGE_PRESERVE_STATIC"
+	ConfigNativeImageOutputs        = "BP_NATIVE_IMAGE_COPY_OUTPUTS"
+	ConfigNativeImageHybrid         = "BP_NATIVE_IMAGE_HYBRID"
+	ConfigNativeImageProcessTypes   = "BP_NATIVE_IMAGE_PROCESS_TYPES"
+	ConfigNativeImageLaunchArgs     = "BP_NATIVE_IMAGE_LAUNCH_ARGUMENTS"
+	ConfigNativeImageRuntimeOptions = "BP_NATIVE_IMAGE_RUNTIME_OPTIONS"
+	ConfigNativeImageMaxHeapSize    = "BP_NATIVE_IMAGE_MAX_HEAP_SIZE"
+	ConfigNativeImageMaxHeapPercent = "BP_NATIVE_IMAGE_MAX_HEAP_PERCENT"
+	ConfigNativeImageCompressedRefs = "BP_NATIVE_IMAGE_COMPRESSED_REFERENCES"
+	ConfigNativeImageHeapChunkSize  = "BP_NATIVE_IMAGE_ALIGNED_HEAP_CHUNK_SIZE"
+	ConfigNativeImageProperties     = "BP_NATIVE_IMAGE_SYSTEM_PROPERTIES"
+	ConfigNativeImageSummaryPath    = "BP_NATIVE_IMAGE_SUMMARY_PATH"
+	ConfigNativeImageMarch          = "BP_NATIVE_IMAGE_MARCH"
+	ConfigNativeImageCommand        = "BP_NATIVE_IMAGE_COMMAND"
+	ConfigNativeImageSkipGuInstall  = "BP_NATIVE_IMAGE_SKIP_GU_INSTALL"
+	ConfigNativeImageOffline        = "BP_NATIVE_IMAGE_OFFLINE"
+	ConfigNativeImageBuildTools     = "BP_NATIVE_IMAGE_BUILD_TOOLS"
+	ConfigNativeImageInvoker        = "BP_NATIVE_IMAGE_INVOKER"
+	ConfigNativeImageAllowFallback  = "BP_NATIVE_IMAGE_ALLOW_FALLBACK"
+	ConfigNativeImageLibraries      = "BP_NATIVE_IMAGE_NATIVE_LIBRARIES"
+	ConfigNativeImageSerialization  = "BP_NATIVE_IMAGE_SERIALIZATION_CLASSES"
+	ConfigNativeImageProxies        = "BP_NATIVE_IMAGE_DYNAMIC_PROXIES"
+	ConfigNativeImageTarget         = "BP_NATIVE_IMAGE_TARGET"
+	ConfigNativeImageConflicts      = "BP_NATIVE_IMAGE_CLASSPATH_CONFLICTS"
+	ConfigNativeImageStartClass     = "BP_NATIVE_IMAGE_START_CLASS"
+	ConfigNativeImageLegacyMain     = "BP_NATIVE_IMAGE_LEGACY_MAIN_CLASS"
+	ConfigNativeImageLibDirectory   = "BP_NATIVE_IMAGE_LIB_DIRECTORY"
+	ConfigNativeImageLanguages      = "BP_NATIVE_IMAGE_LANGUAGE_DEFAULTS"
+	ConfigNativeImageNetty          = "BP_NATIVE_IMAGE_NETTY_DEFAULTS"
+	ConfigNativeImageLogging        = "BP_NATIVE_IMAGE_LOGGING_DEFAULTS"
+	ConfigNativeImageCache          = "BP_NATIVE_IMAGE_CACHE"
+	ConfigNativeImageExportDiag     = "BP_NATIVE_IMAGE_EXPORT_DIAGNOSTICS"
+	ConfigNativeImageBinaryLayer    = "BP_NATIVE_IMAGE_BINARY_LAYER"
 	CompressorUpx                   = "upx"
 	CompressorGzexe                 = "gzexe"
 	CompressorNone                  = "none"
+
+	CheckWarn = "warn"
+	CheckFail = "fail"
+
+	LabelBinaryName = "io.paketo.native-image.binary.name"
+	LabelBinaryPath = "io.paketo.native-image.binary.path"
+	LabelProcesses  = "io.paketo.native-image.processes"
 )
 
 type Build struct {
-	Logger      bard.Logger
-	SBOMScanner sbom.SBOMScanner
+	// Context is cancelled when the build is aborted, stopping the running command. Defaults to a context that is
+	// only cancelled by SIGTERM or SIGINT.
+	Context            context.Context
+	DependencyDetector DependencyDetector
+	Logger             bard.Logger
+	SBOMScanner        sbom.SBOMScanner
 }
 
 func (b Build) Build(context libcnb.BuildContext) (libcnb.BuildResult, error) {
 	b.Logger.Title(context.Buildpack)
 	result := libcnb.NewBuildResult()
 
-	manifest, err := libjvm.NewManifest(context.Application.Path)
+	manifest, err := NewManifest(context.Application.Path)
 	if err != nil {
 		return libcnb.BuildResult{}, fmt.Errorf("unable to read manifest in %s\n%w", context.Application.Path, err)
 	}
+	DebugManifest(b.Logger, manifest)
 
-	cr, err := libpak.NewConfigurationResolver(context.Buildpack, &b.Logger)
+	cr, err := NewConfigurationResolver(context.Buildpack, &b.Logger)
 	if err != nil {
 		return libcnb.BuildResult{}, fmt.Errorf("unable to create configuration resolver\n%w", err)
 	}
+	cr.WarnDeprecated(b.Logger)
 
-	if _, ok := cr.Resolve(DeprecatedConfigNativeImage); ok {
-		warn(b.Logger, fmt.Sprintf("$%s has been deprecated. Please use $%s instead.",
-			DeprecatedConfigNativeImage,
-			ConfigNativeImage,
-		))
+	warnings := &Warnings{}
+	for _, u := range UnknownConfigurations(os.Environ(), cr.Configurations, cr.Deprecated) {
+		warnings.Warn(b.Logger, fmt.Sprintf("$%s is not a configuration of this buildpack and is ignored", u))
 	}
 
-	args, ok := cr.Resolve(ConfigNativeImageArgs)
-	if !ok {
-		if args, ok = cr.Resolve(DeprecatedConfigNativeImageArgs); ok {
-			warn(b.Logger, fmt.Sprintf("$%s has been deprecated. Please use $%s instead.",
-				DeprecatedConfigNativeImageArgs,
-				ConfigNativeImageArgs,
-			))
-		}
-	}
+	args, _ := cr.Resolve(ConfigNativeImageArgs)
 
 	jarFilePattern, _ := cr.Resolve("BP_NATIVE_IMAGE_BUILT_ARTIFACT")
 	argsFile, _ := cr.Resolve("BP_NATIVE_IMAGE_BUILD_ARGUMENTS_FILE")
@@ -98,29 +183,660 @@ func (b Build) Build(context libcnb.BuildContext) (libcnb.BuildResult, error) {
 		compressor = CompressorNone
 	} else if ok {
 		if compressor != CompressorUpx && compressor != CompressorGzexe && compressor != CompressorNone {
-			warn(b.Logger, fmt.Sprintf("Requested compression method [%s] is unknown, no compression will be performed", compressor))
+			warnings.Warn(b.Logger, fmt.Sprintf("Requested compression method [%s] is unknown, no compression will be performed", compressor))
 			compressor = CompressorNone
 		}
 	}
+	if compressor == CompressorGzexe && runtime.GOOS == Windows {
+		warnings.Warn(b.Logger, "Compression method gzexe is not available on Windows, no compression will be performed")
+		compressor = CompressorNone
+	}
+
+	var timeout time.Duration
+	if t, ok := cr.Resolve(ConfigNativeImageBuildTimeout); ok {
+		if timeout, err = time.ParseDuration(t); err != nil {
+			return libcnb.BuildResult{}, fmt.Errorf("unable to parse $%s value %q as a duration\n%w", ConfigNativeImageBuildTimeout, t, err)
+		}
+	}
+
+	var auxiliary []AuxiliaryBinary
+	if a, ok := cr.Resolve(ConfigNativeImageAuxiliary); ok {
+		if auxiliary, err = ParseAuxiliaryBinaries(a); err != nil {
+			return libcnb.BuildResult{}, fmt.Errorf("unable to parse $%s\n%w", ConfigNativeImageAuxiliary, err)
+		}
+	}
+
+	training, err := FindTrainingArtifacts(context.Application.Path)
+	if err != nil {
+		return libcnb.BuildResult{}, fmt.Errorf("unable to find training artifacts\n%w", err)
+	}
+	var trainingArtifacts []string
+	for _, t := range training {
+		rel, _ := filepath.Rel(context.Application.Path, t)
+		trainingArtifacts = append(trainingArtifacts, rel)
+		b.Logger.Bodyf("Ignoring JVM training run artifact %s, class data sharing archives and AOT caches do not apply to native images", rel)
+	}
+
+	var budget Budget
+	if d, ok := cr.Resolve(ConfigNativeImageDurationBudget); ok {
+		if budget.Duration, err = time.ParseDuration(d); err != nil {
+			return libcnb.BuildResult{}, fmt.Errorf("unable to parse $%s value %q as a duration\n%w", ConfigNativeImageDurationBudget, d, err)
+		}
+	}
+	if m, ok := cr.Resolve(ConfigNativeImageMemoryBudget); ok {
+		if budget.Memory, err = ParseMemoryBudget(m); err != nil {
+			return libcnb.BuildResult{}, err
+		}
+	}
+	if m, ok := cr.Resolve(ConfigNativeImageMaxSize); ok {
+		if budget.Size, err = ParseSizeBudget(m); err != nil {
+			return libcnb.BuildResult{}, err
+		}
+	}
+	a, _ := cr.Resolve(ConfigNativeImageMaxSizeAction)
+	if budget.SizeAction, err = ParseBudgetAction(ConfigNativeImageMaxSizeAction, a, BudgetFail); err != nil {
+		return libcnb.BuildResult{}, err
+	}
+	if t, ok := cr.Resolve(ConfigNativeImagePhaseLimits); ok {
+		if budget.Phases, err = ParsePhaseThresholds(t); err != nil {
+			return libcnb.BuildResult{}, fmt.Errorf("unable to parse $%s\n%w", ConfigNativeImagePhaseLimits, err)
+		}
+	}
+	a, _ = cr.Resolve(ConfigNativeImagePhaseAction)
+	if budget.PhaseAction, err = ParseBudgetAction(ConfigNativeImagePhaseAction, a, BudgetWarn); err != nil {
+		return libcnb.BuildResult{}, err
+	}
+
+	temp := TempSpace{Keep: cr.ResolveBool(ConfigNativeImageKeepTempFiles)}
+	if _, ok := cr.Resolve(ConfigNativeImageDiskCheck); !ok || cr.ResolveBool(ConfigNativeImageDiskCheck) {
+		temp.CheckEstimate = true
+	}
+	t, _ := cr.Resolve(ConfigNativeImageTempDirectory)
+	if temp.Directory, err = ParseTempDirectory(t); err != nil {
+		return libcnb.BuildResult{}, err
+	}
+	m, _ := cr.Resolve(ConfigNativeImageMinDiskSpace)
+	if temp.MinFree, err = ParseMinDiskSpace(m); err != nil {
+		return libcnb.BuildResult{}, err
+	}
+
+	var agent *TracingAgent
+	if cr.ResolveBool(ConfigNativeImageAgent) {
+		agent = &TracingAgent{}
+		if d, ok := cr.Resolve(ConfigNativeImageAgentDuration); ok {
+			if agent.Duration, err = time.ParseDuration(d); err != nil {
+				return libcnb.BuildResult{}, fmt.Errorf("unable to parse $%s value %q as a duration\n%w", ConfigNativeImageAgentDuration, d, err)
+			}
+		}
+		agent.ReadyURL, _ = cr.Resolve(ConfigNativeImageAgentReadyURL)
+	}
+
+	entries, err := ReadClasspathIndex(context.Application.Path, manifest)
+	if err != nil {
+		return libcnb.BuildResult{}, fmt.Errorf("unable to read classpath index\n%w", err)
+	}
+
+	if b.DependencyDetector == nil {
+		b.DependencyDetector = NewDependencyDetector()
+	}
+	dependencies, err := DetectDependencies(b.DependencyDetector, context.Application.Path, entries)
+	if err != nil {
+		return libcnb.BuildResult{}, err
+	}
+
+	overrides, err := FindConfigurationOverrides(context.Application.Path, context.Platform.Bindings)
+	if err != nil {
+		return libcnb.BuildResult{}, fmt.Errorf("unable to find native-image configuration overrides\n%w", err)
+	}
+	for _, o := range overrides {
+		b.Logger.Bodyf("Merging native-image configuration from %s", o)
+	}
+
+	var (
+		effective    EffectiveConfiguration
+		springNative *Artifact
+	)
+	if a, ok := FindSpringNative(dependencies); ok {
+		b.Logger.Bodyf("Found %s %s", a.ArtifactID, a.Version)
+		effective.SpringNative = a.Version
+		springNative = &a
+	}
+
+	compatibility, err := ParseCompatibility(context.Buildpack.Metadata)
+	if err != nil {
+		return libcnb.BuildResult{}, fmt.Errorf("unable to read Spring Native compatibility\n%w", err)
+	}
+
+	var protocols []string
+	if p, ok := cr.Resolve(ConfigNativeImageURLProtocols); ok {
+		protocols = ParseURLProtocols(p)
+	} else if springWeb, ok := FindSpringWeb(dependencies); ok {
+		protocols = DefaultWebURLProtocols
+		b.Logger.Bodyf("Enabling %s URL protocols for %s. Set $%s to override.",
+			strings.Join(protocols, ","), springWeb.ArtifactID, ConfigNativeImageURLProtocols)
+	}
+
+	excludeDevServices := true
+	if _, ok := cr.Resolve(ConfigNativeImageDevServices); ok {
+		excludeDevServices = cr.ResolveBool(ConfigNativeImageDevServices)
+	}
+
+	var excluded []string
+	if excludeDevServices {
+		for _, d := range FindDevServices(dependencies) {
+			warnings.Warn(b.Logger, fmt.Sprintf("Excluding development-time dependency %s from the native image. Set $%s to false to include it.",
+				filepath.Base(d.Path),
+				ConfigNativeImageDevServices,
+			))
+			excluded = append(excluded, d.Path)
+		}
+	}
+
+	buildOnlyCoordinates, _ := cr.Resolve(ConfigNativeImageBuildOnly)
+	for _, a := range FindBuildOnly(dependencies, ParseResourcePatterns(buildOnlyCoordinates)...) {
+		b.Logger.Bodyf("Excluding build-only %s from the native image", filepath.Base(a.Path))
+		if !containsPath(excluded, a.Path) {
+			excluded = append(excluded, a.Path)
+		}
+	}
+
+	additional, _ := cr.Resolve(ConfigNativeImageClasspath)
+	additionalClasspath, err := FindAdditionalClasspath(context.Application.Path, additional, context.Platform.Bindings)
+	if err != nil {
+		return libcnb.BuildResult{}, fmt.Errorf("unable to find additional classpath entries\n%w", err)
+	}
+	for _, e := range additionalClasspath {
+		b.Logger.Bodyf("Appending %s to the native image classpath", e)
+	}
+
+	denied, err := ParseDeniedArguments(context.Buildpack.Metadata)
+	if err != nil {
+		return libcnb.BuildResult{}, fmt.Errorf("unable to read denied native-image arguments\n%w", err)
+	}
+
+	if patterns, ok := cr.Resolve(ConfigNativeImageExcluded); ok {
+		matched, err := MatchClasspathEntries(entries, ParseResourcePatterns(patterns))
+		if err != nil {
+			return libcnb.BuildResult{}, fmt.Errorf("unable to match $%s\n%w", ConfigNativeImageExcluded, err)
+		} else if len(matched) == 0 {
+			b.Logger.Debugf("$%s %q matches no classpath entries", ConfigNativeImageExcluded, patterns)
+		}
+
+		for _, m := range matched {
+			b.Logger.Bodyf("Excluding %s from the native image", m)
+			if path := filepath.Join(context.Application.Path, m); !containsPath(excluded, path) {
+				excluded = append(excluded, path)
+			}
+		}
+	}
+
+	startClass, _ := cr.Resolve(ConfigNativeImageStartClass)
+	libDirectory, _ := cr.Resolve(ConfigNativeImageLibDirectory)
+
+	n := New(
+		WithApplicationPath(context.Application.Path),
+		WithArguments(args),
+		WithArgumentsFile(argsFile),
+		WithClasspathStrategy(ClasspathAuto, jarFilePattern),
+		WithCompressor(compressor),
+		WithContext(b.Context),
+		WithLibDirectory(libDirectory),
+		WithLogger(b.Logger),
+		WithManifest(manifest),
+		WithStackID(context.StackID),
+		WithStartClass(strings.TrimSpace(startClass)),
+	)
+	if command, ok := cr.Resolve(ConfigNativeImageCommand); ok && command != "" {
+		n.Command = command
+	}
+	target, _ := cr.Resolve(ConfigNativeImageTarget)
+	if n.Target, err = ParseTarget(target); err != nil {
+		return libcnb.BuildResult{}, fmt.Errorf("unable to parse $%s\n%w", ConfigNativeImageTarget, err)
+	}
+	offline := cr.ResolveBool(ConfigNativeImageOffline)
+	invoker, _ := cr.Resolve(ConfigNativeImageInvoker)
+	if cr.ResolveBool(ConfigNativeImageBuildTools) {
+		invoker = InvokerBuildTools
+	}
+	switch strings.ToLower(strings.TrimSpace(invoker)) {
+	case "", InvokerDirect:
+	case InvokerArgfile:
+		n.Invoker = ArgfileInvoker{Command: n.Command}
+	case InvokerBundle:
+		bundle, ok, err := FindBundle(context.Application.Path)
+		if err != nil {
+			return libcnb.BuildResult{}, err
+		}
+		if !ok {
+			return libcnb.BuildResult{}, fmt.Errorf("$%s is %s but the application has no *.nib bundle", ConfigNativeImageInvoker, InvokerBundle)
+		}
+		b.Logger.Bodyf("Compiling the bundle %s", filepath.Base(bundle))
+		if args != "" {
+			warnings.Warn(b.Logger, fmt.Sprintf("$%s is ignored, the bundle records the arguments of native-image", ConfigNativeImageArgs))
+		}
+		n.Invoker = BundleInvoker{Command: n.Command, Bundle: bundle}
+	case InvokerBuildTools:
+		tool, ok, err := DetectBuildTool(context.Application.Path)
+		if err != nil {
+			return libcnb.BuildResult{}, err
+		}
+		if !ok {
+			return libcnb.BuildResult{}, fmt.Errorf("$%s is set but the application has no mvnw or gradlew wrapper", ConfigNativeImageBuildTools)
+		}
+		b.Logger.Bodyf("Compiling with the %s wrapper and Native Build Tools", tool.Name)
+		if args != "" {
+			warnings.Warn(b.Logger, fmt.Sprintf("$%s is ignored, %s decides the arguments of native-image", ConfigNativeImageArgs, tool.Name))
+		}
+		if offline {
+			tool.Args = append(tool.Args, OfflineArgument)
+		}
+		n.BuildTool = &tool
+	default:
+		return libcnb.BuildResult{}, fmt.Errorf("unknown $%s %q, must be one of %s, %s, %s or %s",
+			ConfigNativeImageInvoker, invoker, InvokerDirect, InvokerArgfile, InvokerBundle, InvokerBuildTools)
+	}
+	if n.Enterprise, err = FindEnterpriseLicense(context.Platform.Bindings); err != nil {
+		return libcnb.BuildResult{}, err
+	}
+	if n.Enterprise != nil {
+		b.Logger.Body("Accepted the GraalVM Enterprise license")
+	}
+	if n.BuildSecrets, err = FindBuildSecrets(context.Platform.Bindings); err != nil {
+		return libcnb.BuildResult{}, err
+	}
+	if n.BuildSecrets != nil {
+		b.Logger.Body("Passing build secrets to native-image")
+	}
+	if n.CACertificates, err = FindCACertificates(context.Platform.Bindings); err != nil {
+		return libcnb.BuildResult{}, err
+	}
+	installer := ComponentInstaller{
+		CACertificates: n.CACertificates,
+		Command:        n.Command,
+		Context:        b.Context,
+		Executor:       n.Executor,
+		JavaHome:       os.Getenv("JAVA_HOME"),
+		Logger:         b.Logger,
+		Offline:        offline,
+	}
+	if installer.Archive, err = FindComponentArchive(context.Platform.Bindings); err != nil {
+		return libcnb.BuildResult{}, err
+	}
+	if offline {
+		missing, err := Offline{
+			ApplicationPath:  context.Application.Path,
+			BuildTool:        n.BuildTool,
+			Command:          n.Command,
+			ComponentArchive: installer.Archive,
+			InstallComponent: !cr.ResolveBool(ConfigNativeImageSkipGuInstall),
+		}.Missing()
+		if err != nil {
+			return libcnb.BuildResult{}, err
+		} else if len(missing) > 0 {
+			return libcnb.BuildResult{}, OfflineError{Missing: missing}
+		}
+		b.Logger.Body("Building offline, resolving the component and build tool dependencies from bindings and caches")
+	}
+	n.Builder = fmt.Sprintf("%s@%s", context.Buildpack.Info.ID, context.Buildpack.Info.Version)
+
+	if err := ValidateArchitecture(os.Getenv("JAVA_HOME"), runtime.GOARCH); err != nil {
+		return libcnb.BuildResult{}, err
+	}
+	n.Architecture = runtime.GOARCH
+	n.March, _ = cr.Resolve(ConfigNativeImageMarch)
+	if n.March == MarchNative {
+		warnings.Warn(b.Logger, fmt.Sprintf("$%s is %s, the native image may crash with SIGILL on hosts with older CPUs than the builder", ConfigNativeImageMarch, MarchNative))
+	}
+	b.Logger.Bodyf("Building for %s", n.Architecture)
+
+	n.Timeout = timeout
+	n.Budget = budget
+	n.TempSpace = temp
+	n.TracingAgent = agent
+	n.Assertions = cr.ResolveBool(ConfigNativeImageAssertions)
+	n.ExitHandlers = cr.ResolveBool(ConfigNativeImageExitHandlers)
+	n.HeapDumpOnOutOfMemory = cr.ResolveBool(ConfigNativeImageHeapDump)
+	n.ReportStackTraces = cr.ResolveBool(ConfigNativeImageStackTraces)
+	if n.RunImageCheck, err = parseCheck(cr, ConfigNativeImageRunImageCheck); err != nil {
+		return libcnb.BuildResult{}, err
+	}
+	if n.ClasspathConflicts, err = parseCheck(cr, ConfigNativeImageConflicts); err != nil {
+		return libcnb.BuildResult{}, err
+	}
+	n.PIE = cr.ResolveBool(ConfigNativeImagePIE)
+	relro, _ := cr.Resolve(ConfigNativeImageRELRO)
+	if n.RELRO, err = ParseRELRO(relro); err != nil {
+		return libcnb.BuildResult{}, fmt.Errorf("unable to parse $%s\n%w", ConfigNativeImageRELRO, err)
+	}
+	n.AnalysisReports = cr.ResolveBool(ConfigNativeImageAnalysis)
+	forbidden, _ := cr.Resolve(ConfigNativeImageForbiddenTypes)
+	n.ForbiddenTypes = ParseClassList(forbidden)
+	n.VerifyReproducible = cr.ResolveBool(ConfigNativeImageVerify)
+	n.Deterministic = cr.ResolveBool(ConfigNativeImageDeterministic) || n.VerifyReproducible
+	if n.Deterministic {
+		epoch, _ := cr.Resolve(SourceDateEpoch)
+		if n.SourceDateEpoch, err = ParseSourceDateEpoch(epoch); err != nil {
+			return libcnb.BuildResult{}, err
+		}
+	}
+	n.RetryOnOutOfMemory = cr.ResolveBool(ConfigNativeImageRetryOnOOM)
+	n.AuxiliaryBinaries = auxiliary
+	n.Excluded = excluded
+	n.LegacyMainClass = cr.ResolveBool(ConfigNativeImageLegacyMain)
+	n.AdditionalClasspath = additionalClasspath
+	preserve, _ := cr.Resolve(ConfigNativeImagePreserve)
+	n.Preserve = ParsePreservePatterns(preserve)
+	if !containsString(n.Preserve, "**") {
+		static, _ := cr.Resolve(ConfigNativeImageStaticContent)
+		n.Preserve = append(n.Preserve, ParseStaticContentPatterns(static)...)
+	}
+	hybrid := cr.ResolveBool(ConfigNativeImageHybrid)
+	if hybrid {
+		// the JVM process runs the whole application, so nothing may be removed
+		n.Preserve = []string{"**"}
+	}
+	outputs, _ := cr.Resolve(ConfigNativeImageOutputs)
+	n.Outputs = ParseResourcePatterns(outputs)
+	n.ConfigurationDirectories = overrides
+	libraries, _ := cr.Resolve(ConfigNativeImageLibraries)
+	var jni []string
+	if n.NativeLibraries, jni, err = FindNativeLibraries(context.Application.Path, ParseNativeLibraryPatterns(libraries), context.Platform.Bindings); err != nil {
+		return libcnb.BuildResult{}, err
+	}
+	for _, j := range jni {
+		b.Logger.Bodyf("Merging JNI configuration from %s", j)
+	}
+	n.ConfigurationDirectories = append(n.ConfigurationDirectories, jni...)
+	serialization, _ := cr.Resolve(ConfigNativeImageSerialization)
+	if n.Generated.SerializationClasses, err = ParseSerializationClasses(serialization); err != nil {
+		return libcnb.BuildResult{}, err
+	}
+	proxies, _ := cr.Resolve(ConfigNativeImageProxies)
+	if n.Generated.DynamicProxies, err = ParseDynamicProxies(proxies); err != nil {
+		return libcnb.BuildResult{}, err
+	}
+	n.RecordArguments = cr.ResolveBool(ConfigNativeImageRecordArgs)
+	n.SummaryPath, _ = cr.Resolve(ConfigNativeImageSummaryPath)
+	n.DeniedArguments = denied
+	n.SpringNative = springNative
+	n.Compatibility = compatibility
 
-	n, err := NewNativeImage(context.Application.Path, args, argsFile, compressor, jarFilePattern, manifest, context.StackID)
+	resources, _ := cr.Resolve(ConfigNativeImageResources)
+	n.IncludeResources = ParseResourcePatterns(resources)
+	n.Locales, _ = cr.Resolve(ConfigNativeImageLocales)
+	n.AllCharsets = cr.ResolveBool(ConfigNativeImageAllCharsets)
+	n.URLProtocols = protocols
+	n.SecurityServices = cr.ResolveBool(ConfigNativeImageSecurity)
+	providers, _ := cr.Resolve(ConfigNativeImageProviders)
+	n.SecurityProviders = ParseSecurityProviders(providers)
+	systemProperties, _ := cr.Resolve(ConfigNativeImageProperties)
+	if n.SystemProperties, err = ParseSystemProperties(systemProperties); err != nil {
+		return libcnb.BuildResult{}, fmt.Errorf("unable to parse $%s\n%w", ConfigNativeImageProperties, err)
+	}
+	maxHeapSize, _ := cr.Resolve(ConfigNativeImageMaxHeapSize)
+	if n.MaxHeapSize, err = ParseMaxHeapSize(maxHeapSize); err != nil {
+		return libcnb.BuildResult{}, err
+	}
+	maxHeapPercent, _ := cr.Resolve(ConfigNativeImageMaxHeapPercent)
+	if n.MaxHeapPercent, err = ParseMaxHeapPercent(maxHeapPercent); err != nil {
+		return libcnb.BuildResult{}, err
+	}
+	compressedReferences, _ := cr.Resolve(ConfigNativeImageCompressedRefs)
+	if n.CompressedReferences, err = ParseCompressedReferences(compressedReferences); err != nil {
+		return libcnb.BuildResult{}, err
+	}
+	chunkSize, _ := cr.Resolve(ConfigNativeImageHeapChunkSize)
+	if n.AlignedHeapChunkSize, err = ParseAlignedHeapChunkSize(chunkSize); err != nil {
+		return libcnb.BuildResult{}, err
+	}
+	buildTime, _ := cr.Resolve(ConfigNativeImageBuildTimeInit)
+	n.InitializeAtBuildTime = ParseClassList(buildTime)
+	runTime, _ := cr.Resolve(ConfigNativeImageRunTimeInit)
+	n.InitializeAtRunTime = ParseClassList(runTime)
+
+	// libraries are detected even if their defaults are disabled, to warn that they may lack reachability metadata
+	_, ok = cr.Resolve(ConfigNativeImageLanguages)
+	languageDefaults := !ok || cr.ResolveBool(ConfigNativeImageLanguages)
+	table, err := ParseLanguageDefaults(context.Buildpack.Metadata)
 	if err != nil {
-		return libcnb.BuildResult{}, fmt.Errorf("unable to create native image layer\n%w", err)
+		return libcnb.BuildResult{}, fmt.Errorf("unable to read JVM language defaults\n%w", err)
+	}
+	languages, artifacts := FindLanguageDefaults(dependencies, table)
+	for i, l := range languages {
+		if languageDefaults {
+			b.Logger.Bodyf("Applying %s defaults for %s %s. Set $%s to false to disable.",
+				l.Name, artifacts[i].ArtifactID, artifacts[i].Version, ConfigNativeImageLanguages)
+		} else {
+			warnings.Warn(b.Logger, missingMetadata(artifacts[i], ConfigNativeImageLanguages))
+		}
+	}
+	if languageDefaults {
+		n.InitializeAtBuildTime, n.IncludeResources = MergeLanguageDefaults(languages, n.InitializeAtBuildTime, n.InitializeAtRunTime, n.IncludeResources)
+	}
+
+	_, ok = cr.Resolve(ConfigNativeImageNetty)
+	nettyDefaults := !ok || cr.ResolveBool(ConfigNativeImageNetty)
+	if netty, ok := FindNetty(dependencies); ok && !nettyDefaults {
+		warnings.Warn(b.Logger, missingMetadata(netty, ConfigNativeImageNetty))
+	} else if ok {
+		b.Logger.Bodyf("Applying Netty defaults for %s %s. Set $%s to false to disable.", netty.ArtifactID, netty.Version, ConfigNativeImageNetty)
+		n.InitializeAtRunTime, n.SystemProperties = MergeNettyDefaults(n.InitializeAtBuildTime, n.InitializeAtRunTime, n.SystemProperties)
+
+		if _, ok := cr.Resolve(ConfigNativeImageURLProtocols); !ok && len(n.URLProtocols) == 0 {
+			n.URLProtocols = DefaultWebURLProtocols
+			b.Logger.Bodyf("Enabling %s URL protocols for %s. Set $%s to override.",
+				strings.Join(n.URLProtocols, ","), netty.ArtifactID, ConfigNativeImageURLProtocols)
+		}
 	}
-	n.Logger = b.Logger
-	result.Layers = append(result.Layers, n)
 
-	startClass, err := findStartOrMainClass(manifest, context.Application.Path, jarFilePattern)
+	_, ok = cr.Resolve(ConfigNativeImageLogging)
+	loggingDefaults := !ok || cr.ResolveBool(ConfigNativeImageLogging)
+	backends, artifacts := FindLoggingBackends(dependencies)
+	var dirs []string
+	for i, l := range backends {
+		if !loggingDefaults {
+			warnings.Warn(b.Logger, missingMetadata(artifacts[i], ConfigNativeImageLogging))
+			continue
+		}
+
+		dir, ok, err := LoggingConfigurationDirectory(context.Buildpack.Path, l)
+		if err != nil {
+			return libcnb.BuildResult{}, err
+		} else if !ok {
+			continue
+		}
+
+		b.Logger.Bodyf("Adding %s configuration for %s %s. Set $%s to false to disable.",
+			l.Name, artifacts[i].ArtifactID, artifacts[i].Version, ConfigNativeImageLogging)
+		dirs = append(dirs, dir)
+	}
+	// hand-written overrides come last
+	n.ConfigurationDirectories = append(dirs, n.ConfigurationDirectories...)
+
+	n.CompareMetrics = true
+	if _, ok := cr.Resolve(ConfigNativeImageCompare); ok {
+		n.CompareMetrics = cr.ResolveBool(ConfigNativeImageCompare)
+	}
+
+	n.LibraryArguments = true
+	if _, ok := cr.Resolve(ConfigNativeImageLibraryArgs); ok {
+		n.LibraryArguments = cr.ResolveBool(ConfigNativeImageLibraryArgs)
+	}
+
+	environment, _ := cr.Resolve(ConfigNativeImageBuildEnv)
+	n.Environment = append(append([]string{}, DefaultEnvironment...), ParseEnvironment(environment)...)
+
+	metrics := &Metrics{}
+	n.Metrics = metrics
+	n.Warnings = warnings
+	n.AllowFallback = cr.ResolveBool(ConfigNativeImageAllowFallback)
+
+	if _, ok := cr.Resolve(ConfigNativeImageCache); ok {
+		n.Cache = cr.ResolveBool(ConfigNativeImageCache)
+	}
+	if !n.Cache {
+		b.Logger.Bodyf("Not caching the native image layer, so the application is compiled on every build")
+	}
+	if n.BinaryLayer = cr.ResolveBool(ConfigNativeImageBinaryLayer); n.BinaryLayer && n.Target == TargetAWSLambda {
+		return libcnb.BuildResult{}, fmt.Errorf("$%s is not supported with $%s=%s, which packages the binary in the application",
+			ConfigNativeImageBinaryLayer, ConfigNativeImageTarget, TargetAWSLambda)
+	}
+	if len(n.NativeLibraries) > 0 && n.Target == TargetAWSLambda {
+		return libcnb.BuildResult{}, fmt.Errorf("native libraries are not supported with $%s=%s, which packages only the binary",
+			ConfigNativeImageTarget, TargetAWSLambda)
+	}
+
+	n.debugClasspath()
+
+	diagnostics := Diagnostics{Export: cr.ResolveBool(ConfigNativeImageExportDiag)}
+	n.DiagnosticsPath = filepath.Join(context.Layers.Path, diagnostics.Name())
+	// the component is installed ahead of the native image layer, which runs native-image
+	if !cr.ResolveBool(ConfigNativeImageSkipGuInstall) {
+		result.Layers = append(result.Layers, installer)
+	}
+	result.Layers = append(result.Layers, n, diagnostics)
+
+	startClass, err = n.startClass()
 	if err != nil {
 		return libcnb.BuildResult{}, fmt.Errorf("unable to find required manifest property\n%w", err)
 	}
 
-	command := filepath.Join(context.Application.Path, startClass)
+	launchArguments, _ := cr.Resolve(ConfigNativeImageLaunchArgs)
+	arguments, err := ParseLaunchArguments(launchArguments)
+	if err != nil {
+		return libcnb.BuildResult{}, fmt.Errorf("unable to parse $%s\n%w", ConfigNativeImageLaunchArgs, err)
+	}
+
+	types, _ := cr.Resolve(ConfigNativeImageProcessTypes)
+	additionalTypes, err := ParseProcessTypes(types)
+	if err != nil {
+		return libcnb.BuildResult{}, fmt.Errorf("unable to parse $%s\n%w", ConfigNativeImageProcessTypes, err)
+	}
+
+	binaries := context.Application.Path
+	if n.BinaryLayer {
+		layer := BinaryLayer{
+			Binaries:        []string{BinaryName(startClass, runtime.GOOS)},
+			Logger:          b.Logger,
+			Outputs:         n.Outputs,
+			SourceDateEpoch: n.SourceDateEpoch,
+			SourcePath:      filepath.Join(context.Layers.Path, n.Name()),
+		}
+		for _, a := range auxiliary {
+			layer.Binaries = append(layer.Binaries, BinaryName(a.Name, runtime.GOOS))
+		}
+		layer.Binaries = append(layer.Binaries, NativeLibraryNames(n.NativeLibraries)...)
+		result.Layers = append(result.Layers, layer)
+		binaries = filepath.Join(context.Layers.Path, layer.Name())
+	}
+
+	command := filepath.Join(binaries, BinaryName(startClass, runtime.GOOS))
 	result.Processes = append(result.Processes,
-		libcnb.Process{Type: "native-image", Command: command, Direct: true},
-		libcnb.Process{Type: "task", Command: command, Direct: true},
-		libcnb.Process{Type: "web", Command: command, Direct: true, Default: true},
+		libcnb.Process{Type: "native-image", Command: command, Arguments: arguments, Direct: true},
+		libcnb.Process{Type: "task", Command: command, Arguments: arguments, Direct: true},
+		libcnb.Process{Type: "web", Command: command, Arguments: arguments, Direct: true, Default: true},
+	)
+
+	if hybrid {
+		strategy, err := n.strategy()
+		if err != nil {
+			return libcnb.BuildResult{}, err
+		}
+
+		var jvm libcnb.Process
+		if strategy == ClasspathDirectory {
+			jvm, err = DirectoryJVMProcess(context.Application.Path, n.LibDirectory, startClass)
+		} else {
+			jvm, err = JVMProcess(context.Application.Path, manifest, jarFilePattern, strategy == ClasspathExplodedJar)
+		}
+		if err != nil {
+			return libcnb.BuildResult{}, err
+		}
+
+		b.Logger.Bodyf("Keeping the application to run on the JVM with the %s process type", ProcessTypeJVM)
+		result.Processes = append(result.Processes,
+			libcnb.Process{Type: ProcessTypeNative, Command: command, Arguments: arguments, Direct: true},
+			jvm,
+		)
+	}
+
+	procfile, err := ReadProcfile(context.Application.Path)
+	if err != nil {
+		return libcnb.BuildResult{}, fmt.Errorf("unable to read Procfile\n%w", err)
+	}
+	for _, e := range procfile {
+		p, err := ProcfileProcess(e, command, b.Logger)
+		if err != nil {
+			return libcnb.BuildResult{}, err
+		}
+		b.Logger.Bodyf("Contributing Procfile process type %s", p.Type)
+
+		result.Processes = replaceProcess(result.Processes, p)
+	}
+
+	for _, t := range additionalTypes {
+		for _, p := range result.Processes {
+			if t.Type == p.Type {
+				return libcnb.BuildResult{}, fmt.Errorf("process type %s conflicts with a contributed process type", t.Type)
+			}
+		}
+		result.Processes = append(result.Processes, libcnb.Process{
+			Type:      t.Type,
+			Command:   command,
+			Arguments: append(append([]string{}, arguments...), t.Arguments...),
+			Direct:    true,
+		})
+	}
+
+	for _, a := range auxiliary {
+		for _, p := range result.Processes {
+			if a.Name == p.Type || a.Name == startClass {
+				return libcnb.BuildResult{}, fmt.Errorf("auxiliary binary %s conflicts with the application binary or process types", a.Name)
+			}
+		}
+		result.Processes = append(result.Processes,
+			libcnb.Process{Type: a.Name, Command: filepath.Join(binaries, BinaryName(a.Name, runtime.GOOS)), Direct: true})
+	}
+
+	if cr.ResolveBool(ConfigNativeImageRuntimeOptions) {
+		b.Logger.Body("Passing the runtime options of $JAVA_TOOL_OPTIONS and $BPL_NATIVE_IMAGE_OPTS to the native image, which requires a shell at launch")
+		h := libpak.NewHelperLayerContributor(context.Buildpack, "runtime-options")
+		h.Logger = b.Logger
+		result.Layers = append(result.Layers, h)
+
+		for i, p := range result.Processes {
+			result.Processes[i] = RuntimeOptionsProcess(p, command)
+		}
+	}
+
+	// stable labels allow tooling such as the Spring Boot build plugins to validate what was contributed
+	var processTypes []string
+	for _, p := range result.Processes {
+		processTypes = append(processTypes, p.Type)
+	}
+	result.Labels = append(result.Labels,
+		libcnb.Label{Key: LabelBinaryName, Value: filepath.Base(command)},
+		libcnb.Label{Key: LabelBinaryPath, Value: command},
+		libcnb.Label{Key: LabelProcesses, Value: strings.Join(processTypes, ",")},
 	)
+	if springNative != nil {
+		result.Labels = append(result.Labels, libcnb.Label{Key: LabelSpringNativeVersion, Value: springNative.Version})
+	}
+
+	effective.Configuration = ResolveConfiguration(cr.ConfigurationResolver)
+	effective.StartClass = startClass
+	effective.Processes = processTypes
+	effective.Excluded = excluded
+	effective.TrainingArtifacts = trainingArtifacts
+	configuration := Configuration{Effective: effective, Metrics: metrics}
+	if len(n.NativeLibraries) > 0 {
+		configuration.LibraryPath = binaries
+	}
+	result.Layers = append(result.Layers, configuration)
+
+	if cr.ResolveBool(ConfigNativeImageUsageStats) {
+		result.Layers = append(result.Layers, UsageStatistics{Metrics: metrics})
+	}
 
 	if b.SBOMScanner == nil {
 		b.SBOMScanner = sbom.NewSyftCLISBOMScanner(context.Layers, effect.NewExecutor(), b.Logger)
@@ -129,9 +845,32 @@ func (b Build) Build(context libcnb.BuildContext) (libcnb.BuildResult, error) {
 		return libcnb.BuildResult{}, fmt.Errorf("unable to create Build SBoM \n%w", err)
 	}
 
+	// metric labels are filled in when the native image layer is contributed, by key in the labels shared once complete
+	keys := MetricsLabelKeys
+	if n.RecordArguments {
+		keys = append(keys[:len(keys):len(keys)], LabelArguments)
+	}
+	for _, k := range keys {
+		result.Labels = append(result.Labels, libcnb.Label{Key: k})
+	}
+	metrics.Labels = result.Labels
+
 	return result, nil
 }
 
+// replaceProcess replaces the process of the same type, keeping whether it is the default, or appends the process
+func replaceProcess(processes []libcnb.Process, process libcnb.Process) []libcnb.Process {
+	for i, p := range processes {
+		if p.Type == process.Type {
+			process.Default = p.Default
+			processes[i] = process
+			return processes
+		}
+	}
+
+	return append(processes, process)
+}
+
 // todo: move warn method to the logger
 func warn(l bard.Logger, msg string) {
 	l.Headerf(
@@ -141,8 +880,29 @@ func warn(l bard.Logger, msg string) {
 	)
 }
 
-func findStartOrMainClass(manifest *properties.Properties, appPath, jarFilePattern string) (string, error) {
-	_, startClass, err := ExplodedJarArguments{Manifest: manifest}.Configure(nil)
+// parseCheck resolves whether a check warns, fails or, when false, is skipped. Checks warn by default.
+func parseCheck(cr ConfigurationResolver, name string) (string, error) {
+	value, _ := cr.Resolve(name)
+	switch value = strings.ToLower(strings.TrimSpace(value)); value {
+	case "", CheckWarn:
+		return CheckWarn, nil
+	case CheckFail:
+		return CheckFail, nil
+	case "false":
+		return "", nil
+	default:
+		return "", fmt.Errorf("invalid $%s %q, expected %s, %s or false", name, value, CheckWarn, CheckFail)
+	}
+}
+
+// missingMetadata is the warning about a library on the classpath whose bundled defaults are disabled by config
+func missingMetadata(artifact Artifact, config string) string {
+	return fmt.Sprintf("%s %s is on the classpath but $%s is false, the native image may lack its reachability metadata",
+		artifact.ArtifactID, artifact.Version, config)
+}
+
+func findStartOrMainClass(manifest *properties.Properties, appPath, jarFilePattern, override string) (string, error) {
+	_, startClass, err := ExplodedJarArguments{Manifest: manifest, StartClass: override}.Configure(nil)
 	if err != nil && !errors.Is(err, NoStartOrMainClass{}) {
 		return "", fmt.Errorf("unable to find startClass\n%w", err)
 	}
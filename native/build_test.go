@@ -18,16 +18,21 @@ package native_test
 
 import (
 	"bytes"
+	gocontext "context"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/paketo-buildpacks/libpak/sbom/mocks"
 	"github.com/paketo-buildpacks/libpak/sherpa"
 
 	"github.com/buildpacks/libcnb"
 	. "github.com/onsi/gomega"
+	"github.com/paketo-buildpacks/libpak"
 	"github.com/paketo-buildpacks/libpak/bard"
 	"github.com/sclevine/spec"
 
@@ -79,6 +84,96 @@ func testBuild(t *testing.T, context spec.G, it spec.S) {
 		Expect(os.RemoveAll(ctx.Layers.Path)).To(Succeed())
 	})
 
+	context("BP_NATIVE_IMAGE_SKIP_GU_INSTALL", func() {
+		it.Before(func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_SKIP_GU_INSTALL", "true")).To(Succeed())
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("BP_NATIVE_IMAGE_SKIP_GU_INSTALL")).To(Succeed())
+		})
+
+		it("does not contribute the component installer", func() {
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"), []byte(`
+Start-Class: test-start-class
+`), 0644)).To(Succeed())
+
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[0].Name()).To(Equal("native-image"))
+		})
+	})
+
+	context("BP_NATIVE_IMAGE_OFFLINE", func() {
+		it.Before(func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_OFFLINE", "true")).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"), []byte(`
+Start-Class: test-start-class
+`), 0644)).To(Succeed())
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("BP_NATIVE_IMAGE_OFFLINE")).To(Succeed())
+			Expect(os.Unsetenv("BP_NATIVE_IMAGE_COMMAND")).To(Succeed())
+			Expect(os.Unsetenv("BP_NATIVE_IMAGE_BUILD_TOOLS")).To(Succeed())
+		})
+
+		it("fails with the artifacts to provide", func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_COMMAND", filepath.Join(ctx.Application.Path, "missing-native-image"))).To(Succeed())
+
+			_, err := build.Build(ctx)
+			Expect(err).To(MatchError(native.OfflineError{Missing: []native.OfflineArtifact{
+				{Name: "native-image component", Provide: "bind the component JAR with a binding of type native-image-component"},
+			}}))
+		})
+
+		it("runs the build tool wrapper offline", func() {
+			command := filepath.Join(ctx.Application.Path, "native-image")
+			Expect(ioutil.WriteFile(command, []byte{}, 0755)).To(Succeed())
+			Expect(os.Setenv("BP_NATIVE_IMAGE_COMMAND", command)).To(Succeed())
+			Expect(os.Setenv("BP_NATIVE_IMAGE_BUILD_TOOLS", "true")).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "gradlew"), []byte{}, 0755)).To(Succeed())
+			Expect(os.Setenv("GRADLE_USER_HOME", ctx.Application.Path)).To(Succeed())
+			defer os.Unsetenv("GRADLE_USER_HOME")
+			Expect(os.MkdirAll(filepath.Join(ctx.Application.Path, "caches"), 0755)).To(Succeed())
+
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[1].(native.NativeImage).BuildTool.Args).To(Equal([]string{"nativeCompile", "--offline"}))
+		})
+	})
+
+	it("logs the manifest and the classpath resolution in debug mode", func() {
+		Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"), []byte(`
+Spring-Boot-Version: 1.1.1
+Spring-Boot-Classes: BOOT-INF/classes
+Spring-Boot-Lib: BOOT-INF/lib
+Start-Class: test-start-class
+`), 0644)).To(Succeed())
+		build.Logger = bard.NewLoggerWithOptions(&out, bard.WithDebug(&out))
+
+		_, err := build.Build(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(out.String()).To(ContainSubstring("Manifest attributes:"))
+		Expect(out.String()).To(ContainSubstring("Start-Class: test-start-class"))
+		Expect(out.String()).To(ContainSubstring("Classpath strategy: exploded-jar"))
+		Expect(out.String()).To(ContainSubstring("Classpath entries:"))
+	})
+
+	it("does not log the classpath resolution without debug mode", func() {
+		Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"), []byte(`
+Start-Class: test-start-class
+`), 0644)).To(Succeed())
+
+		_, err := build.Build(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(out.String()).NotTo(ContainSubstring("Classpath strategy"))
+	})
+
 	it("contributes native image layer", func() {
 		Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"), []byte(`
 Spring-Boot-Version: 1.1.1
@@ -91,13 +186,44 @@ Start-Class: test-start-class
 		result, err := build.Build(ctx)
 		Expect(err).NotTo(HaveOccurred())
 
-		Expect(result.Layers).To(HaveLen(1))
-		Expect(result.Layers[0].(native.NativeImage).Arguments).To(BeEmpty())
+		Expect(result.Layers).To(HaveLen(4))
+		Expect(result.Layers[0].Name()).To(Equal("native-image-component"))
+		Expect(result.Layers[1].(native.NativeImage).Arguments).To(BeEmpty())
+		Expect(result.Layers[1].(native.NativeImage).DiagnosticsPath).To(Equal(filepath.Join(ctx.Layers.Path, "diagnostics")))
+		Expect(result.Layers[2].Name()).To(Equal("diagnostics"))
+		Expect(result.Layers[3].Name()).To(Equal("configuration"))
+		Expect(result.Layers[3].(native.Configuration).Effective.StartClass).To(Equal("test-start-class"))
+		Expect(result.Layers[3].(native.Configuration).Effective.Processes).To(Equal([]string{"native-image", "task", "web"}))
 		Expect(result.Processes).To(ContainElements(
 			libcnb.Process{Type: "native-image", Command: filepath.Join(ctx.Application.Path, "test-start-class"), Direct: true},
 			libcnb.Process{Type: "task", Command: filepath.Join(ctx.Application.Path, "test-start-class"), Direct: true},
 			libcnb.Process{Type: "web", Command: filepath.Join(ctx.Application.Path, "test-start-class"), Direct: true, Default: true},
 		))
+		Expect(result.Labels).To(Equal([]libcnb.Label{
+			{Key: "io.paketo.native-image.binary.name", Value: "test-start-class"},
+			{Key: "io.paketo.native-image.binary.path", Value: filepath.Join(ctx.Application.Path, "test-start-class")},
+			{Key: "io.paketo.native-image.processes", Value: "native-image,task,web"},
+			{Key: "io.paketo.native-image.build-duration-seconds"},
+			{Key: "io.paketo.native-image.peak-rss-bytes"},
+			{Key: "io.paketo.native-image.binary-size-bytes"},
+			{Key: "io.paketo.native-image.graalvm-version"},
+			{Key: "io.paketo.native-image.arguments-digest"},
+			{Key: "io.paketo.native-image.graalvm-release"},
+			{Key: "io.paketo.native-image.distribution"},
+		}))
+
+		metrics := result.Layers[1].(native.NativeImage).Metrics
+		metrics.GraalVMVersion = "test-version"
+		metrics.UpdateLabels()
+		Expect(result.Labels).To(ContainElement(libcnb.Label{Key: "io.paketo.native-image.graalvm-version", Value: "test-version"}))
+
+		metrics.GraalVMVersion = "GraalVM 22.3.1 Java 17 CE (Java Version 17.0.6+10-jvmci-22.3-b13)"
+		metrics.Distribution = "GraalVM CE"
+		metrics.UpdateLabels()
+		Expect(result.Labels).To(ContainElements(
+			libcnb.Label{Key: "io.paketo.native-image.graalvm-release", Value: "22.3.1"},
+			libcnb.Label{Key: "io.paketo.native-image.distribution", Value: "GraalVM CE"},
+		))
 		sbomScanner.AssertCalled(t, "ScanLaunch", ctx.Application.Path, libcnb.SyftJSON, libcnb.CycloneDXJSON)
 	})
 
@@ -122,8 +248,8 @@ Start-Class: test-start-class
 			result, err := build.Build(ctx)
 			Expect(err).NotTo(HaveOccurred())
 
-			Expect(result.Layers).To(HaveLen(1))
-			Expect(result.Layers[0].(native.NativeImage).Arguments).To(BeEmpty())
+			Expect(result.Layers).To(HaveLen(4))
+			Expect(result.Layers[1].(native.NativeImage).Arguments).To(BeEmpty())
 			Expect(result.Processes).To(ContainElements(
 				libcnb.Process{Type: "native-image", Command: filepath.Join(ctx.Application.Path, "test-start-class"), Direct: true},
 				libcnb.Process{Type: "task", Command: filepath.Join(ctx.Application.Path, "test-start-class"), Direct: true},
@@ -156,7 +282,7 @@ Start-Class: test-start-class
 			result, err := build.Build(ctx)
 			Expect(err).NotTo(HaveOccurred())
 
-			Expect(result.Layers[0].(native.NativeImage).Arguments).To(Equal("test-native-image-argument"))
+			Expect(result.Layers[1].(native.NativeImage).Arguments).To(Equal("test-native-image-argument"))
 		})
 	})
 
@@ -181,12 +307,1278 @@ Start-Class: test-start-class
 			result, err := build.Build(ctx)
 			Expect(err).NotTo(HaveOccurred())
 
-			Expect(result.Layers[0].(native.NativeImage).Arguments).To(Equal("test-native-image-argument"))
+			Expect(result.Layers[1].(native.NativeImage).Arguments).To(Equal("test-native-image-argument"))
 
 			Expect(out.String()).To(ContainSubstring("$BP_BOOT_NATIVE_IMAGE_BUILD_ARGUMENTS has been deprecated. Please use $BP_NATIVE_IMAGE_BUILD_ARGUMENTS instead."))
 		})
 	})
 
+	context("BP_NATIVE_IMAGE_BUILD_TIMEOUT", func() {
+		it.Before(func() {
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"), []byte(`
+Start-Class: test-start-class
+`), 0644)).To(Succeed())
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("BP_NATIVE_IMAGE_BUILD_TIMEOUT")).To(Succeed())
+		})
+
+		it("sets the timeout", func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_BUILD_TIMEOUT", "45m")).To(Succeed())
+
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[1].(native.NativeImage).Timeout).To(Equal(45 * time.Minute))
+		})
+
+		it("fails on an invalid duration", func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_BUILD_TIMEOUT", "forever")).To(Succeed())
+
+			_, err := build.Build(ctx)
+			Expect(err).To(MatchError(ContainSubstring(`unable to parse $BP_NATIVE_IMAGE_BUILD_TIMEOUT value "forever" as a duration`)))
+		})
+	})
+
+	context("BP_NATIVE_IMAGE_TEMP_DIRECTORY", func() {
+		it.Before(func() {
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"), []byte(`
+Start-Class: test-start-class
+`), 0644)).To(Succeed())
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("BP_NATIVE_IMAGE_TEMP_DIRECTORY")).To(Succeed())
+			Expect(os.Unsetenv("BP_NATIVE_IMAGE_KEEP_TEMP_FILES")).To(Succeed())
+			Expect(os.Unsetenv("BP_NATIVE_IMAGE_MIN_DISK_SPACE")).To(Succeed())
+		})
+
+		it("configures the temporary space", func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_TEMP_DIRECTORY", "layer")).To(Succeed())
+			Expect(os.Setenv("BP_NATIVE_IMAGE_KEEP_TEMP_FILES", "true")).To(Succeed())
+			Expect(os.Setenv("BP_NATIVE_IMAGE_MIN_DISK_SPACE", "1g")).To(Succeed())
+
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[1].(native.NativeImage).TempSpace).To(Equal(native.TempSpace{
+				Directory:     "layer",
+				Keep:          true,
+				MinFree:       1024 * 1024 * 1024,
+				CheckEstimate: true,
+			}))
+		})
+
+		it("disables the estimated disk space check", func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_DISK_SPACE_CHECK", "false")).To(Succeed())
+			defer os.Unsetenv("BP_NATIVE_IMAGE_DISK_SPACE_CHECK")
+
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[1].(native.NativeImage).TempSpace.CheckEstimate).To(BeFalse())
+		})
+
+		it("fails on a relative directory", func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_TEMP_DIRECTORY", "tmp")).To(Succeed())
+
+			_, err := build.Build(ctx)
+			Expect(err).To(MatchError(ContainSubstring(`unknown $BP_NATIVE_IMAGE_TEMP_DIRECTORY "tmp"`)))
+		})
+	})
+
+	context("BP_NATIVE_IMAGE_MAX_SIZE", func() {
+		it.Before(func() {
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"), []byte(`
+Start-Class: test-start-class
+`), 0644)).To(Succeed())
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("BP_NATIVE_IMAGE_MAX_SIZE")).To(Succeed())
+			Expect(os.Unsetenv("BP_NATIVE_IMAGE_MAX_SIZE_ACTION")).To(Succeed())
+		})
+
+		it("sets the size budget", func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_MAX_SIZE", "80m")).To(Succeed())
+			Expect(os.Setenv("BP_NATIVE_IMAGE_MAX_SIZE_ACTION", "warn")).To(Succeed())
+
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			budget := result.Layers[1].(native.NativeImage).Budget
+			Expect(budget.Size).To(Equal(int64(80 * 1024 * 1024)))
+			Expect(budget.SizeAction).To(Equal(native.BudgetWarn))
+		})
+
+		it("sets the phase thresholds", func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_PHASE_THRESHOLDS", "analysis=10m")).To(Succeed())
+			Expect(os.Setenv("BP_NATIVE_IMAGE_PHASE_THRESHOLD_ACTION", "fail")).To(Succeed())
+			defer os.Unsetenv("BP_NATIVE_IMAGE_PHASE_THRESHOLDS")
+			defer os.Unsetenv("BP_NATIVE_IMAGE_PHASE_THRESHOLD_ACTION")
+
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			budget := result.Layers[1].(native.NativeImage).Budget
+			Expect(budget.Phases).To(Equal([]native.PhaseThreshold{{Phase: "analysis", Duration: 10 * time.Minute}}))
+			Expect(budget.PhaseAction).To(Equal(native.BudgetFail))
+		})
+
+		it("fails on an invalid size", func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_MAX_SIZE", "small")).To(Succeed())
+
+			_, err := build.Build(ctx)
+			Expect(err).To(MatchError(`unable to parse $BP_NATIVE_IMAGE_MAX_SIZE value "small" as a memory size`))
+		})
+	})
+
+	context("BP_NATIVE_IMAGE_ENABLE_ASSERTIONS", func() {
+		it.Before(func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_ENABLE_ASSERTIONS", "true")).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"), []byte(`
+Start-Class: test-start-class
+`), 0644)).To(Succeed())
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("BP_NATIVE_IMAGE_ENABLE_ASSERTIONS")).To(Succeed())
+		})
+
+		it("enables assertions", func() {
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[1].(native.NativeImage).Assertions).To(BeTrue())
+		})
+	})
+
+	context("container arguments", func() {
+		it.Before(func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_INSTALL_EXIT_HANDLERS", "true")).To(Succeed())
+			Expect(os.Setenv("BP_NATIVE_IMAGE_HEAP_DUMP_ON_OOM", "true")).To(Succeed())
+			Expect(os.Setenv("BP_NATIVE_IMAGE_REPORT_STACK_TRACES", "true")).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"), []byte(`
+Start-Class: test-start-class
+`), 0644)).To(Succeed())
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("BP_NATIVE_IMAGE_INSTALL_EXIT_HANDLERS")).To(Succeed())
+			Expect(os.Unsetenv("BP_NATIVE_IMAGE_HEAP_DUMP_ON_OOM")).To(Succeed())
+			Expect(os.Unsetenv("BP_NATIVE_IMAGE_REPORT_STACK_TRACES")).To(Succeed())
+		})
+
+		it("installs exit handlers, writes a heap dump on out of memory and reports stack traces", func() {
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[1].(native.NativeImage).ExitHandlers).To(BeTrue())
+			Expect(result.Layers[1].(native.NativeImage).HeapDumpOnOutOfMemory).To(BeTrue())
+			Expect(result.Layers[1].(native.NativeImage).ReportStackTraces).To(BeTrue())
+		})
+	})
+
+	context("BP_NATIVE_IMAGE_DETERMINISTIC", func() {
+		it.Before(func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_DETERMINISTIC", "true")).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"), []byte(`
+Start-Class: test-start-class
+`), 0644)).To(Succeed())
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("BP_NATIVE_IMAGE_DETERMINISTIC")).To(Succeed())
+		})
+
+		it("requests a deterministic image heap", func() {
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[1].(native.NativeImage).Deterministic).To(BeTrue())
+		})
+
+		it("sets the modification time from SOURCE_DATE_EPOCH", func() {
+			Expect(os.Setenv("SOURCE_DATE_EPOCH", "1700000000")).To(Succeed())
+			defer os.Unsetenv("SOURCE_DATE_EPOCH")
+
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[1].(native.NativeImage).SourceDateEpoch.Unix()).To(Equal(int64(1700000000)))
+		})
+	})
+
+	context("BP_NATIVE_IMAGE_AUXILIARY_BINARIES", func() {
+		it.Before(func() {
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"), []byte(`
+Start-Class: test-start-class
+`), 0644)).To(Succeed())
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("BP_NATIVE_IMAGE_AUXILIARY_BINARIES")).To(Succeed())
+		})
+
+		it("contributes a process for each auxiliary binary", func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_AUXILIARY_BINARIES", "migrate=test.Migrate")).To(Succeed())
+
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[1].(native.NativeImage).AuxiliaryBinaries).To(Equal([]native.AuxiliaryBinary{
+				{Name: "migrate", Class: "test.Migrate"},
+			}))
+			Expect(result.Processes).To(ContainElement(libcnb.Process{
+				Type: "migrate", Command: filepath.Join(ctx.Application.Path, "migrate"), Direct: true,
+			}))
+		})
+
+		it("fails when an auxiliary binary conflicts with a process type", func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_AUXILIARY_BINARIES", "web=test.Web")).To(Succeed())
+
+			_, err := build.Build(ctx)
+			Expect(err).To(MatchError(ContainSubstring("auxiliary binary web conflicts")))
+		})
+	})
+
+	context("dev services", func() {
+		it.Before(func() {
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"), []byte(`
+Start-Class: test-start-class
+Spring-Boot-Classpath-Index: BOOT-INF/classpath.idx
+`), 0644)).To(Succeed())
+			Expect(os.MkdirAll(filepath.Join(ctx.Application.Path, "BOOT-INF"), 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "BOOT-INF", "classpath.idx"), []byte(`- "BOOT-INF/lib/spring-boot-3.1.0.jar"
+- "BOOT-INF/lib/spring-boot-docker-compose-3.1.0.jar"
+`), 0644)).To(Succeed())
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("BP_NATIVE_IMAGE_EXCLUDE_DEV_SERVICES")).To(Succeed())
+		})
+
+		it("excludes dev services by default", func() {
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[1].(native.NativeImage).Excluded).To(Equal([]string{
+				filepath.Join(ctx.Application.Path, "BOOT-INF", "lib", "spring-boot-docker-compose-3.1.0.jar"),
+			}))
+		})
+
+		it("excludes artifacts matching $BP_NATIVE_IMAGE_EXCLUDED_ARTIFACTS", func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_EXCLUDED_ARTIFACTS", "spring-boot-3.*.jar,spring-boot-docker-compose-*.jar")).To(Succeed())
+			defer os.Unsetenv("BP_NATIVE_IMAGE_EXCLUDED_ARTIFACTS")
+
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[1].(native.NativeImage).Excluded).To(Equal([]string{
+				filepath.Join(ctx.Application.Path, "BOOT-INF", "lib", "spring-boot-docker-compose-3.1.0.jar"),
+				filepath.Join(ctx.Application.Path, "BOOT-INF", "lib", "spring-boot-3.1.0.jar"),
+			}))
+		})
+
+		it("includes dev services when disabled", func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_EXCLUDE_DEV_SERVICES", "false")).To(Succeed())
+
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[1].(native.NativeImage).Excluded).To(BeEmpty())
+		})
+	})
+
+	context("build-only artifacts", func() {
+		it.Before(func() {
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"), []byte(`
+Start-Class: test-start-class
+Spring-Boot-Classpath-Index: BOOT-INF/classpath.idx
+`), 0644)).To(Succeed())
+			Expect(os.MkdirAll(filepath.Join(ctx.Application.Path, "BOOT-INF"), 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "BOOT-INF", "classpath.idx"), []byte(`- "BOOT-INF/lib/spring-boot-3.1.0.jar"
+- "BOOT-INF/lib/spring-boot-jarmode-layertools-3.1.0.jar"
+- "BOOT-INF/lib/jacoco-agent-0.8.10.jar"
+`), 0644)).To(Succeed())
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("BP_NATIVE_IMAGE_BUILD_ONLY_ARTIFACTS")).To(Succeed())
+		})
+
+		it("excludes the jar mode tools", func() {
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[1].(native.NativeImage).Excluded).To(Equal([]string{
+				filepath.Join(ctx.Application.Path, "BOOT-INF", "lib", "spring-boot-jarmode-layertools-3.1.0.jar"),
+			}))
+		})
+
+		it("excludes configured build-only artifacts", func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_BUILD_ONLY_ARTIFACTS", "org.jacoco:jacoco-agent")).To(Succeed())
+
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[1].(native.NativeImage).Excluded).To(Equal([]string{
+				filepath.Join(ctx.Application.Path, "BOOT-INF", "lib", "spring-boot-jarmode-layertools-3.1.0.jar"),
+				filepath.Join(ctx.Application.Path, "BOOT-INF", "lib", "jacoco-agent-0.8.10.jar"),
+			}))
+		})
+	})
+
+	it("labels the image with the Spring Native version", func() {
+		Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"), []byte(`
+Start-Class: test-start-class
+Spring-Boot-Classpath-Index: BOOT-INF/classpath.idx
+`), 0644)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(ctx.Application.Path, "BOOT-INF"), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "BOOT-INF", "classpath.idx"), []byte(`- "BOOT-INF/lib/spring-native-0.11.2.jar"
+`), 0644)).To(Succeed())
+
+		result, err := build.Build(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(result.Labels).To(ContainElement(libcnb.Label{Key: "io.paketo.native-image.spring-native-version", Value: "0.11.2"}))
+	})
+
+	context("JVM language defaults", func() {
+		it.Before(func() {
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"), []byte(`
+Start-Class: test-start-class
+Spring-Boot-Classpath-Index: BOOT-INF/classpath.idx
+`), 0644)).To(Succeed())
+			Expect(os.MkdirAll(filepath.Join(ctx.Application.Path, "BOOT-INF"), 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "BOOT-INF", "classpath.idx"), []byte(`- "BOOT-INF/lib/kotlin-stdlib-1.8.22.jar"
+`), 0644)).To(Succeed())
+			Expect(os.Setenv("BP_NATIVE_IMAGE_INITIALIZE_AT_BUILD_TIME", "com.example")).To(Succeed())
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("BP_NATIVE_IMAGE_INITIALIZE_AT_BUILD_TIME")).To(Succeed())
+			Expect(os.Unsetenv("BP_NATIVE_IMAGE_LANGUAGE_DEFAULTS")).To(Succeed())
+		})
+
+		it("merges the Kotlin defaults", func() {
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			n := result.Layers[1].(native.NativeImage)
+			Expect(n.InitializeAtBuildTime).To(HaveLen(5))
+			Expect(n.InitializeAtBuildTime[0]).To(Equal("com.example"))
+			Expect(n.IncludeResources).To(ContainElement("META-INF/*.kotlin_module"))
+		})
+
+		it("does not merge the defaults when disabled", func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_LANGUAGE_DEFAULTS", "false")).To(Succeed())
+
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[1].(native.NativeImage).InitializeAtBuildTime).To(Equal([]string{"com.example"}))
+		})
+	})
+
+	context("Netty defaults", func() {
+		it.Before(func() {
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"), []byte(`
+Start-Class: test-start-class
+Spring-Boot-Classpath-Index: BOOT-INF/classpath.idx
+`), 0644)).To(Succeed())
+			Expect(os.MkdirAll(filepath.Join(ctx.Application.Path, "BOOT-INF"), 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "BOOT-INF", "classpath.idx"), []byte(`- "BOOT-INF/lib/reactor-netty-core-1.1.7.jar"
+`), 0644)).To(Succeed())
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("BP_NATIVE_IMAGE_NETTY_DEFAULTS")).To(Succeed())
+		})
+
+		it("applies the Netty defaults", func() {
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			n := result.Layers[1].(native.NativeImage)
+			Expect(n.InitializeAtRunTime).To(Equal(native.DefaultNettyInitializeAtRunTime))
+			Expect(n.SystemProperties).To(Equal(native.DefaultNettySystemProperties))
+			Expect(n.URLProtocols).To(Equal([]string{"https"}))
+		})
+
+		it("does not apply the defaults when disabled", func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_NETTY_DEFAULTS", "false")).To(Succeed())
+
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			n := result.Layers[1].(native.NativeImage)
+			Expect(n.InitializeAtRunTime).To(BeEmpty())
+			Expect(n.SystemProperties).To(BeEmpty())
+			Expect(n.URLProtocols).To(BeEmpty())
+			Expect(n.Warnings.Messages).To(ContainElement("reactor-netty-core 1.1.7 is on the classpath but $BP_NATIVE_IMAGE_NETTY_DEFAULTS is false, the native image may lack its reachability metadata"))
+		})
+	})
+
+	context("logging defaults", func() {
+		it.Before(func() {
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"), []byte(`
+Start-Class: test-start-class
+Spring-Boot-Classpath-Index: BOOT-INF/classpath.idx
+`), 0644)).To(Succeed())
+			Expect(os.MkdirAll(filepath.Join(ctx.Application.Path, "BOOT-INF"), 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "BOOT-INF", "classpath.idx"), []byte(`- "BOOT-INF/lib/logback-classic-1.4.8.jar"
+`), 0644)).To(Succeed())
+
+			var err error
+			ctx.Buildpack.Path, err = filepath.Abs("..")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		it.After(func() {
+			ctx.Buildpack.Path = ""
+			Expect(os.Unsetenv("BP_NATIVE_IMAGE_LOGGING_DEFAULTS")).To(Succeed())
+		})
+
+		it("adds the configuration of the logging backend", func() {
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[1].(native.NativeImage).ConfigurationDirectories).To(Equal([]string{
+				filepath.Join(ctx.Buildpack.Path, "resources", "logging", "logback"),
+			}))
+		})
+
+		it("does not add the configuration when disabled", func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_LOGGING_DEFAULTS", "false")).To(Succeed())
+
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[1].(native.NativeImage).ConfigurationDirectories).To(BeEmpty())
+		})
+	})
+
+	context("layer types", func() {
+		it.Before(func() {
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"), []byte(`
+Start-Class: test-start-class
+`), 0644)).To(Succeed())
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("BP_NATIVE_IMAGE_CACHE")).To(Succeed())
+			Expect(os.Unsetenv("BP_NATIVE_IMAGE_EXPORT_DIAGNOSTICS")).To(Succeed())
+		})
+
+		it("caches the native image and does not export diagnostics by default", func() {
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[1].(native.NativeImage).Cache).To(BeTrue())
+			Expect(result.Layers[2].(native.Diagnostics).Export).To(BeFalse())
+		})
+
+		it("copies the binary into a launch layer", func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_BINARY_LAYER", "true")).To(Succeed())
+			defer os.Unsetenv("BP_NATIVE_IMAGE_BINARY_LAYER")
+
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[1].(native.NativeImage).BinaryLayer).To(BeTrue())
+			Expect(result.Layers[3].(native.BinaryLayer).Binaries).To(Equal([]string{"test-start-class"}))
+			Expect(result.Layers[3].(native.BinaryLayer).SourcePath).To(Equal(filepath.Join(ctx.Layers.Path, "native-image")))
+			Expect(result.Processes).To(ContainElement(libcnb.Process{
+				Type: "web", Command: filepath.Join(ctx.Layers.Path, "native-image-binary", "test-start-class"), Direct: true, Default: true,
+			}))
+		})
+
+		it("copies native libraries next to the binary", func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_BINARY_LAYER", "true")).To(Succeed())
+			Expect(os.Setenv("BP_NATIVE_IMAGE_NATIVE_LIBRARIES", "lib/*.so")).To(Succeed())
+			defer os.Unsetenv("BP_NATIVE_IMAGE_BINARY_LAYER")
+			defer os.Unsetenv("BP_NATIVE_IMAGE_NATIVE_LIBRARIES")
+			Expect(os.MkdirAll(filepath.Join(ctx.Application.Path, "lib"), 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "lib", "libzstd.so"), []byte{}, 0644)).To(Succeed())
+
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[1].(native.NativeImage).NativeLibraries).To(Equal([]string{filepath.Join(ctx.Application.Path, "lib", "libzstd.so")}))
+			Expect(result.Layers[3].(native.BinaryLayer).Binaries).To(Equal([]string{"test-start-class", "libzstd.so"}))
+			Expect(result.Layers[4].(native.Configuration).LibraryPath).To(Equal(filepath.Join(ctx.Layers.Path, "native-image-binary")))
+		})
+
+		it("does not copy the binary into a launch layer for AWS Lambda", func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_BINARY_LAYER", "true")).To(Succeed())
+			Expect(os.Setenv("BP_NATIVE_IMAGE_TARGET", "aws-lambda")).To(Succeed())
+			defer os.Unsetenv("BP_NATIVE_IMAGE_BINARY_LAYER")
+			defer os.Unsetenv("BP_NATIVE_IMAGE_TARGET")
+
+			_, err := build.Build(ctx)
+			Expect(err).To(MatchError(ContainSubstring("BP_NATIVE_IMAGE_BINARY_LAYER is not supported")))
+		})
+
+		it("configures the layer types", func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_CACHE", "false")).To(Succeed())
+			Expect(os.Setenv("BP_NATIVE_IMAGE_EXPORT_DIAGNOSTICS", "true")).To(Succeed())
+
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[1].(native.NativeImage).Cache).To(BeFalse())
+			Expect(result.Layers[2].(native.Diagnostics).Export).To(BeTrue())
+		})
+	})
+
+	context("URL protocols", func() {
+		it.Before(func() {
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"), []byte(`
+Start-Class: test-start-class
+Spring-Boot-Classpath-Index: BOOT-INF/classpath.idx
+`), 0644)).To(Succeed())
+			Expect(os.MkdirAll(filepath.Join(ctx.Application.Path, "BOOT-INF"), 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "BOOT-INF", "classpath.idx"), []byte(`- "BOOT-INF/lib/spring-web-6.0.9.jar"
+`), 0644)).To(Succeed())
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("BP_NATIVE_IMAGE_URL_PROTOCOLS")).To(Succeed())
+		})
+
+		it("enables https for Spring web frameworks", func() {
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[1].(native.NativeImage).URLProtocols).To(Equal([]string{"https"}))
+		})
+
+		it("uses configured protocols", func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_URL_PROTOCOLS", "http,https")).To(Succeed())
+
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[1].(native.NativeImage).URLProtocols).To(Equal([]string{"http", "https"}))
+		})
+
+		it("enables no protocols when configured empty", func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_URL_PROTOCOLS", "")).To(Succeed())
+
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[1].(native.NativeImage).URLProtocols).To(BeEmpty())
+		})
+	})
+
+	context("BP_NATIVE_IMAGE_RECORD_ARGUMENTS", func() {
+		it.Before(func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_RECORD_ARGUMENTS", "true")).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"), []byte(`
+Start-Class: test-start-class
+`), 0644)).To(Succeed())
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("BP_NATIVE_IMAGE_RECORD_ARGUMENTS")).To(Succeed())
+		})
+
+		it("adds an arguments label", func() {
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[1].(native.NativeImage).RecordArguments).To(BeTrue())
+			Expect(result.Labels[len(result.Labels)-1].Key).To(Equal("io.paketo.native-image.arguments"))
+		})
+	})
+
+	context("BP_NATIVE_IMAGE_USAGE_STATISTICS", func() {
+		it.Before(func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_USAGE_STATISTICS", "true")).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"), []byte(`
+Start-Class: test-start-class
+`), 0644)).To(Succeed())
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("BP_NATIVE_IMAGE_USAGE_STATISTICS")).To(Succeed())
+		})
+
+		it("contributes a usage statistics layer sharing the metrics", func() {
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[4].Name()).To(Equal("usage-statistics"))
+			Expect(result.Layers[4].(native.UsageStatistics).Metrics).To(BeIdenticalTo(result.Layers[1].(native.NativeImage).Metrics))
+		})
+	})
+
+	context("BP_NATIVE_IMAGE_INCLUDE_RESOURCES", func() {
+		it.Before(func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_INCLUDE_RESOURCES", "static/**,*.properties")).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"), []byte(`
+Start-Class: test-start-class
+`), 0644)).To(Succeed())
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("BP_NATIVE_IMAGE_INCLUDE_RESOURCES")).To(Succeed())
+		})
+
+		it("sets the resource patterns", func() {
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[1].(native.NativeImage).IncludeResources).To(Equal([]string{"static/**", "*.properties"}))
+		})
+	})
+
+	context("BP_NATIVE_IMAGE_INCLUDE_LOCALES", func() {
+		it.Before(func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_INCLUDE_LOCALES", "en,fr")).To(Succeed())
+			Expect(os.Setenv("BP_NATIVE_IMAGE_ADD_ALL_CHARSETS", "true")).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"), []byte(`
+Start-Class: test-start-class
+`), 0644)).To(Succeed())
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("BP_NATIVE_IMAGE_INCLUDE_LOCALES")).To(Succeed())
+			Expect(os.Unsetenv("BP_NATIVE_IMAGE_ADD_ALL_CHARSETS")).To(Succeed())
+		})
+
+		it("sets locales and charsets", func() {
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[1].(native.NativeImage).Locales).To(Equal("en,fr"))
+			Expect(result.Layers[1].(native.NativeImage).AllCharsets).To(BeTrue())
+		})
+	})
+
+	context("security", func() {
+		it.Before(func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_ENABLE_ALL_SECURITY_SERVICES", "true")).To(Succeed())
+			Expect(os.Setenv("BP_NATIVE_IMAGE_SECURITY_PROVIDERS", "bc")).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"), []byte(`
+Start-Class: test-start-class
+`), 0644)).To(Succeed())
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("BP_NATIVE_IMAGE_ENABLE_ALL_SECURITY_SERVICES")).To(Succeed())
+			Expect(os.Unsetenv("BP_NATIVE_IMAGE_SECURITY_PROVIDERS")).To(Succeed())
+		})
+
+		it("sets security services and providers", func() {
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[1].(native.NativeImage).SecurityServices).To(BeTrue())
+			Expect(result.Layers[1].(native.NativeImage).SecurityProviders).
+				To(Equal([]string{"org.bouncycastle.jce.provider.BouncyCastleProvider"}))
+		})
+	})
+
+	context("class initialization", func() {
+		it.Before(func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_INITIALIZE_AT_BUILD_TIME", "com.example,org.example")).To(Succeed())
+			Expect(os.Setenv("BP_NATIVE_IMAGE_INITIALIZE_AT_RUN_TIME", "com.example.Random")).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"), []byte(`
+Start-Class: test-start-class
+`), 0644)).To(Succeed())
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("BP_NATIVE_IMAGE_INITIALIZE_AT_BUILD_TIME")).To(Succeed())
+			Expect(os.Unsetenv("BP_NATIVE_IMAGE_INITIALIZE_AT_RUN_TIME")).To(Succeed())
+		})
+
+		it("sets the initialization lists", func() {
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[1].(native.NativeImage).InitializeAtBuildTime).To(Equal([]string{"com.example", "org.example"}))
+			Expect(result.Layers[1].(native.NativeImage).InitializeAtRunTime).To(Equal([]string{"com.example.Random"}))
+		})
+	})
+
+	context("denied arguments", func() {
+		it.Before(func() {
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"), []byte(`
+Start-Class: test-start-class
+`), 0644)).To(Succeed())
+		})
+
+		it("reads the deny-list from the buildpack metadata", func() {
+			ctx.Buildpack.Metadata["denied-arguments"] = []map[string]interface{}{
+				{"argument": "-H:Path", "action": "reject", "reason": "test-reason"},
+			}
+
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[1].(native.NativeImage).DeniedArguments).To(Equal([]native.DeniedArgument{
+				{Argument: "-H:Path", Action: "reject", Reason: "test-reason"},
+			}))
+		})
+
+		it("fails on an invalid deny-list", func() {
+			ctx.Buildpack.Metadata["denied-arguments"] = "-H:Path"
+
+			_, err := build.Build(ctx)
+			Expect(err).To(MatchError(ContainSubstring("unable to read denied native-image arguments")))
+		})
+	})
+
+	context("BP_NATIVE_IMAGE_ADDITIONAL_CLASSPATH", func() {
+		it.Before(func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_ADDITIONAL_CLASSPATH", "aot/classes")).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"), []byte(`
+Start-Class: test-start-class
+`), 0644)).To(Succeed())
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("BP_NATIVE_IMAGE_ADDITIONAL_CLASSPATH")).To(Succeed())
+		})
+
+		it("appends to the classpath", func() {
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[1].(native.NativeImage).AdditionalClasspath).
+				To(Equal([]string{filepath.Join(ctx.Application.Path, "aot", "classes")}))
+		})
+	})
+
+	context("BP_NATIVE_IMAGE_PRESERVE_APP", func() {
+		it.Before(func() {
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"), []byte(`
+Start-Class: test-start-class
+`), 0644)).To(Succeed())
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("BP_NATIVE_IMAGE_PRESERVE_APP")).To(Succeed())
+		})
+
+		it("preserves patterns", func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_PRESERVE_APP", "static/**,templates/**")).To(Succeed())
+
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[1].(native.NativeImage).Preserve).
+				To(Equal(append([]string{"static/**", "templates/**"}, native.DefaultStaticContent...)))
+		})
+
+		it("preserves static content by default", func() {
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[1].(native.NativeImage).Preserve).To(Equal(native.DefaultStaticContent))
+		})
+
+		it("does not preserve static content when disabled", func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_PRESERVE_STATIC", "false")).To(Succeed())
+			defer os.Unsetenv("BP_NATIVE_IMAGE_PRESERVE_STATIC")
+
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[1].(native.NativeImage).Preserve).To(BeEmpty())
+		})
+
+		it("preserves everything", func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_PRESERVE_APP", "true")).To(Succeed())
+
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[1].(native.NativeImage).Preserve).To(Equal([]string{"**"}))
+		})
+	})
+
+	context("BP_NATIVE_IMAGE_COPY_OUTPUTS", func() {
+		it.Before(func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_COPY_OUTPUTS", "*.so,*.debug")).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"), []byte(`
+Start-Class: test-start-class
+`), 0644)).To(Succeed())
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("BP_NATIVE_IMAGE_COPY_OUTPUTS")).To(Succeed())
+		})
+
+		it("sets the output patterns", func() {
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[1].(native.NativeImage).Outputs).To(Equal([]string{"*.so", "*.debug"}))
+		})
+	})
+
+	context("BP_NATIVE_IMAGE_HYBRID", func() {
+		it.Before(func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_HYBRID", "true")).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"), []byte(`
+Main-Class: test-main-class
+Start-Class: test-start-class
+`), 0644)).To(Succeed())
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("BP_NATIVE_IMAGE_HYBRID")).To(Succeed())
+		})
+
+		it("keeps the application and contributes native and JVM processes", func() {
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[1].(native.NativeImage).Preserve).To(Equal([]string{"**"}))
+			Expect(result.Processes).To(ContainElements(
+				libcnb.Process{Type: "web", Command: filepath.Join(ctx.Application.Path, "test-start-class"), Direct: true, Default: true},
+				libcnb.Process{Type: "web-native", Command: filepath.Join(ctx.Application.Path, "test-start-class"), Direct: true},
+				libcnb.Process{Type: "web-jvm", Command: "java", Arguments: []string{"-cp", ctx.Application.Path, "test-main-class"}, Direct: true},
+			))
+			Expect(result.Labels).To(ContainElement(libcnb.Label{Key: "io.paketo.native-image.processes", Value: "native-image,task,web,web-native,web-jvm"}))
+		})
+	})
+
+	context("BP_NATIVE_IMAGE_START_CLASS", func() {
+		it.Before(func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_START_CLASS", "com.example.App")).To(Succeed())
+			Expect(os.MkdirAll(filepath.Join(ctx.Application.Path, "lib"), 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "lib", "app.jar"), []byte{}, 0644)).To(Succeed())
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("BP_NATIVE_IMAGE_START_CLASS")).To(Succeed())
+			Expect(os.Unsetenv("BP_NATIVE_IMAGE_HYBRID")).To(Succeed())
+		})
+
+		it("builds a directory without a manifest", func() {
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[1].(native.NativeImage).StartClass).To(Equal("com.example.App"))
+			Expect(result.Layers[3].(native.Configuration).Effective.StartClass).To(Equal("com.example.App"))
+			Expect(result.Processes).To(ContainElement(
+				libcnb.Process{Type: "web", Command: filepath.Join(ctx.Application.Path, "com.example.App"), Direct: true, Default: true},
+			))
+		})
+
+		it("passes the start class as the trailing argument in legacy mode", func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_LEGACY_MAIN_CLASS", "true")).To(Succeed())
+			defer os.Unsetenv("BP_NATIVE_IMAGE_LEGACY_MAIN_CLASS")
+
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[1].(native.NativeImage).LegacyMainClass).To(BeTrue())
+		})
+
+		it("runs the directory on the JVM for hybrid images", func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_HYBRID", "true")).To(Succeed())
+
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Processes).To(ContainElement(libcnb.Process{
+				Type:    "web-jvm",
+				Command: "java",
+				Arguments: []string{"-cp", strings.Join([]string{
+					ctx.Application.Path,
+					filepath.Join(ctx.Application.Path, "lib", "app.jar"),
+				}, string(filepath.ListSeparator)), "com.example.App"},
+				Direct: true,
+			}))
+		})
+	})
+
+	context("process types", func() {
+		it.Before(func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_LAUNCH_ARGUMENTS", "--server.port=8081")).To(Succeed())
+			Expect(os.Setenv("BP_NATIVE_IMAGE_PROCESS_TYPES", "worker=--mode=worker;batch")).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"), []byte(`
+Start-Class: test-start-class
+`), 0644)).To(Succeed())
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("BP_NATIVE_IMAGE_LAUNCH_ARGUMENTS")).To(Succeed())
+			Expect(os.Unsetenv("BP_NATIVE_IMAGE_PROCESS_TYPES")).To(Succeed())
+		})
+
+		it("contributes process types with default arguments", func() {
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			command := filepath.Join(ctx.Application.Path, "test-start-class")
+			Expect(result.Processes).To(Equal([]libcnb.Process{
+				{Type: "native-image", Command: command, Arguments: []string{"--server.port=8081"}, Direct: true},
+				{Type: "task", Command: command, Arguments: []string{"--server.port=8081"}, Direct: true},
+				{Type: "web", Command: command, Arguments: []string{"--server.port=8081"}, Direct: true, Default: true},
+				{Type: "worker", Command: command, Arguments: []string{"--server.port=8081", "--mode=worker"}, Direct: true},
+				{Type: "batch", Command: command, Arguments: []string{"--server.port=8081"}, Direct: true},
+			}))
+		})
+
+		it("fails on conflicting process types", func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_PROCESS_TYPES", "task")).To(Succeed())
+
+			_, err := build.Build(ctx)
+			Expect(err).To(MatchError("process type task conflicts with a contributed process type"))
+		})
+	})
+
+	context("Procfile", func() {
+		it.Before(func() {
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"), []byte(`
+Start-Class: test-start-class
+`), 0644)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "Procfile"), []byte(`
+web: java -jar app.jar --server.port=8081
+worker: java -cp app.jar com.example.Worker
+`), 0644)).To(Succeed())
+		})
+
+		it("contributes Procfile processes targeting the binary", func() {
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			command := filepath.Join(ctx.Application.Path, "test-start-class")
+			Expect(result.Processes).To(Equal([]libcnb.Process{
+				{Type: "native-image", Command: command, Direct: true},
+				{Type: "task", Command: command, Direct: true},
+				{Type: "web", Command: command, Arguments: []string{"--server.port=8081"}, Direct: true, Default: true},
+				{Type: "worker", Command: command, Direct: true},
+			}))
+		})
+	})
+
+	context("BP_NATIVE_IMAGE_RUNTIME_OPTIONS", func() {
+		it.Before(func() {
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"), []byte(`
+Start-Class: test-start-class
+`), 0644)).To(Succeed())
+			Expect(os.Setenv("BP_NATIVE_IMAGE_RUNTIME_OPTIONS", "true")).To(Succeed())
+			Expect(os.Setenv("BP_NATIVE_IMAGE_LAUNCH_ARGUMENTS", "--server.port=8081")).To(Succeed())
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("BP_NATIVE_IMAGE_RUNTIME_OPTIONS")).To(Succeed())
+			Expect(os.Unsetenv("BP_NATIVE_IMAGE_LAUNCH_ARGUMENTS")).To(Succeed())
+		})
+
+		it("contributes the helper and passes the runtime options", func() {
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[3].Name()).To(Equal("helper"))
+			Expect(result.Layers[3].(libpak.HelperLayerContributor).Names).To(Equal([]string{"runtime-options"}))
+
+			command := filepath.Join(ctx.Application.Path, "test-start-class")
+			Expect(result.Processes).To(Equal([]libcnb.Process{
+				{Type: "native-image", Command: command + " $NATIVE_IMAGE_RUNTIME_OPTIONS --server.port=8081"},
+				{Type: "task", Command: command + " $NATIVE_IMAGE_RUNTIME_OPTIONS --server.port=8081"},
+				{Type: "web", Command: command + " $NATIVE_IMAGE_RUNTIME_OPTIONS --server.port=8081", Default: true},
+			}))
+		})
+	})
+
+	context("BP_NATIVE_IMAGE_MAX_HEAP_SIZE", func() {
+		it.Before(func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_MAX_HEAP_SIZE", "512m")).To(Succeed())
+			Expect(os.Setenv("BP_NATIVE_IMAGE_MAX_HEAP_PERCENT", "75%")).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"), []byte(`
+Start-Class: test-start-class
+`), 0644)).To(Succeed())
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("BP_NATIVE_IMAGE_MAX_HEAP_SIZE")).To(Succeed())
+			Expect(os.Unsetenv("BP_NATIVE_IMAGE_MAX_HEAP_PERCENT")).To(Succeed())
+		})
+
+		it("sets the default heap settings", func() {
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[1].(native.NativeImage).MaxHeapSize).To(Equal("512m"))
+			Expect(result.Layers[1].(native.NativeImage).MaxHeapPercent).To(Equal(75))
+		})
+
+		it("fails on an invalid heap size", func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_MAX_HEAP_SIZE", "half")).To(Succeed())
+
+			_, err := build.Build(ctx)
+			Expect(err).To(MatchError(`unable to parse $BP_NATIVE_IMAGE_MAX_HEAP_SIZE value "half" as a memory size`))
+		})
+
+		it("generates serialization and proxy configuration", func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_SERIALIZATION_CLASSES", "com.example.Order")).To(Succeed())
+			Expect(os.Setenv("BP_NATIVE_IMAGE_DYNAMIC_PROXIES", "com.example.A,com.example.B")).To(Succeed())
+			defer os.Unsetenv("BP_NATIVE_IMAGE_SERIALIZATION_CLASSES")
+			defer os.Unsetenv("BP_NATIVE_IMAGE_DYNAMIC_PROXIES")
+
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[1].(native.NativeImage).Generated).To(Equal(native.GeneratedConfiguration{
+				SerializationClasses: []string{"com.example.Order"},
+				DynamicProxies:       [][]string{{"com.example.A", "com.example.B"}},
+			}))
+		})
+
+		it("sets the heap layout", func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_COMPRESSED_REFERENCES", "false")).To(Succeed())
+			Expect(os.Setenv("BP_NATIVE_IMAGE_ALIGNED_HEAP_CHUNK_SIZE", "1m")).To(Succeed())
+			defer func() {
+				Expect(os.Unsetenv("BP_NATIVE_IMAGE_COMPRESSED_REFERENCES")).To(Succeed())
+				Expect(os.Unsetenv("BP_NATIVE_IMAGE_ALIGNED_HEAP_CHUNK_SIZE")).To(Succeed())
+			}()
+
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[1].(native.NativeImage).CompressedReferences).To(Equal("false"))
+			Expect(result.Layers[1].(native.NativeImage).AlignedHeapChunkSize).To(Equal("1m"))
+		})
+	})
+
+	context("BP_NATIVE_IMAGE_SYSTEM_PROPERTIES", func() {
+		it.Before(func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_SYSTEM_PROPERTIES", "spring.profiles.active=prod,cloud spring.native.remove-yaml-support=true")).
+				To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"), []byte(`
+Start-Class: test-start-class
+`), 0644)).To(Succeed())
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("BP_NATIVE_IMAGE_SYSTEM_PROPERTIES")).To(Succeed())
+		})
+
+		it("sets the system properties", func() {
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[1].(native.NativeImage).SystemProperties).
+				To(Equal([]string{"spring.profiles.active=prod,cloud", "spring.native.remove-yaml-support=true"}))
+		})
+
+		it("fails on an invalid system property", func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_SYSTEM_PROPERTIES", "=true")).To(Succeed())
+
+			_, err := build.Build(ctx)
+			Expect(err).To(MatchError(ContainSubstring(`invalid system property "=true", expected key=value`)))
+		})
+	})
+
+	context("BP_NATIVE_IMAGE_MARCH", func() {
+		it.Before(func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_MARCH", "native")).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"), []byte(`
+Start-Class: test-start-class
+`), 0644)).To(Succeed())
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("BP_NATIVE_IMAGE_MARCH")).To(Succeed())
+		})
+
+		it("sets the micro-architecture and warns about portability", func() {
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[1].(native.NativeImage).March).To(Equal("native"))
+			Expect(result.Layers[1].(native.NativeImage).Architecture).To(Equal(runtime.GOARCH))
+			Expect(out.String()).To(ContainSubstring("may crash with SIGILL"))
+		})
+	})
+
+	context("BP_NATIVE_IMAGE_COMMAND", func() {
+		it.Before(func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_COMMAND", "/opt/mandrel/bin/native-image")).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"), []byte(`
+Start-Class: test-start-class
+`), 0644)).To(Succeed())
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("BP_NATIVE_IMAGE_COMMAND")).To(Succeed())
+		})
+
+		it("sets the command", func() {
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[1].(native.NativeImage).Command).To(Equal("/opt/mandrel/bin/native-image"))
+		})
+	})
+
+	context("build context", func() {
+		it("passes the context to the native image", func() {
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"), []byte(`
+Start-Class: test-start-class
+`), 0644)).To(Succeed())
+
+			c, cancel := gocontext.WithCancel(gocontext.Background())
+			defer cancel()
+			build.Context = c
+
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[1].(native.NativeImage).Context).To(Equal(c))
+		})
+	})
+
+	context("BP_NATIVE_IMAGE_BUILD_ENV", func() {
+		it.Before(func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_BUILD_ENV", "MAVEN_OPTS,FOO=bar")).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"), []byte(`
+Start-Class: test-start-class
+`), 0644)).To(Succeed())
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("BP_NATIVE_IMAGE_BUILD_ENV")).To(Succeed())
+		})
+
+		it("adds to the default environment", func() {
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			env := result.Layers[1].(native.NativeImage).Environment
+			Expect(env).To(ContainElements("PATH", "JAVA_HOME", "MAVEN_OPTS", "FOO=bar"))
+			Expect(native.Environment(env, []string{"MAVEN_OPTS=-Xmx1g"})).To(ContainElements("FOO=bar", "MAVEN_OPTS=-Xmx1g"))
+		})
+	})
+
+	context("warnings", func() {
+		it.Before(func() {
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"), []byte(`
+Start-Class: test-start-class
+`), 0644)).To(Succeed())
+			ctx.Buildpack.Metadata = map[string]interface{}{
+				"configurations": []map[string]interface{}{
+					{"name": "BP_NATIVE_IMAGE_BUILD_ARGUMENTS"},
+				},
+			}
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("BP_NATIVE_IMAGE_BUILD_ARGUMENTS")).To(Succeed())
+			Expect(os.Unsetenv("BP_NATIVE_IMAGE_BUILD_ARGUMENT")).To(Succeed())
+		})
+
+		it("collects unknown configurations", func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_BUILD_ARGUMENTS", "")).To(Succeed())
+			Expect(os.Setenv("BP_NATIVE_IMAGE_BUILD_ARGUMENT", "--verbose")).To(Succeed())
+
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[1].(native.NativeImage).Warnings.Messages).To(Equal([]string{
+				"$BP_NATIVE_IMAGE_BUILD_ARGUMENT is not a configuration of this buildpack and is ignored",
+			}))
+			Expect(out.String()).To(ContainSubstring("$BP_NATIVE_IMAGE_BUILD_ARGUMENT is not a configuration"))
+		})
+	})
+
+	context("BP_NATIVE_IMAGE_ALLOW_FALLBACK", func() {
+		it.Before(func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_ALLOW_FALLBACK", "true")).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"), []byte(`
+Start-Class: test-start-class
+`), 0644)).To(Succeed())
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("BP_NATIVE_IMAGE_ALLOW_FALLBACK")).To(Succeed())
+		})
+
+		it("accepts fallback images", func() {
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[1].(native.NativeImage).AllowFallback).To(BeTrue())
+		})
+	})
+
+	context("BP_NATIVE_IMAGE_INVOKER", func() {
+		it.Before(func() {
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"), []byte(`
+Start-Class: test-start-class
+`), 0644)).To(Succeed())
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("BP_NATIVE_IMAGE_INVOKER")).To(Succeed())
+		})
+
+		it("selects the argument file invoker", func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_INVOKER", "argfile")).To(Succeed())
+
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[1].(native.NativeImage).Invoker).To(Equal(native.ArgfileInvoker{Command: native.DefaultCommand}))
+		})
+
+		it("selects the bundle invoker", func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_INVOKER", "bundle")).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "demo.nib"), []byte{}, 0644)).To(Succeed())
+
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[1].(native.NativeImage).Invoker).To(Equal(native.BundleInvoker{
+				Command: native.DefaultCommand,
+				Bundle:  filepath.Join(ctx.Application.Path, "demo.nib"),
+			}))
+		})
+
+		it("fails without a bundle", func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_INVOKER", "bundle")).To(Succeed())
+
+			_, err := build.Build(ctx)
+			Expect(err).To(MatchError("$BP_NATIVE_IMAGE_INVOKER is bundle but the application has no *.nib bundle"))
+		})
+
+		it("fails with an unknown invoker", func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_INVOKER", "docker")).To(Succeed())
+
+			_, err := build.Build(ctx)
+			Expect(err).To(MatchError(ContainSubstring(`unknown $BP_NATIVE_IMAGE_INVOKER "docker"`)))
+		})
+	})
+
+	context("configuration overrides", func() {
+		it("merges configuration from bindings", func() {
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"), []byte(`
+Start-Class: test-start-class
+`), 0644)).To(Succeed())
+			ctx.Platform.Bindings = libcnb.Bindings{
+				{Name: "overrides", Type: "native-image-configuration", Path: "/bindings/overrides"},
+			}
+			defer func() { ctx.Platform.Bindings = nil }()
+
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Layers[1].(native.NativeImage).ConfigurationDirectories).To(Equal([]string{"/bindings/overrides"}))
+			Expect(out.String()).To(ContainSubstring("Merging native-image configuration from /bindings/overrides"))
+		})
+	})
+
 	context("BP_NATIVE_IMAGE_BUILT_ARTIFACT", func() {
 		it.Before(func() {
 			Expect(os.Setenv("BP_NATIVE_IMAGE_BUILT_ARTIFACT", "target/*.jar")).To(Succeed())
@@ -206,7 +1598,7 @@ Start-Class: test-start-class
 			result, err := build.Build(ctx)
 			Expect(err).NotTo(HaveOccurred())
 
-			Expect(result.Layers[0].(native.NativeImage).JarFilePattern).To(Equal("target/*.jar"))
+			Expect(result.Layers[1].(native.NativeImage).JarFilePattern).To(Equal("target/*.jar"))
 			Expect(result.Processes).To(ContainElements(
 				libcnb.Process{Type: "native-image", Command: filepath.Join(ctx.Application.Path, "test-fixture"), Direct: true},
 				libcnb.Process{Type: "task", Command: filepath.Join(ctx.Application.Path, "test-fixture"), Direct: true},
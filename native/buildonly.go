@@ -0,0 +1,32 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+// buildOnlyCoordinates are the coordinates of artifacts that only package or inspect the application, such as the jar
+// modes of layered Spring Boot JARs. They are never run by the application and so never compiled into the native image.
+var buildOnlyCoordinates = []string{
+	"org.springframework.boot:spring-boot-jarmode-layertools",
+	"org.springframework.boot:spring-boot-jarmode-tools",
+}
+
+// FindBuildOnly returns the build-only artifacts in the dependencies of the classpath, the built-in ones and those with
+// one of the additional groupId:artifactId or artifactId coordinates. Artifacts are also matched on their file name,
+// as Spring Boot's own JARs do not carry a pom.properties.
+func FindBuildOnly(dependencies Dependencies, additional ...string) []Artifact {
+	coordinates := append(append([]string{}, buildOnlyCoordinates...), additional...)
+	return dependencies.WithNames().FindAll(coordinates...)
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testBuildpack(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		variable = regexp.MustCompile(`"(BPL?_[A-Z0-9_]+)"`)
+	)
+
+	it("declares every configuration in buildpack.toml", func() {
+		b, err := ioutil.ReadFile(filepath.Join("..", "buildpack.toml"))
+		Expect(err).NotTo(HaveOccurred())
+
+		declared := map[string]bool{}
+		for _, m := range regexp.MustCompile(`name\s*=\s*"(BPL?_[A-Z0-9_]+)"`).FindAllStringSubmatch(string(b), -1) {
+			declared[m[1]] = true
+		}
+
+		files, err := filepath.Glob("*.go")
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, f := range files {
+			if strings.HasSuffix(f, "_test.go") {
+				continue
+			}
+
+			b, err := ioutil.ReadFile(f)
+			Expect(err).NotTo(HaveOccurred())
+
+			for _, m := range variable.FindAllStringSubmatch(string(b), -1) {
+				// set by the buildpack rather than configured
+				if m[1] == native.EffectiveConfigurationEnv {
+					continue
+				}
+
+				Expect(declared).To(HaveKey(m[1]), "%s in %s", m[1], f)
+			}
+		}
+	})
+}
@@ -0,0 +1,125 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/paketo-buildpacks/libpak/effect"
+)
+
+const (
+	BuildToolMaven  = "maven"
+	BuildToolGradle = "gradle"
+)
+
+// BuildTool is a Maven or Gradle wrapper in the application that compiles the native image with Native Build Tools
+type BuildTool struct {
+	Name string
+
+	// Wrapper is the path of the wrapper script
+	Wrapper string
+
+	// Args are the arguments of the wrapper compiling the native image
+	Args []string
+
+	// OutputDirectory is the directory, relative to the application, Native Build Tools writes the native image to
+	OutputDirectory string
+}
+
+// DetectBuildTool returns the Maven or Gradle wrapper in the application, preferring Maven when both are present.
+// Returns false if the application has no wrapper.
+func DetectBuildTool(applicationPath string) (BuildTool, bool, error) {
+	candidates := []BuildTool{
+		{
+			Name:            BuildToolMaven,
+			Wrapper:         filepath.Join(applicationPath, "mvnw"),
+			Args:            []string{"-Pnative", "native:compile"},
+			OutputDirectory: "target",
+		},
+		{
+			Name:            BuildToolGradle,
+			Wrapper:         filepath.Join(applicationPath, "gradlew"),
+			Args:            []string{"nativeCompile"},
+			OutputDirectory: filepath.Join("build", "native", "nativeCompile"),
+		},
+	}
+
+	for _, c := range candidates {
+		if fi, err := os.Stat(c.Wrapper); err == nil && !fi.IsDir() {
+			return c, true, nil
+		} else if err != nil && !os.IsNotExist(err) {
+			return BuildTool{}, false, fmt.Errorf("unable to stat %s\n%w", c.Wrapper, err)
+		}
+	}
+
+	return BuildTool{}, false, nil
+}
+
+// Execution returns the execution of the wrapper in the application
+func (b BuildTool) Execution(applicationPath string, env []string, stdout io.Writer, stderr io.Writer) effect.Execution {
+	return effect.Execution{
+		Command: b.Wrapper,
+		Args:    b.Args,
+		Dir:     applicationPath,
+		Env:     env,
+		Stdout:  stdout,
+		Stderr:  stderr,
+	}
+}
+
+// Harvest returns the native image written by Native Build Tools, the only executable file in the output directory
+// that is neither a JAR nor a shared library
+func (b BuildTool) Harvest(applicationPath string) (string, error) {
+	return harvest(filepath.Join(applicationPath, b.OutputDirectory), filepath.Base(b.Wrapper))
+}
+
+// harvest returns the only executable file in dir that is neither a JAR nor a shared library, written by source
+func harvest(dir string, source string) (string, error) {
+	children, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return "", fmt.Errorf("unable to list children of %s\n%w", dir, err)
+	}
+
+	var candidates []string
+	for _, c := range children {
+		if !c.Mode().IsRegular() || c.Mode().Perm()&0111 == 0 {
+			continue
+		}
+		switch strings.ToLower(filepath.Ext(c.Name())) {
+		case ".jar", ".so", ".dylib", ".dll":
+			continue
+		}
+		candidates = append(candidates, c.Name())
+	}
+	sort.Strings(candidates)
+
+	switch len(candidates) {
+	case 0:
+		return "", fmt.Errorf("no native image found in %s after running %s", dir, source)
+	case 1:
+		return filepath.Join(dir, candidates[0]), nil
+	default:
+		return "", fmt.Errorf("found more than one executable in %s: %s", dir, strings.Join(candidates, ", "))
+	}
+}
@@ -0,0 +1,113 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testBuildTools(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		path string
+	)
+
+	it.Before(func() {
+		var err error
+
+		path, err = ioutil.TempDir("", "build-tools")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(path)).To(Succeed())
+	})
+
+	context("DetectBuildTool", func() {
+		it("detects the Maven wrapper", func() {
+			Expect(ioutil.WriteFile(filepath.Join(path, "mvnw"), []byte{}, 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(path, "gradlew"), []byte{}, 0755)).To(Succeed())
+
+			tool, ok, err := native.DetectBuildTool(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+			Expect(tool).To(Equal(native.BuildTool{
+				Name:            native.BuildToolMaven,
+				Wrapper:         filepath.Join(path, "mvnw"),
+				Args:            []string{"-Pnative", "native:compile"},
+				OutputDirectory: "target",
+			}))
+		})
+
+		it("detects the Gradle wrapper", func() {
+			Expect(ioutil.WriteFile(filepath.Join(path, "gradlew"), []byte{}, 0755)).To(Succeed())
+
+			tool, ok, err := native.DetectBuildTool(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+			Expect(tool.Name).To(Equal(native.BuildToolGradle))
+			Expect(tool.Args).To(Equal([]string{"nativeCompile"}))
+			Expect(tool.OutputDirectory).To(Equal(filepath.Join("build", "native", "nativeCompile")))
+		})
+
+		it("detects nothing without a wrapper", func() {
+			_, ok, err := native.DetectBuildTool(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	context("Harvest", func() {
+		var tool native.BuildTool
+
+		it.Before(func() {
+			tool = native.BuildTool{Name: native.BuildToolMaven, Wrapper: filepath.Join(path, "mvnw"), OutputDirectory: "target"}
+			Expect(os.MkdirAll(filepath.Join(path, "target", "classes"), 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(path, "target", "demo.jar"), []byte{}, 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(path, "target", "libawt.so"), []byte{}, 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(path, "target", "demo.txt"), []byte{}, 0644)).To(Succeed())
+		})
+
+		it("returns the only native image", func() {
+			Expect(ioutil.WriteFile(filepath.Join(path, "target", "demo"), []byte{}, 0755)).To(Succeed())
+
+			Expect(tool.Harvest(path)).To(Equal(filepath.Join(path, "target", "demo")))
+		})
+
+		it("fails without a native image", func() {
+			_, err := tool.Harvest(path)
+			Expect(err).To(MatchError(ContainSubstring("no native image found")))
+		})
+
+		it("fails with more than one executable", func() {
+			Expect(ioutil.WriteFile(filepath.Join(path, "target", "demo"), []byte{}, 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(path, "target", "other"), []byte{}, 0755)).To(Succeed())
+
+			_, err := tool.Harvest(path)
+			Expect(err).To(MatchError(ContainSubstring("demo, other")))
+		})
+	})
+}
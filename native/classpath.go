@@ -0,0 +1,176 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/buildpacks/libcnb"
+	"github.com/magiconair/properties"
+	"github.com/paketo-buildpacks/libpak/bindings"
+)
+
+// ClasspathBindingType is the type of bindings holding JARs and directories to append to the native image classpath
+const ClasspathBindingType = "native-image-classpath"
+
+// artifactPattern splits a Maven style file name into artifact id, version and classifier. The version may carry
+// milestone, release candidate, SNAPSHOT or timestamped SNAPSHOT qualifiers, anything following it is the classifier.
+var artifactPattern = regexp.MustCompile(
+	`^(?P<artifact>.+?)-(?P<version>\d[\w.]*(?:-(?:BUILD-SNAPSHOT|SNAPSHOT|M\d+|RC\d+|\d{8}\.\d{6}-\d+))*)(?:-(?P<classifier>.+))?$`)
+
+// timestampPattern matches the timestamp and build number of a unique SNAPSHOT version
+var timestampPattern = regexp.MustCompile(`-\d{8}\.\d{6}-\d+$`)
+
+// Artifact describes the coordinates of a JAR on the classpath
+type Artifact struct {
+	GroupID    string
+	ArtifactID string
+	Version    string
+	Classifier string
+	Path       string
+}
+
+// BaseVersion returns the version with a timestamped SNAPSHOT qualifier normalized to SNAPSHOT
+func (a Artifact) BaseVersion() string {
+	return timestampPattern.ReplaceAllString(a.Version, "-SNAPSHOT")
+}
+
+// ParseArtifact parses the coordinates of a JAR from its file name, e.g. spring-native-0.11.0-SNAPSHOT-exec.jar
+func ParseArtifact(path string) (Artifact, bool) {
+	name := strings.TrimSuffix(filepath.Base(path), ".jar")
+
+	m := artifactPattern.FindStringSubmatch(name)
+	if m == nil {
+		return Artifact{}, false
+	}
+
+	return Artifact{
+		ArtifactID: m[artifactPattern.SubexpIndex("artifact")],
+		Version:    m[artifactPattern.SubexpIndex("version")],
+		Classifier: m[artifactPattern.SubexpIndex("classifier")],
+		Path:       path,
+	}, true
+}
+
+// ReadClasspathIndex returns the entries listed in the Spring Boot classpath index, relative to the application path.
+// Returns nil if the application does not have a classpath index.
+func ReadClasspathIndex(appPath string, manifest *properties.Properties) ([]string, error) {
+	index, ok := manifest.Get("Spring-Boot-Classpath-Index")
+	if !ok {
+		return nil, nil
+	}
+
+	lib, ok := manifest.Get("Spring-Boot-Lib")
+	if !ok {
+		lib = "BOOT-INF/lib"
+	}
+
+	file := filepath.Join(appPath, index)
+	in, err := os.Open(file)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, ClasspathIndexError{File: file, Err: err}
+	}
+	defer in.Close()
+
+	parsed, err := ParseClasspathIndex(in)
+	if err != nil {
+		return nil, ClasspathIndexError{File: file, Err: err}
+	}
+
+	return parsed.Paths(lib), nil
+}
+
+// springNativeCoordinates are the coordinates Spring Native has been published under
+var springNativeCoordinates = []string{
+	"org.springframework.experimental:spring-native",
+	"org.springframework.experimental:spring-graalvm-native",
+}
+
+// FindSpringNative returns the Spring Native artifact from the dependencies of the classpath. Artifacts are matched on their
+// coordinates rather than a file name prefix so that classifiers, SNAPSHOT timestamps and renamed JARs are handled.
+func FindSpringNative(dependencies Dependencies) (Artifact, bool) {
+	return dependencies.Find(springNativeCoordinates...)
+}
+
+// MatchClasspathEntries returns the classpath entries matching any of the glob patterns. A pattern is matched against
+// the entry relative to the application, e.g. BOOT-INF/lib/jacoco*.jar, and against its file name, e.g. jacoco*.jar.
+func MatchClasspathEntries(entries []string, patterns []string) ([]string, error) {
+	var matched []string
+
+	for _, entry := range entries {
+		for _, pattern := range patterns {
+			ok, err := filepath.Match(pattern, entry)
+			if err != nil {
+				return nil, fmt.Errorf("unable to match %s\n%w", pattern, err)
+			}
+			if !ok {
+				ok, _ = filepath.Match(pattern, filepath.Base(entry))
+			}
+
+			if ok {
+				matched = append(matched, entry)
+				break
+			}
+		}
+	}
+
+	return matched, nil
+}
+
+// FindAdditionalClasspath returns the entries to append to the native image classpath, from a classpath style list of
+// paths, relative to the application or absolute, followed by the JARs and directories in bindings
+func FindAdditionalClasspath(appPath string, value string, binds libcnb.Bindings) ([]string, error) {
+	var entries []string
+
+	for _, e := range filepath.SplitList(value) {
+		if e = strings.TrimSpace(e); e == "" {
+			continue
+		}
+
+		if !filepath.IsAbs(e) {
+			e = filepath.Join(appPath, e)
+		}
+		entries = append(entries, e)
+	}
+
+	for _, b := range bindings.Resolve(binds, bindings.OfType(ClasspathBindingType)) {
+		children, err := ioutil.ReadDir(b.Path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to list children of %s\n%w", b.Path, err)
+		}
+
+		for _, c := range children {
+			// Kubernetes mounts bindings through hidden ..data directories
+			if strings.HasPrefix(c.Name(), ".") {
+				continue
+			}
+
+			if c.IsDir() || strings.HasSuffix(c.Name(), ".jar") {
+				entries = append(entries, filepath.Join(b.Path, c.Name()))
+			}
+		}
+	}
+
+	return entries, nil
+}
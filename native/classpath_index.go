@@ -0,0 +1,100 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// ClasspathIndexV1 is the classpath index of Spring Boot 2.3, listing the file names of the libraries
+	ClasspathIndexV1 = "v1"
+
+	// ClasspathIndexV2 is the classpath index of later Spring Boot versions, listing paths relative to the application
+	ClasspathIndexV2 = "v2"
+)
+
+// ClasspathIndex is a Spring Boot classpath index
+type ClasspathIndex struct {
+	// Format is the detected format of the index, ClasspathIndexV2 if any entry is a path
+	Format string
+
+	// Entries are the entries of the index as listed
+	Entries []string
+}
+
+// ParseClasspathIndex parses a Spring Boot classpath index, a YAML sequence of file names or relative paths. Quoted
+// and unquoted entries, CRLF line endings, a byte order mark and comments are accepted, and blank entries ignored.
+func ParseClasspathIndex(in io.Reader) (ClasspathIndex, error) {
+	b, err := io.ReadAll(in)
+	if err != nil {
+		return ClasspathIndex{}, err
+	}
+	b = bytes.TrimPrefix(b, []byte("\xef\xbb\xbf"))
+
+	var raw []string
+	if err := yaml.Unmarshal(b, &raw); err != nil {
+		return ClasspathIndex{}, fmt.Errorf("classpath index is not a sequence of entries\n%w", err)
+	}
+
+	index := ClasspathIndex{Format: ClasspathIndexV1}
+	for _, e := range raw {
+		if e = strings.TrimSpace(e); e == "" {
+			continue
+		}
+		if strings.Contains(e, "/") {
+			index.Format = ClasspathIndexV2
+		}
+		index.Entries = append(index.Entries, e)
+	}
+
+	return index, nil
+}
+
+// Paths returns the entries of the index relative to the application. File names, listed by Spring Boot 2.3, are
+// resolved against lib.
+func (c ClasspathIndex) Paths(lib string) []string {
+	var paths []string
+	for _, e := range c.Entries {
+		if !strings.Contains(e, "/") {
+			e = filepath.ToSlash(filepath.Join(lib, e))
+		}
+		paths = append(paths, e)
+	}
+	return paths
+}
+
+// Missing returns the entries of the index, relative to the application, that do not exist in the application. A tool
+// can verify an index with it before building.
+func (c ClasspathIndex) Missing(appPath string, lib string) ([]string, error) {
+	var missing []string
+	for _, p := range c.Paths(lib) {
+		if _, err := os.Stat(filepath.Join(appPath, filepath.FromSlash(p))); os.IsNotExist(err) {
+			missing = append(missing, p)
+		} else if err != nil {
+			return nil, fmt.Errorf("unable to stat %s\n%w", p, err)
+		}
+	}
+	return missing, nil
+}
@@ -0,0 +1,81 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testClasspathIndex(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+	)
+
+	it("parses file names of Spring Boot 2.3", func() {
+		index, err := native.ParseClasspathIndex(strings.NewReader("- \"spring-core-5.2.8.RELEASE.jar\"\n- \"spring-jcl-5.2.8.RELEASE.jar\"\n"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(index.Format).To(Equal(native.ClasspathIndexV1))
+		Expect(index.Paths("BOOT-INF/lib")).
+			To(Equal([]string{"BOOT-INF/lib/spring-core-5.2.8.RELEASE.jar", "BOOT-INF/lib/spring-jcl-5.2.8.RELEASE.jar"}))
+	})
+
+	it("parses relative paths", func() {
+		index, err := native.ParseClasspathIndex(strings.NewReader("- \"BOOT-INF/lib/spring-core-6.0.4.jar\"\n"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(index.Format).To(Equal(native.ClasspathIndexV2))
+		Expect(index.Paths("BOOT-INF/lib")).To(Equal([]string{"BOOT-INF/lib/spring-core-6.0.4.jar"}))
+	})
+
+	it("tolerates CRLF, a byte order mark, comments, unquoted and blank entries", func() {
+		index, err := native.ParseClasspathIndex(strings.NewReader(
+			"\xef\xbb\xbf# generated\r\n- \"BOOT-INF/lib/a-1.0.jar\"\r\n-   BOOT-INF/lib/b-1.0.jar # unquoted\r\n- ''\r\n- 'BOOT-INF/lib/c-1.0.jar'\r\n"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(index.Entries).To(Equal([]string{"BOOT-INF/lib/a-1.0.jar", "BOOT-INF/lib/b-1.0.jar", "BOOT-INF/lib/c-1.0.jar"}))
+	})
+
+	it("parses an empty index", func() {
+		index, err := native.ParseClasspathIndex(strings.NewReader(""))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(index.Entries).To(BeEmpty())
+	})
+
+	it("fails for an index that is not a sequence", func() {
+		_, err := native.ParseClasspathIndex(strings.NewReader("lib: [a.jar\n"))
+		Expect(err).To(MatchError(ContainSubstring("classpath index is not a sequence of entries")))
+	})
+
+	it("finds missing entries", func() {
+		appPath, err := ioutil.TempDir("", "classpath-index")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(appPath)
+
+		Expect(os.MkdirAll(filepath.Join(appPath, "BOOT-INF", "lib"), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(appPath, "BOOT-INF", "lib", "a-1.0.jar"), []byte{}, 0644)).To(Succeed())
+
+		index := native.ClasspathIndex{Entries: []string{"a-1.0.jar", "BOOT-INF/lib/b-1.0.jar"}}
+		Expect(index.Missing(appPath, "BOOT-INF/lib")).To(Equal([]string{"BOOT-INF/lib/b-1.0.jar"}))
+	})
+}
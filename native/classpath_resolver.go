@@ -0,0 +1,263 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/magiconair/properties"
+	"github.com/paketo-buildpacks/libpak/sherpa"
+)
+
+// ClasspathEntry is a JAR or directory on the classpath of an application
+type ClasspathEntry struct {
+	// Path is the absolute path of the entry
+	Path string
+
+	// Digest is the hex encoded SHA-256 of a JAR, or of the file listing of a directory. It is empty unless digests
+	// were requested.
+	Digest string
+}
+
+// ClasspathResolver resolves the ordered classpath of an application
+type ClasspathResolver interface {
+	Resolve(applicationPath string, manifest *properties.Properties) ([]ClasspathEntry, error)
+}
+
+// ManifestClasspathResolver resolves the classpath of an exploded JAR from its manifest. The application directory
+// comes first, followed by the Spring Boot classes directory, the Spring Boot libraries in the order of the classpath
+// index, the layers index or their file names, and finally the Class-Path entries of the manifest.
+type ManifestClasspathResolver struct {
+	// Digests requests the digest of every entry
+	Digests bool
+}
+
+// Resolve returns the classpath entries of the application in applicationPath
+func (m ManifestClasspathResolver) Resolve(applicationPath string, manifest *properties.Properties) ([]ClasspathEntry, error) {
+	paths := []string{applicationPath}
+
+	if classes, ok := manifest.Get("Spring-Boot-Classes"); ok {
+		paths = append(paths, filepath.Join(applicationPath, classes))
+	}
+
+	libraries, err := springBootLibraries(applicationPath, manifest)
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range libraries {
+		paths = append(paths, filepath.Join(applicationPath, l))
+	}
+
+	if cp, ok := manifest.Get("Class-Path"); ok {
+		paths = append(paths, ManifestClasspath(cp, applicationPath)...)
+	}
+
+	var entries []ClasspathEntry
+	for _, p := range paths {
+		if containsEntry(entries, p) {
+			continue
+		}
+
+		e := ClasspathEntry{Path: p}
+		if m.Digests {
+			if e.Digest, err = digest(p); err != nil {
+				return nil, err
+			}
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, nil
+}
+
+// ManifestClasspath resolves the Class-Path attribute of a manifest against base, the directory containing the JAR or
+// the exploded JAR directory. Entries are space separated URLs: relative, percent-encoded paths, or file: URLs and
+// absolute paths. Entries with other schemes cannot be on the classpath of a native image and are skipped.
+func ManifestClasspath(value string, base string) []string {
+	var paths []string
+
+	for _, e := range strings.Fields(value) {
+		u, err := url.Parse(e)
+		if err != nil {
+			paths = append(paths, filepath.Join(base, filepath.FromSlash(e)))
+			continue
+		}
+
+		switch {
+		case u.Scheme == "file":
+			paths = append(paths, filepath.Clean(filepath.FromSlash(u.Path)))
+		case u.Scheme != "" && len(u.Scheme) > 1:
+			continue
+		case filepath.IsAbs(e):
+			paths = append(paths, filepath.Clean(e))
+		case path.IsAbs(u.Path):
+			paths = append(paths, filepath.Clean(filepath.FromSlash(u.Path)))
+		default:
+			paths = append(paths, filepath.Join(base, filepath.FromSlash(u.Path)))
+		}
+	}
+
+	return paths
+}
+
+// springBootLibraries returns the libraries of a Spring Boot application relative to the application, from the
+// classpath index, the layers index or the file names in Spring-Boot-Lib, in that order of preference
+func springBootLibraries(applicationPath string, manifest *properties.Properties) ([]string, error) {
+	lib, ok := manifest.Get("Spring-Boot-Lib")
+	if !ok {
+		return nil, nil
+	}
+
+	if entries, err := ReadClasspathIndex(applicationPath, manifest); err != nil {
+		return nil, err
+	} else if entries != nil {
+		return entries, nil
+	}
+
+	if entries, err := ReadLayersIndex(applicationPath, manifest); err != nil {
+		return nil, err
+	} else if entries != nil {
+		var libraries []string
+		for _, e := range entries {
+			if strings.HasPrefix(e, strings.TrimSuffix(lib, "/")+"/") && strings.HasSuffix(e, ".jar") {
+				libraries = append(libraries, e)
+			}
+		}
+		if libraries != nil {
+			return libraries, nil
+		}
+	}
+
+	jars, err := filepath.Glob(filepath.Join(applicationPath, lib, "*.jar"))
+	if err != nil {
+		return nil, fmt.Errorf("unable to list %s\n%w", lib, err)
+	}
+	sort.Strings(jars)
+
+	var libraries []string
+	for _, j := range jars {
+		r, err := filepath.Rel(applicationPath, j)
+		if err != nil {
+			return nil, fmt.Errorf("unable to relativize %s\n%w", j, err)
+		}
+		libraries = append(libraries, filepath.ToSlash(r))
+	}
+
+	return libraries, nil
+}
+
+// ReadLayersIndex returns the files and directories listed in the Spring Boot layers index, relative to the
+// application path and in the order of the index. Returns nil if the application does not have a layers index.
+func ReadLayersIndex(appPath string, manifest *properties.Properties) ([]string, error) {
+	index, ok := manifest.Get("Spring-Boot-Layers-Index")
+	if !ok {
+		return nil, nil
+	}
+
+	file := filepath.Join(appPath, index)
+	in, err := os.Open(file)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("unable to open %s\n%w", file, err)
+	}
+	defer in.Close()
+
+	var entries []string
+	s := bufio.NewScanner(in)
+	for s.Scan() {
+		// layers are listed as - "name": and their contents, indented, as - "path"
+		line := s.Text()
+		if !strings.HasPrefix(line, " ") {
+			continue
+		}
+
+		entry := strings.TrimSpace(line)
+		if !strings.HasPrefix(entry, "-") {
+			continue
+		}
+		entries = append(entries, strings.Trim(strings.TrimSpace(strings.TrimPrefix(entry, "-")), `"`))
+	}
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read %s\n%w", file, err)
+	}
+
+	return entries, nil
+}
+
+// ClasspathPaths returns the paths of classpath entries
+func ClasspathPaths(entries []ClasspathEntry) []string {
+	var paths []string
+	for _, e := range entries {
+		paths = append(paths, e.Path)
+	}
+	return paths
+}
+
+// digest returns the SHA-256 of a file, or of the file listing of a directory. Entries that do not exist, which the
+// JVM ignores, have no digest.
+func digest(path string) (string, error) {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return "", nil
+	} else if err != nil {
+		return "", fmt.Errorf("unable to stat %s\n%w", path, err)
+	}
+
+	h := sha256.New()
+
+	if info.IsDir() {
+		files, err := sherpa.NewFileListing(path)
+		if err != nil {
+			return "", fmt.Errorf("unable to create file listing for %s\n%w", path, err)
+		}
+		for _, f := range files {
+			r, _ := filepath.Rel(path, f.Path)
+			_, _ = fmt.Fprintf(h, "%s %s %s\n", filepath.ToSlash(r), f.Mode, f.SHA256)
+		}
+	} else {
+		in, err := os.Open(path)
+		if err != nil {
+			return "", fmt.Errorf("unable to open %s\n%w", path, err)
+		}
+		defer in.Close()
+
+		if _, err := io.Copy(h, in); err != nil {
+			return "", fmt.Errorf("unable to hash %s\n%w", path, err)
+		}
+	}
+
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
+func containsEntry(entries []ClasspathEntry, path string) bool {
+	for _, e := range entries {
+		if e.Path == path {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,175 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/magiconair/properties"
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testClasspathResolver(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		appPath  string
+		manifest *properties.Properties
+		resolver native.ManifestClasspathResolver
+	)
+
+	it.Before(func() {
+		var err error
+
+		appPath, err = ioutil.TempDir("", "classpath-resolver")
+		Expect(err).NotTo(HaveOccurred())
+
+		manifest = properties.NewProperties()
+		resolver = native.ManifestClasspathResolver{}
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(appPath)).To(Succeed())
+	})
+
+	it("resolves the Class-Path of the manifest", func() {
+		_, _, err := manifest.Set("Class-Path", "lib/a.jar lib/b.jar")
+		Expect(err).NotTo(HaveOccurred())
+
+		entries, err := resolver.Resolve(appPath, manifest)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(native.ClasspathPaths(entries)).To(Equal([]string{
+			appPath,
+			filepath.Join(appPath, "lib", "a.jar"),
+			filepath.Join(appPath, "lib", "b.jar"),
+		}))
+	})
+
+	it("resolves relative, percent-encoded, file: and absolute Class-Path entries", func() {
+		Expect(native.ManifestClasspath("lib/a.jar lib/my%20lib.jar classes/ file:/opt/lib/c.jar /opt/lib/d.jar https://example.com/e.jar", "/workspace")).
+			To(Equal([]string{
+				filepath.Join("/workspace", "lib", "a.jar"),
+				filepath.Join("/workspace", "lib", "my lib.jar"),
+				filepath.Join("/workspace", "classes"),
+				filepath.Join("/opt", "lib", "c.jar"),
+				filepath.Join("/opt", "lib", "d.jar"),
+			}))
+	})
+
+	context("Spring Boot", func() {
+		it.Before(func() {
+			Expect(os.MkdirAll(filepath.Join(appPath, "BOOT-INF", "lib"), 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(appPath, "BOOT-INF", "lib", "b.jar"), []byte{}, 0644)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(appPath, "BOOT-INF", "lib", "a.jar"), []byte{}, 0644)).To(Succeed())
+
+			_, _, err := manifest.Set("Spring-Boot-Classes", "BOOT-INF/classes/")
+			Expect(err).NotTo(HaveOccurred())
+			_, _, err = manifest.Set("Spring-Boot-Lib", "BOOT-INF/lib/")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		it("resolves the libraries in the order of the classpath index", func() {
+			_, _, err := manifest.Set("Spring-Boot-Classpath-Index", "BOOT-INF/classpath.idx")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ioutil.WriteFile(filepath.Join(appPath, "BOOT-INF", "classpath.idx"), []byte(`- "BOOT-INF/lib/b.jar"
+- "BOOT-INF/lib/a.jar"
+`), 0644)).To(Succeed())
+
+			entries, err := resolver.Resolve(appPath, manifest)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(native.ClasspathPaths(entries)).To(Equal([]string{
+				appPath,
+				filepath.Join(appPath, "BOOT-INF", "classes"),
+				filepath.Join(appPath, "BOOT-INF", "lib", "b.jar"),
+				filepath.Join(appPath, "BOOT-INF", "lib", "a.jar"),
+			}))
+		})
+
+		it("resolves the libraries in the order of the layers index", func() {
+			_, _, err := manifest.Set("Spring-Boot-Layers-Index", "BOOT-INF/layers.idx")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ioutil.WriteFile(filepath.Join(appPath, "BOOT-INF", "layers.idx"), []byte(`- "dependencies":
+  - "BOOT-INF/lib/b.jar"
+- "snapshot-dependencies":
+  - "BOOT-INF/lib/a.jar"
+- "application":
+  - "BOOT-INF/classes/"
+  - "META-INF/"
+`), 0644)).To(Succeed())
+
+			entries, err := resolver.Resolve(appPath, manifest)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(native.ClasspathPaths(entries)[2:]).To(Equal([]string{
+				filepath.Join(appPath, "BOOT-INF", "lib", "b.jar"),
+				filepath.Join(appPath, "BOOT-INF", "lib", "a.jar"),
+			}))
+		})
+
+		it("resolves the libraries in the order of their file names", func() {
+			entries, err := resolver.Resolve(appPath, manifest)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(native.ClasspathPaths(entries)[2:]).To(Equal([]string{
+				filepath.Join(appPath, "BOOT-INF", "lib", "a.jar"),
+				filepath.Join(appPath, "BOOT-INF", "lib", "b.jar"),
+			}))
+		})
+	})
+
+	context("digests", func() {
+		it.Before(func() {
+			resolver.Digests = true
+		})
+
+		it("digests files and directories", func() {
+			Expect(ioutil.WriteFile(filepath.Join(appPath, "a.jar"), []byte("test-jar"), 0644)).To(Succeed())
+			_, _, err := manifest.Set("Class-Path", "a.jar missing.jar")
+			Expect(err).NotTo(HaveOccurred())
+
+			entries, err := resolver.Resolve(appPath, manifest)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(HaveLen(3))
+			Expect(entries[0].Digest).To(HaveLen(64))
+			Expect(entries[1].Digest).To(Equal(fmt.Sprintf("%x", sha256.Sum256([]byte("test-jar")))))
+			Expect(entries[2].Digest).To(BeEmpty())
+		})
+
+		it("changes the digest of a directory when its contents change", func() {
+			before, err := resolver.Resolve(appPath, manifest)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(ioutil.WriteFile(filepath.Join(appPath, "application.properties"), []byte("a=b"), 0644)).To(Succeed())
+
+			after, err := resolver.Resolve(appPath, manifest)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(after[0].Digest).NotTo(Equal(before[0].Digest))
+		})
+	})
+
+	context("ReadLayersIndex", func() {
+		it("returns nil without an index", func() {
+			Expect(native.ReadLayersIndex(appPath, manifest)).To(BeNil())
+		})
+	})
+}
@@ -0,0 +1,185 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/buildpacks/libcnb"
+	"github.com/magiconair/properties"
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testClasspath(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		appPath string
+		props   *properties.Properties
+	)
+
+	it.Before(func() {
+		var err error
+
+		appPath, err = ioutil.TempDir("", "classpath-application")
+		Expect(err).NotTo(HaveOccurred())
+
+		props = properties.NewProperties()
+		_, _, err = props.Set("Spring-Boot-Classpath-Index", "BOOT-INF/classpath.idx")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(os.MkdirAll(filepath.Join(appPath, "BOOT-INF"), 0755)).To(Succeed())
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(appPath)).To(Succeed())
+	})
+
+	context("ParseArtifact", func() {
+		it("parses a release", func() {
+			a, ok := native.ParseArtifact("BOOT-INF/lib/spring-native-0.11.2.jar")
+			Expect(ok).To(BeTrue())
+			Expect(a.ArtifactID).To(Equal("spring-native"))
+			Expect(a.Version).To(Equal("0.11.2"))
+			Expect(a.Classifier).To(BeEmpty())
+		})
+
+		it("parses a classifier", func() {
+			a, ok := native.ParseArtifact("BOOT-INF/lib/spring-native-0.11.2-exec.jar")
+			Expect(ok).To(BeTrue())
+			Expect(a.ArtifactID).To(Equal("spring-native"))
+			Expect(a.Version).To(Equal("0.11.2"))
+			Expect(a.Classifier).To(Equal("exec"))
+		})
+
+		it("parses a milestone with a classifier", func() {
+			a, ok := native.ParseArtifact("spring-native-0.11.0-M1-exec.jar")
+			Expect(ok).To(BeTrue())
+			Expect(a.Version).To(Equal("0.11.0-M1"))
+			Expect(a.Classifier).To(Equal("exec"))
+		})
+
+		it("parses a timestamped SNAPSHOT", func() {
+			a, ok := native.ParseArtifact("spring-graalvm-native-0.8.6-20210203.101112-3-exec.jar")
+			Expect(ok).To(BeTrue())
+			Expect(a.ArtifactID).To(Equal("spring-graalvm-native"))
+			Expect(a.Version).To(Equal("0.8.6-20210203.101112-3"))
+			Expect(a.BaseVersion()).To(Equal("0.8.6-SNAPSHOT"))
+			Expect(a.Classifier).To(Equal("exec"))
+		})
+
+		it("does not parse a file without a version", func() {
+			_, ok := native.ParseArtifact("test-jar.jar")
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	context("ReadClasspathIndex", func() {
+		it("returns nil without an index", func() {
+			entries, err := native.ReadClasspathIndex(appPath, properties.NewProperties())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(BeNil())
+		})
+
+		it("reads file names and relative paths", func() {
+			Expect(ioutil.WriteFile(filepath.Join(appPath, "BOOT-INF", "classpath.idx"), []byte(`
+- "test-jar.jar"
+- "BOOT-INF/lib/spring-native-0.11.2.jar"
+`), 0644)).To(Succeed())
+
+			entries, err := native.ReadClasspathIndex(appPath, props)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(Equal([]string{"BOOT-INF/lib/test-jar.jar", "BOOT-INF/lib/spring-native-0.11.2.jar"}))
+		})
+	})
+
+	context("FindSpringNative", func() {
+		it("finds spring-native with a classifier", func() {
+			dependencies, err := native.DetectDependencies(native.NewDependencyDetector(), appPath, []string{
+				"BOOT-INF/lib/spring-native-configuration-0.11.2.jar",
+				"BOOT-INF/lib/spring-native-0.11.2-exec.jar",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			a, ok := native.FindSpringNative(dependencies)
+			Expect(ok).To(BeTrue())
+			Expect(a.Path).To(Equal(filepath.Join(appPath, "BOOT-INF/lib/spring-native-0.11.2-exec.jar")))
+		})
+
+		it("does not match on prefix", func() {
+			dependencies, err := native.DetectDependencies(native.NewDependencyDetector(), appPath, []string{
+				"BOOT-INF/lib/spring-native-configuration-0.11.2.jar",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			_, ok := native.FindSpringNative(dependencies)
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	context("MatchClasspathEntries", func() {
+		entries := []string{
+			"BOOT-INF/lib/spring-boot-3.1.0.jar",
+			"BOOT-INF/lib/spring-boot-devtools-3.1.0.jar",
+			"BOOT-INF/lib/org.jacoco.agent-0.8.10-runtime.jar",
+		}
+
+		it("matches paths and file names", func() {
+			Expect(native.MatchClasspathEntries(entries, []string{"spring-boot-devtools-*.jar", "BOOT-INF/lib/*jacoco*"})).
+				To(Equal([]string{"BOOT-INF/lib/spring-boot-devtools-3.1.0.jar", "BOOT-INF/lib/org.jacoco.agent-0.8.10-runtime.jar"}))
+		})
+
+		it("does not match other entries", func() {
+			Expect(native.MatchClasspathEntries(entries, []string{"spring-boot-*-test.jar"})).To(BeEmpty())
+		})
+
+		it("fails on invalid patterns", func() {
+			_, err := native.MatchClasspathEntries(entries, []string{"[a-"})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	context("FindAdditionalClasspath", func() {
+		it("finds entries from the configuration and bindings", func() {
+			binding := filepath.Join(appPath, "binding")
+			Expect(os.MkdirAll(filepath.Join(binding, "classes"), 0755)).To(Succeed())
+			Expect(os.MkdirAll(filepath.Join(binding, "..data"), 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(binding, "type"), []byte("native-image-classpath"), 0644)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(binding, "substitutions.jar"), []byte{}, 0644)).To(Succeed())
+
+			entries, err := native.FindAdditionalClasspath(appPath, "aot/classes::/opt/extra.jar", libcnb.Bindings{
+				{Name: "classpath", Type: "native-image-classpath", Path: binding},
+				{Name: "other", Type: "maven", Path: "/bindings/maven"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(Equal([]string{
+				filepath.Join(appPath, "aot", "classes"),
+				"/opt/extra.jar",
+				filepath.Join(binding, "classes"),
+				filepath.Join(binding, "substitutions.jar"),
+			}))
+		})
+
+		it("finds no entries", func() {
+			Expect(native.FindAdditionalClasspath(appPath, "", nil)).To(BeEmpty())
+		})
+	})
+}
@@ -0,0 +1,154 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"fmt"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/paketo-buildpacks/libpak/bard"
+)
+
+// CompatibilityMetadataKey is the key of the Spring Native compatibility table in the buildpack metadata
+const CompatibilityMetadataKey = "spring-native-compatibility"
+
+const (
+	CompatibilityFail = "fail"
+	CompatibilityWarn = "warn"
+)
+
+// Compatibility is the range of GraalVM versions supported by a range of Spring Native versions. Platform operators
+// maintain the table in the buildpack metadata.
+type Compatibility struct {
+	SpringNative string `toml:"spring-native"`
+	GraalVM      string `toml:"graalvm"`
+	Action       string `toml:"action"`
+}
+
+// DefaultCompatibility is the table used when the buildpack metadata does not have one
+var DefaultCompatibility = []Compatibility{
+	{SpringNative: "~0.9.0", GraalVM: ">=21.0.0, <21.2.0", Action: CompatibilityFail},
+	{SpringNative: "~0.10.0", GraalVM: ">=21.2.0, <21.3.0", Action: CompatibilityFail},
+	{SpringNative: "~0.11.0", GraalVM: ">=21.3.0, <22.2.0", Action: CompatibilityFail},
+	{SpringNative: "~0.12.0", GraalVM: ">=22.1.0, <23.0.0", Action: CompatibilityFail},
+}
+
+// ParseCompatibility reads the Spring Native compatibility table from the buildpack metadata
+func ParseCompatibility(metadata map[string]interface{}) ([]Compatibility, error) {
+	var entries []map[string]interface{}
+
+	switch v := metadata[CompatibilityMetadataKey].(type) {
+	case nil:
+		return DefaultCompatibility, nil
+	case []map[string]interface{}:
+		entries = v
+	case []interface{}:
+		for _, e := range v {
+			m, ok := e.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("invalid %s entry %v", CompatibilityMetadataKey, e)
+			}
+			entries = append(entries, m)
+		}
+	default:
+		return nil, fmt.Errorf("invalid %s %v", CompatibilityMetadataKey, v)
+	}
+
+	var table []Compatibility
+	for _, e := range entries {
+		c := Compatibility{Action: CompatibilityFail}
+		c.SpringNative, _ = e["spring-native"].(string)
+		c.GraalVM, _ = e["graalvm"].(string)
+		if a, ok := e["action"].(string); ok {
+			c.Action = a
+		}
+
+		if c.Action != CompatibilityFail && c.Action != CompatibilityWarn {
+			return nil, fmt.Errorf("invalid %s entry %v, expected an action of %s or %s",
+				CompatibilityMetadataKey, e, CompatibilityFail, CompatibilityWarn)
+		}
+		if _, err := semver.NewConstraint(c.SpringNative); err != nil {
+			return nil, fmt.Errorf("invalid %s entry %v, unable to parse spring-native constraint\n%w", CompatibilityMetadataKey, e, err)
+		}
+		if _, err := semver.NewConstraint(c.GraalVM); err != nil {
+			return nil, fmt.Errorf("invalid %s entry %v, unable to parse graalvm constraint\n%w", CompatibilityMetadataKey, e, err)
+		}
+
+		table = append(table, c)
+	}
+
+	return table, nil
+}
+
+// FindCompatibility returns the entry of the table matching a Spring Native release. Milestones, release candidates
+// and SNAPSHOTs match the entry of their release.
+func FindCompatibility(table []Compatibility, springNative Artifact) (Compatibility, bool) {
+	v, err := semver.NewVersion(springNative.BaseVersion())
+	if err != nil {
+		return Compatibility{}, false
+	}
+
+	release, err := v.SetPrerelease("")
+	if err != nil {
+		return Compatibility{}, false
+	}
+
+	for _, c := range table {
+		if constraint, err := semver.NewConstraint(c.SpringNative); err == nil && constraint.Check(&release) {
+			return c, true
+		}
+	}
+
+	return Compatibility{}, false
+}
+
+// CompatibilityCheck validates the GraalVM version providing native-image against the Spring Native release of the
+// application
+type CompatibilityCheck struct {
+	Table  []Compatibility
+	Logger bard.Logger
+}
+
+// Check warns or fails, depending on the action of the matching entry, if the GraalVM version of the output of
+// native-image --version is not supported by Spring Native. Releases versioned like the JDK are not checked.
+func (c CompatibilityCheck) Check(springNative Artifact, version string) error {
+	entry, ok := FindCompatibility(c.Table, springNative)
+	if !ok {
+		return nil
+	}
+
+	v, ok := ParseGraalVMVersion(version)
+	if !ok {
+		return nil
+	}
+
+	constraint, err := semver.NewConstraint(entry.GraalVM)
+	if err != nil {
+		return fmt.Errorf("unable to parse constraint %q\n%w", entry.GraalVM, err)
+	}
+	if constraint.Check(v) {
+		return nil
+	}
+
+	err = IncompatibleGraalVMError{SpringNative: springNative, Supported: entry.GraalVM, GraalVM: v.String()}
+	if entry.Action == CompatibilityFail {
+		return err
+	}
+
+	warn(c.Logger, err.Error())
+	return nil
+}
@@ -0,0 +1,128 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/paketo-buildpacks/libpak/bard"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testCompatibility(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		springNative = native.Artifact{ArtifactID: "spring-native", Version: "0.11.2"}
+	)
+
+	context("ParseCompatibility", func() {
+		it("returns the default table", func() {
+			Expect(native.ParseCompatibility(map[string]interface{}{})).To(Equal(native.DefaultCompatibility))
+		})
+
+		it("parses the table", func() {
+			Expect(native.ParseCompatibility(map[string]interface{}{
+				"spring-native-compatibility": []interface{}{
+					map[string]interface{}{"spring-native": "~0.12.0", "graalvm": "22.*", "action": "warn"},
+					map[string]interface{}{"spring-native": "~0.11.0", "graalvm": ">=21.3.0, <22.2.0"},
+				},
+			})).To(Equal([]native.Compatibility{
+				{SpringNative: "~0.12.0", GraalVM: "22.*", Action: "warn"},
+				{SpringNative: "~0.11.0", GraalVM: ">=21.3.0, <22.2.0", Action: "fail"},
+			}))
+		})
+
+		it("fails on invalid actions", func() {
+			_, err := native.ParseCompatibility(map[string]interface{}{
+				"spring-native-compatibility": []interface{}{
+					map[string]interface{}{"spring-native": "~0.11.0", "graalvm": "22.*", "action": "ignore"},
+				},
+			})
+			Expect(err).To(HaveOccurred())
+		})
+
+		it("fails on invalid constraints", func() {
+			_, err := native.ParseCompatibility(map[string]interface{}{
+				"spring-native-compatibility": []interface{}{
+					map[string]interface{}{"spring-native": "~0.11.0"},
+				},
+			})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	context("FindCompatibility", func() {
+		it("finds the entry of a release", func() {
+			c, ok := native.FindCompatibility(native.DefaultCompatibility, springNative)
+			Expect(ok).To(BeTrue())
+			Expect(c.GraalVM).To(Equal(">=21.3.0, <22.2.0"))
+		})
+
+		it("finds the entry of milestones and timestamped SNAPSHOTs", func() {
+			c, ok := native.FindCompatibility(native.DefaultCompatibility, native.Artifact{Version: "0.12.0-M1"})
+			Expect(ok).To(BeTrue())
+			Expect(c.GraalVM).To(Equal(">=22.1.0, <23.0.0"))
+
+			c, ok = native.FindCompatibility(native.DefaultCompatibility, native.Artifact{Version: "0.12.2-20220601.101112-3"})
+			Expect(ok).To(BeTrue())
+			Expect(c.GraalVM).To(Equal(">=22.1.0, <23.0.0"))
+		})
+
+		it("does not find other releases", func() {
+			_, ok := native.FindCompatibility(native.DefaultCompatibility, native.Artifact{Version: "0.8.5"})
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	context("CompatibilityCheck", func() {
+		var (
+			buf   *bytes.Buffer
+			check native.CompatibilityCheck
+		)
+
+		it.Before(func() {
+			buf = &bytes.Buffer{}
+			check = native.CompatibilityCheck{Table: native.DefaultCompatibility, Logger: bard.NewLogger(buf)}
+		})
+
+		it("accepts a supported version", func() {
+			Expect(check.Check(springNative, "GraalVM 22.0.0.2 Java 17 CE")).To(Succeed())
+			Expect(buf.String()).To(BeEmpty())
+		})
+
+		it("fails on an unsupported version", func() {
+			Expect(check.Check(springNative, "GraalVM 22.3.0 Java 17 CE")).To(MatchError(
+				"spring-native 0.11.2 requires GraalVM >=21.3.0, <22.2.0, but found GraalVM 22.3.0. Set $BP_NATIVE_IMAGE_VERSION to a supported version, or upgrade spring-native to a release supporting GraalVM 22.3.0."))
+		})
+
+		it("warns on an unsupported version", func() {
+			check.Table = []native.Compatibility{{SpringNative: "~0.11.0", GraalVM: ">=21.3.0, <22.2.0", Action: "warn"}}
+
+			Expect(check.Check(springNative, "GraalVM 22.3.0 Java 17 CE")).To(Succeed())
+			Expect(buf.String()).To(ContainSubstring("requires GraalVM >=21.3.0, <22.2.0, but found GraalVM 22.3.0"))
+		})
+
+		it("does not check releases versioned like the JDK", func() {
+			Expect(check.Check(springNative, "native-image 17.0.7 2023-04-18")).To(Succeed())
+		})
+	})
+}
@@ -0,0 +1,210 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/buildpacks/libcnb"
+	"github.com/paketo-buildpacks/libpak"
+	"github.com/paketo-buildpacks/libpak/bard"
+	"github.com/paketo-buildpacks/libpak/bindings"
+	"github.com/paketo-buildpacks/libpak/effect"
+)
+
+// ComponentBindingType is the type of bindings holding a native-image component archive, installed without network
+// access when the JDK does not provide native-image
+const ComponentBindingType = "native-image-component"
+
+// FindComponentArchive returns the first component archive, a JAR, in bindings of type native-image-component.
+// Returns an empty string if there is no such binding.
+func FindComponentArchive(binds libcnb.Bindings) (string, error) {
+	for _, b := range bindings.Resolve(binds, bindings.OfType(ComponentBindingType)) {
+		children, err := ioutil.ReadDir(b.Path)
+		if err != nil {
+			return "", fmt.Errorf("unable to list children of %s\n%w", b.Path, err)
+		}
+
+		var archives []string
+		for _, c := range children {
+			if !c.IsDir() && strings.HasSuffix(c.Name(), ".jar") {
+				archives = append(archives, filepath.Join(b.Path, c.Name()))
+			}
+		}
+		sort.Strings(archives)
+
+		if len(archives) > 0 {
+			return archives[0], nil
+		}
+	}
+
+	return "", nil
+}
+
+// ComponentInstallation returns the gu execution installing the native-image component, from archive when set and
+// from the GraalVM catalog otherwise
+func ComponentInstallation(archive string, env []string, stdout io.Writer, stderr io.Writer) effect.Execution {
+	args := []string{"install", "--no-progress", "native-image"}
+	if archive != "" {
+		args = []string{"install", "--no-progress", "--local-file", archive}
+	}
+
+	return effect.Execution{
+		Command: "gu",
+		Args:    args,
+		Env:     env,
+		Stdout:  stdout,
+		Stderr:  stderr,
+	}
+}
+
+// ComponentInstaller contributes a cached layer recording the native-image component that gu installed into the JDK,
+// when the JDK does not provide native-image. gu installs into the JDK rather than the layer, so the layer is reused
+// only while native-image is available, the JDK is unchanged and so is the component archive. The install is then not
+// repeated, while a JDK without the component, such as a freshly contributed one, always gets it installed.
+type ComponentInstaller struct {
+	// Archive is the component archive to install from instead of the GraalVM catalog
+	Archive string
+	// CACertificates are the certificate authorities trusted by gu, such as those of corporate proxies
+	CACertificates []string
+	Command        string
+	Context        context.Context
+	Executor       effect.Executor
+	JavaHome       string
+	Logger         bard.Logger
+	// Offline fails the install rather than downloading the component from the GraalVM catalog
+	Offline bool
+}
+
+func (c ComponentInstaller) Contribute(layer libcnb.Layer) (libcnb.Layer, error) {
+	ctx := c.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	expected := map[string]interface{}{
+		"component": "native-image",
+		"java-home": c.JavaHome,
+	}
+	if c.Archive != "" {
+		digest, err := FileDigest(c.Archive)
+		if err != nil {
+			return libcnb.Layer{}, fmt.Errorf("unable to compute checksum of %s\n%w", c.Archive, err)
+		}
+		expected["sha256"] = digest
+	}
+
+	buf := &bytes.Buffer{}
+	if err := executeContext(ctx, c.Executor, c.version(buf)); err == nil {
+		if len(layer.Metadata) == 0 {
+			c.Logger.Bodyf("%s is available, not installing the native-image component", c.Command)
+			return layer, nil
+		}
+		expected["version"] = GraalVMVersion(buf.String())
+	} else {
+		// the component recorded by the layer is no longer in the JDK
+		layer.Metadata = nil
+	}
+
+	contributor := libpak.NewLayerContributor("Native Image Component", expected, libcnb.LayerTypes{
+		Build: true,
+		Cache: true,
+	})
+	contributor.Logger = c.Logger
+
+	layer, err := contributor.Contribute(layer, func() (libcnb.Layer, error) {
+		if err := c.install(ctx); err != nil {
+			return libcnb.Layer{}, err
+		}
+
+		buf.Reset()
+		if err := executeContext(ctx, c.Executor, c.version(buf)); err != nil {
+			return libcnb.Layer{}, NativeImageUnavailableError{Command: c.Command, Err: err}
+		}
+
+		// the layer is only restored from the cache with content
+		file := filepath.Join(layer.Path, "version.txt")
+		if err := ioutil.WriteFile(file, buf.Bytes(), 0644); err != nil {
+			return libcnb.Layer{}, fmt.Errorf("unable to write %s\n%w", file, err)
+		}
+
+		return layer, nil
+	})
+	if err != nil {
+		return libcnb.Layer{}, err
+	}
+
+	layer.Metadata["version"] = GraalVMVersion(buf.String())
+	return layer, nil
+}
+
+// install installs the native-image component with gu, trusting the configured certificate authorities
+func (c ComponentInstaller) install(ctx context.Context) error {
+	if c.Offline && c.Archive == "" {
+		return OfflineError{Missing: []OfflineArtifact{componentArtifact}}
+	}
+
+	c.Logger.Bodyf("%s is not available, installing the native-image component", c.Command)
+	if c.Archive != "" {
+		c.Logger.Bodyf("Installing from %s", c.Archive)
+	}
+
+	options := ProxyProperties(os.Environ())
+	if len(c.CACertificates) > 0 {
+		dir, err := ioutil.TempDir("", "native-image-component-trust-store")
+		if err != nil {
+			return fmt.Errorf("unable to create trust store directory\n%w", err)
+		}
+		defer os.RemoveAll(dir)
+
+		trustStore, err := TrustStore(ctx, c.Executor, c.Logger, c.JavaHome, dir, c.CACertificates)
+		if err != nil {
+			return err
+		}
+		options = append(options, trustStore...)
+	}
+
+	installation := ComponentInstallation(c.Archive, JavaToolOptions(nil, options), c.Logger.InfoWriter(), c.Logger.InfoWriter())
+	if err := executeContext(ctx, c.Executor, installation); err != nil {
+		return fmt.Errorf("unable to install the native-image component, set $%s if native-image is provided otherwise\n%w",
+			ConfigNativeImageSkipGuInstall, err)
+	}
+
+	return nil
+}
+
+// version returns the execution printing the version of native-image to stdout
+func (c ComponentInstaller) version(stdout io.Writer) effect.Execution {
+	return effect.Execution{
+		Command: c.Command,
+		Args:    []string{"--version"},
+		Stdout:  stdout,
+		Stderr:  c.Logger.BodyWriter(),
+	}
+}
+
+func (ComponentInstaller) Name() string {
+	return "native-image-component"
+}
@@ -0,0 +1,186 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/buildpacks/libcnb"
+	. "github.com/onsi/gomega"
+	"github.com/paketo-buildpacks/libpak/effect"
+	"github.com/paketo-buildpacks/libpak/effect/mocks"
+	"github.com/sclevine/spec"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testComponent(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		path string
+	)
+
+	it.Before(func() {
+		var err error
+
+		path, err = ioutil.TempDir("", "component")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(path)).To(Succeed())
+	})
+
+	it("finds the component archive in a binding", func() {
+		Expect(ioutil.WriteFile(filepath.Join(path, "type"), []byte(native.ComponentBindingType), 0644)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(path, "native-image-installable-svm-java17-linux-amd64-22.3.0.jar"), []byte{}, 0644)).
+			To(Succeed())
+
+		Expect(native.FindComponentArchive(libcnb.Bindings{
+			{Name: "other", Type: "maven", Path: "/bindings/maven"},
+			{Name: "component", Type: native.ComponentBindingType, Path: path},
+		})).To(Equal(filepath.Join(path, "native-image-installable-svm-java17-linux-amd64-22.3.0.jar")))
+	})
+
+	it("finds no archive without a binding", func() {
+		Expect(native.FindComponentArchive(nil)).To(BeEmpty())
+	})
+
+	it("installs from the catalog or an archive", func() {
+		Expect(native.ComponentInstallation("", nil, nil, nil).Args).To(Equal([]string{"install", "--no-progress", "native-image"}))
+		Expect(native.ComponentInstallation("/bindings/component/svm.jar", nil, nil, nil).Args).
+			To(Equal([]string{"install", "--no-progress", "--local-file", "/bindings/component/svm.jar"}))
+	})
+
+	context("ComponentInstaller", func() {
+		var (
+			archive   string
+			available bool
+			executor  *mocks.Executor
+			installer native.ComponentInstaller
+			layer     libcnb.Layer
+		)
+
+		it.Before(func() {
+			archive = filepath.Join(path, "svm.jar")
+			Expect(ioutil.WriteFile(archive, []byte("component"), 0644)).To(Succeed())
+
+			var err error
+			layers := &libcnb.Layers{Path: path}
+			layer, err = layers.Layer("native-image-component")
+			Expect(err).NotTo(HaveOccurred())
+
+			available = false
+			executor = &mocks.Executor{}
+			executor.On("Execute", mock.MatchedBy(func(e effect.Execution) bool {
+				return e.Command == "native-image"
+			})).Return(func(e effect.Execution) error {
+				if !available {
+					return fmt.Errorf("executable file not found in $PATH")
+				}
+				_, err := e.Stdout.Write([]byte("GraalVM 22.3.1 Java 17 CE\n"))
+				return err
+			})
+			executor.On("Execute", mock.MatchedBy(func(e effect.Execution) bool {
+				return e.Command == "gu"
+			})).Run(func(mock.Arguments) {
+				available = true
+			}).Return(nil)
+
+			installer = native.ComponentInstaller{
+				Archive:  archive,
+				Command:  "native-image",
+				Executor: executor,
+				JavaHome: "/opt/graalvm",
+			}
+		})
+
+		it("installs the component when native-image is missing", func() {
+			layer, err := installer.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			install := executor.Calls[1].Arguments[0].(effect.Execution)
+			Expect(install.Command).To(Equal("gu"))
+			Expect(install.Args).To(Equal([]string{"install", "--no-progress", "--local-file", archive}))
+			Expect(executor.Calls[2].Arguments[0].(effect.Execution).Args).To(Equal([]string{"--version"}))
+
+			Expect(layer.LayerTypes).To(Equal(libcnb.LayerTypes{Build: true, Cache: true}))
+			Expect(layer.Metadata).To(HaveKeyWithValue("component", "native-image"))
+			Expect(layer.Metadata).To(HaveKeyWithValue("java-home", "/opt/graalvm"))
+			Expect(layer.Metadata).To(HaveKeyWithValue("sha256", "6985ca1f4daa5a584a28eae043a239cb96689af1337ea13afb63e00c2bf512fa"))
+			Expect(layer.Metadata).To(HaveKeyWithValue("version", "GraalVM 22.3.1 Java 17 CE"))
+		})
+
+		it("does not install the component when native-image is available", func() {
+			available = true
+
+			layer, err := installer.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(executor.Calls).To(HaveLen(1))
+			Expect(layer.LayerTypes).To(Equal(libcnb.LayerTypes{}))
+		})
+
+		it("reuses the layer while the installed component is available", func() {
+			layer, err := installer.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(filepath.Join(layer.Path, "version.txt")).To(BeARegularFile())
+
+			layer, err = installer.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(executor.Calls).To(HaveLen(4))
+			Expect(executor.Calls[3].Arguments[0].(effect.Execution).Command).To(Equal("native-image"))
+			Expect(layer.LayerTypes).To(Equal(libcnb.LayerTypes{Build: true, Cache: true}))
+		})
+
+		it("installs the component again when it is missing from the JDK", func() {
+			layer, err := installer.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			available = false
+			_, err = installer.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(executor.Calls[4].Arguments[0].(effect.Execution).Command).To(Equal("gu"))
+		})
+
+		it("does not download the component offline", func() {
+			installer.Archive = ""
+			installer.Offline = true
+
+			_, err := installer.Contribute(layer)
+			Expect(err).To(MatchError(ContainSubstring("$BP_NATIVE_IMAGE_OFFLINE is set")))
+			Expect(executor.Calls).To(HaveLen(1))
+		})
+
+		it("fails when the component cannot be installed", func() {
+			executor = &mocks.Executor{}
+			executor.On("Execute", mock.Anything).Return(fmt.Errorf("test-error"))
+			installer.Executor = executor
+
+			_, err := installer.Contribute(layer)
+			Expect(err).To(MatchError(ContainSubstring("unable to install the native-image component, set $BP_NATIVE_IMAGE_SKIP_GU_INSTALL")))
+		})
+	})
+}
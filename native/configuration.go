@@ -0,0 +1,113 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/buildpacks/libcnb"
+	"github.com/paketo-buildpacks/libpak"
+)
+
+// EffectiveConfigurationFile is the name of the file in the configuration layer holding the effective configuration
+const EffectiveConfigurationFile = "configuration.json"
+
+// EffectiveConfigurationEnv is the launch environment variable holding the path of the effective configuration
+const EffectiveConfigurationEnv = "BP_NATIVE_IMAGE_CONFIGURATION"
+
+// secretAssignmentPattern matches name=value assignments whose name looks like it holds a secret
+var secretAssignmentPattern = regexp.MustCompile(`(?i)([^\s,=]*(?:password|passwd|secret|token|credential|api[-_.]?key|private[-_.]?key)[^\s,=]*)=[^\s,]*`)
+
+// EffectiveConfiguration describes how the native image was configured and what the buildpack detected
+type EffectiveConfiguration struct {
+	Configuration     map[string]string `json:"configuration"`
+	StartClass        string            `json:"start-class"`
+	Processes         []string          `json:"processes"`
+	SpringNative      string            `json:"spring-native,omitempty"`
+	Excluded          []string          `json:"excluded,omitempty"`
+	TrainingArtifacts []string          `json:"training-artifacts,omitempty"`
+	GraalVMVersion    string            `json:"graalvm-version"`
+	Distribution      string            `json:"distribution,omitempty"`
+	Arguments         []string          `json:"arguments"`
+}
+
+// ResolveConfiguration returns the resolved value of every build configuration of the buildpack, with secrets redacted
+func ResolveConfiguration(cr libpak.ConfigurationResolver) map[string]string {
+	resolved := map[string]string{}
+
+	for _, c := range cr.Configurations {
+		if !c.Build {
+			continue
+		}
+
+		if v, _ := cr.Resolve(c.Name); v != "" {
+			resolved[c.Name] = secretAssignmentPattern.ReplaceAllString(v, "$1="+Redacted)
+		}
+	}
+
+	return resolved
+}
+
+// Configuration contributes a launch layer holding the effective configuration, so that it can be inspected in the
+// image without access to the build logs. The native-image arguments and GraalVM version are taken from Metrics,
+// which is filled in when the native image layer, contributed before this layer, is contributed.
+type Configuration struct {
+	Effective EffectiveConfiguration
+	Metrics   *Metrics
+
+	// LibraryPath is the directory of the native libraries next to the binary, prepended to $LD_LIBRARY_PATH at launch
+	LibraryPath string
+}
+
+func (c Configuration) Contribute(layer libcnb.Layer) (libcnb.Layer, error) {
+	if err := os.MkdirAll(layer.Path, 0755); err != nil {
+		return libcnb.Layer{}, fmt.Errorf("unable to create %s\n%w", layer.Path, err)
+	}
+
+	effective := c.Effective
+	if c.Metrics != nil {
+		effective.GraalVMVersion = c.Metrics.GraalVMVersion
+		effective.Distribution = c.Metrics.Distribution
+		effective.Arguments = c.Metrics.Arguments
+	}
+
+	b, err := json.MarshalIndent(effective, "", "  ")
+	if err != nil {
+		return libcnb.Layer{}, fmt.Errorf("unable to encode effective configuration\n%w", err)
+	}
+
+	file := filepath.Join(layer.Path, EffectiveConfigurationFile)
+	if err := ioutil.WriteFile(file, b, 0644); err != nil {
+		return libcnb.Layer{}, fmt.Errorf("unable to write %s\n%w", file, err)
+	}
+
+	layer.LaunchEnvironment.Default(EffectiveConfigurationEnv, file)
+	if c.LibraryPath != "" {
+		layer.LaunchEnvironment.Prepend(LibraryPathEnv, string(os.PathListSeparator), c.LibraryPath)
+	}
+	layer.Launch = true
+	return layer, nil
+}
+
+func (Configuration) Name() string {
+	return "configuration"
+}
@@ -0,0 +1,101 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/buildpacks/libcnb"
+	. "github.com/onsi/gomega"
+	"github.com/paketo-buildpacks/libpak"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testConfiguration(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		ctx libcnb.BuildContext
+	)
+
+	it.Before(func() {
+		var err error
+
+		ctx.Layers.Path, err = ioutil.TempDir("", "configuration-layers")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(ctx.Layers.Path)).To(Succeed())
+	})
+
+	it("contributes the effective configuration", func() {
+		layer, err := ctx.Layers.Layer("configuration")
+		Expect(err).NotTo(HaveOccurred())
+
+		layer, err = native.Configuration{
+			Effective: native.EffectiveConfiguration{StartClass: "test-start-class"},
+			Metrics:   &native.Metrics{GraalVMVersion: "test-version", Arguments: []string{"test-argument"}},
+		}.Contribute(layer)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(layer.Launch).To(BeTrue())
+		file := filepath.Join(layer.Path, "configuration.json")
+		Expect(layer.LaunchEnvironment).To(HaveKeyWithValue("BP_NATIVE_IMAGE_CONFIGURATION.default", file))
+
+		b, err := ioutil.ReadFile(file)
+		Expect(err).NotTo(HaveOccurred())
+		var effective native.EffectiveConfiguration
+		Expect(json.Unmarshal(b, &effective)).To(Succeed())
+		Expect(effective.StartClass).To(Equal("test-start-class"))
+		Expect(effective.GraalVMVersion).To(Equal("test-version"))
+		Expect(effective.Arguments).To(Equal([]string{"test-argument"}))
+	})
+
+	it("puts the native libraries on the library path", func() {
+		layer, err := ctx.Layers.Layer("configuration")
+		Expect(err).NotTo(HaveOccurred())
+
+		layer, err = native.Configuration{LibraryPath: "/workspace"}.Contribute(layer)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(layer.LaunchEnvironment).To(HaveKeyWithValue("LD_LIBRARY_PATH.prepend", "/workspace"))
+		Expect(layer.LaunchEnvironment).To(HaveKeyWithValue("LD_LIBRARY_PATH.delim", ":"))
+	})
+
+	it("resolves configuration without secrets", func() {
+		Expect(os.Setenv("BP_NATIVE_IMAGE_BUILD_ARGUMENTS", "--no-fallback -Ddb.password=hunter2")).To(Succeed())
+		defer os.Unsetenv("BP_NATIVE_IMAGE_BUILD_ARGUMENTS")
+
+		cr := libpak.ConfigurationResolver{Configurations: []libpak.BuildpackConfiguration{
+			{Name: "BP_NATIVE_IMAGE_BUILD_ARGUMENTS", Build: true},
+			{Name: "BP_BINARY_COMPRESSION_METHOD", Build: true, Default: "none"},
+			{Name: "BP_LAUNCH_ONLY"},
+		}}
+
+		Expect(native.ResolveConfiguration(cr)).To(Equal(map[string]string{
+			"BP_NATIVE_IMAGE_BUILD_ARGUMENTS": "--no-fallback -Ddb.password=[REDACTED]",
+			"BP_BINARY_COMPRESSION_METHOD":    "none",
+		}))
+	})
+}
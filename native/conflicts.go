@@ -0,0 +1,123 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/paketo-buildpacks/libpak/bard"
+)
+
+// NormalizeClasspath cleans the entries of a classpath and removes empty and repeated entries, keeping the first
+// occurrence so that the order, and so the classes native-image sees first, is the order of the classpath
+func NormalizeClasspath(entries []string) []string {
+	var normalized []string
+	seen := map[string]bool{}
+
+	for _, e := range entries {
+		if strings.TrimSpace(e) == "" {
+			continue
+		}
+
+		e = filepath.Clean(e)
+		if seen[e] {
+			continue
+		}
+		seen[e] = true
+		normalized = append(normalized, e)
+	}
+
+	return normalized
+}
+
+// ArtifactConflict is an artifact on the classpath more than once, in differing versions
+type ArtifactConflict struct {
+	ArtifactID string
+	Artifacts  []Artifact
+}
+
+func (a ArtifactConflict) String() string {
+	var versions []string
+	for _, r := range a.Artifacts {
+		versions = append(versions, fmt.Sprintf("%s (%s)", r.Version, r.Path))
+	}
+	return fmt.Sprintf("%s: %s", a.ArtifactID, strings.Join(versions, ", "))
+}
+
+// FindArtifactConflicts returns the artifacts, identified by artifact id and classifier, that are on the classpath in
+// more than one version. Which of the versions wins is decided by the order of the classpath, and can differ from the
+// JVM run of the application.
+func FindArtifactConflicts(entries []string) []ArtifactConflict {
+	var conflicts []ArtifactConflict
+
+	var keys []string
+	artifacts := map[string][]Artifact{}
+	for _, e := range entries {
+		a, ok := ParseArtifact(e)
+		if !ok || !strings.HasSuffix(e, ".jar") {
+			continue
+		}
+
+		key := a.ArtifactID
+		if a.Classifier != "" {
+			key = fmt.Sprintf("%s:%s", key, a.Classifier)
+		}
+		if _, ok := artifacts[key]; !ok {
+			keys = append(keys, key)
+		}
+		artifacts[key] = append(artifacts[key], a)
+	}
+
+	for _, k := range keys {
+		as := artifacts[k]
+		for _, a := range as[1:] {
+			if a.Version != as[0].Version {
+				conflicts = append(conflicts, ArtifactConflict{ArtifactID: k, Artifacts: as})
+				break
+			}
+		}
+	}
+
+	return conflicts
+}
+
+// CheckArtifactConflicts warns about or, when action is fail, fails on artifacts that are on the classpath in more
+// than one version
+func CheckArtifactConflicts(logger bard.Logger, entries []string, action string) error {
+	conflicts := FindArtifactConflicts(entries)
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	var lines []string
+	for _, c := range conflicts {
+		lines = append(lines, c.String())
+	}
+
+	if action == CheckFail {
+		return fmt.Errorf("classpath has artifacts in more than one version, set $%s to warn only\n%s",
+			ConfigNativeImageConflicts, strings.Join(lines, "\n"))
+	}
+
+	warn(logger, "Classpath has artifacts in more than one version, the native image may behave differently from the JVM")
+	for _, l := range lines {
+		logger.Body(l)
+	}
+	return nil
+}
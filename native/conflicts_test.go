@@ -0,0 +1,74 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/paketo-buildpacks/libpak/bard"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testConflicts(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		classpath = []string{
+			"/workspace",
+			"/workspace/BOOT-INF/lib/commons-lang3-3.11.jar",
+			"/workspace/BOOT-INF/lib/jackson-core-2.13.4.jar",
+			"/workspace/lib/commons-lang3-3.12.0.jar",
+			"/workspace/BOOT-INF/lib/netty-transport-native-epoll-4.1.86.Final-linux-x86_64.jar",
+			"/workspace/BOOT-INF/lib/netty-transport-native-epoll-4.1.86.Final-linux-aarch_64.jar",
+			"/workspace/lib/jackson-core-2.13.4.jar",
+		}
+	)
+
+	it("normalizes the classpath", func() {
+		Expect(native.NormalizeClasspath([]string{"/workspace/", "", "/workspace/lib/../BOOT-INF/classes", "/workspace", "/workspace/BOOT-INF/classes"})).
+			To(Equal([]string{"/workspace", "/workspace/BOOT-INF/classes"}))
+	})
+
+	it("finds artifacts in more than one version", func() {
+		conflicts := native.FindArtifactConflicts(classpath)
+
+		Expect(conflicts).To(HaveLen(1))
+		Expect(conflicts[0].ArtifactID).To(Equal("commons-lang3"))
+		Expect(conflicts[0].String()).
+			To(Equal("commons-lang3: 3.11 (/workspace/BOOT-INF/lib/commons-lang3-3.11.jar), 3.12.0 (/workspace/lib/commons-lang3-3.12.0.jar)"))
+	})
+
+	it("warns about conflicts", func() {
+		b := &bytes.Buffer{}
+
+		Expect(native.CheckArtifactConflicts(bard.NewLogger(b), classpath, native.CheckWarn)).To(Succeed())
+		Expect(b.String()).To(ContainSubstring("commons-lang3: 3.11"))
+	})
+
+	it("fails on conflicts", func() {
+		Expect(native.CheckArtifactConflicts(bard.NewLogger(&bytes.Buffer{}), classpath, native.CheckFail)).
+			To(MatchError(ContainSubstring("commons-lang3: 3.11")))
+	})
+
+	it("accepts a classpath without conflicts", func() {
+		Expect(native.CheckArtifactConflicts(bard.NewLogger(&bytes.Buffer{}), classpath[:3], native.CheckFail)).To(Succeed())
+	})
+}
@@ -0,0 +1,119 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package native
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/magiconair/properties"
+	"github.com/paketo-buildpacks/libpak/bard"
+)
+
+// DebugManifest logs the attributes of the manifest of the application at debug level
+func DebugManifest(logger bard.Logger, manifest *properties.Properties) {
+	if !logger.IsDebugEnabled() {
+		return
+	}
+
+	keys := manifest.Keys()
+	if len(keys) == 0 {
+		logger.Debug("Application has no manifest attributes")
+		return
+	}
+
+	sort.Strings(keys)
+	logger.Debug("Manifest attributes:")
+	for _, k := range keys {
+		v, _ := manifest.Get(k)
+		logger.Debugf("  %s: %s", k, v)
+	}
+}
+
+// debugClasspath logs at debug level how the classpath of the application is resolved: the strategy, where the
+// entries come from, the resolved entries and the excluded ones
+func (n NativeImage) debugClasspath() {
+	if !n.Logger.IsDebugEnabled() {
+		return
+	}
+
+	strategy, err := n.strategy()
+	if err != nil {
+		n.Logger.Debugf("Unable to determine the classpath strategy: %s", err)
+		return
+	}
+	n.Logger.Debugf("Classpath strategy: %s", strategy)
+
+	switch strategy {
+	case ClasspathDirectory:
+		n.Logger.Debugf("Using %s and the JARs of its library directory", n.ApplicationPath)
+	case ClasspathJar:
+		n.Logger.Debugf("Using the JAR matching %q and the Class-Path of its manifest", n.JarFilePattern)
+	default:
+		if os.Getenv("CLASSPATH") != "" {
+			n.Logger.Debug("Using the classpath of $CLASSPATH")
+		} else {
+			n.Logger.Debugf("Resolving the classpath from the manifest, ordering libraries by the %s", springBootLibraryOrder(n.ApplicationPath, n.Manifest))
+		}
+	}
+
+	cp, err := n.classpath()
+	if err != nil {
+		n.Logger.Debugf("Unable to resolve the classpath: %s", err)
+		return
+	}
+
+	n.Logger.Debug("Classpath entries:")
+	for _, e := range filepath.SplitList(cp) {
+		if containsPath(n.AdditionalClasspath, e) {
+			n.Logger.Debugf("  %s (additional)", e)
+		} else {
+			n.Logger.Debugf("  %s", e)
+		}
+	}
+
+	for _, e := range n.Excluded {
+		n.Logger.Debugf("Excluded from the classpath: %s", e)
+	}
+}
+
+// springBootLibraryOrder describes how the libraries of a Spring Boot application are ordered on the classpath
+func springBootLibraryOrder(applicationPath string, manifest *properties.Properties) string {
+	if manifest == nil {
+		return "file names"
+	}
+
+	if _, ok := manifest.Get("Spring-Boot-Lib"); !ok {
+		return "Class-Path attribute"
+	}
+
+	if entries, err := ReadClasspathIndex(applicationPath, manifest); err == nil && entries != nil {
+		return "classpath index"
+	}
+
+	lib, _ := manifest.Get("Spring-Boot-Lib")
+	if entries, err := ReadLayersIndex(applicationPath, manifest); err == nil {
+		for _, e := range entries {
+			if strings.HasPrefix(e, strings.TrimSuffix(lib, "/")+"/") {
+				return "layers index"
+			}
+		}
+	}
+
+	return "file names"
+}
@@ -0,0 +1,122 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/paketo-buildpacks/libpak/bard"
+)
+
+// DeniedArgumentsMetadataKey is the key of the deny-list in the buildpack metadata
+const DeniedArgumentsMetadataKey = "denied-arguments"
+
+const (
+	DeniedArgumentReject = "reject"
+	DeniedArgumentRemove = "remove"
+)
+
+// DeniedArgument is a native-image option known to break images built by the buildpack. Platform operators maintain
+// the list in the buildpack metadata.
+type DeniedArgument struct {
+	Argument string `toml:"argument"`
+	Action   string `toml:"action"`
+	Reason   string `toml:"reason"`
+}
+
+// Matches returns true if arg is the denied option, with or without a value
+func (d DeniedArgument) Matches(arg string) bool {
+	return arg == d.Argument || strings.HasPrefix(arg, d.Argument+"=")
+}
+
+// ParseDeniedArguments reads the deny-list from the buildpack metadata
+func ParseDeniedArguments(metadata map[string]interface{}) ([]DeniedArgument, error) {
+	var entries []map[string]interface{}
+
+	switch v := metadata[DeniedArgumentsMetadataKey].(type) {
+	case nil:
+		return nil, nil
+	case []map[string]interface{}:
+		entries = v
+	case []interface{}:
+		for _, e := range v {
+			m, ok := e.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("invalid %s entry %v", DeniedArgumentsMetadataKey, e)
+			}
+			entries = append(entries, m)
+		}
+	default:
+		return nil, fmt.Errorf("invalid %s %v", DeniedArgumentsMetadataKey, v)
+	}
+
+	var denied []DeniedArgument
+	for _, e := range entries {
+		d := DeniedArgument{Action: DeniedArgumentReject}
+		d.Argument, _ = e["argument"].(string)
+		d.Reason, _ = e["reason"].(string)
+		if a, ok := e["action"].(string); ok {
+			d.Action = a
+		}
+
+		if d.Argument == "" || (d.Action != DeniedArgumentReject && d.Action != DeniedArgumentRemove) {
+			return nil, fmt.Errorf("invalid %s entry %v, expected an argument and an action of %s or %s",
+				DeniedArgumentsMetadataKey, e, DeniedArgumentReject, DeniedArgumentRemove)
+		}
+
+		denied = append(denied, d)
+	}
+
+	return denied, nil
+}
+
+// DeniedArgumentsFilter rejects or removes arguments on the deny-list
+type DeniedArgumentsFilter struct {
+	Denied []DeniedArgument
+	Logger bard.Logger
+}
+
+// Filter returns arguments without the removed arguments, or fails if an argument is rejected
+func (d DeniedArgumentsFilter) Filter(arguments []string) ([]string, error) {
+	var filtered []string
+
+	for _, arg := range arguments {
+		denied, ok := d.find(arg)
+		if !ok {
+			filtered = append(filtered, arg)
+			continue
+		}
+
+		if denied.Action == DeniedArgumentReject {
+			return nil, fmt.Errorf("native-image argument %s is not allowed: %s", arg, denied.Reason)
+		}
+		d.Logger.Bodyf("Removing native-image argument %s: %s", arg, denied.Reason)
+	}
+
+	return filtered, nil
+}
+
+func (d DeniedArgumentsFilter) find(arg string) (DeniedArgument, bool) {
+	for _, denied := range d.Denied {
+		if denied.Matches(arg) {
+			return denied, true
+		}
+	}
+
+	return DeniedArgument{}, false
+}
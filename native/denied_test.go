@@ -0,0 +1,77 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/paketo-buildpacks/libpak/bard"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testDeniedArguments(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+	)
+
+	it("parses the deny-list", func() {
+		Expect(native.ParseDeniedArguments(map[string]interface{}{
+			"denied-arguments": []map[string]interface{}{
+				{"argument": "-H:Path", "reason": "test-reason"},
+				{"argument": "--pgo-instrument", "action": "remove"},
+			},
+		})).To(Equal([]native.DeniedArgument{
+			{Argument: "-H:Path", Action: "reject", Reason: "test-reason"},
+			{Argument: "--pgo-instrument", Action: "remove"},
+		}))
+
+		Expect(native.ParseDeniedArguments(map[string]interface{}{
+			"denied-arguments": []interface{}{map[string]interface{}{"argument": "-H:Path"}},
+		})).To(Equal([]native.DeniedArgument{{Argument: "-H:Path", Action: "reject"}}))
+
+		Expect(native.ParseDeniedArguments(map[string]interface{}{})).To(BeEmpty())
+	})
+
+	it("fails on invalid entries", func() {
+		_, err := native.ParseDeniedArguments(map[string]interface{}{
+			"denied-arguments": []map[string]interface{}{{"argument": "-H:Path", "action": "rewrite"}},
+		})
+		Expect(err).To(MatchError(ContainSubstring("expected an argument and an action of reject or remove")))
+	})
+
+	it("rejects denied arguments", func() {
+		_, err := native.DeniedArgumentsFilter{Denied: []native.DeniedArgument{
+			{Argument: "-H:Path", Action: "reject", Reason: "test-reason"},
+		}}.Filter([]string{"--no-fallback", "-H:Path=/tmp"})
+		Expect(err).To(MatchError("native-image argument -H:Path=/tmp is not allowed: test-reason"))
+	})
+
+	it("removes denied arguments", func() {
+		log := &bytes.Buffer{}
+
+		Expect(native.DeniedArgumentsFilter{
+			Denied: []native.DeniedArgument{{Argument: "--pgo-instrument", Action: "remove", Reason: "test-reason"}},
+			Logger: bard.NewLogger(log),
+		}.Filter([]string{"--pgo-instrument", "--pgo-instrumented", "--no-fallback"})).
+			To(Equal([]string{"--pgo-instrumented", "--no-fallback"}))
+		Expect(log.String()).To(ContainSubstring("Removing native-image argument --pgo-instrument: test-reason"))
+	})
+}
@@ -0,0 +1,236 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/magiconair/properties"
+)
+
+// DependencyDetector identifies the artifacts contained in a JAR. A shaded JAR may contain more than one artifact.
+type DependencyDetector interface {
+	Detect(path string) ([]Artifact, error)
+}
+
+// NewDependencyDetector creates the default detector, preferring pom.properties and falling back to the file name.
+// The manifest is not used, as its Implementation-Title is a display name rather than an artifactId.
+func NewDependencyDetector() DependencyDetector {
+	return CompositeDependencyDetector{
+		PomPropertiesDetector{},
+		FileNameDetector{},
+	}
+}
+
+// CompositeDependencyDetector returns the artifacts found by the first detector to find any
+type CompositeDependencyDetector []DependencyDetector
+
+func (c CompositeDependencyDetector) Detect(path string) ([]Artifact, error) {
+	for _, d := range c {
+		artifacts, err := d.Detect(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(artifacts) > 0 {
+			return artifacts, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// PomPropertiesDetector reads the META-INF/maven/<group>/<artifact>/pom.properties files in a JAR
+type PomPropertiesDetector struct{}
+
+func (PomPropertiesDetector) Detect(file string) ([]Artifact, error) {
+	z, err := openJAR(file)
+	if err != nil || z == nil {
+		return nil, err
+	}
+	defer z.Close()
+
+	var artifacts []Artifact
+	for _, f := range z.File {
+		if ok, _ := path.Match("META-INF/maven/*/*/pom.properties", f.Name); !ok {
+			continue
+		}
+
+		in, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("unable to open %s in %s\n%w", f.Name, file, err)
+		}
+		b, err := ioutil.ReadAll(in)
+		in.Close()
+		if err != nil {
+			return nil, fmt.Errorf("unable to read %s in %s\n%w", f.Name, file, err)
+		}
+
+		p, err := properties.Load(b, properties.UTF8)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse %s in %s\n%w", f.Name, file, err)
+		}
+
+		artifacts = append(artifacts, Artifact{
+			GroupID:    p.GetString("groupId", ""),
+			ArtifactID: p.GetString("artifactId", ""),
+			Version:    p.GetString("version", ""),
+			Path:       file,
+		})
+	}
+
+	sort.Slice(artifacts, func(i, j int) bool {
+		return artifacts[i].Coordinates() < artifacts[j].Coordinates()
+	})
+
+	return artifacts, nil
+}
+
+// FileNameDetector parses the Maven style file name of a JAR
+type FileNameDetector struct{}
+
+func (FileNameDetector) Detect(file string) ([]Artifact, error) {
+	if a, ok := ParseArtifact(file); ok {
+		return []Artifact{a}, nil
+	}
+
+	return nil, nil
+}
+
+// Dependency is the artifacts of a classpath entry
+type Dependency struct {
+	// Artifacts are the artifacts detected in the entry
+	Artifacts []Artifact
+
+	// Named is the artifact parsed from the Maven style file name of the entry, if any
+	Named *Artifact
+}
+
+// Dependencies are the dependencies of the entries of a classpath, in classpath order. Detection opens every JAR on the
+// classpath, so a build detects them once and shares them with every lookup.
+type Dependencies []Dependency
+
+// DetectDependencies detects the artifacts of each entry of a classpath
+func DetectDependencies(detector DependencyDetector, appPath string, entries []string) (Dependencies, error) {
+	var dependencies Dependencies
+
+	for _, entry := range entries {
+		artifacts, err := detector.Detect(filepath.Join(appPath, entry))
+		if err != nil {
+			return nil, fmt.Errorf("unable to detect dependencies in %s\n%w", entry, err)
+		}
+
+		d := Dependency{Artifacts: artifacts}
+		if a, ok := ParseArtifact(filepath.Join(appPath, entry)); ok {
+			d.Named = &a
+		}
+		dependencies = append(dependencies, d)
+	}
+
+	return dependencies, nil
+}
+
+// Find returns the first artifact on the classpath matching one of the groupId:artifactId coordinates
+func (d Dependencies) Find(coordinates ...string) (Artifact, bool) {
+	for _, dependency := range d {
+		for _, a := range dependency.Artifacts {
+			if a.MatchesAny(coordinates...) {
+				return a, true
+			}
+		}
+	}
+
+	return Artifact{}, false
+}
+
+// FindAll returns the first artifact of each classpath entry that matches one of the coordinates
+func (d Dependencies) FindAll(coordinates ...string) []Artifact {
+	var found []Artifact
+
+	for _, dependency := range d {
+		for _, a := range dependency.Artifacts {
+			if a.MatchesAny(coordinates...) {
+				found = append(found, a)
+				break
+			}
+		}
+	}
+
+	return found
+}
+
+// WithNames returns the dependencies with the artifact named by the file name of each entry added after those detected,
+// so that JARs without a pom.properties, such as those of Spring Boot, are also matched on their file name
+func (d Dependencies) WithNames() Dependencies {
+	named := make(Dependencies, len(d))
+
+	for i, dependency := range d {
+		named[i] = dependency
+		if dependency.Named != nil {
+			named[i].Artifacts = append(append([]Artifact{}, dependency.Artifacts...), *dependency.Named)
+		}
+	}
+
+	return named
+}
+
+// openJAR opens a JAR for reading, returning nil if the file does not exist or is not a JAR
+func openJAR(file string) (*zip.ReadCloser, error) {
+	z, err := zip.OpenReader(file)
+	if errors.Is(err, os.ErrNotExist) || errors.Is(err, zip.ErrFormat) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("unable to open %s\n%w", file, err)
+	}
+
+	return z, nil
+}
+
+// Coordinates returns the groupId:artifactId of the artifact
+func (a Artifact) Coordinates() string {
+	return strings.Join([]string{a.GroupID, a.ArtifactID}, ":")
+}
+
+// Matches returns true if the artifact has the groupId:artifactId coordinates. The group is not compared when it is
+// unknown, for example when the artifact was identified by its file name.
+func (a Artifact) Matches(coordinates string) bool {
+	group, artifact := "", coordinates
+	if i := strings.Index(coordinates, ":"); i >= 0 {
+		group, artifact = coordinates[:i], coordinates[i+1:]
+	}
+
+	return a.ArtifactID == artifact && (a.GroupID == "" || group == "" || a.GroupID == group)
+}
+
+// MatchesAny returns true if the artifact has any of the groupId:artifactId coordinates
+func (a Artifact) MatchesAny(coordinates ...string) bool {
+	for _, c := range coordinates {
+		if a.Matches(c) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,147 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"archive/zip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testDependency(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		appPath string
+
+		writeJAR = func(name string, files map[string]string) {
+			out, err := os.Create(filepath.Join(appPath, name))
+			Expect(err).NotTo(HaveOccurred())
+			defer out.Close()
+
+			z := zip.NewWriter(out)
+			for n, c := range files {
+				w, err := z.Create(n)
+				Expect(err).NotTo(HaveOccurred())
+				_, err = w.Write([]byte(c))
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Expect(z.Close()).To(Succeed())
+		}
+	)
+
+	it.Before(func() {
+		var err error
+
+		appPath, err = ioutil.TempDir("", "dependency-application")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(appPath)).To(Succeed())
+	})
+
+	it("detects a renamed JAR from pom.properties", func() {
+		writeJAR("renamed.jar", map[string]string{
+			"META-INF/maven/org.springframework.experimental/spring-native/pom.properties": "groupId=org.springframework.experimental\nartifactId=spring-native\nversion=0.11.2\n",
+		})
+
+		a, err := native.NewDependencyDetector().Detect(filepath.Join(appPath, "renamed.jar"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(a).To(Equal([]native.Artifact{{
+			GroupID:    "org.springframework.experimental",
+			ArtifactID: "spring-native",
+			Version:    "0.11.2",
+			Path:       filepath.Join(appPath, "renamed.jar"),
+		}}))
+	})
+
+	it("detects all artifacts in a shaded JAR", func() {
+		writeJAR("shaded-1.0.0.jar", map[string]string{
+			"META-INF/maven/com.example/shaded/pom.properties":                             "groupId=com.example\nartifactId=shaded\nversion=1.0.0\n",
+			"META-INF/maven/org.springframework.experimental/spring-native/pom.properties": "groupId=org.springframework.experimental\nartifactId=spring-native\nversion=0.11.2\n",
+		})
+
+		dependencies, err := native.DetectDependencies(native.NewDependencyDetector(), appPath, []string{"shaded-1.0.0.jar"})
+		Expect(err).NotTo(HaveOccurred())
+		a, ok := native.FindSpringNative(dependencies)
+		Expect(ok).To(BeTrue())
+		Expect(a.Version).To(Equal("0.11.2"))
+	})
+
+	it("does not take the manifest title for an artifactId", func() {
+		writeJAR("commons-lang3-3.12.0.jar", map[string]string{
+			"META-INF/MANIFEST.MF": "Manifest-Version: 1.0\nImplementation-Title: Apache Commons Lang\nImplementation-Version: 3.12.0\n",
+		})
+
+		a, err := native.NewDependencyDetector().Detect(filepath.Join(appPath, "commons-lang3-3.12.0.jar"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(a).To(HaveLen(1))
+		Expect(a[0].ArtifactID).To(Equal("commons-lang3"))
+		Expect(a[0].Version).To(Equal("3.12.0"))
+	})
+
+	it("detects each entry once for every lookup", func() {
+		detector := &countingDetector{DependencyDetector: native.NewDependencyDetector()}
+
+		dependencies, err := native.DetectDependencies(detector, appPath, []string{
+			"spring-boot-jarmode-layertools-3.1.0.jar",
+			"netty-transport-4.1.92.Final.jar",
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		_, ok := native.FindNetty(dependencies)
+		Expect(ok).To(BeTrue())
+		_, ok = native.FindSpringNative(dependencies)
+		Expect(ok).To(BeFalse())
+		Expect(native.FindBuildOnly(dependencies)).To(HaveLen(1))
+		Expect(detector.calls).To(Equal(2))
+	})
+
+	it("falls back to the file name", func() {
+		a, err := native.NewDependencyDetector().Detect(filepath.Join(appPath, "spring-native-0.11.2.jar"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(a).To(HaveLen(1))
+		Expect(a[0].ArtifactID).To(Equal("spring-native"))
+		Expect(a[0].GroupID).To(BeEmpty())
+	})
+
+	it("matches coordinates", func() {
+		Expect(native.Artifact{GroupID: "g", ArtifactID: "a"}.Matches("g:a")).To(BeTrue())
+		Expect(native.Artifact{GroupID: "g", ArtifactID: "a"}.Matches("other:a")).To(BeFalse())
+		Expect(native.Artifact{ArtifactID: "a"}.Matches("g:a")).To(BeTrue())
+		Expect(native.Artifact{ArtifactID: "a"}.Matches("a")).To(BeTrue())
+	})
+}
+
+type countingDetector struct {
+	native.DependencyDetector
+
+	calls int
+}
+
+func (c *countingDetector) Detect(path string) ([]native.Artifact, error) {
+	c.calls++
+	return c.DependencyDetector.Detect(path)
+}
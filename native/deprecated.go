@@ -0,0 +1,100 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/buildpacks/libcnb"
+	"github.com/paketo-buildpacks/libpak"
+	"github.com/paketo-buildpacks/libpak/bard"
+)
+
+// DeprecatedConfiguration is a configuration that has been renamed, which is still honored if its replacement is not
+// set so that existing pipelines keep working across upgrades
+type DeprecatedConfiguration struct {
+	Name        string
+	Replacement string
+
+	// Value replaces the value of the deprecated configuration, if its values have a different meaning
+	Value string
+}
+
+// DeprecatedConfigurations are the deprecated configurations of the buildpack
+var DeprecatedConfigurations = []DeprecatedConfiguration{
+	// any value of $BP_BOOT_NATIVE_IMAGE enabled the build
+	{Name: DeprecatedConfigNativeImage, Replacement: ConfigNativeImage, Value: "true"},
+	{Name: DeprecatedConfigNativeImageArgs, Replacement: ConfigNativeImageArgs},
+}
+
+// ConfigurationResolver resolves the configuration of the buildpack, falling back to the deprecated names of a
+// configuration
+type ConfigurationResolver struct {
+	libpak.ConfigurationResolver
+	Deprecated []DeprecatedConfiguration
+}
+
+// NewConfigurationResolver creates a resolver for the configuration of the buildpack and its deprecated names
+func NewConfigurationResolver(buildpack libcnb.Buildpack, logger *bard.Logger) (ConfigurationResolver, error) {
+	cr, err := libpak.NewConfigurationResolver(buildpack, logger)
+	if err != nil {
+		return ConfigurationResolver{}, err
+	}
+
+	return ConfigurationResolver{ConfigurationResolver: cr, Deprecated: DeprecatedConfigurations}, nil
+}
+
+// Resolve returns the value of a configuration. If it is not set, the value of a deprecated name of the
+// configuration is returned.
+func (c ConfigurationResolver) Resolve(name string) (string, bool) {
+	if v, ok := os.LookupEnv(name); ok {
+		return v, ok
+	}
+
+	for _, d := range c.Deprecated {
+		if d.Replacement != name {
+			continue
+		}
+
+		if v, ok := os.LookupEnv(d.Name); ok {
+			if d.Value != "" {
+				v = d.Value
+			}
+			return v, true
+		}
+	}
+
+	return c.ConfigurationResolver.Resolve(name)
+}
+
+// ResolveBool returns the value of a boolean configuration, or false if it cannot be parsed
+func (c ConfigurationResolver) ResolveBool(name string) bool {
+	v, _ := c.Resolve(name)
+	t, err := strconv.ParseBool(v)
+	return err == nil && t
+}
+
+// WarnDeprecated warns about every deprecated configuration that is set
+func (c ConfigurationResolver) WarnDeprecated(logger bard.Logger) {
+	for _, d := range c.Deprecated {
+		if _, ok := os.LookupEnv(d.Name); ok {
+			warn(logger, fmt.Sprintf("$%s has been deprecated. Please use $%s instead.", d.Name, d.Replacement))
+		}
+	}
+}
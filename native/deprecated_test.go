@@ -0,0 +1,98 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/paketo-buildpacks/libpak"
+	"github.com/paketo-buildpacks/libpak/bard"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testDeprecated(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		cr native.ConfigurationResolver
+	)
+
+	it.Before(func() {
+		cr = native.ConfigurationResolver{
+			ConfigurationResolver: libpak.ConfigurationResolver{Configurations: []libpak.BuildpackConfiguration{
+				{Name: "BP_NATIVE_IMAGE_BUILD_ARGUMENTS", Build: true},
+				{Name: "BP_BINARY_COMPRESSION_METHOD", Build: true, Default: "none"},
+			}},
+			Deprecated: native.DeprecatedConfigurations,
+		}
+	})
+
+	it.After(func() {
+		Expect(os.Unsetenv("BP_BOOT_NATIVE_IMAGE")).To(Succeed())
+		Expect(os.Unsetenv("BP_BOOT_NATIVE_IMAGE_BUILD_ARGUMENTS")).To(Succeed())
+		Expect(os.Unsetenv("BP_NATIVE_IMAGE_BUILD_ARGUMENTS")).To(Succeed())
+	})
+
+	it("resolves configuration and defaults", func() {
+		Expect(os.Setenv("BP_NATIVE_IMAGE_BUILD_ARGUMENTS", "--no-fallback")).To(Succeed())
+
+		v, ok := cr.Resolve("BP_NATIVE_IMAGE_BUILD_ARGUMENTS")
+		Expect(v).To(Equal("--no-fallback"))
+		Expect(ok).To(BeTrue())
+
+		v, ok = cr.Resolve("BP_BINARY_COMPRESSION_METHOD")
+		Expect(v).To(Equal("none"))
+		Expect(ok).To(BeFalse())
+	})
+
+	it("falls back to the deprecated name", func() {
+		Expect(os.Setenv("BP_BOOT_NATIVE_IMAGE_BUILD_ARGUMENTS", "--no-fallback")).To(Succeed())
+
+		v, ok := cr.Resolve("BP_NATIVE_IMAGE_BUILD_ARGUMENTS")
+		Expect(v).To(Equal("--no-fallback"))
+		Expect(ok).To(BeTrue())
+	})
+
+	it("prefers the replacement", func() {
+		Expect(os.Setenv("BP_BOOT_NATIVE_IMAGE_BUILD_ARGUMENTS", "--no-fallback")).To(Succeed())
+		Expect(os.Setenv("BP_NATIVE_IMAGE_BUILD_ARGUMENTS", "-Ob")).To(Succeed())
+
+		v, _ := cr.Resolve("BP_NATIVE_IMAGE_BUILD_ARGUMENTS")
+		Expect(v).To(Equal("-Ob"))
+	})
+
+	it("maps the value of the deprecated name", func() {
+		Expect(os.Setenv("BP_BOOT_NATIVE_IMAGE", "")).To(Succeed())
+
+		Expect(cr.ResolveBool("BP_NATIVE_IMAGE")).To(BeTrue())
+	})
+
+	it("warns about deprecated names", func() {
+		Expect(os.Setenv("BP_BOOT_NATIVE_IMAGE_BUILD_ARGUMENTS", "--no-fallback")).To(Succeed())
+
+		buf := &bytes.Buffer{}
+		cr.WarnDeprecated(bard.NewLogger(buf))
+
+		Expect(buf.String()).To(ContainSubstring("$BP_BOOT_NATIVE_IMAGE_BUILD_ARGUMENTS has been deprecated. Please use $BP_NATIVE_IMAGE_BUILD_ARGUMENTS instead."))
+		Expect(buf.String()).NotTo(ContainSubstring("$BP_BOOT_NATIVE_IMAGE has been deprecated"))
+	})
+}
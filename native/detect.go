@@ -21,25 +21,30 @@ import (
 	"strconv"
 
 	"github.com/buildpacks/libcnb"
-	"github.com/paketo-buildpacks/libpak"
+	"github.com/paketo-buildpacks/libpak/bard"
 )
 
 const (
 	ConfigNativeImage           = "BP_NATIVE_IMAGE"
 	DeprecatedConfigNativeImage = "BP_BOOT_NATIVE_IMAGE"
 	BinaryCompressionMethod     = "BP_BINARY_COMPRESSION_METHOD"
+	ConfigNativeImageVersion    = "BP_NATIVE_IMAGE_VERSION"
 
 	PlanEntryNativeImage        = "native-image-application"
 	PlanEntryNativeImageBuilder = "native-image-builder"
 	PlanEntryJVMApplication     = "jvm-application"
+	PlanEntryJRE                = "jre"
 	PlanEntrySpringBoot         = "spring-boot"
 	PlanEntryUpx                = "upx"
 )
 
-type Detect struct{}
+type Detect struct {
+	DependencyDetector DependencyDetector
+	Logger             bard.Logger
+}
 
 func (d Detect) Detect(context libcnb.DetectContext) (libcnb.DetectResult, error) {
-	cr, err := libpak.NewConfigurationResolver(context.Buildpack, nil)
+	cr, err := NewConfigurationResolver(context.Buildpack, nil)
 	if err != nil {
 		return libcnb.DetectResult{}, fmt.Errorf("unable to create configuration resolver\n%w", err)
 	}
@@ -86,9 +91,14 @@ func (d Detect) Detect(context libcnb.DetectContext) (libcnb.DetectResult, error
 		},
 	}
 
-	if ok, err := d.nativeImageEnabled(cr); err != nil {
+	if ok, set, err := d.nativeImageEnabled(cr); err != nil {
 		return libcnb.DetectResult{}, err
+	} else if set && !ok {
+		// opted out, even if an upstream buildpack such as spring-boot requests a native image
+		d.Logger.Debugf("$%s is false, not participating", ConfigNativeImage)
+		return libcnb.DetectResult{Pass: false}, nil
 	} else if ok {
+		d.Logger.Debugf("$%s is true, requiring a native image", ConfigNativeImage)
 		for i := range result.Plans {
 			result.Plans[i].Requires = append(result.Plans[i].Requires, libcnb.BuildPlanRequire{
 				Name: PlanEntryNativeImage,
@@ -96,7 +106,12 @@ func (d Detect) Detect(context libcnb.DetectContext) (libcnb.DetectResult, error
 		}
 	}
 
+	if err := d.requireBuilderVersion(context, cr, result.Plans); err != nil {
+		return libcnb.DetectResult{}, err
+	}
+
 	if d.upxCompressionEnabled(cr) {
+		d.Logger.Debugf("$%s is %s, requiring UPX", BinaryCompressionMethod, CompressorUpx)
 		for i := range result.Plans {
 			result.Plans[i].Requires = append(result.Plans[i].Requires, libcnb.BuildPlanRequire{
 				Name: PlanEntryUpx,
@@ -104,29 +119,94 @@ func (d Detect) Detect(context libcnb.DetectContext) (libcnb.DetectResult, error
 		}
 	}
 
+	// a hybrid image runs the application on the JVM as well
+	if cr.ResolveBool(ConfigNativeImageHybrid) {
+		d.Logger.Debugf("$%s is true, requiring a JRE at launch", ConfigNativeImageHybrid)
+		for i := range result.Plans {
+			result.Plans[i].Requires = append(result.Plans[i].Requires, libcnb.BuildPlanRequire{
+				Name:     PlanEntryJRE,
+				Metadata: map[string]interface{}{"launch": true},
+			})
+		}
+	}
+
 	// still participates if a downstream buildpack requires native-image-applications or upx
 	return result, nil
 }
 
-func (d Detect) upxCompressionEnabled(cr libpak.ConfigurationResolver) bool {
+// requireBuilderVersion constrains the version of the native-image-builder to the configured version or to the GraalVM
+// versions supported by the Spring Native release of the application
+func (d Detect) requireBuilderVersion(context libcnb.DetectContext, cr ConfigurationResolver, plans []libcnb.BuildPlan) error {
+	manifest, err := NewManifest(context.Application.Path)
+	if err != nil {
+		return fmt.Errorf("unable to read manifest in %s\n%w", context.Application.Path, err)
+	}
+
+	entries, err := ReadClasspathIndex(context.Application.Path, manifest)
+	if err != nil {
+		return fmt.Errorf("unable to read classpath index\n%w", err)
+	}
+
+	if d.DependencyDetector == nil {
+		d.DependencyDetector = NewDependencyDetector()
+	}
+
+	dependencies, err := DetectDependencies(d.DependencyDetector, context.Application.Path, entries)
+	if err != nil {
+		return err
+	}
+
+	var springNative *Artifact
+	if a, ok := FindSpringNative(dependencies); ok {
+		d.Logger.Debugf("Found %s %s", a.ArtifactID, a.Version)
+		springNative = &a
+	}
+
+	table, err := ParseCompatibility(context.Buildpack.Metadata)
+	if err != nil {
+		return fmt.Errorf("unable to read Spring Native compatibility\n%w", err)
+	}
+
+	configured, _ := cr.Resolve(ConfigNativeImageVersion)
+	version, source, err := BuilderRequirement(table, configured, springNative)
+	if err != nil {
+		return err
+	} else if version == "" {
+		d.Logger.Debug("Not constraining the native-image-builder version")
+		return nil
+	}
+	d.Logger.Debugf("Requiring native-image-builder version %s from %s", version, source)
+
+	for i := range plans {
+		for j := range plans[i].Requires {
+			if plans[i].Requires[j].Name == PlanEntryNativeImageBuilder {
+				plans[i].Requires[j].Metadata = map[string]interface{}{"version": version, "version-source": source}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (d Detect) upxCompressionEnabled(cr ConfigurationResolver) bool {
 	if val, ok := cr.Resolve(BinaryCompressionMethod); ok {
 		return val == CompressorUpx
 	}
 	return false
 }
 
-func (d Detect) nativeImageEnabled(cr libpak.ConfigurationResolver) (bool, error) {
+// nativeImageEnabled returns whether a native image is requested and whether the end user configured it at all
+func (d Detect) nativeImageEnabled(cr ConfigurationResolver) (bool, bool, error) {
 	if val, ok := cr.Resolve(ConfigNativeImage); ok {
 		enable, err := strconv.ParseBool(val)
 		if err != nil {
-			return false, fmt.Errorf(
+			return false, true, fmt.Errorf(
 				"invalid value '%s' for key '%s': expected one of [1, t, T, TRUE, true, True, 0, f, F, FALSE, false, False]",
 				val,
 				ConfigNativeImage,
 			)
 		}
-		return enable, nil
+		return enable, true, nil
 	}
-	_, ok := cr.Resolve(DeprecatedConfigNativeImage)
-	return ok, nil
+	return false, false, nil
 }
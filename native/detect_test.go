@@ -17,11 +17,15 @@
 package native_test
 
 import (
+	"bytes"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/buildpacks/libcnb"
 	. "github.com/onsi/gomega"
+	"github.com/paketo-buildpacks/libpak/bard"
 	"github.com/sclevine/spec"
 
 	"github.com/paketo-buildpacks/native-image/v5/native"
@@ -143,44 +147,16 @@ func testDetect(t *testing.T, context spec.G, it spec.S) {
 				Expect(os.Unsetenv("BP_NATIVE_IMAGE")).To(Succeed())
 			})
 
-			it("provides but does not requires native-image-application", func() {
-				Expect(detect.Detect(ctx)).To(Equal(libcnb.DetectResult{
-					Pass: true,
-					Plans: []libcnb.BuildPlan{
-						{
-							Provides: []libcnb.BuildPlanProvide{
-								{Name: "native-image-application"},
-							},
-							Requires: []libcnb.BuildPlanRequire{
-								{
-									Name: "native-image-builder",
-								},
-								{
-									Name:     "jvm-application",
-									Metadata: map[string]interface{}{"native-image": true},
-								},
-								{
-									Name:     "spring-boot",
-									Metadata: map[string]interface{}{"native-image": true},
-								},
-							},
-						},
-						{
-							Provides: []libcnb.BuildPlanProvide{
-								{Name: "native-image-application"},
-							},
-							Requires: []libcnb.BuildPlanRequire{
-								{
-									Name: "native-image-builder",
-								},
-								{
-									Name:     "jvm-application",
-									Metadata: map[string]interface{}{"native-image": true},
-								},
-							},
-						},
-					},
-				}))
+			it("does not participate, even if spring-native is requested", func() {
+				Expect(detect.Detect(ctx)).To(Equal(libcnb.DetectResult{Pass: false}))
+			})
+
+			it("logs why it does not participate in debug mode", func() {
+				b := &bytes.Buffer{}
+				detect.Logger = bard.NewLoggerWithOptions(b, bard.WithDebug(b))
+
+				Expect(detect.Detect(ctx)).To(Equal(libcnb.DetectResult{Pass: false}))
+				Expect(b.String()).To(ContainSubstring("$BP_NATIVE_IMAGE is false, not participating"))
 			})
 		})
 
@@ -440,6 +416,28 @@ func testDetect(t *testing.T, context spec.G, it spec.S) {
 		})
 	})
 
+	context("$BP_NATIVE_IMAGE_HYBRID", func() {
+		it.Before(func() {
+			Expect(os.Setenv("BP_NATIVE_IMAGE_HYBRID", "true")).To(Succeed())
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("BP_NATIVE_IMAGE_HYBRID")).To(Succeed())
+		})
+
+		it("requires a JRE at launch", func() {
+			result, err := detect.Detect(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			for _, p := range result.Plans {
+				Expect(p.Requires).To(ContainElement(libcnb.BuildPlanRequire{
+					Name:     "jre",
+					Metadata: map[string]interface{}{"launch": true},
+				}))
+			}
+		})
+	})
+
 	context("$BP_BOOT_NATIVE_IMAGE", func() {
 		it.Before(func() {
 			Expect(os.Setenv("BP_BOOT_NATIVE_IMAGE", "true")).To(Succeed())
@@ -495,4 +493,60 @@ func testDetect(t *testing.T, context spec.G, it spec.S) {
 			}))
 		})
 	})
+
+	context("native-image-builder version", func() {
+		it.Before(func() {
+			var err error
+			ctx.Application.Path, err = ioutil.TempDir("", "detect-application")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(os.MkdirAll(filepath.Join(ctx.Application.Path, "META-INF"), 0755)).To(Succeed())
+			Expect(os.MkdirAll(filepath.Join(ctx.Application.Path, "BOOT-INF"), 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"),
+				[]byte("Spring-Boot-Classpath-Index: BOOT-INF/classpath.idx"), 0644)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "BOOT-INF", "classpath.idx"),
+				[]byte(`- "BOOT-INF/lib/spring-native-0.11.2.jar"`), 0644)).To(Succeed())
+		})
+
+		it.After(func() {
+			Expect(os.RemoveAll(ctx.Application.Path)).To(Succeed())
+			ctx.Application.Path = ""
+		})
+
+		it("requires the GraalVM versions supported by Spring Native", func() {
+			result, err := detect.Detect(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			for _, p := range result.Plans {
+				Expect(p.Requires[0]).To(Equal(libcnb.BuildPlanRequire{
+					Name:     "native-image-builder",
+					Metadata: map[string]interface{}{"version": ">=21.3.0, <22.2.0", "version-source": "spring-native"},
+				}))
+			}
+		})
+
+		context("$BP_NATIVE_IMAGE_VERSION", func() {
+			it.After(func() {
+				Expect(os.Unsetenv("BP_NATIVE_IMAGE_VERSION")).To(Succeed())
+			})
+
+			it("requires the configured version", func() {
+				Expect(os.Setenv("BP_NATIVE_IMAGE_VERSION", "22.1.0")).To(Succeed())
+
+				result, err := detect.Detect(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Plans[0].Requires[0].Metadata).To(Equal(map[string]interface{}{
+					"version":        "22.1.0",
+					"version-source": "BP_NATIVE_IMAGE_VERSION",
+				}))
+			})
+
+			it("fails if Spring Native does not support the configured version", func() {
+				Expect(os.Setenv("BP_NATIVE_IMAGE_VERSION", "22.3.1")).To(Succeed())
+
+				_, err := detect.Detect(ctx)
+				Expect(err).To(MatchError(ContainSubstring("is not supported by spring-native 0.11.2")))
+			})
+		})
+	})
 }
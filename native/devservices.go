@@ -0,0 +1,31 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+// devServicesCoordinates are the coordinates of artifacts that start Docker Compose or Testcontainers services, or
+// otherwise support development only. They should never be part of a production image.
+var devServicesCoordinates = []string{
+	"org.springframework.boot:spring-boot-devtools",
+	"org.springframework.boot:spring-boot-docker-compose",
+	"org.springframework.boot:spring-boot-testcontainers",
+	"org.testcontainers:testcontainers",
+}
+
+// FindDevServices returns the development-time dev services artifacts in the dependencies of the classpath
+func FindDevServices(dependencies Dependencies) []Artifact {
+	return dependencies.FindAll(devServicesCoordinates...)
+}
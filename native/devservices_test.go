@@ -0,0 +1,69 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testDevServices(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+	)
+
+	it("finds dev services artifacts", func() {
+		dependencies, err := native.DetectDependencies(native.NewDependencyDetector(), "/workspace", []string{
+			"BOOT-INF/lib/spring-boot-3.1.0.jar",
+			"BOOT-INF/lib/spring-boot-docker-compose-3.1.0.jar",
+			"BOOT-INF/lib/testcontainers-1.18.3.jar",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		a := native.FindDevServices(dependencies)
+		Expect(a).To(HaveLen(2))
+		Expect(a[0].Path).To(Equal(filepath.Join("/workspace", "BOOT-INF/lib/spring-boot-docker-compose-3.1.0.jar")))
+		Expect(a[1].ArtifactID).To(Equal("testcontainers"))
+	})
+
+	it("does not find production artifacts", func() {
+		dependencies, err := native.DetectDependencies(native.NewDependencyDetector(), "/workspace", []string{
+			"BOOT-INF/lib/spring-boot-3.1.0.jar",
+			"BOOT-INF/lib/postgresql-42.6.0.jar",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		a := native.FindDevServices(dependencies)
+		Expect(a).To(BeEmpty())
+	})
+
+	it("finds build-only artifacts", func() {
+		dependencies, err := native.DetectDependencies(native.NewDependencyDetector(), "/workspace", []string{
+			"BOOT-INF/lib/spring-boot-3.1.0.jar",
+			"BOOT-INF/lib/spring-boot-jarmode-layertools-3.1.0.jar",
+			"BOOT-INF/lib/jacoco-agent-0.8.10.jar",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		a := native.FindBuildOnly(dependencies, "jacoco-agent")
+		Expect(a).To(HaveLen(2))
+		Expect(a[0].ArtifactID).To(Equal("spring-boot-jarmode-layertools"))
+		Expect(a[1].ArtifactID).To(Equal("jacoco-agent"))
+	})
+}
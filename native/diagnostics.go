@@ -0,0 +1,48 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/buildpacks/libcnb"
+)
+
+// NativeImageLog is the name of the file in the diagnostics layer holding the output of native-image
+const NativeImageLog = "native-image.log"
+
+// Diagnostics contributes a cached layer holding diagnostic output of the native-image build, so that it can be
+// extracted from the cache and attached to bug reports.
+type Diagnostics struct {
+	// Export also exports the layer in the application image, where it can be read without access to the cache
+	Export bool
+}
+
+func (d Diagnostics) Contribute(layer libcnb.Layer) (libcnb.Layer, error) {
+	if err := os.MkdirAll(layer.Path, 0755); err != nil {
+		return libcnb.Layer{}, fmt.Errorf("unable to create %s\n%w", layer.Path, err)
+	}
+
+	layer.Cache = true
+	layer.Launch = d.Export
+	return layer, nil
+}
+
+func (Diagnostics) Name() string {
+	return "diagnostics"
+}
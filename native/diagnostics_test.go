@@ -0,0 +1,69 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/buildpacks/libcnb"
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testDiagnostics(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		ctx libcnb.BuildContext
+	)
+
+	it.Before(func() {
+		var err error
+
+		ctx.Layers.Path, err = ioutil.TempDir("", "diagnostics-layers")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(ctx.Layers.Path)).To(Succeed())
+	})
+
+	it("contributes a cached layer", func() {
+		layer, err := ctx.Layers.Layer("diagnostics")
+		Expect(err).NotTo(HaveOccurred())
+
+		layer, err = native.Diagnostics{}.Contribute(layer)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(layer.LayerTypes).To(Equal(libcnb.LayerTypes{Cache: true}))
+		Expect(layer.Path).To(BeADirectory())
+	})
+
+	it("exports the layer in the application image", func() {
+		layer, err := ctx.Layers.Layer("diagnostics")
+		Expect(err).NotTo(HaveOccurred())
+
+		layer, err = native.Diagnostics{Export: true}.Contribute(layer)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(layer.LayerTypes).To(Equal(libcnb.LayerTypes{Cache: true, Launch: true}))
+	})
+}
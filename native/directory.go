@@ -0,0 +1,104 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/buildpacks/libcnb"
+)
+
+// DefaultLibDirectory is the directory of the libraries of a plain directory application, as laid out by the
+// installDist task of Gradle
+const DefaultLibDirectory = "lib"
+
+// DirectoryArguments provides a set of arguments specific to building from a plain directory of classes and a library
+// directory, which has neither a manifest nor a JAR to read the start class from
+type DirectoryArguments struct {
+	AdditionalClasspath []string
+	ApplicationPath     string
+	Excluded            []string
+	LayerPath           string
+	LibDirectory        string
+	StartClass          string
+	// LegacyMainClass passes the start class as the trailing argument instead of with -H:Class
+	LegacyMainClass bool
+}
+
+// Configure appends arguments to inputArgs for building from a plain directory
+func (d DirectoryArguments) Configure(inputArgs []string) ([]string, string, error) {
+	if d.StartClass == "" {
+		return []string{}, "", fmt.Errorf("$%s must be set to build a directory without a manifest\n%w",
+			ConfigNativeImageStartClass, NoStartOrMainClass{})
+	}
+
+	cp, err := DirectoryClasspath(d.ApplicationPath, d.LibDirectory)
+	if err != nil {
+		return []string{}, "", err
+	}
+
+	var entries []string
+	for _, entry := range cp {
+		if !containsPath(d.Excluded, entry) {
+			entries = append(entries, entry)
+		}
+	}
+
+	inputArgs = append(inputArgs,
+		fmt.Sprintf("-H:Name=%s", filepath.Join(d.LayerPath, d.StartClass)),
+		"-cp", appendClasspath(strings.Join(entries, string(filepath.ListSeparator)), d.AdditionalClasspath),
+	)
+	inputArgs = append(inputArgs, EntryPointArguments(d.StartClass, d.LegacyMainClass)...)
+
+	return inputArgs, d.StartClass, nil
+}
+
+// DirectoryClasspath returns the classpath of a plain directory application: the application directory, holding the
+// classes and resources, followed by the JARs of the library directory in file name order. The library directory
+// defaults to DefaultLibDirectory and may be missing.
+func DirectoryClasspath(applicationPath string, libDirectory string) ([]string, error) {
+	if libDirectory == "" {
+		libDirectory = DefaultLibDirectory
+	}
+
+	jars, err := filepath.Glob(filepath.Join(applicationPath, libDirectory, "*.jar"))
+	if err != nil {
+		return nil, fmt.Errorf("unable to list %s\n%w", libDirectory, err)
+	}
+	sort.Strings(jars)
+
+	return NormalizeClasspath(append([]string{applicationPath}, jars...)), nil
+}
+
+// DirectoryJVMProcess returns the process running a preserved plain directory application on the JVM, for hybrid
+// images
+func DirectoryJVMProcess(applicationPath string, libDirectory string, startClass string) (libcnb.Process, error) {
+	cp, err := DirectoryClasspath(applicationPath, libDirectory)
+	if err != nil {
+		return libcnb.Process{}, err
+	}
+
+	return libcnb.Process{
+		Type:      ProcessTypeJVM,
+		Command:   "java",
+		Arguments: []string{"-cp", strings.Join(cp, string(filepath.ListSeparator)), startClass},
+		Direct:    true,
+	}, nil
+}
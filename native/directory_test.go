@@ -0,0 +1,109 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/buildpacks/libcnb"
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testDirectory(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		appPath string
+	)
+
+	it.Before(func() {
+		var err error
+		appPath, err = ioutil.TempDir("", "directory")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(os.MkdirAll(filepath.Join(appPath, "lib"), 0755)).To(Succeed())
+		for _, j := range []string{"b.jar", "a.jar", "notes.txt"} {
+			Expect(ioutil.WriteFile(filepath.Join(appPath, "lib", j), []byte{}, 0644)).To(Succeed())
+		}
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(appPath)).To(Succeed())
+	})
+
+	context("DirectoryClasspath", func() {
+		it("lists the application directory and the JARs of the library directory", func() {
+			Expect(native.DirectoryClasspath(appPath, "")).To(Equal([]string{
+				appPath,
+				filepath.Join(appPath, "lib", "a.jar"),
+				filepath.Join(appPath, "lib", "b.jar"),
+			}))
+		})
+
+		it("tolerates a missing library directory", func() {
+			Expect(native.DirectoryClasspath(appPath, "libs")).To(Equal([]string{appPath}))
+		})
+	})
+
+	context("DirectoryArguments", func() {
+		it("builds the start class from the directory classpath", func() {
+			arguments, startClass, err := native.DirectoryArguments{
+				AdditionalClasspath: []string{"/extra"},
+				ApplicationPath:     appPath,
+				Excluded:            []string{filepath.Join(appPath, "lib", "b.jar")},
+				LayerPath:           "/layer",
+				StartClass:          "com.example.App",
+			}.Configure([]string{"--no-fallback"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(startClass).To(Equal("com.example.App"))
+			Expect(arguments).To(Equal([]string{
+				"--no-fallback",
+				"-H:Name=/layer/com.example.App",
+				"-cp", strings.Join([]string{appPath, filepath.Join(appPath, "lib", "a.jar"), "/extra"}, string(filepath.ListSeparator)),
+				"-H:Class=com.example.App",
+			}))
+		})
+
+		it("requires a start class", func() {
+			_, _, err := native.DirectoryArguments{ApplicationPath: appPath}.Configure(nil)
+			Expect(errors.As(err, &native.NoStartOrMainClass{})).To(BeTrue())
+			Expect(err).To(MatchError(ContainSubstring("BP_NATIVE_IMAGE_START_CLASS")))
+		})
+	})
+
+	it("runs the directory on the JVM", func() {
+		Expect(native.DirectoryJVMProcess(appPath, "lib", "com.example.App")).To(Equal(libcnb.Process{
+			Type:    "web-jvm",
+			Command: "java",
+			Arguments: []string{"-cp", strings.Join([]string{
+				appPath,
+				filepath.Join(appPath, "lib", "a.jar"),
+				filepath.Join(appPath, "lib", "b.jar"),
+			}, string(filepath.ListSeparator)), "com.example.App"},
+			Direct: true,
+		}))
+	})
+}
@@ -0,0 +1,49 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"strings"
+)
+
+// DefaultCommand is the native-image command of GraalVM distributions
+const DefaultCommand = "native-image"
+
+const (
+	DistributionGraalVMCE     = "graalvm-ce"
+	DistributionOracleGraalVM = "oracle-graalvm"
+	DistributionMandrel       = "mandrel"
+	DistributionLibericaNIK   = "liberica-nik"
+	DistributionUnknown       = "unknown"
+)
+
+// DetectDistribution returns the GraalVM distribution providing native-image from the output of native-image
+// --version, e.g. `native-image 22.3.1.0-Final Mandrel Distribution (Java Version 17.0.6+10)`
+func DetectDistribution(versionOutput string) string {
+	switch {
+	case strings.Contains(versionOutput, "Mandrel"):
+		return DistributionMandrel
+	case strings.Contains(versionOutput, "Liberica"):
+		return DistributionLibericaNIK
+	case strings.Contains(versionOutput, "Oracle GraalVM"), strings.Contains(versionOutput, " EE"):
+		return DistributionOracleGraalVM
+	case strings.Contains(versionOutput, "GraalVM"):
+		return DistributionGraalVMCE
+	default:
+		return DistributionUnknown
+	}
+}
@@ -0,0 +1,51 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testDistribution(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+	)
+
+	it("detects GraalVM distributions", func() {
+		Expect(native.DetectDistribution("GraalVM 22.3.0 Java 17 CE (Java Version 17.0.5+8-jvmci-22.3-b08)")).
+			To(Equal("graalvm-ce"))
+		Expect(native.DetectDistribution("native-image 17.0.7 2023-04-18\nGraalVM Runtime Environment GraalVM CE 17.0.7+7.1 (build 17.0.7+7-jvmci-23.0-b12)")).
+			To(Equal("graalvm-ce"))
+		Expect(native.DetectDistribution("GraalVM 22.3.0 Java 17 EE (Java Version 17.0.5+9-LTS-jvmci-22.3-b07)")).
+			To(Equal("oracle-graalvm"))
+		Expect(native.DetectDistribution("native-image 17.0.8 2023-07-18\nOracle GraalVM 17.0.8+9.1 (build 17.0.8+9-LTS-jvmci-23.0-b14)")).
+			To(Equal("oracle-graalvm"))
+		Expect(native.DetectDistribution("native-image 22.3.1.0-Final Mandrel Distribution (Java Version 17.0.6+10)")).
+			To(Equal("mandrel"))
+		Expect(native.DetectDistribution("GraalVM 22.3.2 Java 17 CE (Java Version 17.0.7+7-LTS)\nLiberica-NIK-22.3.2-1")).
+			To(Equal("liberica-nik"))
+	})
+
+	it("does not detect other native-image commands", func() {
+		Expect(native.DetectDistribution("1.2.3")).To(Equal("unknown"))
+	})
+}
@@ -0,0 +1,93 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/buildpacks/libcnb"
+	"github.com/paketo-buildpacks/libpak/bindings"
+)
+
+// EnterpriseBindingType is the type of bindings accepting the GraalVM Enterprise license
+const EnterpriseBindingType = "graalvm-ee"
+
+// EnterpriseTokenEnv is the environment variable the GraalVM Enterprise download token is passed to native-image and
+// gu in
+const EnterpriseTokenEnv = "GRAAL_EE_DOWNLOAD_TOKEN"
+
+// enterpriseArguments are native-image options only GraalVM Enterprise supports
+var enterpriseArguments = []string{
+	"--gc=G1",
+	"--pgo",
+	"--pgo-instrument",
+}
+
+// Enterprise is the GraalVM Enterprise license of a graalvm-ee binding
+type Enterprise struct {
+	Token string
+}
+
+// FindEnterpriseLicense returns the license of the graalvm-ee binding, which must set license-accepted to true and
+// may hold a download token. Returns nil if there is no such binding.
+func FindEnterpriseLicense(binds libcnb.Bindings) (*Enterprise, error) {
+	bs := bindings.Resolve(binds, bindings.OfType(EnterpriseBindingType))
+	if len(bs) == 0 {
+		return nil, nil
+	}
+	b := bs[0]
+
+	if strings.TrimSpace(b.Secret["license-accepted"]) != "true" {
+		return nil, fmt.Errorf("binding %s of type %s must set license-accepted to true to accept the GraalVM Enterprise license",
+			b.Name, EnterpriseBindingType)
+	}
+
+	return &Enterprise{Token: strings.TrimSpace(b.Secret["token"])}, nil
+}
+
+// Environment adds the download token to the native-image process environment. A nil env inherits the build
+// environment.
+func (e Enterprise) Environment(env []string) []string {
+	if e.Token == "" {
+		return env
+	}
+
+	if env == nil {
+		env = os.Environ()
+	}
+	return append(append([]string{}, env...), fmt.Sprintf("%s=%s", EnterpriseTokenEnv, e.Token))
+}
+
+// CheckEnterpriseArguments fails if arguments only GraalVM Enterprise supports are used without accepting its license
+func CheckEnterpriseArguments(arguments []string, licensed bool) error {
+	if licensed {
+		return nil
+	}
+
+	for _, a := range arguments {
+		for _, e := range enterpriseArguments {
+			if a == e || (!strings.Contains(e, "=") && strings.HasPrefix(a, e+"=")) {
+				return fmt.Errorf("native-image argument %s requires GraalVM Enterprise, add a binding of type %s accepting its license",
+					a, EnterpriseBindingType)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"testing"
+
+	"github.com/buildpacks/libcnb"
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testEnterprise(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+	)
+
+	context("FindEnterpriseLicense", func() {
+		it("reads the license from a binding", func() {
+			Expect(native.FindEnterpriseLicense(libcnb.Bindings{
+				{Name: "ee", Type: "graalvm-ee", Secret: map[string]string{"license-accepted": "true\n", "token": "test-token"}},
+			})).To(Equal(&native.Enterprise{Token: "test-token"}))
+		})
+
+		it("fails when the license is not accepted", func() {
+			_, err := native.FindEnterpriseLicense(libcnb.Bindings{
+				{Name: "ee", Type: "graalvm-ee", Secret: map[string]string{"token": "test-token"}},
+			})
+			Expect(err).To(MatchError("binding ee of type graalvm-ee must set license-accepted to true to accept the GraalVM Enterprise license"))
+		})
+
+		it("returns nil without a binding", func() {
+			Expect(native.FindEnterpriseLicense(nil)).To(BeNil())
+		})
+	})
+
+	it("adds the token to the environment", func() {
+		Expect(native.Enterprise{Token: "test-token"}.Environment([]string{"PATH=/bin"})).
+			To(Equal([]string{"PATH=/bin", "GRAAL_EE_DOWNLOAD_TOKEN=test-token"}))
+		Expect(native.Enterprise{Token: "test-token"}.Environment(nil)).To(ContainElement("GRAAL_EE_DOWNLOAD_TOKEN=test-token"))
+		Expect(native.Enterprise{}.Environment(nil)).To(BeNil())
+	})
+
+	context("CheckEnterpriseArguments", func() {
+		it("fails on enterprise arguments without a license", func() {
+			Expect(native.CheckEnterpriseArguments([]string{"--no-fallback", "--pgo=default.iprof"}, false)).
+				To(MatchError("native-image argument --pgo=default.iprof requires GraalVM Enterprise, add a binding of type graalvm-ee accepting its license"))
+			Expect(native.CheckEnterpriseArguments([]string{"--gc=G1"}, false)).To(HaveOccurred())
+		})
+
+		it("allows enterprise arguments with a license", func() {
+			Expect(native.CheckEnterpriseArguments([]string{"--pgo-instrument", "--gc=G1"}, true)).To(Succeed())
+		})
+
+		it("allows other arguments", func() {
+			Expect(native.CheckEnterpriseArguments([]string{"--gc=serial", "--pgo-other"}, false)).To(Succeed())
+		})
+	})
+}
@@ -0,0 +1,125 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// EntryPointArguments returns the arguments naming the main class of a native image, -H:Class or, for native-image
+// releases that predate it, the class as the trailing argument
+func EntryPointArguments(class string, legacy bool) []string {
+	if legacy {
+		return []string{class}
+	}
+
+	return []string{fmt.Sprintf("-H:Class=%s", class)}
+}
+
+// StartClassNotFoundError is returned when the start class of a native image is not on its classpath
+type StartClassNotFoundError struct {
+	StartClass string
+	Similar    []string
+}
+
+func (e StartClassNotFoundError) Error() string {
+	msg := fmt.Sprintf("start class %s is not on the classpath, check the Start-Class of the manifest or $%s",
+		e.StartClass, ConfigNativeImageStartClass)
+	if len(e.Similar) > 0 {
+		msg += fmt.Sprintf(". Did you mean %s?", strings.Join(e.Similar, " or "))
+	}
+	return msg
+}
+
+// CheckStartClass fails with a StartClassNotFoundError if class is in none of the classpath entries, which may be JARs
+// or directories. The error suggests classes of the same simple name, ignoring case. A classpath none of whose entries
+// exist, such as one set by $CLASSPATH for another file system, is not checked.
+func CheckStartClass(classpath []string, class string) error {
+	checked := false
+	file := strings.ReplaceAll(class, ".", "/") + ".class"
+	simple := strings.ToLower(simpleClassName(class))
+
+	var similar []string
+	suggest := func(name string) {
+		// packages cannot contain dashes, which excludes the classes of BOOT-INF and META-INF/versions
+		if strings.HasSuffix(name, ".class") && !strings.ContainsAny(name, "$-") &&
+			strings.ToLower(simpleClassName(strings.TrimSuffix(name, ".class"))) == simple {
+			similar = append(similar, strings.ReplaceAll(strings.TrimSuffix(name, ".class"), "/", "."))
+		}
+	}
+
+	for _, entry := range classpath {
+		fi, err := os.Stat(entry)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return fmt.Errorf("unable to stat %s\n%w", entry, err)
+		}
+		checked = true
+
+		if fi.IsDir() {
+			if _, err := os.Stat(filepath.Join(entry, filepath.FromSlash(file))); err == nil {
+				return nil
+			}
+			if err := filepath.Walk(entry, func(path string, info os.FileInfo, err error) error {
+				if err != nil {
+					return err
+				}
+				if rel, err := filepath.Rel(entry, path); err == nil && !info.IsDir() {
+					suggest(filepath.ToSlash(rel))
+				}
+				return nil
+			}); err != nil {
+				return fmt.Errorf("unable to walk %s\n%w", entry, err)
+			}
+			continue
+		}
+
+		z, err := openJAR(entry)
+		if err != nil {
+			return err
+		} else if z == nil {
+			continue
+		}
+		for _, f := range z.File {
+			if f.Name == file {
+				z.Close()
+				return nil
+			}
+			suggest(f.Name)
+		}
+		z.Close()
+	}
+
+	if !checked {
+		return nil
+	}
+
+	sort.Strings(similar)
+	return StartClassNotFoundError{StartClass: class, Similar: similar}
+}
+
+func simpleClassName(class string) string {
+	if i := strings.LastIndexAny(class, "./"); i >= 0 {
+		return class[i+1:]
+	}
+	return class
+}
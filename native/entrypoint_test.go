@@ -0,0 +1,92 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package native_test
+
+import (
+	"archive/zip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testEntryPoint(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		appPath string
+	)
+
+	it.Before(func() {
+		var err error
+		appPath, err = ioutil.TempDir("", "entry-point")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(os.MkdirAll(filepath.Join(appPath, "BOOT-INF", "classes", "com", "example"), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(appPath, "BOOT-INF", "classes", "com", "example", "Application.class"), []byte{}, 0644)).To(Succeed())
+
+		out, err := os.Create(filepath.Join(appPath, "dep.jar"))
+		Expect(err).NotTo(HaveOccurred())
+		z := zip.NewWriter(out)
+		_, err = z.Create("org/example/Main.class")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = z.Create("org/example/other/Application.class")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(z.Close()).To(Succeed())
+		Expect(out.Close()).To(Succeed())
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(appPath)).To(Succeed())
+	})
+
+	it("names the entry point with -H:Class", func() {
+		Expect(native.EntryPointArguments("com.example.Application", false)).To(Equal([]string{"-H:Class=com.example.Application"}))
+		Expect(native.EntryPointArguments("com.example.Application", true)).To(Equal([]string{"com.example.Application"}))
+	})
+
+	context("CheckStartClass", func() {
+		var classpath []string
+
+		it.Before(func() {
+			classpath = []string{appPath, filepath.Join(appPath, "BOOT-INF", "classes"), filepath.Join(appPath, "dep.jar")}
+		})
+
+		it("finds classes in directories and JARs", func() {
+			Expect(native.CheckStartClass(classpath, "com.example.Application")).To(Succeed())
+			Expect(native.CheckStartClass(classpath, "org.example.Main")).To(Succeed())
+		})
+
+		it("suggests classes of the same simple name", func() {
+			Expect(native.CheckStartClass(classpath, "com.exmaple.application")).To(MatchError(
+				"start class com.exmaple.application is not on the classpath, check the Start-Class of the manifest or " +
+					"$BP_NATIVE_IMAGE_START_CLASS. Did you mean com.example.Application or org.example.other.Application?"))
+		})
+
+		it("fails without suggestions", func() {
+			Expect(native.CheckStartClass(classpath, "com.example.Missing")).To(MatchError(native.StartClassNotFoundError{StartClass: "com.example.Missing"}))
+		})
+
+		it("does not check a classpath that does not exist", func() {
+			Expect(native.CheckStartClass([]string{filepath.Join(appPath, "missing")}, "com.example.Missing")).To(Succeed())
+		})
+	})
+}
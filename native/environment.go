@@ -0,0 +1,103 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/paketo-buildpacks/libpak/bard"
+)
+
+// DefaultEnvironment are the names of the build environment variables passed through to native-image
+var DefaultEnvironment = []string{
+	"PATH",
+	"HOME",
+	"JAVA_HOME",
+	"GRAALVM_HOME",
+	"LD_LIBRARY_PATH",
+	"LANG",
+	"LC_ALL",
+	"TMPDIR",
+	"SOURCE_DATE_EPOCH",
+	"USE_NATIVE_IMAGE_JAVA_PLATFORM_MODULE_SYSTEM",
+	"http_proxy",
+	"https_proxy",
+	"no_proxy",
+	"HTTP_PROXY",
+	"HTTPS_PROXY",
+	"NO_PROXY",
+}
+
+// ParseEnvironment parses a comma separated list of environment variables. Each entry is either the name of a
+// variable to pass through from the build environment, or a NAME=VALUE pair to set.
+func ParseEnvironment(value string) []string {
+	var entries []string
+
+	for _, entry := range strings.Split(value, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries
+}
+
+// Environment builds a sorted process environment from the allowed entries, taking the values of names from environ.
+// Explicit NAME=VALUE entries take precedence over values from environ.
+func Environment(allowed []string, environ []string) []string {
+	inherited := map[string]string{}
+	for _, e := range environ {
+		if parts := strings.SplitN(e, "=", 2); len(parts) == 2 {
+			inherited[parts[0]] = parts[1]
+		}
+	}
+
+	values := map[string]string{}
+	for _, a := range allowed {
+		if parts := strings.SplitN(a, "=", 2); len(parts) == 2 {
+			values[parts[0]] = parts[1]
+		} else if v, ok := inherited[a]; ok {
+			if _, set := values[a]; !set {
+				values[a] = v
+			}
+		}
+	}
+
+	var env []string
+	for k, v := range values {
+		env = append(env, k+"="+v)
+	}
+	sort.Strings(env)
+
+	return env
+}
+
+// LogEnvironment prints the process environment, redacting secrets and proxy credentials
+func LogEnvironment(logger bard.Logger, env []string) {
+	logger.Body("native-image environment:")
+	for _, e := range RedactArguments(env) {
+		if parts := strings.SplitN(e, "=", 2); len(parts) == 2 && strings.HasSuffix(strings.ToLower(parts[0]), "_proxy") {
+			if u, err := url.Parse(parts[1]); err == nil && u.User != nil {
+				u.User = url.User(Redacted)
+				e = parts[0] + "=" + u.String()
+			}
+		}
+		logger.Bodyf("  %s", e)
+	}
+}
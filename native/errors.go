@@ -0,0 +1,157 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// ClasspathIndexError is returned when the Spring Boot classpath index of the application cannot be read
+type ClasspathIndexError struct {
+	File string
+	Err  error
+}
+
+func (e ClasspathIndexError) Error() string {
+	return fmt.Sprintf("unable to read %s\n%s", e.File, e.Err)
+}
+
+func (e ClasspathIndexError) Unwrap() error {
+	return e.Err
+}
+
+// NativeImageUnavailableError is returned when the native-image command cannot be run, and the native-image component
+// was not or could not be installed
+type NativeImageUnavailableError struct {
+	Command string
+	Err     error
+}
+
+func (e NativeImageUnavailableError) Error() string {
+	return fmt.Sprintf("error running version\n%s", e.Err)
+}
+
+func (e NativeImageUnavailableError) Unwrap() error {
+	return e.Err
+}
+
+// IncompatibleGraalVMError is returned when the GraalVM version providing native-image is not supported by the Spring
+// Native release of the application
+type IncompatibleGraalVMError struct {
+	SpringNative Artifact
+	Supported    string
+	GraalVM      string
+}
+
+func (e IncompatibleGraalVMError) Error() string {
+	return fmt.Sprintf("%s %s requires GraalVM %s, but found GraalVM %s. Set $%s to a supported version, or upgrade %s to a release supporting GraalVM %s.",
+		e.SpringNative.ArtifactID, e.SpringNative.Version, e.Supported, e.GraalVM, ConfigNativeImageVersion, e.SpringNative.ArtifactID, e.GraalVM)
+}
+
+// NativeImageExitError is returned when native-image fails. ExitCode is -1 if native-image did not exit on its own,
+// and LogPath is the log holding its full output, if diagnostics are enabled.
+type NativeImageExitError struct {
+	ExitCode  int
+	LogPath   string
+	Diagnoses []Diagnosis
+	Err       error
+}
+
+// NewNativeImageExitError creates a NativeImageExitError, taking the exit code from err
+func NewNativeImageExitError(err error, logPath string, diagnoses []Diagnosis) NativeImageExitError {
+	code := -1
+	var exit *exec.ExitError
+	if errors.As(err, &exit) {
+		code = exit.ExitCode()
+	}
+
+	return NativeImageExitError{ExitCode: code, LogPath: logPath, Diagnoses: diagnoses, Err: err}
+}
+
+func (e NativeImageExitError) Error() string {
+	return fmt.Sprintf("error running build\n%s", e.Err)
+}
+
+func (e NativeImageExitError) Unwrap() error {
+	return e.Err
+}
+
+// FallbackImageError is returned when native-image produces a fallback image, which requires a JVM at runtime that
+// native images are not launched with
+type FallbackImageError struct {
+	Image string
+}
+
+func (e FallbackImageError) Error() string {
+	return fmt.Sprintf("native-image produced %s as a fallback image, which requires a JDK at runtime. Fix the errors reported above, "+
+		"or set $%s to true to accept a fallback image.", e.Image, ConfigNativeImageAllowFallback)
+}
+
+// ImageSizeError is returned when the native image is larger than the size budget
+type ImageSizeError struct {
+	Image   string
+	Size    int64
+	MaxSize int64
+}
+
+func (e ImageSizeError) Error() string {
+	return fmt.Sprintf("native image %s of %s exceeds the maximum size of %s set with $%s",
+		e.Image, formatMegabytes(e.Size), formatMegabytes(e.MaxSize), ConfigNativeImageMaxSize)
+}
+
+// PhaseThresholdError is returned when native-image phases take longer than their thresholds and the thresholds fail
+// the build
+type PhaseThresholdError struct {
+	Violations []ThresholdViolation
+}
+
+func (e PhaseThresholdError) Error() string {
+	var s []string
+	for _, v := range e.Violations {
+		s = append(s, v.String())
+	}
+
+	return fmt.Sprintf("native-image exceeded the thresholds set with $%s: %s", ConfigNativeImagePhaseLimits, strings.Join(s, "; "))
+}
+
+// PreflightError is returned when the checks before compiling find problems, which are reported at once instead of
+// one native-image failure at a time
+type PreflightError struct {
+	Problems []string
+}
+
+func (e PreflightError) Error() string {
+	return fmt.Sprintf("unable to build the native image:\n  * %s", strings.Join(e.Problems, "\n  * "))
+}
+
+// OfflineError is returned when an offline build lacks artifacts that it would otherwise download, listing all of them
+type OfflineError struct {
+	Missing []OfflineArtifact
+}
+
+func (e OfflineError) Error() string {
+	var s []string
+	for _, m := range e.Missing {
+		s = append(s, m.String())
+	}
+
+	return fmt.Sprintf("$%s is set, but the build requires artifacts it would download:\n  * %s",
+		ConfigNativeImageOffline, strings.Join(s, "\n  * "))
+}
@@ -0,0 +1,78 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/magiconair/properties"
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testErrors(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+	)
+
+	context("NativeImageExitError", func() {
+		it("takes the exit code from the error", func() {
+			err := exec.Command("sh", "-c", "exit 3").Run()
+
+			e := native.NewNativeImageExitError(fmt.Errorf("wrapped\n%w", err), "/diagnostics/native-image.log", nil)
+			Expect(e.ExitCode).To(Equal(3))
+			Expect(e.LogPath).To(Equal("/diagnostics/native-image.log"))
+			Expect(e).To(MatchError("error running build\nwrapped\nexit status 3"))
+			Expect(errors.Unwrap(e)).To(MatchError(ContainSubstring("exit status 3")))
+		})
+
+		it("does not have an exit code without an exit error", func() {
+			Expect(native.NewNativeImageExitError(fmt.Errorf("signal: killed"), "", nil).ExitCode).To(Equal(-1))
+		})
+	})
+
+	it("returns a ClasspathIndexError for an unreadable classpath index", func() {
+		appPath, err := ioutil.TempDir("", "errors")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(appPath)
+
+		// a directory can be opened, but not read
+		Expect(os.MkdirAll(filepath.Join(appPath, "BOOT-INF", "classpath.idx"), 0755)).To(Succeed())
+
+		_, err = native.ReadClasspathIndex(appPath, properties.MustLoadString("Spring-Boot-Classpath-Index: BOOT-INF/classpath.idx"))
+
+		var index native.ClasspathIndexError
+		Expect(errors.As(fmt.Errorf("unable to read classpath index\n%w", err), &index)).To(BeTrue())
+		Expect(index.File).To(Equal(filepath.Join(appPath, "BOOT-INF", "classpath.idx")))
+	})
+
+	it("describes an incompatible GraalVM", func() {
+		Expect(native.IncompatibleGraalVMError{
+			SpringNative: native.Artifact{ArtifactID: "spring-native", Version: "0.11.2"},
+			Supported:    ">=21.3.0, <22.2.0",
+			GraalVM:      "22.3.0",
+		}).To(MatchError(ContainSubstring("spring-native 0.11.2 requires GraalVM >=21.3.0, <22.2.0, but found GraalVM 22.3.0")))
+	})
+}
@@ -0,0 +1,107 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/paketo-buildpacks/libpak/bard"
+)
+
+// maxExceptions is the most distinct exceptions printed in the summary of a build
+const maxExceptions = 10
+
+// maxExceptionMessage is the longest exception message printed in the summary of a build
+const maxExceptionMessage = 200
+
+var exceptionPattern = regexp.MustCompile(`^(?:Caused by: |Exception in thread "[^"]*" )?((?:[A-Za-z_$][\w$]*\.)+[A-Z][\w$]*(?:Exception|Error))(?::\s*(.*))?$`)
+
+// BuildException is an exception reported in the output of native-image
+type BuildException struct {
+	Type    string
+	Message string
+
+	// Frame is the top of the stack trace, if the output includes it
+	Frame string
+
+	// Count is how often the exception is reported
+	Count int
+}
+
+// FindExceptions returns the distinct exceptions reported in the output of native-image, in the order they are first
+// reported
+func FindExceptions(output string) []BuildException {
+	var exceptions []BuildException
+	index := map[string]int{}
+
+	lines := strings.Split(output, "\n")
+	for i, line := range lines {
+		m := exceptionPattern.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+
+		e := BuildException{Type: m[1], Message: strings.TrimSpace(m[2]), Count: 1}
+		if len(e.Message) > maxExceptionMessage {
+			e.Message = e.Message[:maxExceptionMessage] + "..."
+		}
+		if i+1 < len(lines) {
+			if next := strings.TrimSpace(lines[i+1]); strings.HasPrefix(next, "at ") {
+				e.Frame = next
+			}
+		}
+
+		key := e.Type + ": " + e.Message
+		if j, ok := index[key]; ok {
+			exceptions[j].Count++
+			continue
+		}
+		index[key] = len(exceptions)
+		exceptions = append(exceptions, e)
+	}
+
+	return exceptions
+}
+
+// LogExceptions prints a condensed summary of the exceptions reported during the build
+func LogExceptions(logger bard.Logger, exceptions []BuildException) {
+	if len(exceptions) == 0 {
+		return
+	}
+
+	logger.Header("Build-time exceptions:")
+	for i, e := range exceptions {
+		if i == maxExceptions {
+			logger.Bodyf("... and %d more", len(exceptions)-maxExceptions)
+			break
+		}
+
+		s := e.Type
+		if e.Message != "" {
+			s = fmt.Sprintf("%s: %s", s, e.Message)
+		}
+		if e.Count > 1 {
+			s = fmt.Sprintf("%s (%d times)", s, e.Count)
+		}
+		logger.Body(s)
+		if e.Frame != "" {
+			logger.Bodyf("    %s", e.Frame)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/paketo-buildpacks/libpak/bard"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testExceptions(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+	)
+
+	it("finds distinct exceptions with the top of their stack trace", func() {
+		Expect(native.FindExceptions(`[1/7] Initializing...
+Error: Classes that should be initialized at run time got initialized during image building:
+com.oracle.graal.pointsto.constraints.UnsupportedFeatureException: Detected an instance of Random/SplittableRandom
+	at com.example.Application.<clinit>(Application.java:12)
+Caused by: java.lang.ClassNotFoundException: com.example.Missing
+	at java.base/java.net.URLClassLoader.findClass(URLClassLoader.java:445)
+Caused by: java.lang.ClassNotFoundException: com.example.Missing
+java.lang.IllegalStateException
+`)).To(Equal([]native.BuildException{
+			{
+				Type:    "com.oracle.graal.pointsto.constraints.UnsupportedFeatureException",
+				Message: "Detected an instance of Random/SplittableRandom",
+				Frame:   "at com.example.Application.<clinit>(Application.java:12)",
+				Count:   1,
+			},
+			{
+				Type:    "java.lang.ClassNotFoundException",
+				Message: "com.example.Missing",
+				Frame:   "at java.base/java.net.URLClassLoader.findClass(URLClassLoader.java:445)",
+				Count:   2,
+			},
+			{Type: "java.lang.IllegalStateException", Count: 1},
+		}))
+	})
+
+	it("finds no exceptions in a clean build", func() {
+		Expect(native.FindExceptions("[7/7] Creating image...\nFinished generating 'app' in 1m 2s.\n")).To(BeEmpty())
+	})
+
+	it("prints a condensed summary", func() {
+		b := &bytes.Buffer{}
+
+		native.LogExceptions(bard.NewLogger(b), []native.BuildException{
+			{Type: "java.lang.ClassNotFoundException", Message: "com.example.Missing", Frame: "at com.example.Loader", Count: 2},
+		})
+
+		Expect(b.String()).To(ContainSubstring("Build-time exceptions:"))
+		Expect(b.String()).To(ContainSubstring("java.lang.ClassNotFoundException: com.example.Missing (2 times)"))
+		Expect(b.String()).To(ContainSubstring("at com.example.Loader"))
+	})
+
+	it("prints nothing without exceptions", func() {
+		b := &bytes.Buffer{}
+
+		native.LogExceptions(bard.NewLogger(b), nil)
+
+		Expect(b.String()).To(BeEmpty())
+	})
+}
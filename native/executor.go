@@ -0,0 +1,103 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"time"
+
+	"github.com/paketo-buildpacks/libpak/effect"
+)
+
+// ContextExecutor is an effect.Executor whose executions can be cancelled through a context
+type ContextExecutor interface {
+	effect.Executor
+
+	// ExecuteContext executes the command described in the Execution, killing it when ctx is done.
+	ExecuteContext(ctx context.Context, execution effect.Execution) error
+}
+
+// ProcessGroupExecutor is an implementation of ContextExecutor that runs each command in its own process group, so
+// that the whole process tree is killed on cancellation.
+type ProcessGroupExecutor struct {
+
+	// GracePeriod is how long the process group is given to exit after being asked to terminate on cancellation,
+	// before it is killed. Processes are killed immediately if zero.
+	GracePeriod time.Duration
+}
+
+func (p ProcessGroupExecutor) Execute(execution effect.Execution) error {
+	return p.ExecuteContext(context.Background(), execution)
+}
+
+func (p ProcessGroupExecutor) ExecuteContext(ctx context.Context, execution effect.Execution) error {
+	cmd := exec.Command(execution.Command, execution.Args...)
+
+	if execution.Dir != "" {
+		cmd.Dir = execution.Dir
+	}
+
+	if len(execution.Env) > 0 {
+		cmd.Env = execution.Env
+	}
+
+	cmd.Stdin = execution.Stdin
+	cmd.Stdout = execution.Stdout
+	cmd.Stderr = execution.Stderr
+	setProcessGroup(cmd)
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("unable to start %s\n%w", execution.Command, err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		if p.GracePeriod > 0 {
+			terminateProcessGroup(cmd)
+			select {
+			case <-done:
+				return ctx.Err()
+			case <-time.After(p.GracePeriod):
+			}
+		}
+		killProcessGroup(cmd)
+		<-done
+		return ctx.Err()
+	}
+}
+
+// executeContext executes with executor, honouring the cancellation of ctx when the executor supports it
+func executeContext(ctx context.Context, executor effect.Executor, execution effect.Execution) error {
+	if e, ok := executor.(ContextExecutor); ok {
+		return e.ExecuteContext(ctx, execution)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return executor.Execute(execution)
+}
@@ -0,0 +1,75 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"bytes"
+	gocontext "context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/paketo-buildpacks/libpak/effect"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testExecutor(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		executor native.ProcessGroupExecutor
+	)
+
+	it("executes a command", func() {
+		out := &bytes.Buffer{}
+		Expect(executor.Execute(effect.Execution{
+			Command: "echo",
+			Args:    []string{"test-output"},
+			Stdout:  out,
+		})).To(Succeed())
+		Expect(out.String()).To(Equal("test-output\n"))
+	})
+
+	it("kills the process tree when the context is done", func() {
+		ctx, cancel := gocontext.WithTimeout(gocontext.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		err := executor.ExecuteContext(ctx, effect.Execution{
+			Command: "sh",
+			Args:    []string{"-c", "sleep 30 & wait"},
+		})
+		Expect(err).To(MatchError(gocontext.DeadlineExceeded))
+		Expect(time.Since(start)).To(BeNumerically("<", 10*time.Second))
+	})
+
+	it("asks the process tree to terminate before killing it", func() {
+		ctx, cancel := gocontext.WithTimeout(gocontext.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		out := &bytes.Buffer{}
+		err := native.ProcessGroupExecutor{GracePeriod: 5 * time.Second}.ExecuteContext(ctx, effect.Execution{
+			Command: "sh",
+			Args:    []string{"-c", "trap 'echo terminated; exit 0' TERM; while true; do sleep 0.01; done"},
+			Stdout:  out,
+		})
+		Expect(err).To(MatchError(gocontext.DeadlineExceeded))
+		Expect(out.String()).To(Equal("terminated\n"))
+	})
+}
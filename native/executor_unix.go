@@ -0,0 +1,58 @@
+//go:build !windows
+
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+	"syscall"
+)
+
+// abortSignals are the signals the platform sends to abort a build
+var abortSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+func terminateProcessGroup(cmd *exec.Cmd) {
+	_ = syscall.Kill(-cmd.Process.Pid, syscall.SIGTERM)
+}
+
+func killProcessGroup(cmd *exec.Cmd) {
+	// a negative pid signals every process in the group
+	_ = syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
+
+// peakChildRSS returns the peak resident set size in bytes of the largest terminated child process of the buildpack.
+// The kernel keeps the largest child of the whole process, so this is the native-image compilation only when it is
+// larger than the other children such as the component installation or the compilation of auxiliary binaries.
+func peakChildRSS() int64 {
+	var usage syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_CHILDREN, &usage); err != nil {
+		return 0
+	}
+
+	// Linux reports kilobytes, Darwin and the BSDs report bytes
+	if runtime.GOOS == "linux" {
+		return int64(usage.Maxrss) * 1024
+	}
+	return int64(usage.Maxrss)
+}
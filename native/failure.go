@@ -0,0 +1,100 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"regexp"
+
+	"github.com/paketo-buildpacks/libpak/bard"
+)
+
+// Diagnosis describes the cause of a known native-image failure and how to remediate it
+type Diagnosis struct {
+	Cause       string
+	Remediation string
+}
+
+type failureSignature struct {
+	pattern   *regexp.Regexp
+	diagnosis Diagnosis
+}
+
+// DiagnosisOutOfMemory is the diagnosis for a native-image build that ran out of memory
+var DiagnosisOutOfMemory = Diagnosis{
+	Cause:       "native-image ran out of memory",
+	Remediation: "Increase the memory available to the build, or limit the heap with -J-Xmx and reduce --parallelism in $BP_NATIVE_IMAGE_BUILD_ARGUMENTS.",
+}
+
+var failureSignatures = []failureSignature{
+	{
+		pattern: regexp.MustCompile(`ClassNotFoundException: \S+__(?:BeanDefinitions|BeanFactoryRegistrations|ApplicationContextInitializer)`),
+		diagnosis: Diagnosis{
+			Cause:       "Spring AOT generated classes are missing",
+			Remediation: "Run the Spring AOT processing (process-aot / processAot) as part of the application build before building the native image.",
+		},
+	},
+	{
+		pattern: regexp.MustCompile(`MissingReflectionRegistrationError|Warning: Could not resolve class \S+ for reflection configuration|NoSuchMethodException`),
+		diagnosis: Diagnosis{
+			Cause:       "reflection configuration is missing or incomplete",
+			Remediation: "Add the missing types to META-INF/native-image/reflect-config.json or generate configuration with the native-image tracing agent.",
+		},
+	},
+	{
+		pattern: regexp.MustCompile(`UnsupportedFeatureException|Unsupported features in \d+ methods`),
+		diagnosis: Diagnosis{
+			Cause:       "the application uses a feature that native-image does not support",
+			Remediation: "Review the unsupported feature report above, typically a class must be initialized at run time with --initialize-at-run-time.",
+		},
+	},
+	{
+		pattern:   regexp.MustCompile(`java\.lang\.OutOfMemoryError|GC overhead limit exceeded|signal: killed|exit status 137`),
+		diagnosis: DiagnosisOutOfMemory,
+	},
+}
+
+// DiagnoseFailure inspects the output of a failed native-image build, and the error it failed with, for known
+// failure signatures.
+func DiagnoseFailure(output string, err error) []Diagnosis {
+	var diagnoses []Diagnosis
+
+	for _, s := range failureSignatures {
+		if s.pattern.MatchString(output) || (err != nil && s.pattern.MatchString(err.Error())) {
+			diagnoses = append(diagnoses, s.diagnosis)
+		}
+	}
+
+	return diagnoses
+}
+
+func containsDiagnosis(diagnoses []Diagnosis, diagnosis Diagnosis) bool {
+	for _, d := range diagnoses {
+		if d == diagnosis {
+			return true
+		}
+	}
+
+	return false
+}
+
+// LogDiagnoses prints each diagnosis as a warning followed by its remediation
+func LogDiagnoses(logger bard.Logger, diagnoses []Diagnosis) {
+	for _, d := range diagnoses {
+		warn(logger, d.Cause)
+		logger.Body(d.Remediation)
+	}
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/paketo-buildpacks/libpak/bard"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testFailure(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+	)
+
+	it("diagnoses missing Spring AOT classes", func() {
+		d := native.DiagnoseFailure("java.lang.ClassNotFoundException: com.example.Application__ApplicationContextInitializer", nil)
+		Expect(d).To(HaveLen(1))
+		Expect(d[0].Cause).To(Equal("Spring AOT generated classes are missing"))
+	})
+
+	it("diagnoses missing reflection configuration", func() {
+		d := native.DiagnoseFailure("Warning: Could not resolve class com.example.Foo for reflection configuration.", nil)
+		Expect(d).To(HaveLen(1))
+		Expect(d[0].Cause).To(Equal("reflection configuration is missing or incomplete"))
+	})
+
+	it("diagnoses unsupported features", func() {
+		d := native.DiagnoseFailure("Error: Unsupported features in 2 methods", nil)
+		Expect(d).To(HaveLen(1))
+		Expect(d[0].Cause).To(Equal("the application uses a feature that native-image does not support"))
+	})
+
+	it("diagnoses an OOM kill from the error", func() {
+		d := native.DiagnoseFailure("", fmt.Errorf("signal: killed"))
+		Expect(d).To(Equal([]native.Diagnosis{native.DiagnosisOutOfMemory}))
+	})
+
+	it("does not diagnose unknown failures", func() {
+		Expect(native.DiagnoseFailure("Error: something else", fmt.Errorf("exit status 1"))).To(BeEmpty())
+	})
+
+	it("logs remediation", func() {
+		out := &bytes.Buffer{}
+		native.LogDiagnoses(bard.NewLogger(out), []native.Diagnosis{native.DiagnosisOutOfMemory})
+		Expect(out.String()).To(ContainSubstring("native-image ran out of memory"))
+		Expect(out.String()).To(ContainSubstring("Increase the memory available to the build"))
+	})
+}
@@ -0,0 +1,32 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package native
+
+import (
+	"regexp"
+)
+
+// fallbackPattern matches native-image reporting a fallback image, e.g. `Warning: Image 'demo' is a fallback image
+// that requires a JDK for execution (use --no-fallback to suppress fallback image generation).`
+var fallbackPattern = regexp.MustCompile(`Image '([^']*)' is a fallback image`)
+
+// FindFallbackImage returns the name of the image if the output of native-image reports that it is a fallback image
+func FindFallbackImage(output string) (string, bool) {
+	if m := fallbackPattern.FindStringSubmatch(output); m != nil {
+		return m[1], true
+	}
+	return "", false
+}
@@ -0,0 +1,44 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package native_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testFallback(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+	)
+
+	it("finds a fallback image", func() {
+		image, ok := native.FindFallbackImage(`[7/7] Creating image...
+Warning: Image 'demo' is a fallback image that requires a JDK for execution (use --no-fallback to suppress fallback image generation and to print more detailed information why a fallback image was necessary).
+`)
+		Expect(ok).To(BeTrue())
+		Expect(image).To(Equal("demo"))
+	})
+
+	it("does not find a fallback image in a regular build", func() {
+		_, ok := native.FindFallbackImage("Produced artifacts:\n /workspace/demo (executable)\n")
+		Expect(ok).To(BeFalse())
+	})
+}
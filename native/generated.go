@@ -0,0 +1,129 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package native
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// GeneratedConfigurationDirectory is the directory in the native image layer the configuration generated from
+// $BP_NATIVE_IMAGE_SERIALIZATION_CLASSES and $BP_NATIVE_IMAGE_DYNAMIC_PROXIES is written to
+const GeneratedConfigurationDirectory = "generated-configuration"
+
+// GeneratedConfigurationMetadataKey is the key of the generated configuration in the native image layer metadata
+const GeneratedConfigurationMetadataKey = "generated-configuration"
+
+// classNamePattern matches a binary class name, e.g. com.example.Outer$Inner
+var classNamePattern = regexp.MustCompile(`^[\p{L}_$][\p{L}\p{N}_$]*(?:\.[\p{L}_$][\p{L}\p{N}_$]*)*$`)
+
+// ParseSerializationClasses parses a comma separated list of classes serialized with Java serialization
+func ParseSerializationClasses(value string) ([]string, error) {
+	classes := ParseClassList(value)
+	for _, c := range classes {
+		if !classNamePattern.MatchString(c) {
+			return nil, fmt.Errorf("invalid class name %q in $%s", c, ConfigNativeImageSerialization)
+		}
+	}
+
+	return classes, nil
+}
+
+// ParseDynamicProxies parses a semicolon separated list of dynamic proxies, each a comma separated list of the
+// interfaces the proxy implements in order, e.g. com.example.A,com.example.B;com.example.C
+func ParseDynamicProxies(value string) ([][]string, error) {
+	var proxies [][]string
+
+	for _, p := range strings.Split(value, ";") {
+		interfaces := ParseClassList(p)
+		if len(interfaces) == 0 {
+			continue
+		}
+
+		for _, i := range interfaces {
+			if !classNamePattern.MatchString(i) {
+				return nil, fmt.Errorf("invalid interface name %q in $%s", i, ConfigNativeImageProxies)
+			}
+		}
+		proxies = append(proxies, interfaces)
+	}
+
+	return proxies, nil
+}
+
+// GeneratedConfiguration is native-image configuration generated from lists of names, sparing the JSON
+type GeneratedConfiguration struct {
+	SerializationClasses []string
+	DynamicProxies       [][]string
+}
+
+// IsEmpty returns whether there is no configuration to generate
+func (g GeneratedConfiguration) IsEmpty() bool {
+	return len(g.SerializationClasses) == 0 && len(g.DynamicProxies) == 0
+}
+
+// Metadata returns the generated configuration for the layer metadata, so that a change rebuilds the image
+func (g GeneratedConfiguration) Metadata() map[string]interface{} {
+	return map[string]interface{}{
+		"serialization-classes": g.SerializationClasses,
+		"dynamic-proxies":       g.DynamicProxies,
+	}
+}
+
+// WriteTo writes serialization-config.json and proxy-config.json to dir, creating it
+func (g GeneratedConfiguration) WriteTo(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("unable to create %s\n%w", dir, err)
+	}
+
+	type serialization struct {
+		Name string `json:"name"`
+	}
+	serializations := []serialization{}
+	for _, c := range g.SerializationClasses {
+		serializations = append(serializations, serialization{Name: c})
+	}
+
+	type proxy struct {
+		Interfaces []string `json:"interfaces"`
+	}
+	proxies := []proxy{}
+	for _, p := range g.DynamicProxies {
+		proxies = append(proxies, proxy{Interfaces: p})
+	}
+
+	for file, v := range map[string]interface{}{
+		"serialization-config.json": serializations,
+		"proxy-config.json":         proxies,
+	} {
+		b, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return fmt.Errorf("unable to encode %s\n%w", file, err)
+		}
+
+		path := filepath.Join(dir, file)
+		if err := ioutil.WriteFile(path, append(b, '\n'), 0644); err != nil {
+			return fmt.Errorf("unable to write %s\n%w", path, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package native_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testGenerated(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		dir string
+	)
+
+	it.Before(func() {
+		var err error
+
+		dir, err = ioutil.TempDir("", "generated")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	it("parses serialization classes", func() {
+		Expect(native.ParseSerializationClasses(" com.example.Order, com.example.Order$Line ,")).
+			To(Equal([]string{"com.example.Order", "com.example.Order$Line"}))
+
+		_, err := native.ParseSerializationClasses("com.example.Order Line")
+		Expect(err).To(MatchError(`invalid class name "com.example.Order Line" in $BP_NATIVE_IMAGE_SERIALIZATION_CLASSES`))
+	})
+
+	it("parses dynamic proxies", func() {
+		Expect(native.ParseDynamicProxies("com.example.A, com.example.B; com.example.C;")).
+			To(Equal([][]string{{"com.example.A", "com.example.B"}, {"com.example.C"}}))
+		Expect(native.ParseDynamicProxies("")).To(BeEmpty())
+
+		_, err := native.ParseDynamicProxies("com.example.A;com/example/B")
+		Expect(err).To(MatchError(`invalid interface name "com/example/B" in $BP_NATIVE_IMAGE_DYNAMIC_PROXIES`))
+	})
+
+	it("writes the configuration files", func() {
+		generated := native.GeneratedConfiguration{
+			SerializationClasses: []string{"com.example.Order"},
+			DynamicProxies:       [][]string{{"com.example.A", "com.example.B"}},
+		}
+		Expect(generated.IsEmpty()).To(BeFalse())
+		Expect(generated.WriteTo(filepath.Join(dir, "generated"))).To(Succeed())
+
+		var serialization []map[string]interface{}
+		b, err := ioutil.ReadFile(filepath.Join(dir, "generated", "serialization-config.json"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(json.Unmarshal(b, &serialization)).To(Succeed())
+		Expect(serialization).To(Equal([]map[string]interface{}{{"name": "com.example.Order"}}))
+
+		var proxies []map[string][]string
+		b, err = ioutil.ReadFile(filepath.Join(dir, "generated", "proxy-config.json"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(json.Unmarshal(b, &proxies)).To(Succeed())
+		Expect(proxies).To(Equal([]map[string][]string{{"interfaces": {"com.example.A", "com.example.B"}}}))
+	})
+
+	it("writes empty lists", func() {
+		Expect(native.GeneratedConfiguration{SerializationClasses: []string{"com.example.Order"}}.WriteTo(dir)).To(Succeed())
+		Expect(ioutil.ReadFile(filepath.Join(dir, "proxy-config.json"))).To(Equal([]byte("[]\n")))
+	})
+}
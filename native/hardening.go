@@ -0,0 +1,180 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"debug/elf"
+	"fmt"
+	"strings"
+)
+
+// HardeningMetadataKey is the key of the hardening properties of the binary in the native image layer metadata
+const HardeningMetadataKey = "hardening"
+
+const (
+	RELRONone    = "none"
+	RELROPartial = "partial"
+	RELROFull    = "full"
+)
+
+// ParseRELRO parses the requested RELRO, either empty, none, partial or full
+func ParseRELRO(value string) (string, error) {
+	switch v := strings.ToLower(strings.TrimSpace(value)); v {
+	case "", RELRONone:
+		return "", nil
+	case RELROPartial, RELROFull:
+		return v, nil
+	default:
+		return "", fmt.Errorf("invalid RELRO %q, expected %s, %s or %s", value, RELRONone, RELROPartial, RELROFull)
+	}
+}
+
+// HardeningArguments passes linker options to native-image that harden the binary against memory corruption
+// exploits
+type HardeningArguments struct {
+	PIE   bool
+	RELRO string
+}
+
+// Configure appends a -H:NativeLinkerOption to inputArgs for each of the requested hardening options
+func (h HardeningArguments) Configure(inputArgs []string) ([]string, string, error) {
+	if h.PIE {
+		inputArgs = append(inputArgs, "-H:NativeLinkerOption=-pie")
+	}
+
+	switch h.RELRO {
+	case RELROPartial:
+		inputArgs = append(inputArgs, "-H:NativeLinkerOption=-Wl,-z,relro")
+	case RELROFull:
+		inputArgs = append(inputArgs, "-H:NativeLinkerOption=-Wl,-z,relro", "-H:NativeLinkerOption=-Wl,-z,now")
+	}
+
+	return inputArgs, "", nil
+}
+
+// Hardening are the hardening properties of an ELF binary
+type Hardening struct {
+	PIE   bool
+	RELRO string
+}
+
+// Metadata returns the hardening properties for the layer metadata
+func (h Hardening) Metadata() map[string]interface{} {
+	return map[string]interface{}{
+		"pie":   h.PIE,
+		"relro": h.RELRO,
+	}
+}
+
+// Verify returns an error if the binary lacks a requested hardening property
+func (h Hardening) Verify(requested HardeningArguments) error {
+	var missing []string
+
+	if requested.PIE && !h.PIE {
+		missing = append(missing, "it is not a position-independent executable")
+	}
+	if requested.RELRO == RELROFull && h.RELRO != RELROFull || requested.RELRO == RELROPartial && h.RELRO == RELRONone {
+		missing = append(missing, fmt.Sprintf("it has %s RELRO rather than %s", h.RELRO, requested.RELRO))
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("native image is not hardened as requested, %s", strings.Join(missing, " and "))
+	}
+
+	return nil
+}
+
+// ReadHardening reads the hardening properties of an ELF binary
+func ReadHardening(file string) (Hardening, error) {
+	f, err := elf.Open(file)
+	if err != nil {
+		return Hardening{}, fmt.Errorf("unable to open %s\n%w", file, err)
+	}
+	defer f.Close()
+
+	h := Hardening{RELRO: RELRONone}
+
+	interpreter, relro := false, false
+	for _, p := range f.Progs {
+		switch p.Type {
+		case elf.PT_INTERP:
+			interpreter = true
+		case elf.PT_GNU_RELRO:
+			relro = true
+		}
+	}
+	h.PIE = f.Type == elf.ET_DYN && interpreter
+
+	if relro {
+		h.RELRO = RELROPartial
+
+		now, err := bindNow(f)
+		if err != nil {
+			return Hardening{}, fmt.Errorf("unable to read dynamic section of %s\n%w", file, err)
+		}
+		if now {
+			h.RELRO = RELROFull
+		}
+	}
+
+	return h, nil
+}
+
+// bindNow returns whether the dynamic section of the binary requests that symbols are bound at load time, which makes
+// the relocations read-only for the whole run
+func bindNow(f *elf.File) (bool, error) {
+	s := f.Section(".dynamic")
+	if s == nil {
+		return false, nil
+	}
+
+	b, err := s.Data()
+	if err != nil {
+		return false, err
+	}
+
+	size := 16
+	if f.Class == elf.ELFCLASS32 {
+		size = 8
+	}
+
+	for i := 0; i+size <= len(b); i += size {
+		var tag, value uint64
+		if size == 16 {
+			tag, value = f.ByteOrder.Uint64(b[i:]), f.ByteOrder.Uint64(b[i+8:])
+		} else {
+			tag, value = uint64(f.ByteOrder.Uint32(b[i:])), uint64(f.ByteOrder.Uint32(b[i+4:]))
+		}
+
+		switch elf.DynTag(tag) {
+		case elf.DT_NULL:
+			return false, nil
+		case elf.DT_BIND_NOW:
+			return true, nil
+		case elf.DT_FLAGS:
+			if elf.DynFlag(value)&elf.DF_BIND_NOW != 0 {
+				return true, nil
+			}
+		case elf.DT_FLAGS_1:
+			if value&0x1 != 0 { // DF_1_NOW
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,86 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"os"
+	"runtime"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testHardening(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+	)
+
+	it("parses RELRO", func() {
+		Expect(native.ParseRELRO("")).To(BeEmpty())
+		Expect(native.ParseRELRO("none")).To(BeEmpty())
+		Expect(native.ParseRELRO("Full")).To(Equal(native.RELROFull))
+
+		_, err := native.ParseRELRO("some")
+		Expect(err).To(MatchError(ContainSubstring(`invalid RELRO "some"`)))
+	})
+
+	it("passes hardening linker options", func() {
+		args, _, err := native.HardeningArguments{}.Configure([]string{"one"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(args).To(Equal([]string{"one"}))
+
+		args, _, err = native.HardeningArguments{PIE: true, RELRO: native.RELROFull}.Configure([]string{"one"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(args).To(Equal([]string{
+			"one",
+			"-H:NativeLinkerOption=-pie",
+			"-H:NativeLinkerOption=-Wl,-z,relro",
+			"-H:NativeLinkerOption=-Wl,-z,now",
+		}))
+	})
+
+	it("verifies the requested properties", func() {
+		requested := native.HardeningArguments{PIE: true, RELRO: native.RELROFull}
+
+		Expect(native.Hardening{PIE: true, RELRO: native.RELROFull}.Verify(requested)).To(Succeed())
+		Expect(native.Hardening{PIE: true, RELRO: native.RELROPartial}.Verify(native.HardeningArguments{RELRO: native.RELROPartial})).
+			To(Succeed())
+		Expect(native.Hardening{RELRO: native.RELROPartial}.Verify(requested)).
+			To(MatchError("native image is not hardened as requested, it is not a position-independent executable and it has partial RELRO rather than full"))
+	})
+
+	it("records the properties in metadata", func() {
+		Expect(native.Hardening{PIE: true, RELRO: native.RELROFull}.Metadata()).
+			To(Equal(map[string]interface{}{"pie": true, "relro": "full"}))
+	})
+
+	it("reads the properties of a binary", func() {
+		if runtime.GOOS != "linux" {
+			return
+		}
+		if _, err := os.Stat("/bin/ls"); err != nil {
+			return
+		}
+
+		h, err := native.ReadHardening("/bin/ls")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(h.RELRO).To(BeElementOf(native.RELRONone, native.RELROPartial, native.RELROFull))
+	})
+}
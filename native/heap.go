@@ -0,0 +1,168 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mattn/go-shellwords"
+)
+
+// ParseMaxHeapSize parses the default maximum heap size of the native image, such as 512m, in the form accepted by -Xmx
+func ParseMaxHeapSize(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", nil
+	}
+
+	if size, ok := parseMemorySize(value); !ok || size <= 0 {
+		return "", fmt.Errorf("unable to parse $%s value %q as a memory size", ConfigNativeImageMaxHeapSize, value)
+	}
+
+	return value, nil
+}
+
+// ParseMaxHeapPercent parses the default maximum heap size of the native image as a percentage of the physical memory
+func ParseMaxHeapPercent(value string) (int, error) {
+	value = strings.TrimSuffix(strings.TrimSpace(value), "%")
+	if value == "" {
+		return 0, nil
+	}
+
+	p, err := strconv.Atoi(value)
+	if err != nil || p <= 0 || p > 100 {
+		return 0, fmt.Errorf("unable to parse $%s value %q as a percentage between 1 and 100", ConfigNativeImageMaxHeapPercent, value)
+	}
+
+	return p, nil
+}
+
+// MaxCompressedHeapSize is the largest heap compressed references can address
+const MaxCompressedHeapSize = 32 * 1024 * 1024 * 1024
+
+// ParseCompressedReferences parses whether the native image uses compressed references, returning true or false, or
+// empty to keep the default of native-image
+func ParseCompressedReferences(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", nil
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return "", fmt.Errorf("unable to parse $%s value %q as a boolean", ConfigNativeImageCompressedRefs, value)
+	}
+
+	return strconv.FormatBool(b), nil
+}
+
+// ParseAlignedHeapChunkSize parses the size of the aligned chunks of the heap, which must be a power of two of at
+// least a page
+func ParseAlignedHeapChunkSize(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", nil
+	}
+
+	if size, ok := parseMemorySize(value); !ok || size < 4096 || size&(size-1) != 0 {
+		return "", fmt.Errorf("unable to parse $%s value %q as a power of two memory size of at least 4k", ConfigNativeImageHeapChunkSize, value)
+	}
+
+	return value, nil
+}
+
+// HeapArguments bakes the default maximum heap size and the heap layout into the native image. Options passed when
+// the image is launched still take precedence over the maximum heap size.
+type HeapArguments struct {
+	MaxHeapSize    string
+	MaxHeapPercent int
+
+	// CompressedReferences is true or false to enable or disable compressed references, or empty for the default
+	CompressedReferences string
+
+	// AlignedHeapChunkSize is the size of the aligned chunks of the heap, or empty for the default
+	AlignedHeapChunkSize string
+
+	// UserArguments are the raw arguments of the end user, checked for combinations with CompressedReferences that
+	// native-image does not support
+	UserArguments string
+}
+
+// Configure appends -R:MaxHeapSize, -R:MaximumHeapSizePercent, -H:±UseCompressedReferences and
+// -H:AlignedHeapChunkSize to inputArgs as configured. Combinations native-image does not support fail.
+func (h HeapArguments) Configure(inputArgs []string) ([]string, string, error) {
+	userArgs, err := shellwords.Parse(h.UserArguments)
+	if err != nil {
+		return []string{}, "", fmt.Errorf("unable to parse arguments from %s\n%w", h.UserArguments, err)
+	}
+
+	if err := h.validate(append(append([]string{}, inputArgs...), userArgs...)); err != nil {
+		return []string{}, "", err
+	}
+
+	if h.MaxHeapSize != "" {
+		inputArgs = append(inputArgs, fmt.Sprintf("-R:MaxHeapSize=%s", h.MaxHeapSize))
+	}
+
+	if h.MaxHeapPercent > 0 {
+		inputArgs = append(inputArgs, fmt.Sprintf("-R:MaximumHeapSizePercent=%d", h.MaxHeapPercent))
+	}
+
+	switch h.CompressedReferences {
+	case "true":
+		inputArgs = append(inputArgs, "-H:+UseCompressedReferences")
+	case "false":
+		inputArgs = append(inputArgs, "-H:-UseCompressedReferences")
+	}
+
+	if h.AlignedHeapChunkSize != "" {
+		size, _ := parseMemorySize(h.AlignedHeapChunkSize)
+		inputArgs = append(inputArgs, fmt.Sprintf("-H:AlignedHeapChunkSize=%d", size))
+	}
+
+	return inputArgs, "", nil
+}
+
+func (h HeapArguments) validate(arguments []string) error {
+	if h.CompressedReferences == "" {
+		return nil
+	}
+
+	for _, a := range arguments {
+		if a == "-H:+UseCompressedReferences" || a == "-H:-UseCompressedReferences" {
+			return fmt.Errorf("$%s conflicts with the native-image argument %s", ConfigNativeImageCompressedRefs, a)
+		}
+	}
+
+	if h.CompressedReferences != "true" {
+		return nil
+	}
+
+	// compressed references are relative to the heap base of an isolate
+	if containsString(arguments, "-H:-SpawnIsolates") {
+		return fmt.Errorf("compressed references require isolates, remove -H:-SpawnIsolates or set $%s to false", ConfigNativeImageCompressedRefs)
+	}
+
+	if size, ok := parseMemorySize(h.MaxHeapSize); ok && size > MaxCompressedHeapSize {
+		return fmt.Errorf("compressed references address at most 32g, which is less than the $%s of %s. Set $%s to false for larger heaps.",
+			ConfigNativeImageMaxHeapSize, h.MaxHeapSize, ConfigNativeImageCompressedRefs)
+	}
+
+	return nil
+}
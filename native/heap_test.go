@@ -0,0 +1,101 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testHeap(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+	)
+
+	it("parses heap sizes", func() {
+		Expect(native.ParseMaxHeapSize(" 2G ")).To(Equal("2G"))
+		Expect(native.ParseMaxHeapSize("")).To(BeEmpty())
+
+		_, err := native.ParseMaxHeapSize("0")
+		Expect(err).To(HaveOccurred())
+	})
+
+	it("parses heap percentages", func() {
+		Expect(native.ParseMaxHeapPercent("80")).To(Equal(80))
+		Expect(native.ParseMaxHeapPercent("")).To(Equal(0))
+
+		_, err := native.ParseMaxHeapPercent("150")
+		Expect(err).To(MatchError(`unable to parse $BP_NATIVE_IMAGE_MAX_HEAP_PERCENT value "150" as a percentage between 1 and 100`))
+	})
+
+	it("adds heap arguments", func() {
+		Expect(native.HeapArguments{MaxHeapSize: "512m", MaxHeapPercent: 75}.Configure([]string{"test"})).
+			To(Equal([]string{"test", "-R:MaxHeapSize=512m", "-R:MaximumHeapSizePercent=75"}))
+	})
+
+	it("does not add arguments without configuration", func() {
+		args, _, err := native.HeapArguments{}.Configure([]string{"test"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(args).To(Equal([]string{"test"}))
+	})
+
+	it("parses the heap layout", func() {
+		Expect(native.ParseCompressedReferences("")).To(BeEmpty())
+		Expect(native.ParseCompressedReferences("TRUE")).To(Equal("true"))
+		Expect(native.ParseCompressedReferences("0")).To(Equal("false"))
+		_, err := native.ParseCompressedReferences("yes")
+		Expect(err).To(MatchError(`unable to parse $BP_NATIVE_IMAGE_COMPRESSED_REFERENCES value "yes" as a boolean`))
+
+		Expect(native.ParseAlignedHeapChunkSize("1m")).To(Equal("1m"))
+		_, err = native.ParseAlignedHeapChunkSize("3m")
+		Expect(err).To(HaveOccurred())
+		_, err = native.ParseAlignedHeapChunkSize("1k")
+		Expect(err).To(HaveOccurred())
+	})
+
+	it("adds heap layout arguments", func() {
+		Expect(native.HeapArguments{CompressedReferences: "false", AlignedHeapChunkSize: "1m"}.Configure([]string{"test"})).
+			To(Equal([]string{"test", "-H:-UseCompressedReferences", "-H:AlignedHeapChunkSize=1048576"}))
+		Expect(native.HeapArguments{CompressedReferences: "true", MaxHeapSize: "32g"}.Configure(nil)).
+			To(Equal([]string{"-R:MaxHeapSize=32g", "-H:+UseCompressedReferences"}))
+	})
+
+	it("fails on unsupported heap layouts", func() {
+		_, _, err := native.HeapArguments{CompressedReferences: "true", MaxHeapSize: "64g"}.Configure(nil)
+		Expect(err).To(MatchError(ContainSubstring("compressed references address at most 32g")))
+
+		_, _, err = native.HeapArguments{CompressedReferences: "true", UserArguments: "-H:-SpawnIsolates"}.Configure(nil)
+		Expect(err).To(MatchError(ContainSubstring("compressed references require isolates")))
+
+		_, _, err = native.HeapArguments{CompressedReferences: "false"}.Configure([]string{"-H:+UseCompressedReferences"})
+		Expect(err).To(MatchError("$BP_NATIVE_IMAGE_COMPRESSED_REFERENCES conflicts with the native-image argument -H:+UseCompressedReferences"))
+	})
+
+	it("allows larger heaps without compressed references", func() {
+		_, _, err := native.HeapArguments{CompressedReferences: "false", MaxHeapSize: "64g", UserArguments: "-H:-SpawnIsolates"}.Configure(nil)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	it("lets a later argument replace the default", func() {
+		Expect(native.ArgumentResolver{}.Resolve([]string{"-R:MaxHeapSize=512m", "-R:MaxHeapSize=1g"})).
+			To(Equal([]string{"-R:MaxHeapSize=1g"}))
+	})
+}
@@ -0,0 +1,66 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/buildpacks/libcnb"
+	"github.com/magiconair/properties"
+)
+
+const (
+	ProcessTypeNative = "web-native"
+	ProcessTypeJVM    = "web-jvm"
+)
+
+// JVMProcess returns the process running the preserved application on the JVM, for hybrid images. An exploded
+// application is started with its Main-Class, which is the launcher of a Spring Boot application, and a JAR with
+// java -jar.
+func JVMProcess(appPath string, manifest *properties.Properties, jarFilePattern string, exploded bool) (libcnb.Process, error) {
+	if !exploded {
+		jar, err := findJar(appPath, jarFilePattern)
+		if err != nil {
+			return libcnb.Process{}, err
+		}
+
+		return libcnb.Process{Type: ProcessTypeJVM, Command: "java", Arguments: []string{"-jar", jar}, Direct: true}, nil
+	}
+
+	mainClass, ok := manifest.Get("Main-Class")
+	if !ok {
+		if mainClass, ok = manifest.Get("Start-Class"); !ok {
+			return libcnb.Process{}, fmt.Errorf("unable to run the application on the JVM\n%w", NoStartOrMainClass{})
+		}
+	}
+
+	cp := []string{appPath}
+	if v, ok := manifest.Get("Class-Path"); ok {
+		for _, e := range strings.Fields(v) {
+			cp = append(cp, filepath.Join(appPath, e))
+		}
+	}
+
+	return libcnb.Process{
+		Type:      ProcessTypeJVM,
+		Command:   "java",
+		Arguments: []string{"-cp", strings.Join(cp, string(filepath.ListSeparator)), mainClass},
+		Direct:    true,
+	}, nil
+}
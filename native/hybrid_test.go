@@ -0,0 +1,80 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/buildpacks/libcnb"
+	"github.com/magiconair/properties"
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testHybrid(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		appPath string
+	)
+
+	it.Before(func() {
+		var err error
+
+		appPath, err = ioutil.TempDir("", "hybrid-application")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(appPath)).To(Succeed())
+	})
+
+	it("runs an exploded application with its Main-Class", func() {
+		manifest := properties.MustLoadString("Main-Class: org.springframework.boot.loader.JarLauncher\nStart-Class: test-start-class\nClass-Path: lib/a.jar lib/b.jar\n")
+
+		Expect(native.JVMProcess(appPath, manifest, "", true)).To(Equal(libcnb.Process{
+			Type:    "web-jvm",
+			Command: "java",
+			Arguments: []string{
+				"-cp", appPath + ":" + filepath.Join(appPath, "lib", "a.jar") + ":" + filepath.Join(appPath, "lib", "b.jar"),
+				"org.springframework.boot.loader.JarLauncher",
+			},
+			Direct: true,
+		}))
+	})
+
+	it("runs a JAR", func() {
+		Expect(ioutil.WriteFile(filepath.Join(appPath, "app.jar"), []byte{}, 0644)).To(Succeed())
+
+		Expect(native.JVMProcess(appPath, properties.NewProperties(), "*.jar", false)).To(Equal(libcnb.Process{
+			Type:      "web-jvm",
+			Command:   "java",
+			Arguments: []string{"-jar", filepath.Join(appPath, "app.jar")},
+			Direct:    true,
+		}))
+	})
+
+	it("fails without a main class", func() {
+		_, err := native.JVMProcess(appPath, properties.NewProperties(), "", true)
+		Expect(err).To(MatchError(ContainSubstring("unable to read Start-Class or Main-Class")))
+	})
+}
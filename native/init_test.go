@@ -25,9 +25,81 @@ import (
 
 func TestUnit(t *testing.T) {
 	suite := spec.New("native", spec.Report(report.Terminal{}))
+	suite("Binary", testBinary)
+	suite("Budget", testBudget)
 	suite("Build", testBuild)
+	suite("BuildTools", testBuildTools)
+	suite("Buildpack", testBuildpack)
+	suite("DeniedArguments", testDeniedArguments)
+	suite("Deprecated", testDeprecated)
 	suite("Detect", testDetect)
 	suite("Arguments", testArguments)
+	suite("ArgumentSource", testArgumentSource)
+	suite("Architecture", testArchitecture)
+	suite("Auxiliary", testAuxiliary)
+	suite("Classpath", testClasspath)
+	suite("ClasspathIndex", testClasspathIndex)
+	suite("ClasspathResolver", testClasspathResolver)
+	suite("Compatibility", testCompatibility)
+	suite("Component", testComponent)
+	suite("Conflicts", testConflicts)
+	suite("Dependency", testDependency)
+	suite("Distribution", testDistribution)
+	suite("Executor", testExecutor)
+	suite("Diagnostics", testDiagnostics)
+	suite("Directory", testDirectory)
+	suite("Enterprise", testEnterprise)
+	suite("EntryPoint", testEntryPoint)
+	suite("Environment", testEnvironment)
+	suite("Errors", testErrors)
+	suite("Exceptions", testExceptions)
+	suite("Configuration", testConfiguration)
+	suite("DevServices", testDevServices)
+	suite("Failure", testFailure)
+	suite("Fallback", testFallback)
+	suite("Generated", testGenerated)
+	suite("Hardening", testHardening)
+	suite("Heap", testHeap)
+	suite("Hybrid", testHybrid)
+	suite("Initialization", testInitialization)
+	suite("Invoker", testInvoker)
+	suite("JNI", testJNI)
+	suite("Lambda", testLambda)
+	suite("Languages", testLanguages)
+	suite("LibraryArguments", testLibraryArguments)
+	suite("Logging", testLogging)
+	suite("Metrics", testMetrics)
 	suite("NativeImage", testNativeImage)
+	suite("Netty", testNetty)
+	suite("Network", testNetwork)
+	suite("Offline", testOffline)
+	suite("Options", testOptions)
+	suite("Outputs", testOutputs)
+	suite("Overrides", testOverrides)
+	suite("Processes", testProcesses)
+	suite("Procfile", testProcfile)
+	suite("Progress", testProgress)
+	suite("Provenance", testProvenance)
+	suite("Platform", testPlatform)
+	suite("Preflight", testPreflight)
+	suite("Preserve", testPreserve)
+	suite("Protocols", testProtocols)
+	suite("Reproducible", testReproducible)
+	suite("Reports", testReports)
+	suite("Requirement", testRequirement)
+	suite("Resolver", testResolver)
+	suite("Resources", testResources)
+	suite("RunImage", testRunImage)
+	suite("RuntimeOptions", testRuntimeOptions)
+	suite("Secrets", testSecrets)
+	suite("Security", testSecurity)
+	suite("Summary", testSummary)
+	suite("SystemProperties", testSystemProperties)
+	suite("Temp", testTemp)
+	suite("Thresholds", testThresholds)
+	suite("Training", testTraining)
+	suite("Tracing", testTracing)
+	suite("UsageStatistics", testUsageStatistics)
+	suite("Warnings", testWarnings)
 	suite.Run(t)
 }
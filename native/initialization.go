@@ -0,0 +1,112 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mattn/go-shellwords"
+)
+
+const (
+	InitializeAtBuildTime = "--initialize-at-build-time"
+	InitializeAtRunTime   = "--initialize-at-run-time"
+)
+
+// ParseClassList parses a comma separated list of class or package names
+func ParseClassList(value string) []string {
+	var names []string
+
+	for _, n := range strings.Split(value, ",") {
+		if n = strings.TrimSpace(n); n != "" {
+			names = append(names, n)
+		}
+	}
+
+	return names
+}
+
+// InitializationArguments initializes classes and packages at build time or at run time
+type InitializationArguments struct {
+	BuildTime []string
+	RunTime   []string
+
+	// UserArguments are the raw arguments of the end user, checked for conflicts with BuildTime and RunTime
+	UserArguments string
+}
+
+// Configure appends --initialize-at-build-time and --initialize-at-run-time to inputArgs. It fails when a class or
+// package is to be initialized both at build time and at run time.
+func (i InitializationArguments) Configure(inputArgs []string) ([]string, string, error) {
+	if len(i.BuildTime) == 0 && len(i.RunTime) == 0 {
+		return inputArgs, "", nil
+	}
+
+	userArgs, err := shellwords.Parse(i.UserArguments)
+	if err != nil {
+		return []string{}, "", fmt.Errorf("unable to parse arguments from %s\n%w", i.UserArguments, err)
+	}
+
+	buildTime, runTime := nameSet(i.BuildTime), nameSet(i.RunTime)
+	for _, arg := range userArgs {
+		parts := strings.SplitN(arg, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		switch parts[0] {
+		case InitializeAtBuildTime:
+			for _, n := range ParseClassList(parts[1]) {
+				buildTime[n] = true
+			}
+		case InitializeAtRunTime:
+			for _, n := range ParseClassList(parts[1]) {
+				runTime[n] = true
+			}
+		}
+	}
+
+	var conflicts []string
+	for n := range buildTime {
+		if runTime[n] {
+			conflicts = append(conflicts, n)
+		}
+	}
+	if len(conflicts) > 0 {
+		sort.Strings(conflicts)
+		return []string{}, "", fmt.Errorf("unable to initialize %s both at build time and at run time", strings.Join(conflicts, ", "))
+	}
+
+	if len(i.BuildTime) > 0 {
+		inputArgs = append(inputArgs, fmt.Sprintf("%s=%s", InitializeAtBuildTime, strings.Join(i.BuildTime, ",")))
+	}
+	if len(i.RunTime) > 0 {
+		inputArgs = append(inputArgs, fmt.Sprintf("%s=%s", InitializeAtRunTime, strings.Join(i.RunTime, ",")))
+	}
+
+	return inputArgs, "", nil
+}
+
+func nameSet(list []string) map[string]bool {
+	m := map[string]bool{}
+	for _, n := range list {
+		m[n] = true
+	}
+	return m
+}
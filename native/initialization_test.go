@@ -0,0 +1,71 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testInitialization(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+	)
+
+	it("parses class lists", func() {
+		Expect(native.ParseClassList(" com.example, org.example.Foo ,")).To(Equal([]string{"com.example", "org.example.Foo"}))
+	})
+
+	it("does nothing by default", func() {
+		args, _, err := native.InitializationArguments{UserArguments: "--initialize-at-run-time=com.example"}.Configure([]string{"test"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(args).To(Equal([]string{"test"}))
+	})
+
+	it("initializes classes at build time and at run time", func() {
+		args, _, err := native.InitializationArguments{
+			BuildTime: []string{"com.example", "org.example"},
+			RunTime:   []string{"com.example.Random"},
+		}.Configure([]string{"test"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(args).To(Equal([]string{
+			"test",
+			"--initialize-at-build-time=com.example,org.example",
+			"--initialize-at-run-time=com.example.Random",
+		}))
+	})
+
+	it("fails on conflicting lists", func() {
+		_, _, err := native.InitializationArguments{
+			BuildTime: []string{"com.example", "org.example"},
+			RunTime:   []string{"org.example", "com.example"},
+		}.Configure(nil)
+		Expect(err).To(MatchError("unable to initialize com.example, org.example both at build time and at run time"))
+	})
+
+	it("fails on conflicts with user arguments", func() {
+		_, _, err := native.InitializationArguments{
+			BuildTime:     []string{"com.example"},
+			UserArguments: "--no-fallback --initialize-at-run-time=org.example,com.example",
+		}.Configure(nil)
+		Expect(err).To(MatchError("unable to initialize com.example both at build time and at run time"))
+	})
+}
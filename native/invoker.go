@@ -0,0 +1,171 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package native
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/paketo-buildpacks/libpak/effect"
+)
+
+const (
+	InvokerDirect     = "direct"
+	InvokerArgfile    = "argfile"
+	InvokerBundle     = "bundle"
+	InvokerBuildTools = "build-tools"
+)
+
+// ArgfileName is the name of the file ArgfileInvoker writes the native-image arguments to
+const ArgfileName = "native-image.args"
+
+// Invoker is a strategy running the tool that compiles a native image
+type Invoker interface {
+
+	// Execution returns the execution compiling the native image named binary in dir. Invokers that decide the
+	// native-image arguments themselves ignore arguments.
+	Execution(dir string, binary string, arguments []string, env []string) (effect.Execution, error)
+
+	// Collect moves the native image named binary into dir once the execution has succeeded.
+	Collect(dir string, binary string) error
+}
+
+// DirectInvoker is an implementation of Invoker that passes the arguments on the native-image command line
+type DirectInvoker struct {
+	Command string
+}
+
+func (d DirectInvoker) Execution(dir string, _ string, arguments []string, env []string) (effect.Execution, error) {
+	return effect.Execution{Command: d.Command, Args: arguments, Dir: dir, Env: env}, nil
+}
+
+func (DirectInvoker) Collect(string, string) error {
+	return nil
+}
+
+// ArgfileInvoker is an implementation of Invoker that writes the arguments to an argument file, so that long classpaths
+// do not exceed the command line length limit
+type ArgfileInvoker struct {
+	Command string
+}
+
+func (a ArgfileInvoker) Execution(dir string, _ string, arguments []string, env []string) (effect.Execution, error) {
+	file := filepath.Join(dir, ArgfileName)
+	if err := ioutil.WriteFile(file, []byte(Argfile(arguments)), 0644); err != nil {
+		return effect.Execution{}, fmt.Errorf("unable to write %s\n%w", file, err)
+	}
+
+	return effect.Execution{Command: a.Command, Args: []string{"@" + file}, Dir: dir, Env: env}, nil
+}
+
+func (ArgfileInvoker) Collect(dir string, _ string) error {
+	file := filepath.Join(dir, ArgfileName)
+	if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("unable to remove %s\n%w", file, err)
+	}
+	return nil
+}
+
+// Argfile returns the contents of a native-image argument file, one double quoted argument per line
+func Argfile(arguments []string) string {
+	escape := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+	var b strings.Builder
+	for _, a := range arguments {
+		b.WriteString(`"` + escape.Replace(a) + "\"\n")
+	}
+	return b.String()
+}
+
+// BundleInvoker is an implementation of Invoker that builds a Native Build Bundle created by native-image
+// --bundle-create. The bundle records the arguments of the build.
+type BundleInvoker struct {
+	Command string
+
+	// Bundle is the path of the bundle
+	Bundle string
+}
+
+// FindBundle returns the path of the only Native Build Bundle (*.nib) in the application. Returns false if there is
+// none.
+func FindBundle(applicationPath string) (string, bool, error) {
+	candidates, err := filepath.Glob(filepath.Join(applicationPath, "*.nib"))
+	if err != nil {
+		return "", false, fmt.Errorf("unable to find bundles in %s\n%w", applicationPath, err)
+	}
+
+	switch len(candidates) {
+	case 0:
+		return "", false, nil
+	case 1:
+		return candidates[0], true, nil
+	default:
+		return "", false, fmt.Errorf("found more than one bundle in %s: %s", applicationPath, strings.Join(candidates, ", "))
+	}
+}
+
+func (b BundleInvoker) Execution(dir string, _ string, _ []string, env []string) (effect.Execution, error) {
+	// native-image writes the build output next to the bundle, which is copied so that the application is unchanged
+	bundle := filepath.Join(dir, filepath.Base(b.Bundle))
+	if err := copyBinary(b.Bundle, bundle); err != nil {
+		return effect.Execution{}, fmt.Errorf("unable to copy bundle %s\n%w", b.Bundle, err)
+	}
+
+	return effect.Execution{Command: b.Command, Args: []string{fmt.Sprintf("--bundle-apply=%s", bundle)}, Dir: dir, Env: env}, nil
+}
+
+func (b BundleInvoker) Collect(dir string, binary string) error {
+	bundle := filepath.Join(dir, filepath.Base(b.Bundle))
+	output := strings.TrimSuffix(bundle, filepath.Ext(bundle)) + ".output"
+
+	image, err := harvest(filepath.Join(output, "default"), "native-image")
+	if err != nil {
+		return err
+	}
+	if err := copyBinary(image, filepath.Join(dir, binary)); err != nil {
+		return err
+	}
+
+	for _, f := range []string{bundle, output} {
+		if err := os.RemoveAll(f); err != nil {
+			return fmt.Errorf("unable to remove %s\n%w", f, err)
+		}
+	}
+	return nil
+}
+
+// BuildToolInvoker is an implementation of Invoker that delegates to the Maven or Gradle wrapper of the application and
+// Native Build Tools, which decide the native-image arguments
+type BuildToolInvoker struct {
+	ApplicationPath string
+	BuildTool       BuildTool
+}
+
+func (b BuildToolInvoker) Execution(_ string, _ string, _ []string, env []string) (effect.Execution, error) {
+	return b.BuildTool.Execution(b.ApplicationPath, env, nil, nil), nil
+}
+
+func (b BuildToolInvoker) Collect(dir string, binary string) error {
+	image, err := b.BuildTool.Harvest(b.ApplicationPath)
+	if err != nil {
+		return err
+	}
+
+	return copyBinary(image, filepath.Join(dir, binary))
+}
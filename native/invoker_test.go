@@ -0,0 +1,152 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package native_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/paketo-buildpacks/libpak/effect"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testInvoker(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		path string
+	)
+
+	it.Before(func() {
+		var err error
+
+		path, err = ioutil.TempDir("", "invoker")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(path)).To(Succeed())
+	})
+
+	context("DirectInvoker", func() {
+		it("passes the arguments on the command line", func() {
+			execution, err := native.DirectInvoker{Command: "native-image"}.Execution(path, "demo", []string{"-cp", "a.jar"}, []string{"PATH=/bin"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(execution).To(Equal(effect.Execution{
+				Command: "native-image",
+				Args:    []string{"-cp", "a.jar"},
+				Dir:     path,
+				Env:     []string{"PATH=/bin"},
+			}))
+		})
+	})
+
+	context("ArgfileInvoker", func() {
+		it("passes the arguments in an argument file", func() {
+			invoker := native.ArgfileInvoker{Command: "native-image"}
+
+			execution, err := invoker.Execution(path, "demo", []string{"-cp", "a.jar:b c.jar", `-Dx="y"`}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(execution.Args).To(Equal([]string{"@" + filepath.Join(path, native.ArgfileName)}))
+			Expect(ioutil.ReadFile(filepath.Join(path, native.ArgfileName))).To(Equal([]byte("\"-cp\"\n\"a.jar:b c.jar\"\n\"-Dx=\\\"y\\\"\"\n")))
+
+			Expect(invoker.Collect(path, "demo")).To(Succeed())
+			Expect(filepath.Join(path, native.ArgfileName)).NotTo(BeAnExistingFile())
+		})
+
+		it("escapes backslashes", func() {
+			Expect(native.Argfile([]string{`C:\app`})).To(Equal("\"C:\\\\app\"\n"))
+		})
+	})
+
+	context("BundleInvoker", func() {
+		var bundle string
+
+		it.Before(func() {
+			Expect(os.MkdirAll(filepath.Join(path, "application"), 0755)).To(Succeed())
+			Expect(os.MkdirAll(filepath.Join(path, "layer"), 0755)).To(Succeed())
+			bundle = filepath.Join(path, "application", "demo.nib")
+			Expect(ioutil.WriteFile(bundle, []byte("bundle"), 0644)).To(Succeed())
+		})
+
+		it("finds the bundle of the application", func() {
+			found, ok, err := native.FindBundle(filepath.Join(path, "application"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+			Expect(found).To(Equal(bundle))
+		})
+
+		it("fails with more than one bundle", func() {
+			Expect(ioutil.WriteFile(filepath.Join(path, "application", "other.nib"), []byte{}, 0644)).To(Succeed())
+
+			_, _, err := native.FindBundle(filepath.Join(path, "application"))
+			Expect(err).To(MatchError(ContainSubstring("found more than one bundle")))
+		})
+
+		it("builds a copy of the bundle and collects the native image", func() {
+			layer := filepath.Join(path, "layer")
+			invoker := native.BundleInvoker{Command: "native-image", Bundle: bundle}
+
+			execution, err := invoker.Execution(layer, "demo-app", []string{"-cp", "ignored"}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(execution.Args).To(Equal([]string{"--bundle-apply=" + filepath.Join(layer, "demo.nib")}))
+			Expect(ioutil.ReadFile(filepath.Join(layer, "demo.nib"))).To(Equal([]byte("bundle")))
+
+			output := filepath.Join(layer, "demo.output", "default")
+			Expect(os.MkdirAll(output, 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(output, "demo"), []byte("native-image"), 0755)).To(Succeed())
+
+			Expect(invoker.Collect(layer, "demo-app")).To(Succeed())
+			Expect(ioutil.ReadFile(filepath.Join(layer, "demo-app"))).To(Equal([]byte("native-image")))
+			Expect(filepath.Join(layer, "demo.nib")).NotTo(BeAnExistingFile())
+			Expect(filepath.Join(layer, "demo.output")).NotTo(BeAnExistingFile())
+			Expect(bundle).To(BeARegularFile())
+		})
+	})
+
+	context("BuildToolInvoker", func() {
+		it("runs the wrapper in the application and collects the native image", func() {
+			layer := filepath.Join(path, "layer")
+			Expect(os.MkdirAll(filepath.Join(path, "target"), 0755)).To(Succeed())
+			Expect(os.MkdirAll(layer, 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(path, "target", "demo"), []byte("native-image"), 0755)).To(Succeed())
+
+			invoker := native.BuildToolInvoker{
+				ApplicationPath: path,
+				BuildTool: native.BuildTool{
+					Name:            native.BuildToolMaven,
+					Wrapper:         filepath.Join(path, "mvnw"),
+					Args:            []string{"-Pnative", "native:compile"},
+					OutputDirectory: "target",
+				},
+			}
+
+			execution, err := invoker.Execution(layer, "demo-app", []string{"-cp", "ignored"}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(execution.Command).To(Equal(filepath.Join(path, "mvnw")))
+			Expect(execution.Args).To(Equal([]string{"-Pnative", "native:compile"}))
+			Expect(execution.Dir).To(Equal(path))
+
+			Expect(invoker.Collect(layer, "demo-app")).To(Succeed())
+			Expect(ioutil.ReadFile(filepath.Join(layer, "demo-app"))).To(Equal([]byte("native-image")))
+		})
+	})
+}
@@ -0,0 +1,147 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package native
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/buildpacks/libcnb"
+	"github.com/paketo-buildpacks/libpak/bindings"
+)
+
+// NativeLibrariesBindingType is the type of bindings holding the native libraries an application loads through JNI,
+// and optionally their jni-config.json
+const NativeLibrariesBindingType = "native-libraries"
+
+// JNIConfigurationFile is the name of the native-image JNI configuration
+const JNIConfigurationFile = "jni-config.json"
+
+// LibraryPathEnv is the environment variable native images take the default java.library.path from, and the dynamic
+// linker searches for the dependencies of native libraries
+const LibraryPathEnv = "LD_LIBRARY_PATH"
+
+// nativeLibraryExtensions are the file extensions of native libraries
+var nativeLibraryExtensions = []string{".so", ".dylib", ".dll"}
+
+// ParseNativeLibraryPatterns parses comma separated glob patterns of native libraries, relative to the application,
+// e.g. BOOT-INF/lib/*.so
+func ParseNativeLibraryPatterns(value string) []string {
+	var patterns []string
+
+	for _, p := range strings.Split(value, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			patterns = append(patterns, strings.TrimPrefix(filepath.ToSlash(p), "/"))
+		}
+	}
+
+	return patterns
+}
+
+// FindNativeLibraries returns the native libraries in the application matching one of the glob patterns, followed by
+// the native libraries in bindings of type native-libraries, and the bindings holding a jni-config.json. Libraries
+// are copied next to the binary, so their file names must be unique.
+func FindNativeLibraries(appPath string, patterns []string, binds libcnb.Bindings) ([]string, []string, error) {
+	var (
+		libraries   []string
+		directories []string
+	)
+
+	if len(patterns) > 0 {
+		var expressions []*regexp.Regexp
+		for _, p := range patterns {
+			expressions = append(expressions, regexp.MustCompile("^(?:"+ResourceGlobToRegex(p)+")$"))
+		}
+
+		if err := filepath.Walk(appPath, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.Mode().IsRegular() {
+				return nil
+			}
+
+			rel, err := filepath.Rel(appPath, path)
+			if err != nil {
+				return err
+			}
+			if matchesAny(expressions, filepath.ToSlash(rel)) {
+				libraries = append(libraries, path)
+			}
+			return nil
+		}); err != nil {
+			return nil, nil, fmt.Errorf("unable to find native libraries in %s\n%w", appPath, err)
+		}
+	}
+
+	for _, b := range bindings.Resolve(binds, bindings.OfType(NativeLibrariesBindingType)) {
+		children, err := ioutil.ReadDir(b.Path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to list children of %s\n%w", b.Path, err)
+		}
+
+		var found []string
+		for _, c := range children {
+			// Kubernetes mounts bindings through hidden ..data directories
+			if strings.HasPrefix(c.Name(), ".") || c.IsDir() {
+				continue
+			}
+
+			if c.Name() == JNIConfigurationFile {
+				directories = append(directories, b.Path)
+			} else if isNativeLibrary(c.Name()) {
+				found = append(found, filepath.Join(b.Path, c.Name()))
+			}
+		}
+		sort.Strings(found)
+		libraries = append(libraries, found...)
+	}
+
+	names := map[string]string{}
+	for _, l := range libraries {
+		if other, ok := names[filepath.Base(l)]; ok {
+			return nil, nil, fmt.Errorf("native libraries %s and %s have the same name", other, l)
+		}
+		names[filepath.Base(l)] = l
+	}
+
+	return libraries, directories, nil
+}
+
+// isNativeLibrary returns whether name is the file name of a native library, including versioned shared objects such
+// as libfoo.so.1
+func isNativeLibrary(name string) bool {
+	for _, e := range nativeLibraryExtensions {
+		if strings.HasSuffix(name, e) || strings.Contains(name, e+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// NativeLibraryNames returns the file names of native libraries
+func NativeLibraryNames(libraries []string) []string {
+	var names []string
+	for _, l := range libraries {
+		names = append(names, filepath.Base(l))
+	}
+	return names
+}
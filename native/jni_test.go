@@ -0,0 +1,98 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package native_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/buildpacks/libcnb"
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testJNI(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		appPath     string
+		bindingPath string
+	)
+
+	it.Before(func() {
+		var err error
+
+		appPath, err = ioutil.TempDir("", "jni-application")
+		Expect(err).NotTo(HaveOccurred())
+		bindingPath, err = ioutil.TempDir("", "jni-binding")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(os.MkdirAll(filepath.Join(appPath, "BOOT-INF", "lib"), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(appPath, "BOOT-INF", "lib", "libzstd.so"), []byte{}, 0644)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(appPath, "BOOT-INF", "lib", "zstd-1.0.jar"), []byte{}, 0644)).To(Succeed())
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(appPath)).To(Succeed())
+		Expect(os.RemoveAll(bindingPath)).To(Succeed())
+	})
+
+	it("parses native library patterns", func() {
+		Expect(native.ParseNativeLibraryPatterns(" BOOT-INF/lib/*.so, /lib/*.so.*,")).To(Equal([]string{"BOOT-INF/lib/*.so", "lib/*.so.*"}))
+	})
+
+	it("finds native libraries in the application", func() {
+		libraries, dirs, err := native.FindNativeLibraries(appPath, []string{"BOOT-INF/lib/*.so"}, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(libraries).To(Equal([]string{filepath.Join(appPath, "BOOT-INF", "lib", "libzstd.so")}))
+		Expect(dirs).To(BeEmpty())
+	})
+
+	it("does not look in the application without patterns", func() {
+		libraries, _, err := native.FindNativeLibraries(appPath, nil, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(libraries).To(BeEmpty())
+	})
+
+	it("finds native libraries and JNI configuration in bindings", func() {
+		Expect(ioutil.WriteFile(filepath.Join(bindingPath, "libcrypto.so.3"), []byte{}, 0644)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(bindingPath, "jni-config.json"), []byte("[]"), 0644)).To(Succeed())
+
+		libraries, dirs, err := native.FindNativeLibraries(appPath, nil, libcnb.Bindings{
+			{Name: "jni", Type: "native-libraries", Path: bindingPath, Secret: map[string]string{}},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(libraries).To(Equal([]string{filepath.Join(bindingPath, "libcrypto.so.3")}))
+		Expect(dirs).To(Equal([]string{bindingPath}))
+	})
+
+	it("fails on native libraries with the same name", func() {
+		Expect(ioutil.WriteFile(filepath.Join(bindingPath, "libzstd.so"), []byte{}, 0644)).To(Succeed())
+
+		_, _, err := native.FindNativeLibraries(appPath, []string{"BOOT-INF/lib/*.so"}, libcnb.Bindings{
+			{Name: "jni", Type: "native-libraries", Path: bindingPath},
+		})
+		Expect(err).To(MatchError(ContainSubstring("have the same name")))
+	})
+
+	it("returns the names of native libraries", func() {
+		Expect(native.NativeLibraryNames([]string{"/a/libzstd.so", "/b/libcrypto.so.3"})).To(Equal([]string{"libzstd.so", "libcrypto.so.3"}))
+	})
+}
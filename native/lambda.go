@@ -0,0 +1,119 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// TargetAWSLambda packages the native image as an AWS Lambda custom runtime
+	TargetAWSLambda = "aws-lambda"
+
+	// LambdaBootstrap is the executable AWS Lambda custom runtimes start
+	LambdaBootstrap = "bootstrap"
+
+	// LambdaArchive is the deployable archive of a custom runtime written into the application
+	LambdaArchive = "function.zip"
+)
+
+// ParseTarget validates the packaging target of the native image. An empty target contributes the image only.
+func ParseTarget(target string) (string, error) {
+	switch target {
+	case "", TargetAWSLambda:
+		return target, nil
+	default:
+		return "", fmt.Errorf("unsupported target %q, expected %s", target, TargetAWSLambda)
+	}
+}
+
+// LambdaShim returns the bootstrap script starting the native image from the task root of the function
+func LambdaShim(binary string) string {
+	return fmt.Sprintf(`#!/bin/sh
+set -e
+
+cd "$(dirname "$0")"
+exec ./%s "$@"
+`, binary)
+}
+
+// PackageLambda writes the bootstrap shim next to the native image and an archive of both, deployable as a custom
+// runtime. Entries are dated modTime, or their modification time if it is zero.
+func PackageLambda(applicationPath string, binary string, modTime time.Time) error {
+	bootstrap := filepath.Join(applicationPath, LambdaBootstrap)
+	if err := ioutil.WriteFile(bootstrap, []byte(LambdaShim(binary)), 0755); err != nil {
+		return fmt.Errorf("unable to write %s\n%w", bootstrap, err)
+	}
+
+	file := filepath.Join(applicationPath, LambdaArchive)
+	out, err := os.Create(file)
+	if err != nil {
+		return fmt.Errorf("unable to create %s\n%w", file, err)
+	}
+	defer out.Close()
+
+	z := zip.NewWriter(out)
+	for _, name := range []string{LambdaBootstrap, binary} {
+		if err := addExecutable(z, filepath.Join(applicationPath, name), name, modTime); err != nil {
+			return fmt.Errorf("unable to add %s to %s\n%w", name, file, err)
+		}
+	}
+
+	if err := z.Close(); err != nil {
+		return fmt.Errorf("unable to close %s\n%w", file, err)
+	}
+
+	return nil
+}
+
+func addExecutable(z *zip.Writer, path string, name string, modTime time.Time) error {
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	fi, err := in.Stat()
+	if err != nil {
+		return err
+	}
+
+	header, err := zip.FileInfoHeader(fi)
+	if err != nil {
+		return err
+	}
+	header.Name = name
+	header.Method = zip.Deflate
+	header.SetMode(0755)
+	if !modTime.IsZero() {
+		header.Modified = modTime
+	}
+
+	w, err := z.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(w, in)
+	return err
+}
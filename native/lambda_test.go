@@ -0,0 +1,83 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"archive/zip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testLambda(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		path string
+	)
+
+	it.Before(func() {
+		var err error
+
+		path, err = ioutil.TempDir("", "lambda")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(path)).To(Succeed())
+	})
+
+	it("parses the target", func() {
+		Expect(native.ParseTarget("")).To(BeEmpty())
+		Expect(native.ParseTarget("aws-lambda")).To(Equal(native.TargetAWSLambda))
+
+		_, err := native.ParseTarget("azure-functions")
+		Expect(err).To(MatchError(ContainSubstring(`unsupported target "azure-functions"`)))
+	})
+
+	it("starts the native image from the bootstrap shim", func() {
+		Expect(native.LambdaShim("com.example.Function")).To(ContainSubstring(`exec ./com.example.Function "$@"`))
+	})
+
+	it("packages the bootstrap shim and native image", func() {
+		Expect(ioutil.WriteFile(filepath.Join(path, "com.example.Function"), []byte("native-image"), 0755)).To(Succeed())
+		epoch := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		Expect(native.PackageLambda(path, "com.example.Function", epoch)).To(Succeed())
+
+		Expect(filepath.Join(path, native.LambdaBootstrap)).To(BeARegularFile())
+
+		z, err := zip.OpenReader(filepath.Join(path, native.LambdaArchive))
+		Expect(err).NotTo(HaveOccurred())
+		defer z.Close()
+
+		Expect(z.File).To(HaveLen(2))
+		Expect(z.File[0].Name).To(Equal("bootstrap"))
+		Expect(z.File[1].Name).To(Equal("com.example.Function"))
+		for _, f := range z.File {
+			Expect(f.Mode().Perm()).To(Equal(os.FileMode(0755)))
+			Expect(f.Modified.UTC()).To(Equal(epoch))
+		}
+	})
+}
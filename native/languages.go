@@ -0,0 +1,163 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"fmt"
+)
+
+// LanguageDefaultsMetadataKey is the key of the JVM language defaults in the buildpack metadata
+const LanguageDefaultsMetadataKey = "language-defaults"
+
+// LanguageDefaults are the classes to initialize at build time and the resources to include that the runtime of a JVM
+// language other than Java requires, applied when one of its artifacts is on the classpath. Platform operators
+// maintain the defaults in the buildpack metadata.
+type LanguageDefaults struct {
+	Name                  string   `toml:"name"`
+	Coordinates           []string `toml:"coordinates"`
+	InitializeAtBuildTime []string `toml:"initialize-at-build-time"`
+	IncludeResources      []string `toml:"include-resources"`
+}
+
+// DefaultLanguageDefaults are used when the buildpack metadata does not have any
+var DefaultLanguageDefaults = []LanguageDefaults{
+	{
+		Name:        "Kotlin",
+		Coordinates: []string{"org.jetbrains.kotlin:kotlin-stdlib"},
+		InitializeAtBuildTime: []string{
+			"kotlin.DeprecationLevel",
+			"kotlin.annotation.AnnotationRetention",
+			"kotlin.annotation.AnnotationTarget",
+			"kotlin.coroutines.intrinsics.CoroutineSingletons",
+		},
+		IncludeResources: []string{"META-INF/*.kotlin_module", "kotlin/**/*.kotlin_builtins"},
+	},
+	{
+		Name:                  "Scala",
+		Coordinates:           []string{"org.scala-lang:scala-library", "org.scala-lang:scala3-library_3"},
+		InitializeAtBuildTime: []string{"scala.Symbol", "scala.Symbol$", "scala.runtime.Statics$VM"},
+		IncludeResources:      []string{"library.properties"},
+	},
+}
+
+// ParseLanguageDefaults reads the JVM language defaults from the buildpack metadata
+func ParseLanguageDefaults(metadata map[string]interface{}) ([]LanguageDefaults, error) {
+	var entries []map[string]interface{}
+
+	switch v := metadata[LanguageDefaultsMetadataKey].(type) {
+	case nil:
+		return DefaultLanguageDefaults, nil
+	case []map[string]interface{}:
+		entries = v
+	case []interface{}:
+		for _, e := range v {
+			m, ok := e.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("invalid %s entry %v", LanguageDefaultsMetadataKey, e)
+			}
+			entries = append(entries, m)
+		}
+	default:
+		return nil, fmt.Errorf("invalid %s %v", LanguageDefaultsMetadataKey, v)
+	}
+
+	var defaults []LanguageDefaults
+	for _, e := range entries {
+		d := LanguageDefaults{}
+		d.Name, _ = e["name"].(string)
+
+		var err error
+		if d.Coordinates, err = metadataStrings(e, "coordinates"); err != nil {
+			return nil, err
+		}
+		if d.InitializeAtBuildTime, err = metadataStrings(e, "initialize-at-build-time"); err != nil {
+			return nil, err
+		}
+		if d.IncludeResources, err = metadataStrings(e, "include-resources"); err != nil {
+			return nil, err
+		}
+
+		if d.Name == "" || len(d.Coordinates) == 0 {
+			return nil, fmt.Errorf("invalid %s entry %v, expected a name and coordinates", LanguageDefaultsMetadataKey, e)
+		}
+
+		defaults = append(defaults, d)
+	}
+
+	return defaults, nil
+}
+
+// metadataStrings returns the list of strings of a key of a metadata entry
+func metadataStrings(entry map[string]interface{}, key string) ([]string, error) {
+	switch v := entry[key].(type) {
+	case nil:
+		return nil, nil
+	case []string:
+		return v, nil
+	case []interface{}:
+		var values []string
+		for _, s := range v {
+			value, ok := s.(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid %s entry %v, expected %s to be a list of strings", LanguageDefaultsMetadataKey, entry, key)
+			}
+			values = append(values, value)
+		}
+		return values, nil
+	default:
+		return nil, fmt.Errorf("invalid %s entry %v, expected %s to be a list of strings", LanguageDefaultsMetadataKey, entry, key)
+	}
+}
+
+// FindLanguageDefaults returns the defaults of the languages whose artifacts are in the dependencies of the classpath,
+// with the artifact found for each. Artifacts are also matched on their file name, as the language runtimes do not all
+// carry a pom.properties.
+func FindLanguageDefaults(dependencies Dependencies, defaults []LanguageDefaults) ([]LanguageDefaults, []Artifact) {
+	var (
+		found     []LanguageDefaults
+		artifacts []Artifact
+	)
+
+	named := dependencies.WithNames()
+	for _, d := range defaults {
+		if a, ok := named.Find(d.Coordinates...); ok {
+			found = append(found, d)
+			artifacts = append(artifacts, a)
+		}
+	}
+
+	return found, artifacts
+}
+
+// MergeLanguageDefaults adds the classes and resources of the language defaults to those configured by the end user.
+// Classes the end user initializes at run time are not initialized at build time, and entries are not repeated.
+func MergeLanguageDefaults(defaults []LanguageDefaults, buildTime []string, runTime []string, resources []string) ([]string, []string) {
+	for _, d := range defaults {
+		for _, c := range d.InitializeAtBuildTime {
+			if !containsString(buildTime, c) && !containsString(runTime, c) {
+				buildTime = append(buildTime, c)
+			}
+		}
+		for _, r := range d.IncludeResources {
+			if !containsString(resources, r) {
+				resources = append(resources, r)
+			}
+		}
+	}
+
+	return buildTime, resources
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testLanguages(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+	)
+
+	context("ParseLanguageDefaults", func() {
+		it("returns the default table", func() {
+			Expect(native.ParseLanguageDefaults(map[string]interface{}{})).To(Equal(native.DefaultLanguageDefaults))
+		})
+
+		it("parses the table", func() {
+			Expect(native.ParseLanguageDefaults(map[string]interface{}{
+				"language-defaults": []interface{}{
+					map[string]interface{}{
+						"name":                     "Groovy",
+						"coordinates":              []interface{}{"org.apache.groovy:groovy"},
+						"initialize-at-build-time": []interface{}{"groovy.lang.MetaClassImpl"},
+					},
+				},
+			})).To(Equal([]native.LanguageDefaults{
+				{Name: "Groovy", Coordinates: []string{"org.apache.groovy:groovy"}, InitializeAtBuildTime: []string{"groovy.lang.MetaClassImpl"}},
+			}))
+		})
+
+		it("fails without coordinates", func() {
+			_, err := native.ParseLanguageDefaults(map[string]interface{}{
+				"language-defaults": []interface{}{map[string]interface{}{"name": "Groovy"}},
+			})
+			Expect(err).To(MatchError(ContainSubstring("expected a name and coordinates")))
+		})
+
+		it("fails on lists that are not strings", func() {
+			_, err := native.ParseLanguageDefaults(map[string]interface{}{
+				"language-defaults": []interface{}{
+					map[string]interface{}{"name": "Groovy", "coordinates": "org.apache.groovy:groovy"},
+				},
+			})
+			Expect(err).To(MatchError(ContainSubstring("expected coordinates to be a list of strings")))
+		})
+	})
+
+	it("finds the languages on the classpath", func() {
+		dependencies, err := native.DetectDependencies(native.NewDependencyDetector(), "/workspace", []string{
+			"BOOT-INF/lib/spring-boot-3.1.0.jar",
+			"BOOT-INF/lib/kotlin-stdlib-1.8.22.jar",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		languages, artifacts := native.FindLanguageDefaults(dependencies, native.DefaultLanguageDefaults)
+		Expect(languages).To(HaveLen(1))
+		Expect(languages[0].Name).To(Equal("Kotlin"))
+		Expect(artifacts[0].Version).To(Equal("1.8.22"))
+	})
+
+	it("merges the defaults with the configuration of the end user", func() {
+		buildTime, resources := native.MergeLanguageDefaults(
+			[]native.LanguageDefaults{{
+				Name:                  "Scala",
+				InitializeAtBuildTime: []string{"scala.Symbol", "scala.Symbol$", "scala.runtime.Statics$VM"},
+				IncludeResources:      []string{"library.properties"},
+			}},
+			[]string{"scala.Symbol", "com.example"},
+			[]string{"scala.runtime.Statics$VM"},
+			[]string{"static/**"},
+		)
+
+		Expect(buildTime).To(Equal([]string{"scala.Symbol", "com.example", "scala.Symbol$"}))
+		Expect(resources).To(Equal([]string{"static/**", "library.properties"}))
+	})
+}
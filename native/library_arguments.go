@@ -0,0 +1,180 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/magiconair/properties"
+	"github.com/paketo-buildpacks/libpak/bard"
+)
+
+// LibraryArguments merges the Args of the native-image.properties files found in the classpath entries
+type LibraryArguments struct {
+	Classpath []string
+	Logger    bard.Logger
+}
+
+// Configure appends the library arguments, in classpath order and without duplicates, to inputArgs
+func (l LibraryArguments) Configure(inputArgs []string) ([]string, string, error) {
+	for _, entry := range l.Classpath {
+		args, err := ReadLibraryArguments(entry)
+		if err != nil {
+			return []string{}, "", fmt.Errorf("unable to read native-image.properties in %s\n%w", entry, err)
+		}
+
+		var added []string
+		for _, a := range args {
+			if !containsString(inputArgs, a) {
+				inputArgs = append(inputArgs, a)
+				added = append(added, a)
+			}
+		}
+
+		if len(added) > 0 {
+			l.Logger.Bodyf("Adding arguments from %s: %s", filepath.Base(entry), strings.Join(added, " "))
+		} else if len(args) > 0 {
+			l.Logger.Debugf("Skipping arguments from %s, which are already set: %s", filepath.Base(entry), strings.Join(args, " "))
+		}
+	}
+
+	return inputArgs, "", nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ReadLibraryArguments returns the Args of the native-image.properties files in a JAR or directory classpath entry.
+// Arguments that refer to the location of the properties file with ${.} are only supported in directories.
+func ReadLibraryArguments(entry string) ([]string, error) {
+	fi, err := os.Stat(entry)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("unable to stat %s\n%w", entry, err)
+	}
+
+	var args []string
+	if fi.IsDir() {
+		files, err := findNativeImageProperties(entry)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, f := range files {
+			b, err := ioutil.ReadFile(f)
+			if err != nil {
+				return nil, fmt.Errorf("unable to read %s\n%w", f, err)
+			}
+			args = append(args, parseLibraryArguments(string(b), filepath.Dir(f))...)
+		}
+
+		return args, nil
+	}
+
+	z, err := openJAR(entry)
+	if err != nil || z == nil {
+		return nil, err
+	}
+	defer z.Close()
+
+	sort.Slice(z.File, func(i, j int) bool { return z.File[i].Name < z.File[j].Name })
+	for _, f := range z.File {
+		if !strings.HasPrefix(f.Name, "META-INF/native-image/") || path.Base(f.Name) != "native-image.properties" {
+			continue
+		}
+
+		in, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("unable to open %s in %s\n%w", f.Name, entry, err)
+		}
+		b, err := ioutil.ReadAll(in)
+		in.Close()
+		if err != nil {
+			return nil, fmt.Errorf("unable to read %s in %s\n%w", f.Name, entry, err)
+		}
+
+		args = append(args, parseLibraryArguments(string(b), "")...)
+	}
+
+	return args, nil
+}
+
+// findNativeImageProperties returns the native-image.properties files below META-INF/native-image of a directory
+func findNativeImageProperties(dir string) ([]string, error) {
+	root := filepath.Join(dir, "META-INF", "native-image")
+
+	var files []string
+	err := filepath.Walk(root, func(p string, info os.FileInfo, err error) error {
+		if os.IsNotExist(err) {
+			return filepath.SkipDir
+		} else if err != nil {
+			return err
+		}
+
+		if !info.IsDir() && info.Name() == "native-image.properties" {
+			files = append(files, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to walk %s\n%w", root, err)
+	}
+
+	return files, nil
+}
+
+// parseLibraryArguments returns the Args of a native-image.properties file, replacing ${.} with dir. Arguments
+// referring to ${.} are dropped when dir is empty.
+func parseLibraryArguments(content string, dir string) []string {
+	// ${.} is substituted by native-image rather than being a property reference
+	p, err := (&properties.Loader{Encoding: properties.UTF8, DisableExpansion: true}).LoadBytes([]byte(content))
+	if err != nil {
+		return nil
+	}
+
+	value, ok := p.Get("Args")
+	if !ok {
+		return nil
+	}
+
+	var args []string
+	for _, a := range strings.Fields(value) {
+		if strings.Contains(a, "${.}") {
+			if dir == "" {
+				continue
+			}
+			a = strings.ReplaceAll(a, "${.}", dir)
+		}
+		args = append(args, a)
+	}
+
+	return args
+}
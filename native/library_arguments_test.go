@@ -0,0 +1,111 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/paketo-buildpacks/libpak/bard"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testLibraryArguments(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		appPath string
+
+		writeJAR = func(name string, files map[string]string) string {
+			file := filepath.Join(appPath, name)
+			out, err := os.Create(file)
+			Expect(err).NotTo(HaveOccurred())
+			defer out.Close()
+
+			z := zip.NewWriter(out)
+			for n, c := range files {
+				w, err := z.Create(n)
+				Expect(err).NotTo(HaveOccurred())
+				_, err = w.Write([]byte(c))
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Expect(z.Close()).To(Succeed())
+
+			return file
+		}
+	)
+
+	it.Before(func() {
+		var err error
+
+		appPath, err = ioutil.TempDir("", "library-arguments-application")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(appPath)).To(Succeed())
+	})
+
+	it("reads arguments from a JAR", func() {
+		jar := writeJAR("netty.jar", map[string]string{
+			"META-INF/native-image/io.netty/netty-common/native-image.properties": "Args = --initialize-at-run-time=io.netty.util.internal.logging.Log4JLogger \\\n  -H:ResourceConfigurationResources=${.}/resource-config.json",
+		})
+
+		Expect(native.ReadLibraryArguments(jar)).To(Equal([]string{
+			"--initialize-at-run-time=io.netty.util.internal.logging.Log4JLogger",
+		}))
+	})
+
+	it("reads arguments from a directory", func() {
+		dir := filepath.Join(appPath, "classes", "META-INF", "native-image", "com.example", "app")
+		Expect(os.MkdirAll(dir, 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(dir, "native-image.properties"), []byte("Args = -H:ResourceConfigurationFiles=${.}/resource-config.json"), 0644)).To(Succeed())
+
+		Expect(native.ReadLibraryArguments(filepath.Join(appPath, "classes"))).To(Equal([]string{
+			"-H:ResourceConfigurationFiles=" + filepath.Join(dir, "resource-config.json"),
+		}))
+	})
+
+	it("ignores missing entries and entries without properties", func() {
+		Expect(native.ReadLibraryArguments(filepath.Join(appPath, "missing.jar"))).To(BeEmpty())
+		Expect(native.ReadLibraryArguments(writeJAR("plain.jar", map[string]string{"META-INF/MANIFEST.MF": ""}))).To(BeEmpty())
+		Expect(native.ReadLibraryArguments(appPath)).To(BeEmpty())
+	})
+
+	it("merges arguments in classpath order without duplicates", func() {
+		a := writeJAR("a.jar", map[string]string{
+			"META-INF/native-image/a/a/native-image.properties": "Args = --enable-http -H:+ReportExceptionStackTraces",
+		})
+		b := writeJAR("b.jar", map[string]string{
+			"META-INF/native-image/b/b/native-image.properties": "Args = --enable-http --enable-https",
+		})
+		log := &bytes.Buffer{}
+
+		args, _, err := native.LibraryArguments{Classpath: []string{a, b}, Logger: bard.NewLogger(log)}.Configure([]string{"--no-fallback"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(args).To(Equal([]string{"--no-fallback", "--enable-http", "-H:+ReportExceptionStackTraces", "--enable-https"}))
+		Expect(log.String()).To(ContainSubstring("Adding arguments from a.jar: --enable-http -H:+ReportExceptionStackTraces"))
+		Expect(log.String()).To(ContainSubstring("Adding arguments from b.jar: --enable-https"))
+	})
+}
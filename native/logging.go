@@ -0,0 +1,73 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// LoggingDirectory is the directory of the buildpack holding the native-image configuration of each logging backend
+const LoggingDirectory = "resources/logging"
+
+// LoggingBackend is a logging backend that reads its configuration file and instantiates the appenders and layouts
+// it names reflectively, which native images only support with configuration
+type LoggingBackend struct {
+	// Name is the name of the directory of the configuration of the backend in LoggingDirectory
+	Name        string
+	Coordinates []string
+}
+
+// LoggingBackends are the logging backends with configuration in the buildpack
+var LoggingBackends = []LoggingBackend{
+	{Name: "logback", Coordinates: []string{"ch.qos.logback:logback-classic"}},
+	{Name: "log4j2", Coordinates: []string{"org.apache.logging.log4j:log4j-core"}},
+}
+
+// FindLoggingBackends returns the logging backends in the dependencies of the classpath, with the artifact found for
+// each
+func FindLoggingBackends(dependencies Dependencies) ([]LoggingBackend, []Artifact) {
+	var (
+		backends  []LoggingBackend
+		artifacts []Artifact
+	)
+
+	for _, b := range LoggingBackends {
+		if a, ok := dependencies.Find(b.Coordinates...); ok {
+			backends = append(backends, b)
+			artifacts = append(artifacts, a)
+		}
+	}
+
+	return backends, artifacts
+}
+
+// LoggingConfigurationDirectory returns the directory of the native-image configuration of a logging backend in the
+// buildpack, or false if the buildpack does not have one
+func LoggingConfigurationDirectory(buildpackPath string, backend LoggingBackend) (string, bool, error) {
+	dir := filepath.Join(buildpackPath, LoggingDirectory, backend.Name)
+
+	fi, err := os.Stat(dir)
+	if os.IsNotExist(err) {
+		return "", false, nil
+	} else if err != nil {
+		return "", false, fmt.Errorf("unable to stat %s\n%w", dir, err)
+	}
+
+	return dir, fi.IsDir(), nil
+}
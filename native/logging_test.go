@@ -0,0 +1,82 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testLogging(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+	)
+
+	it("finds logging backends", func() {
+		dependencies, err := native.DetectDependencies(native.NewDependencyDetector(), "/workspace", []string{
+			"BOOT-INF/lib/log4j-core-2.20.0.jar",
+			"BOOT-INF/lib/logback-classic-1.4.8.jar",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		backends, artifacts := native.FindLoggingBackends(dependencies)
+		Expect(backends).To(Equal(native.LoggingBackends))
+		Expect(artifacts[0].ArtifactID).To(Equal("logback-classic"))
+		Expect(artifacts[1].Version).To(Equal("2.20.0"))
+	})
+
+	it("does not find other artifacts", func() {
+		dependencies, err := native.DetectDependencies(native.NewDependencyDetector(), "/workspace", []string{
+			"BOOT-INF/lib/log4j-api-2.20.0.jar",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		backends, _ := native.FindLoggingBackends(dependencies)
+		Expect(backends).To(BeEmpty())
+	})
+
+	it("returns false without configuration in the buildpack", func() {
+		_, ok, err := native.LoggingConfigurationDirectory(t.TempDir(), native.LoggingBackends[0])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeFalse())
+	})
+
+	it("bundles valid configuration for every backend", func() {
+		for _, b := range native.LoggingBackends {
+			dir, ok, err := native.LoggingConfigurationDirectory("..", b)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue(), b.Name)
+
+			var reflect []map[string]interface{}
+			content, err := ioutil.ReadFile(filepath.Join(dir, "reflect-config.json"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(json.Unmarshal(content, &reflect)).To(Succeed(), b.Name)
+			Expect(reflect).NotTo(BeEmpty())
+
+			var resources map[string]interface{}
+			content, err = ioutil.ReadFile(filepath.Join(dir, "resource-config.json"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(json.Unmarshal(content, &resources)).To(Succeed(), b.Name)
+			Expect(resources).To(HaveKey("resources"))
+		}
+	})
+}
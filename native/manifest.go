@@ -0,0 +1,86 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/magiconair/properties"
+)
+
+// NewManifest reads the META-INF/MANIFEST.MF of an exploded JAR. Returns empty properties if the application does not
+// have a manifest.
+func NewManifest(applicationPath string) (*properties.Properties, error) {
+	file := filepath.Join(applicationPath, "META-INF", "MANIFEST.MF")
+
+	in, err := os.Open(file)
+	if os.IsNotExist(err) {
+		return properties.NewProperties(), nil
+	} else if err != nil {
+		return nil, fmt.Errorf("unable to open %s\n%w", file, err)
+	}
+	defer in.Close()
+
+	return loadManifest(in, file)
+}
+
+// NewManifestFromJAR reads the META-INF/MANIFEST.MF of a JAR file. Returns empty properties if the JAR does not have a
+// manifest.
+func NewManifestFromJAR(file string) (*properties.Properties, error) {
+	z, err := zip.OpenReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read file %s\n%w", file, err)
+	}
+	defer z.Close()
+
+	in, err := z.Open("META-INF/MANIFEST.MF")
+	if errors.Is(err, fs.ErrNotExist) {
+		return properties.NewProperties(), nil
+	} else if err != nil {
+		return nil, fmt.Errorf("unable to read MANIFEST.MF in %s\n%w", file, err)
+	}
+	defer in.Close()
+
+	return loadManifest(in, file)
+}
+
+// loadManifest parses a manifest, joining continuation lines. Unlike the libjvm equivalent it does not pull in
+// packages that only build on Unix, so that the buildpack also builds for Windows builders.
+func loadManifest(in io.Reader, source string) (*properties.Properties, error) {
+	b, err := io.ReadAll(in)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read MANIFEST.MF in %s\n%w", source, err)
+	}
+
+	b = bytes.ReplaceAll(b, []byte("\r\n"), []byte("\n"))
+	b = bytes.ReplaceAll(b, []byte("\r"), []byte("\n"))
+	b = bytes.ReplaceAll(b, []byte("\n "), []byte{})
+
+	manifest, err := properties.Load(b, properties.UTF8)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse properties in MANIFEST.MF in %s\n%w", source, err)
+	}
+
+	return manifest, nil
+}
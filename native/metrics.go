@@ -0,0 +1,183 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/buildpacks/libcnb"
+)
+
+// MetricsMetadataKey is the key of the build metrics in the native image layer metadata
+const MetricsMetadataKey = "metrics"
+
+const (
+	LabelMetricsDuration        = "io.paketo.native-image.build-duration-seconds"
+	LabelMetricsPeakRSS         = "io.paketo.native-image.peak-rss-bytes"
+	LabelMetricsBinarySize      = "io.paketo.native-image.binary-size-bytes"
+	LabelMetricsGraalVMVersion  = "io.paketo.native-image.graalvm-version"
+	LabelMetricsArgumentsDigest = "io.paketo.native-image.arguments-digest"
+	LabelMetricsGraalVMRelease  = "io.paketo.native-image.graalvm-release"
+	LabelMetricsDistribution    = "io.paketo.native-image.distribution"
+	LabelArguments              = "io.paketo.native-image.arguments"
+	LabelSpringNativeVersion    = "io.paketo.native-image.spring-native-version"
+)
+
+// Metrics are measurements of a native-image build
+type Metrics struct {
+	Duration        time.Duration
+	PeakRSS         int64
+	BinarySize      int64
+	GraalVMVersion  string
+	ArgumentsDigest string
+	ClassCount      int64
+
+	// Distribution is the GraalVM distribution providing native-image. It is not stored in layer metadata.
+	Distribution string
+
+	// Rebuilt is whether the native image was compiled by this build rather than reused from the cache. It is not
+	// stored in layer metadata.
+	Rebuilt bool
+
+	// Arguments are the redacted native-image arguments, written to a JSON array label when recording is requested
+	Arguments []string
+
+	// Labels are the image labels of the build result. libcnb writes labels only after every layer has been
+	// contributed, so Build shares them once its last label is appended and the contributor fills in the values of
+	// the labels whose key is a metric.
+	Labels []libcnb.Label
+}
+
+// MetricsLabelKeys are the keys of the image labels metrics are written to, in order
+var MetricsLabelKeys = []string{
+	LabelMetricsDuration,
+	LabelMetricsPeakRSS,
+	LabelMetricsBinarySize,
+	LabelMetricsGraalVMVersion,
+	LabelMetricsArgumentsDigest,
+	LabelMetricsGraalVMRelease,
+	LabelMetricsDistribution,
+}
+
+// ArgumentsDigest returns the SHA-256 digest of a native-image argument list
+func ArgumentsDigest(arguments []string) string {
+	return fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(strings.Join(arguments, "\x00"))))
+}
+
+// GraalVMVersion returns the first line of the output of native-image --version
+func GraalVMVersion(versionOutput string) string {
+	return strings.TrimSpace(strings.SplitN(strings.TrimSpace(versionOutput), "\n", 2)[0])
+}
+
+// releasePattern matches the first version of the first line of the output of native-image --version, the GraalVM
+// version of releases versioned independently of the JDK, and the JDK version of releases versioned like the JDK
+var releasePattern = regexp.MustCompile(`\b(\d+\.\d+\.\d+)\b`)
+
+// GraalVMRelease returns the version of the GraalVM release providing native-image, such as 22.3.0, or an empty
+// string if the version output has none, so that scanners can match images against vulnerable or EOL releases
+func GraalVMRelease(version string) string {
+	if v, ok := ParseGraalVMVersion(version); ok {
+		return v.String()
+	}
+
+	if m := releasePattern.FindStringSubmatch(GraalVMVersion(version)); m != nil {
+		return m[1]
+	}
+
+	return ""
+}
+
+// Metadata returns the metrics in the form stored in layer metadata
+func (m Metrics) Metadata() map[string]interface{} {
+	return map[string]interface{}{
+		"duration-seconds": m.Duration.Seconds(),
+		"peak-rss-bytes":   m.PeakRSS,
+		"binary-size":      m.BinarySize,
+		"graalvm-version":  m.GraalVMVersion,
+		"arguments-digest": m.ArgumentsDigest,
+		"class-count":      m.ClassCount,
+	}
+}
+
+// MetricsFromMetadata restores metrics stored in layer metadata
+func MetricsFromMetadata(metadata interface{}) (Metrics, bool) {
+	md, ok := metadata.(map[string]interface{})
+	if !ok {
+		return Metrics{}, false
+	}
+
+	m := Metrics{}
+	if v, ok := md["duration-seconds"].(float64); ok {
+		m.Duration = time.Duration(v * float64(time.Second))
+	}
+	m.PeakRSS = toInt64(md["peak-rss-bytes"])
+	m.BinarySize = toInt64(md["binary-size"])
+	m.GraalVMVersion, _ = md["graalvm-version"].(string)
+	m.ArgumentsDigest, _ = md["arguments-digest"].(string)
+	m.ClassCount = toInt64(md["class-count"])
+
+	return m, true
+}
+
+// Compare describes the change in binary size and build duration from a previous build, e.g. "+12.0 MB, +95 s"
+func (m Metrics) Compare(previous Metrics) string {
+	size := float64(m.BinarySize-previous.BinarySize) / (1024 * 1024)
+	duration := (m.Duration - previous.Duration).Round(time.Second).Seconds()
+
+	return fmt.Sprintf("%+.1f MB, %+.0f s", size, duration)
+}
+
+// UpdateLabels writes the metrics into the labels whose key is a metric
+func (m *Metrics) UpdateLabels() {
+	arguments, _ := json.Marshal(m.Arguments)
+
+	values := map[string]string{
+		LabelMetricsDuration:        strconv.FormatFloat(m.Duration.Seconds(), 'f', 1, 64),
+		LabelMetricsPeakRSS:         strconv.FormatInt(m.PeakRSS, 10),
+		LabelMetricsBinarySize:      strconv.FormatInt(m.BinarySize, 10),
+		LabelMetricsGraalVMVersion:  m.GraalVMVersion,
+		LabelMetricsArgumentsDigest: m.ArgumentsDigest,
+		LabelMetricsGraalVMRelease:  GraalVMRelease(m.GraalVMVersion),
+		LabelMetricsDistribution:    m.Distribution,
+		LabelArguments:              string(arguments),
+	}
+
+	for i := range m.Labels {
+		if v, ok := values[m.Labels[i].Key]; ok {
+			m.Labels[i].Value = v
+		}
+	}
+}
+
+func toInt64(v interface{}) int64 {
+	switch n := v.(type) {
+	case int64:
+		return n
+	case int:
+		return int64(n)
+	case float64:
+		return int64(n)
+	}
+
+	return 0
+}
@@ -0,0 +1,87 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/buildpacks/libcnb"
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testMetrics(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+	)
+
+	it("parses the GraalVM release", func() {
+		Expect(native.GraalVMRelease("GraalVM 22.3.1 Java 17 CE (Java Version 17.0.6+10-jvmci-22.3-b13)")).To(Equal("22.3.1"))
+		Expect(native.GraalVMRelease("native-image 21.0.1 2023-10-17\nGraalVM Runtime Environment Oracle GraalVM 21.0.1+12.1")).To(Equal("21.0.1"))
+		Expect(native.GraalVMRelease("native-image")).To(BeEmpty())
+	})
+
+	it("parses the GraalVM version", func() {
+		Expect(native.GraalVMVersion("\nnative-image 17.0.7 2023-04-18\nGraalVM Runtime Environment\n")).
+			To(Equal("native-image 17.0.7 2023-04-18"))
+	})
+
+	it("digests arguments", func() {
+		Expect(native.ArgumentsDigest([]string{"a b"})).NotTo(Equal(native.ArgumentsDigest([]string{"a", "b"})))
+		Expect(native.ArgumentsDigest([]string{"a"})).To(HavePrefix("sha256:"))
+	})
+
+	it("round trips through layer metadata", func() {
+		m := native.Metrics{
+			Duration:        90 * time.Second,
+			PeakRSS:         1024,
+			BinarySize:      2048,
+			GraalVMVersion:  "test-version",
+			ArgumentsDigest: "sha256:test",
+		}
+
+		restored, ok := native.MetricsFromMetadata(m.Metadata())
+		Expect(ok).To(BeTrue())
+		Expect(restored).To(Equal(m))
+	})
+
+	it("compares with a previous build", func() {
+		m := native.Metrics{BinarySize: 80 * 1024 * 1024, Duration: 200 * time.Second}
+
+		Expect(m.Compare(native.Metrics{BinarySize: 68 * 1024 * 1024, Duration: 105 * time.Second})).To(Equal("+12.0 MB, +95 s"))
+		Expect(m.Compare(native.Metrics{BinarySize: 80*1024*1024 + 512*1024, Duration: 210 * time.Second})).To(Equal("-0.5 MB, -10 s"))
+	})
+
+	it("writes recorded arguments as a JSON array label", func() {
+		m := native.Metrics{
+			Arguments: []string{"-H:Name=app", "--no-fallback"},
+			Labels:    []libcnb.Label{{Key: "test-key", Value: "test-value"}, {Key: native.LabelArguments}},
+		}
+		m.UpdateLabels()
+
+		Expect(m.Labels[0].Value).To(Equal("test-value"))
+		Expect(m.Labels[1].Value).To(Equal(`["-H:Name=app","--no-fallback"]`))
+	})
+
+	it("does not restore missing metadata", func() {
+		_, ok := native.MetricsFromMetadata(nil)
+		Expect(ok).To(BeFalse())
+	})
+}
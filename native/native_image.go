@@ -18,13 +18,19 @@ package native
 
 import (
 	"bytes"
+	"context"
 	"crypto/sha256"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"runtime"
 	"strings"
+	"time"
 
 	"github.com/buildpacks/libcnb"
 	"github.com/magiconair/properties"
@@ -35,40 +41,155 @@ import (
 )
 
 type NativeImage struct {
-	ApplicationPath string
-	Arguments       string
-	ArgumentsFile   string
-	Executor        effect.Executor
-	JarFilePattern  string
-	Logger          bard.Logger
-	Manifest        *properties.Properties
-	StackID         string
-	Compressor      string
+	AdditionalClasspath      []string
+	AlignedHeapChunkSize     string
+	AllowFallback            bool
+	AnalysisReports          bool
+	ApplicationPath          string
+	Architecture             string
+	Arguments                string
+	ArgumentsFile            string
+	AuxiliaryBinaries        []AuxiliaryBinary
+	Budget                   Budget
+	Builder                  string
+	BinaryLayer              bool
+	BuildTool                *BuildTool
+	Cache                    bool
+	CACertificates           []string
+	ClasspathConflicts       string
+	ClasspathResolver        ClasspathResolver
+	ClasspathStrategy        ClasspathStrategy
+	Command                  string
+	CompareMetrics           bool
+	CompressedReferences     string
+	ConfigurationDirectories []string
+	Context                  context.Context
+	DiagnosticsPath          string
+	Assertions               bool
+	Deterministic            bool
+	DeniedArguments          []DeniedArgument
+	Enterprise               *Enterprise
+	BuildSecrets             *BuildSecrets
+	Environment              []string
+	RetryOnOutOfMemory       bool
+	RunImageCheck            string
+	Excluded                 []string
+	LegacyMainClass          bool
+	ReportStackTraces        bool
+	Executor                 effect.Executor
+	Invoker                  Invoker
+	ForbiddenTypes           []string
+	Generated                GeneratedConfiguration
+	ExitHandlers             bool
+	HeapDumpOnOutOfMemory    bool
+	IncludeResources         []string
+	Locales                  string
+	AllCharsets              bool
+	URLProtocols             []string
+	SecurityServices         bool
+	SecurityProviders        []string
+	InitializeAtBuildTime    []string
+	InitializeAtRunTime      []string
+	JarFilePattern           string
+	LibDirectory             string
+	LibraryArguments         bool
+	Logger                   bard.Logger
+	Manifest                 *properties.Properties
+	March                    string
+	MaxHeapPercent           int
+	MaxHeapSize              string
+	Metrics                  *Metrics
+	NativeLibraries          []string
+	Outputs                  []string
+	PIE                      bool
+	Preserve                 []string
+	RecordArguments          bool
+	RELRO                    string
+	Resolver                 Resolver
+	SourceDateEpoch          time.Time
+	SpringNative             *Artifact
+	StackID                  string
+	StartClass               string
+	Target                   string
+	SummaryPath              string
+	SystemProperties         []string
+	TempSpace                TempSpace
+	Compatibility            []Compatibility
+	Compressor               string
+	Timeout                  time.Duration
+	TracingAgent             *TracingAgent
+	VerifyReproducible       bool
+	Warnings                 *Warnings
 }
 
+// NewNativeImage creates a NativeImage contributor.
+//
+// Deprecated: use New, which does not require every setting.
 func NewNativeImage(applicationPath string, arguments string, argumentsFile string, compressor string, jarFilePattern string, manifest *properties.Properties, stackID string) (NativeImage, error) {
-	return NativeImage{
-		ApplicationPath: applicationPath,
-		Arguments:       arguments,
-		ArgumentsFile:   argumentsFile,
-		Executor:        effect.NewExecutor(),
-		JarFilePattern:  jarFilePattern,
-		Manifest:        manifest,
-		StackID:         stackID,
-		Compressor:      compressor,
-	}, nil
+	return New(
+		WithApplicationPath(applicationPath),
+		WithArguments(arguments),
+		WithArgumentsFile(argumentsFile),
+		WithClasspathStrategy(ClasspathAuto, jarFilePattern),
+		WithCompressor(compressor),
+		WithManifest(manifest),
+		WithStackID(stackID),
+	), nil
 }
 
 func (n NativeImage) Contribute(layer libcnb.Layer) (libcnb.Layer, error) {
-	files, err := sherpa.NewFileListing(n.ApplicationPath)
+	// configuration and classpath entries from bindings live outside the application, but changes to them must still
+	// rebuild the image
+	roots := append([]string{n.ApplicationPath}, n.ConfigurationDirectories...)
+	for _, e := range n.AdditionalClasspath {
+		if !strings.HasPrefix(e, n.ApplicationPath+string(filepath.Separator)) {
+			roots = append(roots, e)
+		}
+	}
+	files, err := sherpa.NewFileListing(roots...)
 	if err != nil {
 		return libcnb.Layer{}, fmt.Errorf("unable to create file listing for %s\n%w", n.ApplicationPath, err)
 	}
+	files = withoutTrainingArtifacts(files)
+
+	// configuration generated by the tracing agent is written into the layer, so it is not part of the file listing
+	agentDir := filepath.Join(layer.Path, TracingAgentDirectory)
+	if n.TracingAgent != nil {
+		n.ConfigurationDirectories = append(append([]string{}, n.ConfigurationDirectories...), agentDir)
+	}
+	generatedDir := filepath.Join(layer.Path, GeneratedConfigurationDirectory)
+	if !n.Generated.IsEmpty() {
+		n.ConfigurationDirectories = append(append([]string{}, n.ConfigurationDirectories...), generatedDir)
+	}
 
 	arguments, startClass, err := n.ProcessArguments(layer)
 	if err != nil {
 		return libcnb.Layer{}, fmt.Errorf("unable to process arguments\n%w", err)
 	}
+	binary := BinaryName(startClass, runtime.GOOS)
+
+	// problems such as a typo in the start class otherwise only fail native-image once it has analysed the whole
+	// classpath, one at a time
+	if _, takesArguments := n.ResolveInvoker(); takesArguments {
+		if err := n.preflight(startClass); err != nil {
+			return libcnb.Layer{}, err
+		}
+	}
+
+	if n.ClasspathConflicts != "" {
+		cp, err := n.classpath()
+		if err != nil {
+			return libcnb.Layer{}, err
+		}
+		if err := CheckArtifactConflicts(n.Logger, filepath.SplitList(cp), n.ClasspathConflicts); err != nil {
+			return libcnb.Layer{}, err
+		}
+	}
+
+	auxiliary, err := n.ProcessAuxiliaryArguments(layer)
+	if err != nil {
+		return libcnb.Layer{}, fmt.Errorf("unable to process auxiliary arguments\n%w", err)
+	}
 	moduleVar := "USE_NATIVE_IMAGE_JAVA_PLATFORM_MODULE_SYSTEM"
 	if _, set := os.LookupEnv(moduleVar); !set{
 		if err := os.Setenv(moduleVar, "false"); err != nil{
@@ -76,44 +197,257 @@ func (n NativeImage) Contribute(layer libcnb.Layer) (libcnb.Layer, error) {
 		}
 	}
 
+	// without an allow-list native-image inherits the whole build environment
+	var env []string
+	if n.Environment != nil {
+		env = Environment(n.Environment, os.Environ())
+		LogEnvironment(n.Logger, env)
+	}
+	if n.Enterprise != nil {
+		env = n.Enterprise.Environment(env)
+	}
+	// build secrets reach the build processes only, after the environment has been logged
+	if n.BuildSecrets != nil {
+		env = n.BuildSecrets.Environment(env)
+	}
+
+	// an aborted build kills the running command rather than leaving it running and the layer half written
+	lifecycle, stop := signal.NotifyContext(n.context(), abortSignals...)
+	defer stop()
+
+	// JVMs ignore the proxy environment variables and need the certificate authorities of corporate proxies in a
+	// trust store
+	network := env
+	if network == nil {
+		network = os.Environ()
+	}
+	options := ProxyProperties(network)
+	if len(n.CACertificates) > 0 {
+		dir, err := ioutil.TempDir("", "native-image-trust-store")
+		if err != nil {
+			return libcnb.Layer{}, fmt.Errorf("unable to create trust store directory\n%w", err)
+		}
+		defer os.RemoveAll(dir)
+
+		trustStore, err := TrustStore(lifecycle, n.Executor, n.Logger, os.Getenv("JAVA_HOME"), dir, n.CACertificates)
+		if err != nil {
+			return libcnb.Layer{}, err
+		}
+		options = append(options, trustStore...)
+	}
+	env = JavaToolOptions(env, options)
+
 	buf := &bytes.Buffer{}
-	if err := n.Executor.Execute(effect.Execution{
-		Command: "native-image",
+	version := effect.Execution{
+		Command: n.Command,
 		Args:    []string{"--version"},
+		Env:     env,
 		Stdout:  buf,
 		Stderr:  n.Logger.BodyWriter(),
-	}); err != nil {
-		return libcnb.Layer{}, fmt.Errorf("error running version\n%w", err)
+	}
+	if err := executeContext(lifecycle, n.Executor, version); err != nil {
+		if errors.Is(err, context.Canceled) {
+			return libcnb.Layer{}, n.abort(layer, fmt.Errorf("native-image was aborted\n%w", err))
+		}
+		return libcnb.Layer{}, NativeImageUnavailableError{Command: n.Command, Err: err}
 	}
 	nativeBinaryHash := fmt.Sprintf("%x", sha256.Sum256(buf.Bytes()))
+	distribution := DetectDistribution(buf.String())
+	n.Logger.Bodyf("Using %s from the %s distribution", n.Command, distribution)
+
+	if n.SpringNative != nil {
+		if err := (CompatibilityCheck{Table: n.Compatibility, Logger: n.Logger}).Check(*n.SpringNative, GraalVMVersion(buf.String())); err != nil {
+			return libcnb.Layer{}, err
+		}
+	}
 
-	contributor := libpak.NewLayerContributor("Native Image", map[string]interface{}{
+	if !SupportsMarch(GraalVMVersion(buf.String())) {
+		if march := removeMarch(arguments); len(march) < len(arguments) {
+			n.Logger.Body("native-image does not support -march, building for its default micro-architecture")
+			arguments = march
+			for i := range auxiliary {
+				auxiliary[i] = removeMarch(auxiliary[i])
+			}
+		}
+	}
+
+	if !SupportsDeterministicImageHeap(GraalVMVersion(buf.String())) {
+		if heap := removeDeterministicImageHeap(arguments); len(heap) < len(arguments) {
+			n.Warnings.Warn(n.Logger, "native-image does not support -H:+DeterministicImageHeap, repeated builds may produce different image heaps")
+			arguments = heap
+			for i := range auxiliary {
+				auxiliary[i] = removeDeterministicImageHeap(auxiliary[i])
+			}
+		}
+	}
+
+	expected := map[string]interface{}{
 		"files":       files,
 		"arguments":   arguments,
 		"compression": n.Compressor,
 		"version-hash":        nativeBinaryHash,
-	}, libcnb.LayerTypes{
-		Cache: true,
+	}
+	if len(auxiliary) > 0 {
+		expected["auxiliary-arguments"] = auxiliary
+	}
+	if n.Architecture != "" {
+		expected[ArchitectureMetadataKey] = n.Architecture
+	}
+	if !n.Generated.IsEmpty() {
+		expected[GeneratedConfigurationMetadataKey] = n.Generated.Metadata()
+	}
+
+	contributor := libpak.NewLayerContributor("Native Image", expected, libcnb.LayerTypes{
+		Cache: n.Cache,
 	})
 	contributor.Logger = n.Logger
 
+	// metrics vary between builds, so they must not take part in the comparison of expected metadata
+	metrics, hasPrevious := MetricsFromMetadata(layer.Metadata[MetricsMetadataKey])
+	previous, rebuilt := metrics, false
+	hardening := layer.Metadata[HardeningMetadataKey]
+	delete(layer.Metadata, HardeningMetadataKey)
+	delete(layer.Metadata, MetricsMetadataKey)
+	delete(layer.Metadata, ProvenanceMetadataKey)
+
 	layer, err = contributor.Contribute(layer, func() (libcnb.Layer, error) {
-		n.Logger.Bodyf("Executing native-image %s", strings.Join(arguments, " "))
-		if err := n.Executor.Execute(effect.Execution{
-			Command: "native-image",
-			Args:    arguments,
-			Dir:     layer.Path,
-			Stdout:  n.Logger.InfoWriter(),
-			Stderr:  n.Logger.InfoWriter(),
-		}); err != nil {
-			return libcnb.Layer{}, fmt.Errorf("error running build\n%w", err)
+		rebuilt = true
+		if n.DiagnosticsPath != "" {
+			if err := n.resetLog(); err != nil {
+				return libcnb.Layer{}, err
+			}
+		}
+		estimate, err := n.estimate(previous)
+		if err != nil {
+			return libcnb.Layer{}, err
+		}
+		n.Logger.Bodyf("Estimated native-image build: %s", estimate)
+		if err := n.Budget.Check(estimate); err != nil {
+			return libcnb.Layer{}, err
+		}
+
+		// native-image writes large temporary files, which fill up a small /tmp
+		temp, cleanup, err := n.TempSpace.Create(layer)
+		if err != nil {
+			return libcnb.Layer{}, err
+		}
+		defer cleanup()
+		checked := temp
+		if checked == "" {
+			checked = os.TempDir()
+		}
+		if n.TempSpace.CheckEstimate {
+			n.Logger.Bodyf("Estimated disk space: %s", formatBytes(estimate.Disk))
+		}
+		if err := n.TempSpace.Check(n.Logger, estimate.Disk, layer.Path, checked); err != nil {
+			return libcnb.Layer{}, err
+		}
+		env = TempEnvironment(env, temp)
+
+		start := time.Now()
+		metrics = Metrics{
+			GraalVMVersion:  GraalVMVersion(buf.String()),
+			ArgumentsDigest: ArgumentsDigest(arguments),
+			ClassCount:      estimate.Classes,
+		}
+
+		ctx := lifecycle
+		if n.Timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(lifecycle, n.Timeout)
+			defer cancel()
+		}
+
+		if n.TracingAgent != nil {
+			if err := n.trace(ctx, agentDir, env); err != nil {
+				return libcnb.Layer{}, n.abort(layer, err)
+			}
+		}
+		if !n.Generated.IsEmpty() {
+			if err := n.Generated.WriteTo(generatedDir); err != nil {
+				return libcnb.Layer{}, err
+			}
+		}
+
+		invoker, takesArguments := n.ResolveInvoker()
+		compilation, err := n.invoke(ctx, invoker, layer.Path, binary, arguments, env)
+		if err != nil && takesArguments && n.RetryOnOutOfMemory && containsDiagnosis(compilation.Diagnoses, DiagnosisOutOfMemory) {
+			retryArguments, heap := ReduceResourceArguments(arguments, runtime.NumCPU())
+			if heap {
+				n.Warnings.Warn(n.Logger, "Retrying native-image once with reduced heap and parallelism")
+			} else {
+				n.Warnings.Warn(n.Logger, "Retrying native-image once with reduced parallelism, set -J-Xmx to also reduce the heap")
+			}
+			compilation, err = n.invoke(ctx, invoker, layer.Path, binary, retryArguments, env)
+		}
+		if err != nil {
+			return libcnb.Layer{}, n.abort(layer, err)
+		}
+		// a fallback image launches a JVM, which is not contributed to the image
+		if image, ok := FindFallbackImage(compilation.Output); ok && !n.AllowFallback {
+			return libcnb.Layer{}, n.abort(layer, FallbackImageError{Image: image})
+		}
+		for _, a := range auxiliary {
+			if _, err := n.compile(ctx, layer, a, env); err != nil {
+				return libcnb.Layer{}, n.abort(layer, err)
+			}
+		}
+		// compression hides the properties of the binary, so it is verified first
+		if requested := (HardeningArguments{PIE: n.PIE, RELRO: n.RELRO}); requested.PIE || requested.RELRO != "" {
+			h, err := ReadHardening(filepath.Join(layer.Path, binary))
+			if err != nil {
+				return libcnb.Layer{}, n.abort(layer, err)
+			}
+			if err := h.Verify(requested); err != nil {
+				return libcnb.Layer{}, n.abort(layer, err)
+			}
+			n.Logger.Bodyf("Verified hardening: PIE %t, %s RELRO", h.PIE, h.RELRO)
+			hardening = h.Metadata()
+		} else {
+			hardening = nil
+		}
+
+		// compression hides the dynamic linking of the binary, so it is checked first
+		if n.RunImageCheck != "" && runtime.GOOS == "linux" {
+			if err := VerifyRunImage(n.Logger, filepath.Join(layer.Path, binary), n.StackID, n.RunImageCheck); err != nil {
+				return libcnb.Layer{}, n.abort(layer, err)
+			}
+		}
+
+		if n.AnalysisReports || len(n.ForbiddenTypes) > 0 {
+			reports, err := CollectReports(layer.Path, n.DiagnosticsPath)
+			if err != nil {
+				return libcnb.Layer{}, n.abort(layer, err)
+			}
+			if reports != "" {
+				n.Logger.Bodyf("Analysis reports written to %s", reports)
+			}
+		}
+		metrics.Duration = time.Since(start)
+		metrics.PeakRSS = peakChildRSS()
+
+		// thresholds fail the build when requested, otherwise they are soft limits reported as warnings
+		violations := CheckPhaseThresholds(n.Budget.Phases, compilation.Phases, metrics.Duration)
+		if len(violations) > 0 && n.Budget.PhaseAction == BudgetFail {
+			return libcnb.Layer{}, PhaseThresholdError{Violations: violations}
+		}
+		for _, v := range violations {
+			n.Warnings.Warn(n.Logger, v.String())
+		}
+
+		// build tools and bundles decide the arguments of native-image, so a second build with the buildpack's
+		// arguments would not verify anything
+		if n.VerifyReproducible && takesArguments {
+			if err := n.verifyReproducible(ctx, layer, arguments, startClass, binary, env); err != nil {
+				return libcnb.Layer{}, n.abort(layer, err)
+			}
 		}
 
 		if n.Compressor == CompressorUpx {
 			n.Logger.Bodyf("Executing %s to compress native image", n.Compressor)
-			if err := n.Executor.Execute(effect.Execution{
+			if err := executeContext(lifecycle, n.Executor, effect.Execution{
 				Command: "upx",
-				Args:    []string{"-q", "-9", filepath.Join(layer.Path, startClass)},
+				Args:    []string{"-q", "-9", filepath.Join(layer.Path, binary)},
 				Dir:     layer.Path,
 				Stdout:  n.Logger.InfoWriter(),
 				Stderr:  n.Logger.InfoWriter(),
@@ -122,9 +456,9 @@ func (n NativeImage) Contribute(layer libcnb.Layer) (libcnb.Layer, error) {
 			}
 		} else if n.Compressor == CompressorGzexe {
 			n.Logger.Bodyf("Executing %s to compress native image", n.Compressor)
-			if err := n.Executor.Execute(effect.Execution{
+			if err := executeContext(lifecycle, n.Executor, effect.Execution{
 				Command: "gzexe",
-				Args:    []string{filepath.Join(layer.Path, startClass)},
+				Args:    []string{filepath.Join(layer.Path, binary)},
 				Dir:     layer.Path,
 				Stdout:  n.Logger.InfoWriter(),
 				Stderr:  n.Logger.InfoWriter(),
@@ -132,88 +466,232 @@ func (n NativeImage) Contribute(layer libcnb.Layer) (libcnb.Layer, error) {
 				return libcnb.Layer{}, fmt.Errorf("error compressing\n%w", err)
 			}
 
-			if err := os.Remove(filepath.Join(layer.Path, fmt.Sprintf("%s~", startClass))); err != nil {
+			if err := os.Remove(filepath.Join(layer.Path, fmt.Sprintf("%s~", binary))); err != nil {
 				return libcnb.Layer{}, fmt.Errorf("error removing\n%w", err)
 			}
 		}
 
+		if fi, err := os.Stat(filepath.Join(layer.Path, binary)); err == nil {
+			metrics.BinarySize = fi.Size()
+		}
+		if err := n.Budget.CheckSize(binary, metrics.BinarySize); err != nil {
+			if n.Budget.SizeAction != BudgetWarn {
+				return libcnb.Layer{}, err
+			}
+			n.Warnings.Warn(n.Logger, err.Error())
+		}
+
+		n.Warnings.Add(FindOutputWarnings(compilation.Output)...)
+		summary := NewSummary(binary, metrics, compilation.Phases, compilation.Output)
+		if n.Warnings != nil {
+			summary.Warnings = n.Warnings.Messages
+		}
+		if len(violations) > 0 {
+			summary.Violations = violations
+		}
+		if err := summary.WriteTo(filepath.Join(layer.Path, SummaryFile)); err != nil {
+			return libcnb.Layer{}, err
+		}
+
 		return layer, nil
 	})
 	if err != nil {
 		return libcnb.Layer{}, fmt.Errorf("unable to contribute native-image layer\n%w", err)
 	}
 
+	layer.Metadata[MetricsMetadataKey] = metrics.Metadata()
+	if hardening != nil {
+		layer.Metadata[HardeningMetadataKey] = hardening
+	}
+	if rebuilt && hasPrevious && n.CompareMetrics {
+		n.Logger.Bodyf("Native image is %s since last build", metrics.Compare(previous))
+	}
+
+	// provenance is regenerated from the binary, so that it is also present for a reused layer
+	digest, err := FileDigest(filepath.Join(layer.Path, binary))
+	if err != nil {
+		return libcnb.Layer{}, fmt.Errorf("unable to compute checksum of native image\n%w", err)
+	}
+	statement, err := json.MarshalIndent(NewStatement(binary, digest, n.Builder, arguments, InputsDigest(files), nativeBinaryHash), "", "  ")
+	if err != nil {
+		return libcnb.Layer{}, fmt.Errorf("unable to encode provenance\n%w", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(layer.Path, ProvenanceFile), statement, 0644); err != nil {
+		return libcnb.Layer{}, fmt.Errorf("unable to write %s\n%w", ProvenanceFile, err)
+	}
+	layer.Metadata[ProvenanceMetadataKey] = map[string]interface{}{
+		"binary-sha256": digest,
+		"statement":     ProvenanceFile,
+	}
+
+	if n.SummaryPath != "" {
+		n.Logger.Bodyf("Writing build summary to %s", n.SummaryPath)
+		if err := CopySummary(layer.Path, n.SummaryPath); err != nil {
+			return libcnb.Layer{}, err
+		}
+	}
+
+	record := filepath.Join(layer.Path, ArgumentsRecord)
+	metrics.Rebuilt = rebuilt
+	metrics.Distribution = distribution
+	metrics.Arguments = RedactArguments(arguments)
+	if n.RecordArguments {
+		if err := ioutil.WriteFile(record, []byte(strings.Join(metrics.Arguments, "\n")+"\n"), 0644); err != nil {
+			return libcnb.Layer{}, fmt.Errorf("unable to write %s\n%w", record, err)
+		}
+	} else if err := os.RemoveAll(record); err != nil {
+		return libcnb.Layer{}, fmt.Errorf("unable to remove %s\n%w", record, err)
+	}
+
+	if n.Metrics != nil {
+		metrics.Labels = n.Metrics.Labels
+		*n.Metrics = metrics
+		n.Metrics.UpdateLabels()
+	}
+
+	// native libraries are staged in the layer, as those in the application are removed with the bytecode
+	for _, l := range n.NativeLibraries {
+		n.Logger.Bodyf("Bundling native library %s", l)
+		if err := copyBinary(l, filepath.Join(layer.Path, filepath.Base(l))); err != nil {
+			return libcnb.Layer{}, err
+		}
+	}
+
 	n.Logger.Header("Removing bytecode")
-	cs, err := ioutil.ReadDir(n.ApplicationPath)
+	if len(n.Preserve) > 0 {
+		n.Logger.Bodyf("Preserving %s", strings.Join(n.Preserve, ", "))
+	}
+	// an application launched from the binary layer and preserved entirely is never written to, so that it may be
+	// read-only
+	if n.BinaryLayer && containsString(n.Preserve, "**") {
+		n.Logger.Body("Leaving the application unchanged")
+	} else if err := RemoveApplication(n.ApplicationPath, n.Preserve); err != nil {
+		return libcnb.Layer{}, err
+	}
+
+	binaries := []string{binary}
+	for _, b := range n.AuxiliaryBinaries {
+		binaries = append(binaries, BinaryName(b.Name, runtime.GOOS))
+	}
+	binaries = append(binaries, NativeLibraryNames(n.NativeLibraries)...)
+
+	// the BinaryLayer copies the binaries into a launch layer instead
+	if n.BinaryLayer {
+		binaries = nil
+	}
+
+	for _, b := range binaries {
+		if err := copyBinary(filepath.Join(layer.Path, b), filepath.Join(n.ApplicationPath, b)); err != nil {
+			return libcnb.Layer{}, err
+		}
+
+		if !n.SourceDateEpoch.IsZero() {
+			dst := filepath.Join(n.ApplicationPath, b)
+			if err := os.Chtimes(dst, n.SourceDateEpoch, n.SourceDateEpoch); err != nil {
+				return libcnb.Layer{}, fmt.Errorf("unable to set modification time of %s\n%w", dst, err)
+			}
+		}
+	}
+
+	if n.Target == TargetAWSLambda {
+		n.Logger.Bodyf("Packaging %s as an AWS Lambda custom runtime in %s", binary, LambdaArchive)
+		if err := PackageLambda(n.ApplicationPath, binary, n.SourceDateEpoch); err != nil {
+			return libcnb.Layer{}, err
+		}
+	}
+
+	// the BinaryLayer copies the outputs next to the binaries instead
+	outputs := n.Outputs
+	if n.BinaryLayer {
+		outputs = nil
+	}
+
+	outputs, err = CopyOutputs(layer.Path, n.ApplicationPath, outputs)
 	if err != nil {
-		return libcnb.Layer{}, fmt.Errorf("unable to list children of %s\n%w", n.ApplicationPath, err)
+		return libcnb.Layer{}, err
 	}
-	for _, c := range cs {
-		file := filepath.Join(n.ApplicationPath, c.Name())
-		if err := os.RemoveAll(file); err != nil {
-			return libcnb.Layer{}, fmt.Errorf("unable to remove %s\n%w", file, err)
+	for _, o := range outputs {
+		n.Logger.Bodyf("Copying %s next to the native image", o)
+
+		if !n.SourceDateEpoch.IsZero() {
+			dst := filepath.Join(n.ApplicationPath, o)
+			if err := os.Chtimes(dst, n.SourceDateEpoch, n.SourceDateEpoch); err != nil {
+				return libcnb.Layer{}, fmt.Errorf("unable to set modification time of %s\n%w", dst, err)
+			}
 		}
 	}
 
-	src := filepath.Join(layer.Path, startClass)
+	n.Warnings.Log(n.Logger)
+
+	return layer, nil
+}
+
+func copyBinary(src string, dst string) error {
 	in, err := os.Open(src)
 	if err != nil {
-		return libcnb.Layer{}, fmt.Errorf("unable to open %s\n%w", filepath.Join(layer.Path, startClass), err)
+		return fmt.Errorf("unable to open %s\n%w", src, err)
 	}
 	defer in.Close()
 
-	dst := filepath.Join(n.ApplicationPath, startClass)
 	out, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0755)
 	if err != nil {
-		return libcnb.Layer{}, fmt.Errorf("unable to open %s\n%w", dst, err)
+		return fmt.Errorf("unable to open %s\n%w", dst, err)
 	}
 	defer out.Close()
 
 	if _, err := io.Copy(out, in); err != nil {
-		return libcnb.Layer{}, fmt.Errorf("unable to copy\n%w", err)
+		return fmt.Errorf("unable to copy\n%w", err)
 	}
 
-	return layer, nil
+	return nil
 }
 
 func (n NativeImage) ProcessArguments(layer libcnb.Layer) ([]string, string, error) {
-	var arguments []string
 	var startClass string
-	var err error
 
-	arguments, _, err = BaselineArguments{StackID: n.StackID}.Configure(nil)
+	arguments, err := n.baseArguments()
 	if err != nil {
-		return []string{}, "", fmt.Errorf("unable to set baseline arguments\n%w", err)
-	}
-
-	if n.ArgumentsFile != "" {
-		arguments, _, err = UserFileArguments{ArgumentsFile: n.ArgumentsFile}.Configure(arguments)
-		if err != nil {
-			return []string{}, "", fmt.Errorf("unable to create user file arguments\n%w", err)
-		}
+		return []string{}, "", err
 	}
 
-	arguments, _, err = UserArguments{Arguments: n.Arguments}.Configure(arguments)
+	strategy, err := n.strategy()
 	if err != nil {
-		return []string{}, "", fmt.Errorf("unable to create user arguments\n%w", err)
+		return []string{}, "", err
 	}
 
-	_, err = os.Stat(filepath.Join(n.ApplicationPath, "META-INF", "MANIFEST.MF"))
-	if err != nil && !os.IsNotExist(err) {
-		return []string{}, "", fmt.Errorf("unable to check for manifest\n%w", err)
-	} else if err != nil && os.IsNotExist(err) {
+	switch strategy {
+	case ClasspathDirectory:
+		arguments, startClass, err = DirectoryArguments{
+			AdditionalClasspath: n.AdditionalClasspath,
+			ApplicationPath:     n.ApplicationPath,
+			Excluded:            n.Excluded,
+			LayerPath:           layer.Path,
+			LibDirectory:        n.LibDirectory,
+			StartClass:          n.StartClass,
+			LegacyMainClass:     n.LegacyMainClass,
+		}.Configure(arguments)
+		if err != nil {
+			return []string{}, "", fmt.Errorf("unable to append directory arguments\n%w", err)
+		}
+	case ClasspathJar:
 		arguments, startClass, err = JarArguments{
-			ApplicationPath: n.ApplicationPath,
-			JarFilePattern:  n.JarFilePattern,
+			AdditionalClasspath: n.AdditionalClasspath,
+			ApplicationPath:     n.ApplicationPath,
+			JarFilePattern:      n.JarFilePattern,
 		}.Configure(arguments)
 		if err != nil {
 			return []string{}, "", fmt.Errorf("unable to append jar arguments\n%w", err)
 		}
-	} else {
+	default:
 		arguments, startClass, err = ExplodedJarArguments{
-			ApplicationPath: n.ApplicationPath,
-			LayerPath:       layer.Path,
-			Manifest:        n.Manifest,
+			AdditionalClasspath: n.AdditionalClasspath,
+			ApplicationPath:     n.ApplicationPath,
+			ClasspathResolver:   n.ClasspathResolver,
+			Excluded:            n.Excluded,
+			LayerPath:           layer.Path,
+			Manifest:            n.Manifest,
+			StartClass:          n.StartClass,
+			LegacyMainClass:     n.LegacyMainClass,
 		}.Configure(arguments)
 		if err != nil {
 			return []string{}, "", fmt.Errorf("unable to append exploded-jar directory arguments\n%w", err)
@@ -223,6 +701,536 @@ func (n NativeImage) ProcessArguments(layer libcnb.Layer) ([]string, string, err
 	return arguments, startClass, err
 }
 
+// ProcessAuxiliaryArguments returns the arguments for building each of the auxiliary binaries
+func (n NativeImage) ProcessAuxiliaryArguments(layer libcnb.Layer) ([][]string, error) {
+	if len(n.AuxiliaryBinaries) == 0 {
+		return nil, nil
+	}
+
+	cp, err := n.classpath()
+	if err != nil {
+		return nil, err
+	}
+
+	base, err := n.baseArguments()
+	if err != nil {
+		return nil, err
+	}
+
+	var auxiliary [][]string
+	for _, b := range n.AuxiliaryBinaries {
+		arguments, _, err := AuxiliaryArguments{
+			ApplicationPath: n.ApplicationPath,
+			Binary:          b,
+			Classpath:       cp,
+			LayerPath:       layer.Path,
+			LegacyMainClass: n.LegacyMainClass,
+		}.Configure(append([]string{}, base...))
+		if err != nil {
+			return nil, fmt.Errorf("unable to append auxiliary arguments for %s\n%w", b.Name, err)
+		}
+		auxiliary = append(auxiliary, arguments)
+	}
+
+	return auxiliary, nil
+}
+
+// classpath returns the classpath of the application, either the exploded JAR directory, the JAR file or the plain
+// directory
+func (n NativeImage) classpath() (string, error) {
+	strategy, err := n.strategy()
+	if err != nil {
+		return "", err
+	}
+
+	var cp string
+	switch strategy {
+	case ClasspathDirectory:
+		entries, err := DirectoryClasspath(n.ApplicationPath, n.LibDirectory)
+		if err != nil {
+			return "", err
+		}
+
+		var included []string
+		for _, e := range entries {
+			if !containsPath(n.Excluded, e) {
+				included = append(included, e)
+			}
+		}
+		cp = strings.Join(included, string(filepath.ListSeparator))
+	case ClasspathJar:
+		if cp, err = jarClasspath(n.ApplicationPath, n.JarFilePattern); err != nil {
+			return "", err
+		}
+	default:
+		if cp, err = explodedClasspath(n.ClasspathResolver, n.ApplicationPath, n.Manifest, n.Excluded); err != nil {
+			return "", err
+		}
+	}
+
+	return appendClasspath(cp, n.AdditionalClasspath), nil
+}
+
+// jarClasspath returns the JAR of the application followed by the entries of its Class-Path, which thin JARs list their
+// dependencies in
+func jarClasspath(applicationPath string, jarFilePattern string) (string, error) {
+	jar, err := findJar(applicationPath, jarFilePattern)
+	if err != nil {
+		return "", err
+	}
+
+	manifest, err := NewManifestFromJAR(jar)
+	if err != nil {
+		return "", err
+	}
+
+	entries := []string{jar}
+	if cp, ok := manifest.Get("Class-Path"); ok {
+		entries = append(entries, ManifestClasspath(cp, filepath.Dir(jar))...)
+	}
+
+	return strings.Join(NormalizeClasspath(entries), string(filepath.ListSeparator)), nil
+}
+
+// baseArguments returns the arguments shared by every binary built from the application
+func (n NativeImage) baseArguments() ([]string, error) {
+	resolver := n.Resolver
+	if resolver == nil {
+		resolver = ArgumentResolver{Logger: n.Logger}
+	}
+
+	sources, err := n.argumentSources(resolver)
+	if err != nil {
+		return []string{}, err
+	}
+
+	arguments, trace, err := ArgumentChain{Sources: sources}.Apply()
+	if err != nil {
+		return []string{}, err
+	}
+
+	arguments, err = resolver.Resolve(arguments)
+	if err != nil {
+		return []string{}, fmt.Errorf("unable to resolve arguments\n%w", err)
+	}
+
+	arguments, err = DeniedArgumentsFilter{Denied: n.DeniedArguments, Logger: n.Logger}.Filter(arguments)
+	if err != nil {
+		return []string{}, fmt.Errorf("unable to validate arguments\n%w", err)
+	}
+
+	if err := CheckEnterpriseArguments(arguments, n.Enterprise != nil); err != nil {
+		return []string{}, err
+	}
+
+	trace.Log(n.Logger, arguments)
+	return arguments, nil
+}
+
+// argumentSources returns the sources of the native-image arguments in order of increasing precedence: the defaults
+// of the buildpack, the native-image.properties of libraries, the reachability metadata, $BP_NATIVE_IMAGE_BUILD_ARGUMENTS
+// and $BP_NATIVE_IMAGE_BUILD_ARGUMENTS_FILE
+func (n NativeImage) argumentSources(resolver Resolver) ([]ArgumentSource, error) {
+	sources := []ArgumentSource{
+		ConfigurerSource{
+			SourceName: SourceStackDefaults,
+			Configurers: []Arguments{
+				BaselineArguments{StackID: n.StackID},
+				AssertionArguments{Enabled: n.Assertions},
+				DeterministicArguments{Enabled: n.Deterministic},
+				AnalysisReportArguments{Enabled: n.AnalysisReports, ForbiddenTypes: n.ForbiddenTypes},
+				StackTraceArguments{Enabled: n.ReportStackTraces},
+				HardeningArguments{PIE: n.PIE, RELRO: n.RELRO},
+				ContainerArguments{ExitHandlers: n.ExitHandlers, HeapDumpOnOutOfMemory: n.HeapDumpOnOutOfMemory},
+				ArchitectureArguments{Architecture: n.Architecture, March: n.March},
+				LocaleArguments{Locales: n.Locales, AllCharsets: n.AllCharsets},
+				URLProtocolArguments{Protocols: n.URLProtocols},
+				SecurityArguments{AllServices: n.SecurityServices, Providers: n.SecurityProviders},
+				InitializationArguments{
+					BuildTime:     n.InitializeAtBuildTime,
+					RunTime:       n.InitializeAtRunTime,
+					UserArguments: n.Arguments,
+				},
+				SystemPropertyArguments{Properties: n.SystemProperties},
+				HeapArguments{
+					MaxHeapSize:          n.MaxHeapSize,
+					MaxHeapPercent:       n.MaxHeapPercent,
+					CompressedReferences: n.CompressedReferences,
+					AlignedHeapChunkSize: n.AlignedHeapChunkSize,
+					UserArguments:        n.Arguments,
+				},
+			},
+		},
+	}
+
+	if n.LibraryArguments {
+		cp, err := n.classpath()
+		if err != nil {
+			return nil, err
+		}
+
+		sources = append(sources, ConfigurerSource{
+			SourceName:  SourceLibraryProperties,
+			Configurers: []Arguments{LibraryArguments{Classpath: filepath.SplitList(cp), Logger: n.Logger}},
+		})
+	}
+
+	sources = append(sources,
+		ConfigurerSource{
+			SourceName: SourceMetadata,
+			Configurers: []Arguments{
+				ConfigurationDirectoryArguments{Directories: n.ConfigurationDirectories},
+				ResourceArguments{Patterns: n.IncludeResources},
+			},
+		},
+		reportingSource{
+			ArgumentSource: ConfigurerSource{
+				SourceName:  SourceUserEnvironment,
+				Configurers: []Arguments{UserArguments{Arguments: n.Arguments}},
+			},
+			Resolver: resolver,
+		},
+	)
+
+	if n.ArgumentsFile != "" {
+		sources = append(sources, ConfigurerSource{
+			SourceName:  SourceUserFile,
+			Configurers: []Arguments{UserFileArguments{ArgumentsFile: n.ArgumentsFile}},
+		})
+	}
+
+	return sources, nil
+}
+
+// context returns the context of the contributor, which is cancelled when the lifecycle aborts the build
+func (n NativeImage) context() context.Context {
+	if n.Context == nil {
+		return context.Background()
+	}
+	return n.Context
+}
+
+// strategy returns how the application is put on the classpath. Unless a strategy is set, an application with a
+// manifest is built as an exploded JAR, an application with a start class but without a JAR matching the JAR file
+// pattern as a plain directory, and any other application as a JAR file.
+func (n NativeImage) strategy() (ClasspathStrategy, error) {
+	switch n.ClasspathStrategy {
+	case ClasspathExplodedJar, ClasspathJar, ClasspathDirectory:
+		return n.ClasspathStrategy, nil
+	}
+
+	_, err := os.Stat(filepath.Join(n.ApplicationPath, "META-INF", "MANIFEST.MF"))
+	if err == nil {
+		return ClasspathExplodedJar, nil
+	} else if !os.IsNotExist(err) {
+		return "", fmt.Errorf("unable to check for manifest\n%w", err)
+	}
+
+	if n.StartClass == "" {
+		return ClasspathJar, nil
+	}
+
+	if n.JarFilePattern != "" {
+		jars, err := filepath.Glob(filepath.Join(n.ApplicationPath, n.JarFilePattern))
+		if err != nil {
+			return "", fmt.Errorf("unable to find JAR with %s\n%w", n.JarFilePattern, err)
+		}
+		if len(jars) > 0 {
+			return ClasspathJar, nil
+		}
+	}
+
+	return ClasspathDirectory, nil
+}
+
+// preflight checks the manifest of an exploded JAR, the classpath and the start class, which names a JAR file
+// otherwise
+func (n NativeImage) preflight(startClass string) error {
+	strategy, err := n.strategy()
+	if err != nil {
+		return err
+	}
+
+	cp, err := n.classpath()
+	if err != nil {
+		return err
+	}
+
+	p := Preflight{
+		ApplicationPath: n.ApplicationPath,
+		Classpath:       filepath.SplitList(cp),
+		Logger:          n.Logger,
+		Warnings:        n.Warnings,
+	}
+	switch strategy {
+	case ClasspathExplodedJar:
+		p.Manifest, p.StartClass = n.Manifest, startClass
+	case ClasspathDirectory:
+		p.StartClass = startClass
+	}
+
+	return p.Check()
+}
+
+// startClass returns the class started by the native image, which names the binary. A JAR file names the binary
+// after the JAR instead.
+func (n NativeImage) startClass() (string, error) {
+	strategy, err := n.strategy()
+	if err != nil {
+		return "", err
+	}
+
+	switch strategy {
+	case ClasspathDirectory:
+		_, startClass, err := DirectoryArguments{StartClass: n.StartClass}.Configure(nil)
+		return startClass, err
+	case ClasspathJar:
+		_, startClass, err := JarArguments{ApplicationPath: n.ApplicationPath, JarFilePattern: n.JarFilePattern}.Configure(nil)
+		return startClass, err
+	default:
+		return findStartOrMainClass(n.Manifest, n.ApplicationPath, n.JarFilePattern, n.StartClass)
+	}
+}
+
+// Compilation is the outcome of a single native-image run
+type Compilation struct {
+	Diagnoses []Diagnosis
+	Phases    []Phase
+	Output    string
+}
+
+// compile runs native-image, returning the diagnoses of a failed build
+func (n NativeImage) compile(ctx context.Context, layer libcnb.Layer, arguments []string, env []string) (Compilation, error) {
+	invoker, takesArguments := n.ResolveInvoker()
+	if !takesArguments {
+		invoker = DirectInvoker{Command: n.Command}
+	}
+	return n.invoke(ctx, invoker, layer.Path, "", arguments, env)
+}
+
+// ResolveInvoker returns the Invoker compiling the native image and whether it compiles with the arguments of the
+// buildpack
+func (n NativeImage) ResolveInvoker() (Invoker, bool) {
+	invoker := n.Invoker
+	if invoker == nil && n.BuildTool != nil {
+		invoker = BuildToolInvoker{ApplicationPath: n.ApplicationPath, BuildTool: *n.BuildTool}
+	} else if invoker == nil {
+		invoker = DirectInvoker{Command: n.Command}
+	}
+
+	switch invoker.(type) {
+	case BuildToolInvoker, BundleInvoker:
+		return invoker, false
+	default:
+		return invoker, true
+	}
+}
+
+// invoke compiles the native image named binary in dir with invoker
+func (n NativeImage) invoke(ctx context.Context, invoker Invoker, dir string, binary string, arguments []string, env []string) (Compilation, error) {
+	execution, err := invoker.Execution(dir, binary, arguments, env)
+	if err != nil {
+		return Compilation{}, err
+	}
+
+	n.Logger.Bodyf("Executing %s %s", filepath.Base(execution.Command), strings.Join(execution.Args, " "))
+	compilation, err := n.execute(ctx, execution)
+	if err != nil {
+		return compilation, err
+	}
+
+	if err := invoker.Collect(dir, binary); err != nil {
+		return compilation, err
+	}
+
+	return compilation, nil
+}
+
+// execute runs a native image build, capturing its output to report progress and diagnose failures
+func (n NativeImage) execute(ctx context.Context, execution effect.Execution) (Compilation, error) {
+	progress := NewPhaseWriter(n.Logger)
+	output := &bytes.Buffer{}
+	var stdout, stderr io.Writer = io.MultiWriter(progress, output), io.MultiWriter(n.Logger.InfoWriter(), output)
+
+	if n.DiagnosticsPath != "" {
+		log, err := n.openLog(execution)
+		if err != nil {
+			return Compilation{}, fmt.Errorf("unable to open native-image log\n%w", err)
+		}
+		defer log.Close()
+
+		stdout, stderr = io.MultiWriter(stdout, log), io.MultiWriter(stderr, log)
+	}
+
+	execution.Stdout, execution.Stderr = stdout, stderr
+	if err := executeContext(ctx, n.Executor, execution); err != nil {
+		progress.Flush()
+		if errors.Is(err, context.Canceled) {
+			return Compilation{}, fmt.Errorf("native-image was aborted\n%w", err)
+		}
+		if errors.Is(err, context.DeadlineExceeded) {
+			return Compilation{}, fmt.Errorf("native-image did not complete within %s, the timeout can be changed with $%s\n%w",
+				n.Timeout, ConfigNativeImageBuildTimeout, err)
+		}
+
+		LogExceptions(n.Logger, FindExceptions(output.String()))
+		diagnoses := DiagnoseFailure(output.String(), err)
+		LogDiagnoses(n.Logger, diagnoses)
+		var logPath string
+		if n.DiagnosticsPath != "" {
+			logPath = filepath.Join(n.DiagnosticsPath, NativeImageLog)
+			n.Logger.Bodyf("Full native-image output written to %s", logPath)
+		}
+		return Compilation{Diagnoses: diagnoses, Phases: progress.Phases, Output: output.String()},
+			NewNativeImageExitError(err, logPath, diagnoses)
+	}
+	progress.Flush()
+	progress.Summary()
+	LogExceptions(n.Logger, FindExceptions(output.String()))
+
+	return Compilation{Phases: progress.Phases, Output: output.String()}, nil
+}
+
+// trace runs the application with the tracing agent, writing the generated configuration to dir
+func (n NativeImage) trace(ctx context.Context, dir string, env []string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("unable to create %s\n%w", dir, err)
+	}
+
+	cp, err := n.classpath()
+	if err != nil {
+		return err
+	}
+
+	strategy, err := n.strategy()
+	if err != nil {
+		return err
+	}
+
+	var startClass string
+	if strategy == ClasspathJar {
+		// java -jar reads the Class-Path of the JAR itself
+		if cp, err = findJar(n.ApplicationPath, n.JarFilePattern); err != nil {
+			return err
+		}
+	} else if startClass, err = n.startClass(); err != nil {
+		return err
+	}
+
+	return n.TracingAgent.Run(ctx, n.Executor, n.Logger, effect.Execution{
+		Command: "java",
+		Args:    n.TracingAgent.Arguments(dir, cp, startClass),
+		Dir:     n.ApplicationPath,
+		Env:     env,
+		Stdout:  n.Logger.InfoWriter(),
+		Stderr:  n.Logger.InfoWriter(),
+	})
+}
+
+// estimate estimates the build of the application classpath from the previous build
+func (n NativeImage) estimate(previous Metrics) (Estimate, error) {
+	cp, err := n.classpath()
+	if err != nil {
+		return Estimate{}, err
+	}
+
+	classes, err := CountClasses(filepath.SplitList(cp))
+	if err != nil {
+		return Estimate{}, fmt.Errorf("unable to count classes\n%w", err)
+	}
+
+	size, err := MeasureClasspath(filepath.SplitList(cp))
+	if err != nil {
+		return Estimate{}, fmt.Errorf("unable to measure classpath\n%w", err)
+	}
+
+	e := EstimateBuild(classes, previous)
+	e.Disk = EstimateDiskSpace(size)
+	return e, nil
+}
+
+// verifyReproducible builds the native image a second time and fails if the binary differs from the first build
+func (n NativeImage) verifyReproducible(ctx context.Context, layer libcnb.Layer, arguments []string, startClass string, binary string, env []string) error {
+	verify := layer
+	verify.Path = filepath.Join(layer.Path, "reproducibility")
+	if err := os.MkdirAll(verify.Path, 0755); err != nil {
+		return fmt.Errorf("unable to create %s\n%w", verify.Path, err)
+	}
+	defer os.RemoveAll(verify.Path)
+
+	n.Logger.Body("Building native image a second time to verify that it is reproducible")
+	if _, err := n.compile(ctx, verify, replaceName(arguments, filepath.Join(verify.Path, startClass)), env); err != nil {
+		return err
+	}
+
+	expected, err := FileDigest(filepath.Join(layer.Path, binary))
+	if err != nil {
+		return err
+	}
+	actual, err := FileDigest(filepath.Join(verify.Path, binary))
+	if err != nil {
+		return err
+	}
+
+	if expected != actual {
+		return fmt.Errorf("native image is not reproducible, two builds from identical inputs produced sha256:%s and sha256:%s", expected, actual)
+	}
+	n.Logger.Bodyf("Native image is reproducible, sha256:%s", expected)
+
+	return nil
+}
+
+// abort removes the partial contents of the layer when the build was aborted, so that they are not left behind in a
+// cache volume
+func (n NativeImage) abort(layer libcnb.Layer, err error) error {
+	if !errors.Is(err, context.Canceled) {
+		return err
+	}
+
+	n.Logger.Bodyf("Removing partial native image layer %s", layer.Path)
+	if rErr := os.RemoveAll(layer.Path); rErr != nil {
+		n.Logger.Bodyf("unable to remove %s: %s", layer.Path, rErr)
+	}
+
+	return err
+}
+
+// resetLog truncates the native-image log in the diagnostics layer, once per contribution, so that the log holds every
+// run of the build: the main build, the auxiliary builds, retries and the reproducibility verification
+func (n NativeImage) resetLog() error {
+	if err := os.MkdirAll(n.DiagnosticsPath, 0755); err != nil {
+		return fmt.Errorf("unable to create %s\n%w", n.DiagnosticsPath, err)
+	}
+
+	file := filepath.Join(n.DiagnosticsPath, NativeImageLog)
+	if err := ioutil.WriteFile(file, nil, 0644); err != nil {
+		return fmt.Errorf("unable to truncate %s\n%w", file, err)
+	}
+
+	return nil
+}
+
+// openLog opens the native-image log in the diagnostics layer for appending and writes a header naming the run
+func (n NativeImage) openLog(execution effect.Execution) (*os.File, error) {
+	if err := os.MkdirAll(n.DiagnosticsPath, 0755); err != nil {
+		return nil, fmt.Errorf("unable to create %s\n%w", n.DiagnosticsPath, err)
+	}
+
+	file := filepath.Join(n.DiagnosticsPath, NativeImageLog)
+	out, err := os.OpenFile(file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open %s\n%w", file, err)
+	}
+
+	header := append([]string{filepath.Base(execution.Command)}, RedactArguments(execution.Args)...)
+	if _, err := fmt.Fprintf(out, "==> %s\n", strings.Join(header, " ")); err != nil {
+		out.Close()
+		return nil, fmt.Errorf("unable to write to %s\n%w", file, err)
+	}
+
+	return out, nil
+}
+
 func (NativeImage) Name() string {
 	return "native-image"
 }
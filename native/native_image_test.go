@@ -17,6 +17,11 @@
 package native_test
 
 import (
+	"archive/zip"
+	"bytes"
+	gocontext "context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -24,6 +29,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/buildpacks/libcnb"
 	"github.com/magiconair/properties"
@@ -68,14 +74,19 @@ func testNativeImage(t *testing.T, context spec.G, it spec.S) {
 		Expect(err).NotTo(HaveOccurred())
 
 		Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "fixture-marker"), []byte{}, 0644)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "test-start-class.class"), []byte{}, 0644)).To(Succeed())
 		Expect(os.MkdirAll(filepath.Join(ctx.Application.Path, "BOOT-INF"), 0755)).To(Succeed())
 		Expect(os.MkdirAll(filepath.Join(ctx.Application.Path, "META-INF"), 0755)).To(Succeed())
 		Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"), []byte{}, 0644)).To(Succeed())
 
-		nativeImage, err = native.NewNativeImage(ctx.Application.Path, "test-argument-1 test-argument-2", "", "none", "", props, ctx.StackID)
-		nativeImage.Logger = bard.NewLogger(io.Discard)
-		Expect(err).NotTo(HaveOccurred())
-		nativeImage.Executor = executor
+		nativeImage = native.New(
+			native.WithApplicationPath(ctx.Application.Path),
+			native.WithArguments("test-argument-1 test-argument-2"),
+			native.WithExecutor(executor),
+			native.WithLogger(bard.NewLogger(io.Discard)),
+			native.WithManifest(props),
+			native.WithStackID(ctx.StackID),
+		)
 
 		executor.On("Execute", mock.MatchedBy(func(e effect.Execution) bool {
 			return e.Command == "native-image" && len(e.Args) == 1 && e.Args[0] == "--version"
@@ -90,8 +101,7 @@ func testNativeImage(t *testing.T, context spec.G, it spec.S) {
 				(strings.HasPrefix(e.Args[0], "@"))
 		})).Run(func(args mock.Arguments) {
 			exec := args.Get(0).(effect.Execution)
-			lastArg := exec.Args[len(exec.Args)-1]
-			Expect(ioutil.WriteFile(filepath.Join(layer.Path, lastArg), []byte{}, 0644)).To(Succeed())
+			Expect(ioutil.WriteFile(imagePath(exec), []byte{}, 0644)).To(Succeed())
 		}).Return(nil)
 
 		executor.On("Execute", mock.MatchedBy(func(e effect.Execution) bool {
@@ -99,8 +109,7 @@ func testNativeImage(t *testing.T, context spec.G, it spec.S) {
 				(e.Args[0] == "test-argument-1" || (e.Args[0] == "-H:+StaticExecutableWithDynamicLibC" && e.Args[1] == "test-argument-1"))
 		})).Run(func(args mock.Arguments) {
 			exec := args.Get(0).(effect.Execution)
-			lastArg := exec.Args[len(exec.Args)-1]
-			Expect(ioutil.WriteFile(filepath.Join(layer.Path, lastArg), []byte{}, 0644)).To(Succeed())
+			Expect(ioutil.WriteFile(imagePath(exec), []byte{}, 0644)).To(Succeed())
 		}).Return(nil)
 
 		layer, err = ctx.Layers.Layer("test-layer")
@@ -131,7 +140,7 @@ func testNativeImage(t *testing.T, context spec.G, it spec.S) {
 				"test-argument-2",
 				fmt.Sprintf("-H:Name=%s", filepath.Join(layer.Path, "test-start-class")),
 				"-cp", "some-classpath",
-				"test-start-class",
+				"-H:Class=test-start-class",
 			}))
 		})
 	})
@@ -149,9 +158,9 @@ func testNativeImage(t *testing.T, context spec.G, it spec.S) {
 				"-cp",
 				strings.Join([]string{
 					ctx.Application.Path,
-					"manifest-class-path",
+					filepath.Join(ctx.Application.Path, "manifest-class-path"),
 				}, ":"),
-				"test-start-class",
+				"-H:Class=test-start-class",
 			}))
 		})
 
@@ -175,10 +184,855 @@ func testNativeImage(t *testing.T, context spec.G, it spec.S) {
 				"-cp",
 				strings.Join([]string{
 					ctx.Application.Path,
-					"manifest-class-path",
+					filepath.Join(ctx.Application.Path, "manifest-class-path"),
+				}, ":"),
+				"-H:Class=test-start-class",
+			}))
+		})
+
+		it("passes the args from a file after those from the environment, so that the file takes precedence", func() {
+			argsFile := filepath.Join(ctx.Application.Path, "target", "args.txt")
+			Expect(os.MkdirAll(filepath.Join(ctx.Application.Path, "target"), 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(argsFile, []byte(`--verbose`), 0644)).To(Succeed())
+
+			nativeImage, err := native.NewNativeImage(ctx.Application.Path, "test-argument-1 test-argument-2", argsFile, "none", "", props, ctx.StackID)
+			nativeImage.Logger = bard.NewLogger(io.Discard)
+			Expect(err).NotTo(HaveOccurred())
+			nativeImage.Executor = executor
+
+			_, err = nativeImage.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			execution := executor.Calls[1].Arguments[0].(effect.Execution)
+			Expect(execution.Args[:3]).To(Equal([]string{
+				"test-argument-1",
+				"test-argument-2",
+				fmt.Sprintf("@%s", argsFile),
+			}))
+		})
+	})
+
+	context("auxiliary binaries", func() {
+		it.Before(func() {
+			nativeImage.AuxiliaryBinaries = []native.AuxiliaryBinary{{Name: "migrate", Class: "test.Migrate"}}
+
+			executor = &mocks.Executor{}
+			nativeImage.Executor = executor
+			executor.On("Execute", mock.MatchedBy(func(e effect.Execution) bool {
+				return e.Command == "native-image" && len(e.Args) == 1 && e.Args[0] == "--version"
+			})).Return(nil)
+			executor.On("Execute", mock.Anything).Run(func(args mock.Arguments) {
+				exec := args.Get(0).(effect.Execution)
+				for _, a := range exec.Args {
+					if strings.HasPrefix(a, "-H:Name=") {
+						Expect(ioutil.WriteFile(strings.TrimPrefix(a, "-H:Name="), []byte{}, 0644)).To(Succeed())
+					}
+				}
+			}).Return(nil)
+		})
+
+		it("compiles each auxiliary binary on the application classpath", func() {
+			_, err := nativeImage.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			execution := executor.Calls[2].Arguments[0].(effect.Execution)
+			Expect(execution.Args).To(Equal([]string{
+				"test-argument-1",
+				"test-argument-2",
+				fmt.Sprintf("-H:Name=%s", filepath.Join(layer.Path, "migrate")),
+				"-cp",
+				strings.Join([]string{
+					ctx.Application.Path,
+					filepath.Join(ctx.Application.Path, "manifest-class-path"),
+				}, ":"),
+				"-H:Class=test.Migrate",
+			}))
+
+			Expect(filepath.Join(ctx.Application.Path, "test-start-class")).To(BeARegularFile())
+			Expect(filepath.Join(ctx.Application.Path, "migrate")).To(BeARegularFile())
+		})
+	})
+
+	context("plain directory", func() {
+		it.Before(func() {
+			Expect(os.Remove(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"))).To(Succeed())
+			Expect(os.MkdirAll(filepath.Join(ctx.Application.Path, "lib"), 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "lib", "dep.jar"), []byte{}, 0644)).To(Succeed())
+
+			Expect(os.MkdirAll(filepath.Join(ctx.Application.Path, "com", "example"), 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "com", "example", "App.class"), []byte{}, 0644)).To(Succeed())
+
+			nativeImage.Manifest = properties.NewProperties()
+			nativeImage.StartClass = "com.example.App"
+		})
+
+		it("contributes native image built from the start class and the library directory", func() {
+			_, err := nativeImage.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			execution := executor.Calls[1].Arguments[0].(effect.Execution)
+			Expect(execution.Args).To(Equal([]string{
+				"test-argument-1",
+				"test-argument-2",
+				fmt.Sprintf("-H:Name=%s", filepath.Join(layer.Path, "com.example.App")),
+				"-cp",
+				strings.Join([]string{
+					ctx.Application.Path,
+					filepath.Join(ctx.Application.Path, "lib", "dep.jar"),
 				}, ":"),
+				"-H:Class=com.example.App",
+			}))
+		})
+	})
+
+	context("thin JAR", func() {
+		it("puts the Class-Path of the JAR on the classpath of auxiliary binaries", func() {
+			out, err := os.Create(filepath.Join(ctx.Application.Path, "app.jar"))
+			Expect(err).NotTo(HaveOccurred())
+			z := zip.NewWriter(out)
+			w, err := z.Create("META-INF/MANIFEST.MF")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = w.Write([]byte("Main-Class: com.example.App\nClass-Path: lib/dep-1.0.jar\n"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(z.Close()).To(Succeed())
+			Expect(out.Close()).To(Succeed())
+
+			nativeImage.ClasspathStrategy = native.ClasspathJar
+			nativeImage.JarFilePattern = "*.jar"
+			nativeImage.AuxiliaryBinaries = []native.AuxiliaryBinary{{Name: "migrate", Class: "com.example.Migrate"}}
+
+			auxiliary, err := nativeImage.ProcessAuxiliaryArguments(layer)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(auxiliary[0]).To(ContainElement(strings.Join([]string{
+				filepath.Join(ctx.Application.Path, "app.jar"),
+				filepath.Join(ctx.Application.Path, "lib", "dep-1.0.jar"),
+			}, string(filepath.ListSeparator))))
+		})
+	})
+
+	context("library arguments", func() {
+		it("merges native-image.properties arguments ahead of user arguments", func() {
+			dir := filepath.Join(ctx.Application.Path, "META-INF", "native-image", "com.example", "app")
+			Expect(os.MkdirAll(dir, 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(dir, "native-image.properties"), []byte("Args = --enable-https"), 0644)).To(Succeed())
+			nativeImage.LibraryArguments = true
+
+			executor.On("Execute", mock.MatchedBy(func(e effect.Execution) bool {
+				return e.Command == "native-image" && e.Args[0] == "--enable-https"
+			})).Run(func(args mock.Arguments) {
+				exec := args.Get(0).(effect.Execution)
+				Expect(ioutil.WriteFile(imagePath(exec), []byte{}, 0644)).To(Succeed())
+			}).Return(nil)
+
+			_, err := nativeImage.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			execution := executor.Calls[1].Arguments[0].(effect.Execution)
+			Expect(execution.Args[:3]).To(Equal([]string{"--enable-https", "test-argument-1", "test-argument-2"}))
+		})
+	})
+
+	context("training artifacts", func() {
+		it("does not include training artifacts in the file listing", func() {
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "application.jsa"), []byte{}, 0644)).To(Succeed())
+
+			layer, err := nativeImage.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fmt.Sprint(layer.Metadata["files"])).NotTo(ContainSubstring("application.jsa"))
+		})
+	})
+
+	context("tracing agent", func() {
+		it("builds with the configuration generated by the tracing agent", func() {
+			nativeImage.TracingAgent = &native.TracingAgent{Duration: time.Second}
+			executor.On("Execute", mock.MatchedBy(func(e effect.Execution) bool {
+				return e.Command == "java"
+			})).Return(nil)
+			executor.On("Execute", mock.MatchedBy(func(e effect.Execution) bool {
+				return e.Command == "native-image" && strings.HasPrefix(e.Args[0], "-H:ConfigurationFileDirectories=")
+			})).Run(func(args mock.Arguments) {
+				exec := args.Get(0).(effect.Execution)
+				Expect(ioutil.WriteFile(imagePath(exec), []byte{}, 0644)).To(Succeed())
+			}).Return(nil)
+
+			_, err := nativeImage.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			agent := executor.Calls[1].Arguments[0].(effect.Execution)
+			Expect(agent.Command).To(Equal("java"))
+			Expect(agent.Args).To(Equal([]string{
+				fmt.Sprintf("-agentlib:native-image-agent=config-output-dir=%s", filepath.Join(layer.Path, "agent-configuration")),
+				"-cp",
+				strings.Join([]string{ctx.Application.Path, filepath.Join(ctx.Application.Path, "manifest-class-path")}, ":"),
 				"test-start-class",
 			}))
+
+			execution := executor.Calls[2].Arguments[0].(effect.Execution)
+			Expect(execution.Args).To(ContainElement(fmt.Sprintf("-H:ConfigurationFileDirectories=%s", filepath.Join(layer.Path, "agent-configuration"))))
+		})
+	})
+
+	context("diagnostics", func() {
+		it("writes native-image output to a log", func() {
+			nativeImage.DiagnosticsPath = filepath.Join(ctx.Layers.Path, "diagnostics")
+
+			executor.ExpectedCalls = nil
+			executor.On("Execute", mock.MatchedBy(func(e effect.Execution) bool {
+				return e.Command == "native-image" && len(e.Args) == 1 && e.Args[0] == "--version"
+			})).Return(nil)
+			executor.On("Execute", mock.Anything).Run(func(args mock.Arguments) {
+				exec := args.Get(0).(effect.Execution)
+				_, err := exec.Stdout.Write([]byte("[1/7] Initializing... (1.0s @ 0.20GB)\n"))
+				Expect(err).NotTo(HaveOccurred())
+				_, err = exec.Stderr.Write([]byte("Error: compilation failed\n"))
+				Expect(err).NotTo(HaveOccurred())
+			}).Return(fmt.Errorf("exit status 1"))
+
+			_, err := nativeImage.Contribute(layer)
+			Expect(err).To(HaveOccurred())
+
+			data, err := ioutil.ReadFile(filepath.Join(ctx.Layers.Path, "diagnostics", "native-image.log"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(HavePrefix("==> native-image test-argument-1 test-argument-2 "))
+			Expect(string(data)).To(HaveSuffix("\n[1/7] Initializing... (1.0s @ 0.20GB)\nError: compilation failed\n"))
+		})
+
+		it("keeps the output of every run of the build in the log", func() {
+			nativeImage.DiagnosticsPath = filepath.Join(ctx.Layers.Path, "diagnostics")
+			nativeImage.AuxiliaryBinaries = []native.AuxiliaryBinary{{Name: "migrate", Class: "test.Migrate"}}
+			Expect(os.MkdirAll(nativeImage.DiagnosticsPath, 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(nativeImage.DiagnosticsPath, "native-image.log"), []byte("previous build\n"), 0644)).To(Succeed())
+
+			executor.ExpectedCalls = nil
+			executor.On("Execute", mock.MatchedBy(func(e effect.Execution) bool {
+				return e.Command == "native-image" && len(e.Args) == 1 && e.Args[0] == "--version"
+			})).Return(nil)
+			executor.On("Execute", mock.Anything).Run(func(args mock.Arguments) {
+				exec := args.Get(0).(effect.Execution)
+				Expect(ioutil.WriteFile(imagePath(exec), []byte{}, 0644)).To(Succeed())
+				_, err := exec.Stdout.Write([]byte(fmt.Sprintf("built %s\n", filepath.Base(imagePath(exec)))))
+				Expect(err).NotTo(HaveOccurred())
+			}).Return(nil)
+
+			_, err := nativeImage.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			data, err := ioutil.ReadFile(filepath.Join(ctx.Layers.Path, "diagnostics", "native-image.log"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).NotTo(ContainSubstring("previous build"))
+			Expect(strings.Count(string(data), "==> native-image ")).To(Equal(2))
+			Expect(string(data)).To(ContainSubstring("built test-start-class\n"))
+			Expect(string(data)).To(ContainSubstring("built migrate\n"))
+		})
+	})
+
+	context("metrics", func() {
+		it("records metrics in layer metadata and labels", func() {
+			nativeImage.Metrics = &native.Metrics{Labels: []libcnb.Label{
+				{Key: "io.paketo.native-image.graalvm-version"},
+				{Key: "io.paketo.native-image.binary-size-bytes"},
+			}}
+
+			layer, err := nativeImage.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(layer.Metadata["metrics"]).To(HaveKeyWithValue("graalvm-version", "1.2.3"))
+			Expect(layer.Metadata["metrics"]).To(HaveKeyWithValue("binary-size", int64(0)))
+			Expect(nativeImage.Metrics.Labels).To(Equal([]libcnb.Label{
+				{Key: "io.paketo.native-image.graalvm-version", Value: "1.2.3"},
+				{Key: "io.paketo.native-image.binary-size-bytes", Value: "0"},
+			}))
+			Expect(nativeImage.Metrics.Rebuilt).To(BeTrue())
+		})
+
+		it("keeps metrics of a reused layer", func() {
+			layer, err := nativeImage.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+			layer.Metadata["metrics"] = native.Metrics{GraalVMVersion: "previous"}.Metadata()
+			Expect(ioutil.WriteFile(fmt.Sprintf("%s.toml", layer.Path), []byte("[types]\n  cache = true"), 0644)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "fixture-marker"), []byte{}, 0644)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "test-start-class.class"), []byte{}, 0644)).To(Succeed())
+			Expect(os.MkdirAll(filepath.Join(ctx.Application.Path, "BOOT-INF"), 0755)).To(Succeed())
+			Expect(os.MkdirAll(filepath.Join(ctx.Application.Path, "META-INF"), 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"), []byte{}, 0644)).To(Succeed())
+			Expect(os.Remove(filepath.Join(ctx.Application.Path, "test-start-class"))).To(Succeed())
+
+			nativeImage.Metrics = &native.Metrics{}
+			layer, err = nativeImage.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(executor.Calls).To(HaveLen(3))
+			Expect(nativeImage.Metrics.GraalVMVersion).To(Equal("previous"))
+			Expect(nativeImage.Metrics.Rebuilt).To(BeFalse())
+		})
+	})
+
+	context("environment", func() {
+		it.Before(func() {
+			Expect(os.Setenv("TEST_STRAY_VARIABLE", "stray")).To(Succeed())
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("TEST_STRAY_VARIABLE")).To(Succeed())
+		})
+
+		it("runs native-image with an explicit environment", func() {
+			nativeImage.Environment = []string{"PATH", "TEST_EXPLICIT=value"}
+
+			_, err := nativeImage.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			execution := executor.Calls[1].Arguments[0].(effect.Execution)
+			Expect(execution.Env).To(Equal([]string{fmt.Sprintf("PATH=%s", os.Getenv("PATH")), "TEST_EXPLICIT=value"}))
+		})
+
+		it("inherits the environment without an allow-list", func() {
+			_, err := nativeImage.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(executor.Calls[1].Arguments[0].(effect.Execution).Env).To(BeNil())
+		})
+
+		it("writes temporary files to the layer", func() {
+			nativeImage.Environment = []string{"PATH"}
+			nativeImage.TempSpace = native.TempSpace{Directory: "layer"}
+
+			layer, err := nativeImage.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			execution := executor.Calls[1].Arguments[0].(effect.Execution)
+			Expect(execution.Env).To(ContainElement(HavePrefix(fmt.Sprintf("TMPDIR=%s", filepath.Join(layer.Path, "tmp")))))
+			Expect(execution.Env).To(ContainElement(HavePrefix(fmt.Sprintf("JAVA_TOOL_OPTIONS=-Djava.io.tmpdir=%s", filepath.Join(layer.Path, "tmp")))))
+			Expect(filepath.Join(layer.Path, "tmp")).NotTo(BeAnExistingFile())
+		})
+
+		it("checks the free disk space against the estimate", func() {
+			b := &bytes.Buffer{}
+			nativeImage.Logger = bard.NewLogger(b)
+			nativeImage.TempSpace = native.TempSpace{CheckEstimate: true}
+
+			_, err := nativeImage.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(b.String()).To(ContainSubstring("Estimated disk space: 0.2 GB"))
+			Expect(b.String()).To(ContainSubstring("Free disk space in " + layer.Path))
+		})
+
+		it("fails without enough free disk space", func() {
+			nativeImage.TempSpace = native.TempSpace{MinFree: 1 << 60}
+
+			_, err := nativeImage.Contribute(layer)
+			Expect(err).To(MatchError(ContainSubstring("set with $BP_NATIVE_IMAGE_MIN_DISK_SPACE")))
+		})
+
+		it("passes build secrets without logging them", func() {
+			b := &bytes.Buffer{}
+			nativeImage.Logger = bard.NewLogger(b)
+			nativeImage.Environment = []string{"PATH"}
+			nativeImage.BuildSecrets = &native.BuildSecrets{
+				Paths:   []string{"/bindings/secrets"},
+				Secrets: map[string]string{"REPO_TOKEN": "test-secret"},
+			}
+
+			layer, err := nativeImage.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			execution := executor.Calls[1].Arguments[0].(effect.Execution)
+			Expect(execution.Env).To(ContainElements("REPO_TOKEN=test-secret", "NATIVE_IMAGE_BUILD_SECRETS=/bindings/secrets"))
+			Expect(b.String()).NotTo(ContainSubstring("test-secret"))
+			Expect(fmt.Sprint(layer.Metadata)).NotTo(ContainSubstring("test-secret"))
+		})
+	})
+
+	context("provenance", func() {
+		it("writes a checksum and provenance statement", func() {
+			nativeImage.Builder = "test-id@test-version"
+
+			layer, err := nativeImage.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(layer.Metadata["provenance"]).To(Equal(map[string]interface{}{
+				"binary-sha256": "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+				"statement":     "provenance.json",
+			}))
+
+			data, err := ioutil.ReadFile(filepath.Join(layer.Path, "provenance.json"))
+			Expect(err).NotTo(HaveOccurred())
+			var statement native.Statement
+			Expect(json.Unmarshal(data, &statement)).To(Succeed())
+			Expect(statement.Subject[0].Name).To(Equal("test-start-class"))
+			Expect(statement.Predicate.Builder.ID).To(Equal("test-id@test-version"))
+		})
+	})
+
+	context("architecture", func() {
+		var version string
+
+		it.Before(func() {
+			version = "GraalVM 22.3.0 Java 17 CE"
+			executor = &mocks.Executor{}
+			nativeImage.Executor = executor
+			nativeImage.Architecture = "arm64"
+
+			executor.On("Execute", mock.MatchedBy(func(e effect.Execution) bool {
+				return e.Command == "native-image" && len(e.Args) == 1 && e.Args[0] == "--version"
+			})).Run(func(args mock.Arguments) {
+				_, err := args.Get(0).(effect.Execution).Stdout.Write([]byte(version))
+				Expect(err).To(Succeed())
+			}).Return(nil)
+			executor.On("Execute", mock.MatchedBy(func(e effect.Execution) bool {
+				return e.Command == "native-image" && len(e.Args) > 1
+			})).Run(func(args mock.Arguments) {
+				exec := args.Get(0).(effect.Execution)
+				Expect(ioutil.WriteFile(imagePath(exec), []byte{}, 0644)).To(Succeed())
+			}).Return(nil)
+		})
+
+		it("builds portable machine code and records the architecture", func() {
+			layer, err := nativeImage.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(executor.Calls[1].Arguments[0].(effect.Execution).Args[0]).To(Equal("-march=compatibility"))
+			Expect(layer.Metadata["architecture"]).To(Equal("arm64"))
+		})
+
+		it("removes -march for native-image versions without support", func() {
+			version = "GraalVM 22.2.0 Java 17 CE"
+
+			_, err := nativeImage.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(executor.Calls[1].Arguments[0].(effect.Execution).Args).NotTo(ContainElement("-march=compatibility"))
+		})
+	})
+
+	context("deterministic image heap", func() {
+		var version string
+
+		it.Before(func() {
+			version = "GraalVM 23.0.1 Java 17 CE"
+			executor = &mocks.Executor{}
+			nativeImage.Executor = executor
+			nativeImage.Deterministic = true
+
+			executor.On("Execute", mock.MatchedBy(func(e effect.Execution) bool {
+				return e.Command == "native-image" && len(e.Args) == 1 && e.Args[0] == "--version"
+			})).Run(func(args mock.Arguments) {
+				_, err := args.Get(0).(effect.Execution).Stdout.Write([]byte(version))
+				Expect(err).To(Succeed())
+			}).Return(nil)
+			executor.On("Execute", mock.MatchedBy(func(e effect.Execution) bool {
+				return e.Command == "native-image" && len(e.Args) > 1
+			})).Run(func(args mock.Arguments) {
+				exec := args.Get(0).(effect.Execution)
+				Expect(ioutil.WriteFile(imagePath(exec), []byte{}, 0644)).To(Succeed())
+			}).Return(nil)
+		})
+
+		it("requests a deterministic image heap", func() {
+			_, err := nativeImage.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(executor.Calls[1].Arguments[0].(effect.Execution).Args).To(ContainElement("-H:+DeterministicImageHeap"))
+		})
+
+		it("skips the option with a warning for native-image versions without support", func() {
+			version = "GraalVM 22.3.0 Java 17 CE"
+			b := &bytes.Buffer{}
+			nativeImage.Logger = bard.NewLogger(b)
+
+			_, err := nativeImage.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(executor.Calls[1].Arguments[0].(effect.Execution).Args).NotTo(ContainElement("-H:+DeterministicImageHeap"))
+			Expect(b.String()).To(ContainSubstring("native-image does not support -H:+DeterministicImageHeap"))
+		})
+	})
+
+	context("Spring Native compatibility", func() {
+		it.Before(func() {
+			executor = &mocks.Executor{}
+			nativeImage.Executor = executor
+			nativeImage.SpringNative = &native.Artifact{ArtifactID: "spring-native", Version: "0.11.2"}
+			nativeImage.Compatibility = native.DefaultCompatibility
+
+			executor.On("Execute", mock.MatchedBy(func(e effect.Execution) bool {
+				return e.Command == "native-image" && len(e.Args) == 1 && e.Args[0] == "--version"
+			})).Run(func(args mock.Arguments) {
+				_, err := args.Get(0).(effect.Execution).Stdout.Write([]byte("GraalVM 22.3.0 Java 17 CE"))
+				Expect(err).To(Succeed())
+			}).Return(nil)
+		})
+
+		it("fails before compiling with an unsupported GraalVM version", func() {
+			_, err := nativeImage.Contribute(layer)
+			Expect(err).To(MatchError(ContainSubstring("spring-native 0.11.2 requires GraalVM >=21.3.0, <22.2.0, but found GraalVM 22.3.0")))
+			Expect(errors.As(err, &native.IncompatibleGraalVMError{})).To(BeTrue())
+			Expect(executor.Calls).To(HaveLen(1))
+		})
+	})
+
+	context("native-image command", func() {
+		it("runs the configured command", func() {
+			nativeImage.Command = "/opt/mandrel/bin/native-image"
+			nativeImage.Metrics = &native.Metrics{}
+			executor.On("Execute", mock.MatchedBy(func(e effect.Execution) bool {
+				return e.Command == "/opt/mandrel/bin/native-image" && len(e.Args) == 1
+			})).Run(func(args mock.Arguments) {
+				_, err := args.Get(0).(effect.Execution).Stdout.Write([]byte("native-image 22.3.1.0-Final Mandrel Distribution"))
+				Expect(err).To(Succeed())
+			}).Return(nil)
+			executor.On("Execute", mock.MatchedBy(func(e effect.Execution) bool {
+				return e.Command == "/opt/mandrel/bin/native-image" && len(e.Args) > 1
+			})).Run(func(args mock.Arguments) {
+				exec := args.Get(0).(effect.Execution)
+				Expect(ioutil.WriteFile(imagePath(exec), []byte{}, 0644)).To(Succeed())
+			}).Return(nil)
+
+			_, err := nativeImage.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(executor.Calls[0].Arguments[0].(effect.Execution).Command).To(Equal("/opt/mandrel/bin/native-image"))
+			Expect(executor.Calls[1].Arguments[0].(effect.Execution).Command).To(Equal("/opt/mandrel/bin/native-image"))
+			Expect(nativeImage.Metrics.Distribution).To(Equal("mandrel"))
+		})
+	})
+
+	context("build tools", func() {
+		it("compiles with the Maven wrapper and harvests the native image", func() {
+			wrapper := filepath.Join(ctx.Application.Path, "mvnw")
+			Expect(ioutil.WriteFile(wrapper, []byte{}, 0755)).To(Succeed())
+			nativeImage.BuildTool = &native.BuildTool{
+				Name:            native.BuildToolMaven,
+				Wrapper:         wrapper,
+				Args:            []string{"-Pnative", "native:compile"},
+				OutputDirectory: "target",
+			}
+			executor.On("Execute", mock.MatchedBy(func(e effect.Execution) bool {
+				return e.Command == wrapper
+			})).Run(func(args mock.Arguments) {
+				target := filepath.Join(ctx.Application.Path, "target")
+				Expect(os.MkdirAll(target, 0755)).To(Succeed())
+				Expect(ioutil.WriteFile(filepath.Join(target, "demo"), []byte("native-image"), 0755)).To(Succeed())
+			}).Return(nil)
+
+			_, err := nativeImage.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			execution := executor.Calls[1].Arguments[0].(effect.Execution)
+			Expect(execution.Args).To(Equal([]string{"-Pnative", "native:compile"}))
+			Expect(execution.Dir).To(Equal(ctx.Application.Path))
+			Expect(executor.Calls).To(HaveLen(2))
+			Expect(ioutil.ReadFile(filepath.Join(layer.Path, "test-start-class"))).To(Equal([]byte("native-image")))
+			Expect(filepath.Join(ctx.Application.Path, "test-start-class")).To(BeARegularFile())
+		})
+	})
+
+	context("argument file invoker", func() {
+		it("passes the arguments in an argument file", func() {
+			executor = &mocks.Executor{}
+			nativeImage.Executor = executor
+			nativeImage.Invoker = native.ArgfileInvoker{Command: "native-image"}
+			executor.On("Execute", mock.MatchedBy(func(e effect.Execution) bool {
+				return e.Command == "native-image" && len(e.Args) == 1 && e.Args[0] == "--version"
+			})).Return(nil)
+			executor.On("Execute", mock.MatchedBy(func(e effect.Execution) bool {
+				return e.Command == "native-image" && len(e.Args) == 1 && strings.HasPrefix(e.Args[0], "@")
+			})).Run(func(args mock.Arguments) {
+				Expect(ioutil.ReadFile(filepath.Join(layer.Path, native.ArgfileName))).To(ContainSubstring(`"-H:Class=test-start-class"`))
+				Expect(ioutil.WriteFile(filepath.Join(layer.Path, "test-start-class"), []byte{}, 0644)).To(Succeed())
+			}).Return(nil)
+
+			_, err := nativeImage.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(executor.Calls[1].Arguments[0].(effect.Execution).Args).To(Equal([]string{"@" + filepath.Join(layer.Path, native.ArgfileName)}))
+			Expect(filepath.Join(layer.Path, native.ArgfileName)).NotTo(BeAnExistingFile())
+		})
+	})
+
+	context("fallback image", func() {
+		it.Before(func() {
+			executor = &mocks.Executor{}
+			nativeImage.Executor = executor
+			executor.On("Execute", mock.MatchedBy(func(e effect.Execution) bool {
+				return e.Command == "native-image" && len(e.Args) == 1 && e.Args[0] == "--version"
+			})).Return(nil)
+			executor.On("Execute", mock.MatchedBy(func(e effect.Execution) bool {
+				return e.Command == "native-image" && len(e.Args) > 1
+			})).Run(func(args mock.Arguments) {
+				exec := args.Get(0).(effect.Execution)
+				_, err := exec.Stdout.Write([]byte("Warning: Image 'test-start-class' is a fallback image that requires a JDK for execution\n"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ioutil.WriteFile(filepath.Join(layer.Path, "test-start-class"), []byte{}, 0644)).To(Succeed())
+			}).Return(nil)
+		})
+
+		it("fails on a fallback image", func() {
+			_, err := nativeImage.Contribute(layer)
+			Expect(err).To(MatchError(ContainSubstring("native-image produced test-start-class as a fallback image")))
+
+			var fallback native.FallbackImageError
+			Expect(errors.As(err, &fallback)).To(BeTrue())
+		})
+
+		it("accepts a fallback image when allowed", func() {
+			nativeImage.AllowFallback = true
+
+			_, err := nativeImage.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	context("native-image unavailable", func() {
+		it.Before(func() {
+			executor = &mocks.Executor{}
+			nativeImage.Executor = executor
+
+			executor.On("Execute", mock.MatchedBy(func(e effect.Execution) bool {
+				return e.Command == "native-image" && len(e.Args) == 1
+			})).Return(fmt.Errorf("executable file not found in $PATH"))
+		})
+
+		it("fails without installing the component", func() {
+			_, err := nativeImage.Contribute(layer)
+			Expect(err).To(MatchError(ContainSubstring("error running version")))
+			Expect(executor.Calls).To(HaveLen(1))
+		})
+	})
+
+	context("GraalVM Enterprise", func() {
+		it("fails on enterprise arguments without a license", func() {
+			nativeImage.Arguments = "--pgo-instrument"
+
+			_, err := nativeImage.Contribute(layer)
+			Expect(err).To(MatchError(ContainSubstring("native-image argument --pgo-instrument requires GraalVM Enterprise")))
+		})
+	})
+
+	context("build summary", func() {
+		it("writes the summary to the layer and the configured path", func() {
+			nativeImage.SummaryPath = filepath.Join(ctx.Layers.Path, "reports", "summary.json")
+
+			layer, err := nativeImage.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			data, err := ioutil.ReadFile(filepath.Join(layer.Path, "native-build-summary.json"))
+			Expect(err).NotTo(HaveOccurred())
+			var summary native.Summary
+			Expect(json.Unmarshal(data, &summary)).To(Succeed())
+			Expect(summary.Binary).To(Equal("test-start-class"))
+			Expect(summary.GraalVMVersion).To(Equal("1.2.3"))
+
+			Expect(ioutil.ReadFile(nativeImage.SummaryPath)).To(Equal(data))
+		})
+
+		it("writes the warnings of the build to the summary and repeats them at the end", func() {
+			b := &bytes.Buffer{}
+			nativeImage.Logger = bard.NewLogger(b)
+			nativeImage.Warnings = &native.Warnings{Messages: []string{"Excluding development-time dependency"}}
+			// the fixture manifest lists a Class-Path entry that would be warned about as missing
+			Expect(os.MkdirAll(filepath.Join(ctx.Application.Path, "manifest-class-path"), 0755)).To(Succeed())
+
+			layer, err := nativeImage.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			data, err := ioutil.ReadFile(filepath.Join(layer.Path, "native-build-summary.json"))
+			Expect(err).NotTo(HaveOccurred())
+			var summary native.Summary
+			Expect(json.Unmarshal(data, &summary)).To(Succeed())
+			Expect(summary.Warnings).To(Equal([]string{"Excluding development-time dependency"}))
+
+			Expect(b.String()).To(ContainSubstring("WARNINGS"))
+			Expect(b.String()[strings.LastIndex(b.String(), "WARNINGS"):]).To(ContainSubstring("* Excluding development-time dependency"))
+		})
+	})
+
+	context("record arguments", func() {
+		it("writes redacted arguments to the layer and a label", func() {
+			nativeImage.Arguments = "test-argument-1 -Dspring.datasource.password=hunter2"
+			nativeImage.RecordArguments = true
+			nativeImage.Metrics = &native.Metrics{Labels: []libcnb.Label{{Key: "io.paketo.native-image.arguments"}}}
+
+			_, err := nativeImage.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			data, err := ioutil.ReadFile(filepath.Join(layer.Path, "native-image-arguments.txt"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(HavePrefix("test-argument-1\n-Dspring.datasource.password=[REDACTED]\n"))
+			Expect(string(data)).NotTo(ContainSubstring("hunter2"))
+			Expect(nativeImage.Metrics.Labels[0].Value).To(HavePrefix(`["test-argument-1","-Dspring.datasource.password=[REDACTED]",`))
+		})
+
+		it("does not write arguments by default", func() {
+			_, err := nativeImage.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(filepath.Join(layer.Path, "native-image-arguments.txt")).NotTo(BeAnExistingFile())
+		})
+	})
+
+	context("compare builds", func() {
+		it("prints the change since the previous build", func() {
+			b := &bytes.Buffer{}
+			nativeImage.Logger = bard.NewLogger(b)
+			nativeImage.CompareMetrics = true
+			layer.Metadata = map[string]interface{}{"metrics": native.Metrics{BinarySize: 1024 * 1024}.Metadata()}
+
+			_, err := nativeImage.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(b.String()).To(ContainSubstring("Native image is -1.0 MB, +0 s since last build"))
+		})
+
+		it("does not print a change without a previous build", func() {
+			b := &bytes.Buffer{}
+			nativeImage.Logger = bard.NewLogger(b)
+			nativeImage.CompareMetrics = true
+
+			_, err := nativeImage.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(b.String()).NotTo(ContainSubstring("since last build"))
+		})
+	})
+
+	context("budget", func() {
+		it("fails before compiling when the estimate exceeds the budget", func() {
+			nativeImage.Budget = native.Budget{Duration: time.Second}
+
+			_, err := nativeImage.Contribute(layer)
+			Expect(err).To(MatchError(ContainSubstring("exceeds the budget of 1s set with $BP_NATIVE_IMAGE_DURATION_BUDGET")))
+			Expect(executor.Calls).To(HaveLen(1))
+		})
+	})
+
+	context("timeout", func() {
+		it("fails with a timeout error", func() {
+			nativeImage.Timeout = time.Nanosecond
+
+			_, err := nativeImage.Contribute(layer)
+			Expect(err).To(MatchError(ContainSubstring("native-image did not complete within 1ns, the timeout can be changed with $BP_NATIVE_IMAGE_BUILD_TIMEOUT")))
+			Expect(errors.Is(err, gocontext.DeadlineExceeded)).To(BeTrue())
+		})
+	})
+
+	context("reproducible", func() {
+		it("sets the modification time of the binary", func() {
+			nativeImage.SourceDateEpoch = native.DefaultSourceDateEpoch
+
+			_, err := nativeImage.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			fi, err := os.Stat(filepath.Join(ctx.Application.Path, "test-start-class"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fi.ModTime().Equal(native.DefaultSourceDateEpoch)).To(BeTrue())
+		})
+
+		it("verifies that a second build is identical", func() {
+			nativeImage.VerifyReproducible = true
+
+			executor.ExpectedCalls = nil
+			executor.On("Execute", mock.MatchedBy(func(e effect.Execution) bool {
+				return e.Command == "native-image" && len(e.Args) == 1 && e.Args[0] == "--version"
+			})).Return(nil)
+			executor.On("Execute", mock.Anything).Run(func(args mock.Arguments) {
+				exec := args.Get(0).(effect.Execution)
+				name := strings.TrimPrefix(exec.Args[len(exec.Args)-4], "-H:Name=")
+				Expect(ioutil.WriteFile(name, []byte("binary"), 0644)).To(Succeed())
+			}).Return(nil)
+
+			_, err := nativeImage.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			execution := executor.Calls[2].Arguments[0].(effect.Execution)
+			Expect(execution.Args).To(ContainElement(fmt.Sprintf("-H:Name=%s", filepath.Join(layer.Path, "reproducibility", "test-start-class"))))
+			Expect(filepath.Join(layer.Path, "reproducibility")).NotTo(BeAnExistingFile())
+		})
+
+		it("fails when a second build differs", func() {
+			nativeImage.VerifyReproducible = true
+
+			executor.ExpectedCalls = nil
+			executor.On("Execute", mock.MatchedBy(func(e effect.Execution) bool {
+				return e.Command == "native-image" && len(e.Args) == 1 && e.Args[0] == "--version"
+			})).Return(nil)
+			executor.On("Execute", mock.Anything).Run(func(args mock.Arguments) {
+				exec := args.Get(0).(effect.Execution)
+				name := strings.TrimPrefix(exec.Args[len(exec.Args)-4], "-H:Name=")
+				Expect(ioutil.WriteFile(name, []byte(name), 0644)).To(Succeed())
+			}).Return(nil)
+
+			_, err := nativeImage.Contribute(layer)
+			Expect(err).To(MatchError(ContainSubstring("native image is not reproducible")))
+		})
+	})
+
+	context("aborted", func() {
+		it("removes the partial layer", func() {
+			c, cancel := gocontext.WithCancel(gocontext.Background())
+			cancel()
+			nativeImage.Context = c
+
+			_, err := nativeImage.Contribute(layer)
+			Expect(err).To(MatchError(ContainSubstring("native-image was aborted")))
+			Expect(errors.Is(err, gocontext.Canceled)).To(BeTrue())
+			Expect(layer.Path).NotTo(BeADirectory())
+			Expect(executor.Calls).To(BeEmpty())
+		})
+	})
+
+	context("out of memory", func() {
+		it.Before(func() {
+			executor.ExpectedCalls = nil
+			executor.On("Execute", mock.MatchedBy(func(e effect.Execution) bool {
+				return len(e.Args) == 1 && e.Args[0] == "--version"
+			})).Return(nil)
+			executor.On("Execute", mock.MatchedBy(func(e effect.Execution) bool {
+				return e.Args[0] == "test-argument-1"
+			})).Return(fmt.Errorf("signal: killed"))
+			executor.On("Execute", mock.MatchedBy(func(e effect.Execution) bool {
+				return strings.HasPrefix(e.Args[0], "--parallelism=")
+			})).Run(func(args mock.Arguments) {
+				Expect(ioutil.WriteFile(filepath.Join(layer.Path, "test-start-class"), []byte{}, 0644)).To(Succeed())
+			}).Return(nil)
+		})
+
+		it("fails without retry", func() {
+			_, err := nativeImage.Contribute(layer)
+			Expect(err).To(MatchError(ContainSubstring("signal: killed")))
+			Expect(executor.Calls).To(HaveLen(2))
+
+			var exit native.NativeImageExitError
+			Expect(errors.As(err, &exit)).To(BeTrue())
+			Expect(exit.ExitCode).To(Equal(-1))
+			Expect(exit.Diagnoses).To(ContainElement(native.DiagnosisOutOfMemory))
+		})
+
+		it("retries with reduced resources", func() {
+			nativeImage.RetryOnOutOfMemory = true
+
+			_, err := nativeImage.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(executor.Calls).To(HaveLen(3))
+			execution := executor.Calls[2].Arguments[0].(effect.Execution)
+			Expect(execution.Args[0]).To(HavePrefix("--parallelism="))
+			Expect(execution.Args[1:3]).To(Equal([]string{"test-argument-1", "test-argument-2"}))
+		})
+
+		it("warns that the heap is not reduced without -J-Xmx", func() {
+			b := &bytes.Buffer{}
+			nativeImage.Logger = bard.NewLogger(b)
+			nativeImage.RetryOnOutOfMemory = true
+
+			_, err := nativeImage.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			execution := executor.Calls[2].Arguments[0].(effect.Execution)
+			Expect(execution.Args).NotTo(ContainElement(HavePrefix("-J-Xmx")))
+			Expect(b.String()).To(ContainSubstring("Retrying native-image once with reduced parallelism, set -J-Xmx to also reduce the heap"))
 		})
 	})
 
@@ -190,6 +1044,7 @@ func testNativeImage(t *testing.T, context spec.G, it spec.S) {
 			// we do expect a Main-Class
 			_, _, err := props.Set("Main-Class", "test-main-class")
 			Expect(err).NotTo(HaveOccurred())
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "test-main-class.class"), []byte{}, 0644)).To(Succeed())
 		})
 
 		it("contributes native image using Main-Class", func() {
@@ -204,9 +1059,9 @@ func testNativeImage(t *testing.T, context spec.G, it spec.S) {
 				"-cp",
 				strings.Join([]string{
 					ctx.Application.Path,
-					"manifest-class-path",
+					filepath.Join(ctx.Application.Path, "manifest-class-path"),
 				}, ":"),
-				"test-main-class",
+				"-H:Class=test-main-class",
 			}))
 		})
 	})
@@ -269,6 +1124,169 @@ func testNativeImage(t *testing.T, context spec.G, it spec.S) {
 		})
 	})
 
+	it("does not copy the binary into the application for the binary layer", func() {
+		nativeImage.BinaryLayer = true
+
+		layer, err := nativeImage.Contribute(layer)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(filepath.Join(layer.Path, "test-start-class")).To(BeARegularFile())
+		Expect(filepath.Join(ctx.Application.Path, "test-start-class")).NotTo(BeAnExistingFile())
+	})
+
+	it("leaves a preserved application unchanged for the binary layer", func() {
+		Expect(os.MkdirAll(layer.Path, 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(layer.Path, "libawt.so"), []byte{}, 0644)).To(Succeed())
+		nativeImage.BinaryLayer = true
+		nativeImage.Preserve = []string{"**"}
+		nativeImage.Outputs = []string{"*.so"}
+
+		_, err := nativeImage.Contribute(layer)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(filepath.Join(ctx.Application.Path, "fixture-marker")).To(BeARegularFile())
+		Expect(filepath.Join(ctx.Application.Path, "libawt.so")).NotTo(BeAnExistingFile())
+	})
+
+	it("merges the generated configuration into the build", func() {
+		nativeImage.Generated = native.GeneratedConfiguration{SerializationClasses: []string{"com.example.Order"}}
+		executor.On("Execute", mock.MatchedBy(func(e effect.Execution) bool {
+			return e.Command == "native-image" && strings.HasPrefix(e.Args[0], "-H:ConfigurationFileDirectories=")
+		})).Run(func(args mock.Arguments) {
+			exec := args.Get(0).(effect.Execution)
+			Expect(ioutil.WriteFile(imagePath(exec), []byte{}, 0644)).To(Succeed())
+		}).Return(nil)
+
+		layer, err := nativeImage.Contribute(layer)
+		Expect(err).NotTo(HaveOccurred())
+
+		dir := filepath.Join(layer.Path, "generated-configuration")
+		Expect(filepath.Join(dir, "serialization-config.json")).To(BeARegularFile())
+		Expect(layer.Metadata).To(HaveKey("generated-configuration"))
+		Expect(executor.Calls[1].Arguments[0].(effect.Execution).Args).To(ContainElement("-H:ConfigurationFileDirectories=" + dir))
+	})
+
+	it("copies native libraries next to the binary", func() {
+		library := filepath.Join(ctx.Application.Path, "BOOT-INF", "lib", "libzstd.so")
+		Expect(os.MkdirAll(filepath.Dir(library), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(library, []byte("library"), 0644)).To(Succeed())
+		nativeImage.NativeLibraries = []string{library}
+
+		layer, err := nativeImage.Contribute(layer)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(ioutil.ReadFile(filepath.Join(layer.Path, "libzstd.so"))).To(Equal([]byte("library")))
+		Expect(ioutil.ReadFile(filepath.Join(ctx.Application.Path, "libzstd.so"))).To(Equal([]byte("library")))
+		Expect(library).NotTo(BeAnExistingFile())
+	})
+
+	context("size budget", func() {
+		it.Before(func() {
+			executor = &mocks.Executor{}
+			nativeImage.Executor = executor
+			executor.On("Execute", mock.MatchedBy(func(e effect.Execution) bool {
+				return e.Command == "native-image" && len(e.Args) == 1 && e.Args[0] == "--version"
+			})).Return(nil)
+			executor.On("Execute", mock.Anything).Run(func(args mock.Arguments) {
+				exec := args.Get(0).(effect.Execution)
+				Expect(ioutil.WriteFile(imagePath(exec), make([]byte, 2048), 0644)).To(Succeed())
+			}).Return(nil)
+		})
+
+		it("fails when the native image exceeds the maximum size", func() {
+			nativeImage.Budget = native.Budget{Size: 1024, SizeAction: native.BudgetFail}
+
+			_, err := nativeImage.Contribute(layer)
+			Expect(errors.As(err, &native.ImageSizeError{})).To(BeTrue())
+		})
+
+		it("warns when the native image exceeds the maximum size", func() {
+			nativeImage.Budget = native.Budget{Size: 1024, SizeAction: native.BudgetWarn}
+			nativeImage.Warnings = &native.Warnings{}
+
+			_, err := nativeImage.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(nativeImage.Warnings.Messages).To(ContainElement(ContainSubstring("exceeds the maximum size of 0.0 MB")))
+		})
+	})
+
+	context("phase thresholds", func() {
+		it.Before(func() {
+			nativeImage.Warnings = &native.Warnings{}
+		})
+
+		it("warns when the build exceeds a threshold", func() {
+			nativeImage.Budget = native.Budget{Phases: []native.PhaseThreshold{{Phase: "total", Duration: time.Nanosecond}}}
+
+			layer, err := nativeImage.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(nativeImage.Warnings.Messages).To(ContainElement(HavePrefix("total took")))
+
+			data, err := ioutil.ReadFile(filepath.Join(layer.Path, "native-build-summary.json"))
+			Expect(err).NotTo(HaveOccurred())
+			var summary native.Summary
+			Expect(json.Unmarshal(data, &summary)).To(Succeed())
+			Expect(summary.Violations).To(HaveLen(1))
+		})
+
+		it("fails when the build exceeds a threshold", func() {
+			nativeImage.Budget = native.Budget{
+				Phases:      []native.PhaseThreshold{{Phase: "total", Duration: time.Nanosecond}},
+				PhaseAction: native.BudgetFail,
+			}
+
+			_, err := nativeImage.Contribute(layer)
+			Expect(errors.As(err, &native.PhaseThresholdError{})).To(BeTrue())
+		})
+	})
+
+	it("fails when the start class is not on the classpath", func() {
+		_, _, err := props.Set("Start-Class", "com.example.Aplication")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.MkdirAll(filepath.Join(ctx.Application.Path, "com", "example"), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "com", "example", "Application.class"), []byte{}, 0644)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "com", "example", "aplication.class"), []byte{}, 0644)).To(Succeed())
+
+		_, err = nativeImage.Contribute(layer)
+		Expect(err).To(MatchError(native.PreflightError{Problems: []string{
+			native.StartClassNotFoundError{StartClass: "com.example.Aplication", Similar: []string{"com.example.aplication"}}.Error(),
+		}}))
+		Expect(executor.Calls).To(BeEmpty())
+	})
+
+	it("reports all problems found before compiling at once", func() {
+		_, _, err := props.Set("Start-Class", "com.example.Missing")
+		Expect(err).NotTo(HaveOccurred())
+		_, _, err = props.Set("Spring-Boot-Lib", "BOOT-INF/lib/")
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = nativeImage.Contribute(layer)
+		Expect(err).To(MatchError(native.PreflightError{Problems: []string{
+			"manifest attribute Spring-Boot-Lib points at BOOT-INF/lib/, which does not exist",
+			native.StartClassNotFoundError{StartClass: "com.example.Missing"}.Error(),
+		}}))
+	})
+
+	it("passes the start class as the trailing argument in legacy mode", func() {
+		nativeImage.LegacyMainClass = true
+
+		_, err := nativeImage.Contribute(layer)
+		Expect(err).NotTo(HaveOccurred())
+
+		args := executor.Calls[1].Arguments[0].(effect.Execution).Args
+		Expect(args[len(args)-1]).To(Equal("test-start-class"))
+	})
+
+	it("does not cache the layer when caching is disabled", func() {
+		nativeImage.Cache = false
+
+		layer, err := nativeImage.Contribute(layer)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(layer.Cache).To(BeFalse())
+		Expect(filepath.Join(ctx.Application.Path, "test-start-class")).To(BeARegularFile())
+	})
+
 	context("tiny stack", func() {
 		it.Before(func() {
 			nativeImage.StackID = libpak.TinyStackID
@@ -298,11 +1316,22 @@ func testNativeImage(t *testing.T, context spec.G, it spec.S) {
 				"-cp",
 				strings.Join([]string{
 					ctx.Application.Path,
-					"manifest-class-path",
+					filepath.Join(ctx.Application.Path, "manifest-class-path"),
 				}, ":"),
-				"test-start-class",
+				"-H:Class=test-start-class",
 			}))
 			Expect(execution.Dir).To(Equal(layer.Path))
 		})
 	})
 }
+
+// imagePath returns the path of the native image built by a native-image execution
+func imagePath(exec effect.Execution) string {
+	for _, a := range exec.Args {
+		if strings.HasPrefix(a, "-H:Name=") {
+			return strings.TrimPrefix(a, "-H:Name=")
+		}
+	}
+
+	return filepath.Join(exec.Dir, exec.Args[len(exec.Args)-1])
+}
@@ -0,0 +1,76 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"strings"
+)
+
+// nettyCoordinates are the coordinates of Netty and of the reactive stacks running on it
+var nettyCoordinates = []string{
+	"io.netty:netty-transport",
+	"io.netty:netty-common",
+	"io.projectreactor.netty:reactor-netty-core",
+	"org.springframework:spring-webflux",
+}
+
+// DefaultNettyInitializeAtRunTime are the Netty packages that load native transports, which must not be initialized
+// while building the image
+var DefaultNettyInitializeAtRunTime = []string{
+	"io.netty.channel.epoll",
+	"io.netty.channel.kqueue",
+	"io.netty.channel.unix",
+}
+
+// DefaultNettySystemProperties stop Netty from allocating buffers with sun.misc.Unsafe, whose raw memory access native
+// images do not fully support
+var DefaultNettySystemProperties = []string{
+	"io.netty.noUnsafe=true",
+}
+
+// FindNetty returns the first Netty or reactive stack artifact in the dependencies of the classpath
+func FindNetty(dependencies Dependencies) (Artifact, bool) {
+	return dependencies.Find(nettyCoordinates...)
+}
+
+// MergeNettyDefaults adds the Netty packages to initialize at run time and the Netty system properties to those
+// configured by the end user. Packages the end user initializes at build time, and properties the end user sets, are
+// left as configured.
+func MergeNettyDefaults(buildTime []string, runTime []string, properties []string) ([]string, []string) {
+	for _, p := range DefaultNettyInitializeAtRunTime {
+		if !containsString(buildTime, p) && !containsString(runTime, p) {
+			runTime = append(runTime, p)
+		}
+	}
+
+	for _, p := range DefaultNettySystemProperties {
+		key := strings.SplitN(p, "=", 2)[0]
+
+		set := false
+		for _, q := range properties {
+			if strings.SplitN(q, "=", 2)[0] == key {
+				set = true
+				break
+			}
+		}
+		if !set {
+			properties = append(properties, p)
+		}
+	}
+
+	return runTime, properties
+}
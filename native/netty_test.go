@@ -0,0 +1,68 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testNetty(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+	)
+
+	it("finds Netty", func() {
+		dependencies, err := native.DetectDependencies(native.NewDependencyDetector(), "/workspace", []string{
+			"BOOT-INF/lib/spring-core-6.0.9.jar",
+			"BOOT-INF/lib/netty-transport-4.1.92.Final.jar",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		a, ok := native.FindNetty(dependencies)
+		Expect(ok).To(BeTrue())
+		Expect(a.ArtifactID).To(Equal("netty-transport"))
+	})
+
+	it("does not find other artifacts", func() {
+		dependencies, err := native.DetectDependencies(native.NewDependencyDetector(), "/workspace", []string{
+			"BOOT-INF/lib/spring-webmvc-6.0.9.jar",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		_, ok := native.FindNetty(dependencies)
+		Expect(ok).To(BeFalse())
+	})
+
+	it("merges the defaults with the configuration of the end user", func() {
+		runTime, properties := native.MergeNettyDefaults(
+			[]string{"io.netty.channel.kqueue"},
+			[]string{"com.example", "io.netty.channel.epoll"},
+			[]string{"spring.profiles.active=native"},
+		)
+
+		Expect(runTime).To(Equal([]string{"com.example", "io.netty.channel.epoll", "io.netty.channel.unix"}))
+		Expect(properties).To(Equal([]string{"spring.profiles.active=native", "io.netty.noUnsafe=true"}))
+	})
+
+	it("keeps system properties set by the end user", func() {
+		_, properties := native.MergeNettyDefaults(nil, nil, []string{"io.netty.noUnsafe=false"})
+		Expect(properties).To(Equal([]string{"io.netty.noUnsafe=false"}))
+	})
+}
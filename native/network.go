@@ -0,0 +1,196 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"context"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/buildpacks/libcnb"
+	"github.com/paketo-buildpacks/libpak/bard"
+	"github.com/paketo-buildpacks/libpak/bindings"
+	"github.com/paketo-buildpacks/libpak/effect"
+	"github.com/paketo-buildpacks/libpak/sherpa"
+)
+
+// CACertificatesBindingType is the type of bindings holding PEM encoded certificates of additional certificate
+// authorities, such as those of corporate proxies
+const CACertificatesBindingType = "ca-certificates"
+
+// FindCACertificates returns the certificate files in bindings of type ca-certificates
+func FindCACertificates(binds libcnb.Bindings) ([]string, error) {
+	var files []string
+
+	for _, b := range bindings.Resolve(binds, bindings.OfType(CACertificatesBindingType)) {
+		children, err := ioutil.ReadDir(b.Path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to list children of %s\n%w", b.Path, err)
+		}
+
+		for _, c := range children {
+			// Kubernetes mounts bindings through hidden ..data directories
+			if c.IsDir() || strings.HasPrefix(c.Name(), ".") || c.Name() == "type" || c.Name() == "provider" {
+				continue
+			}
+			files = append(files, filepath.Join(b.Path, c.Name()))
+		}
+	}
+
+	return files, nil
+}
+
+// ProxyProperties translates the proxy environment variables, which the JVM ignores, into the equivalent system
+// properties, so that native-image, the tracing agent and gu reach the network through the proxy
+func ProxyProperties(env []string) []string {
+	values := map[string]string{}
+	for _, e := range env {
+		if parts := strings.SplitN(e, "=", 2); len(parts) == 2 {
+			values[parts[0]] = parts[1]
+		}
+	}
+	lookup := func(name string) string {
+		if v := values[strings.ToLower(name)]; v != "" {
+			return v
+		}
+		return values[name]
+	}
+
+	var properties []string
+	for _, p := range []struct{ env, scheme string }{{"HTTP_PROXY", "http"}, {"HTTPS_PROXY", "https"}} {
+		u, err := url.Parse(lookup(p.env))
+		if err != nil || u.Hostname() == "" {
+			continue
+		}
+
+		properties = append(properties, fmt.Sprintf("-D%s.proxyHost=%s", p.scheme, u.Hostname()))
+		if u.Port() != "" {
+			properties = append(properties, fmt.Sprintf("-D%s.proxyPort=%s", p.scheme, u.Port()))
+		}
+	}
+
+	if len(properties) == 0 {
+		return nil
+	}
+
+	var hosts []string
+	for _, h := range strings.Split(lookup("NO_PROXY"), ",") {
+		if h = strings.TrimSpace(h); h == "" {
+			continue
+		}
+		if strings.HasPrefix(h, ".") {
+			h = "*" + h
+		}
+		hosts = append(hosts, h)
+	}
+	if len(hosts) > 0 {
+		properties = append(properties, fmt.Sprintf("-Dhttp.nonProxyHosts=%s", strings.Join(hosts, "|")))
+	}
+
+	return properties
+}
+
+// TrustStore creates a trust store in dir holding the certificate authorities of the JDK in javaHome and those in
+// certificates, using keytool. Returns the system properties selecting the trust store.
+func TrustStore(ctx context.Context, executor effect.Executor, logger bard.Logger, javaHome string, dir string, certificates []string) ([]string, error) {
+	store := filepath.Join(dir, "cacerts")
+
+	// the trust store replaces the one of the JDK, so that it must also hold the default certificate authorities
+	cacerts := filepath.Join(javaHome, "lib", "security", "cacerts")
+	if in, err := os.Open(cacerts); err == nil {
+		defer in.Close()
+		if err := sherpa.CopyFile(in, store); err != nil {
+			return nil, fmt.Errorf("unable to copy %s to %s\n%w", cacerts, store, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("unable to open %s\n%w", cacerts, err)
+	}
+
+	for _, file := range certificates {
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read %s\n%w", file, err)
+		}
+
+		// keytool imports a single certificate per file, while bindings commonly hold bundles
+		for i := 0; ; i++ {
+			var block *pem.Block
+			if block, b = pem.Decode(b); block == nil {
+				break
+			}
+			if block.Type != "CERTIFICATE" {
+				continue
+			}
+
+			alias := fmt.Sprintf("%s-%d", filepath.Base(file), i)
+			cert := filepath.Join(dir, alias+".pem")
+			if err := ioutil.WriteFile(cert, pem.EncodeToMemory(block), 0644); err != nil {
+				return nil, fmt.Errorf("unable to write %s\n%w", cert, err)
+			}
+
+			logger.Bodyf("Adding certificate %s to the trust store", alias)
+			if err := executeContext(ctx, executor, effect.Execution{
+				Command: "keytool",
+				Args:    []string{"-importcert", "-noprompt", "-keystore", store, "-storepass", "changeit", "-alias", alias, "-file", cert},
+				Stdout:  logger.InfoWriter(),
+				Stderr:  logger.InfoWriter(),
+			}); err != nil {
+				return nil, fmt.Errorf("unable to add %s to the trust store\n%w", file, err)
+			}
+		}
+	}
+
+	return []string{
+		fmt.Sprintf("-Djavax.net.ssl.trustStore=%s", store),
+		"-Djavax.net.ssl.trustStorePassword=changeit",
+	}, nil
+}
+
+// JavaToolOptions adds options to $JAVA_TOOL_OPTIONS of env, which every JVM started by the build picks up. A nil
+// env inherits the build environment.
+func JavaToolOptions(env []string, options []string) []string {
+	if len(options) == 0 {
+		return env
+	}
+
+	if env == nil {
+		env = os.Environ()
+	}
+
+	var (
+		result   []string
+		existing string
+	)
+	for _, e := range env {
+		if strings.HasPrefix(e, "JAVA_TOOL_OPTIONS=") {
+			existing = strings.TrimPrefix(e, "JAVA_TOOL_OPTIONS=")
+			continue
+		}
+		result = append(result, e)
+	}
+
+	values := options
+	if existing != "" {
+		values = append([]string{existing}, options...)
+	}
+	return append(result, fmt.Sprintf("JAVA_TOOL_OPTIONS=%s", strings.Join(values, " ")))
+}
@@ -0,0 +1,172 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package native
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/magiconair/properties"
+)
+
+// OfflineArgument is the argument of the Maven and Gradle wrappers resolving dependencies from their local caches only
+const OfflineArgument = "--offline"
+
+// buildToolHome is where the wrapper of a build tool keeps its distributions and the dependencies of the build
+type buildToolHome struct {
+	// Env is the environment variable setting the user home of the build tool
+	Env string
+	// Default is the user home of the build tool, relative to the home directory, when Env is not set
+	Default string
+	// Properties is the wrapper configuration, relative to the application, holding the distributionUrl
+	Properties string
+	// Repository is the directory of the user home holding the dependencies and plugins of the build
+	Repository string
+}
+
+var buildToolHomes = map[string]buildToolHome{
+	BuildToolMaven:  {Env: "MAVEN_USER_HOME", Default: ".m2", Properties: ".mvn/wrapper/maven-wrapper.properties", Repository: "repository"},
+	BuildToolGradle: {Env: "GRADLE_USER_HOME", Default: ".gradle", Properties: "gradle/wrapper/gradle-wrapper.properties", Repository: "caches"},
+}
+
+// OfflineArtifact is an artifact a network-dependent step of the build would download, and how to provide it instead
+type OfflineArtifact struct {
+	Name    string
+	Provide string
+}
+
+func (o OfflineArtifact) String() string {
+	return fmt.Sprintf("%s: %s", o.Name, o.Provide)
+}
+
+// componentArtifact is the native-image component gu installs from the GraalVM catalog
+var componentArtifact = OfflineArtifact{
+	Name:    "native-image component",
+	Provide: fmt.Sprintf("bind the component JAR with a binding of type %s", ComponentBindingType),
+}
+
+// Offline checks that the network-dependent steps of a build resolve from bindings and pre-seeded caches: the install
+// of the native-image component and the distribution and dependencies of the wrapper compiling with Native Build
+// Tools, including the GraalVM reachability metadata
+type Offline struct {
+	ApplicationPath  string
+	BuildTool        *BuildTool
+	Command          string
+	ComponentArchive string
+	InstallComponent bool
+}
+
+// Missing returns the artifacts the build would have to download
+func (o Offline) Missing() ([]OfflineArtifact, error) {
+	var missing []OfflineArtifact
+
+	if o.InstallComponent && o.ComponentArchive == "" {
+		if _, err := exec.LookPath(o.Command); err != nil {
+			missing = append(missing, componentArtifact)
+		}
+	}
+
+	if o.BuildTool != nil {
+		m, err := o.buildToolArtifacts(*o.BuildTool)
+		if err != nil {
+			return nil, err
+		}
+		missing = append(missing, m...)
+	}
+
+	return missing, nil
+}
+
+// buildToolArtifacts returns the distribution of the wrapper and the dependencies of the build missing from the user
+// home of the build tool
+func (o Offline) buildToolArtifacts(tool BuildTool) ([]OfflineArtifact, error) {
+	h, ok := buildToolHomes[tool.Name]
+	if !ok {
+		return nil, nil
+	}
+
+	home := os.Getenv(h.Env)
+	if home == "" {
+		dir, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("unable to determine the home directory\n%w", err)
+		}
+		home = filepath.Join(dir, h.Default)
+	}
+
+	var missing []OfflineArtifact
+
+	if name, ok, err := wrapperDistribution(filepath.Join(o.ApplicationPath, h.Properties)); err != nil {
+		return nil, err
+	} else if ok {
+		dir := filepath.Join(home, "wrapper", "dists", name)
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			missing = append(missing, OfflineArtifact{
+				Name:    fmt.Sprintf("%s distribution %s", tool.Name, name),
+				Provide: fmt.Sprintf("pre-seed %s, or set the distributionUrl of %s to a file: URL", dir, h.Properties),
+			})
+		} else if err != nil {
+			return nil, fmt.Errorf("unable to stat %s\n%w", dir, err)
+		}
+	}
+
+	dir := filepath.Join(home, h.Repository)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		missing = append(missing, OfflineArtifact{
+			Name:    fmt.Sprintf("%s dependencies, plugins and GraalVM reachability metadata", tool.Name),
+			Provide: fmt.Sprintf("pre-seed %s, or set $%s to a cache holding them", dir, h.Env),
+		})
+	} else if err != nil {
+		return nil, fmt.Errorf("unable to stat %s\n%w", dir, err)
+	}
+
+	return missing, nil
+}
+
+// wrapperDistribution returns the name of the distribution the wrapper downloads, from the distributionUrl of its
+// configuration. Returns false if the wrapper has no configuration or the distribution is a local file.
+func wrapperDistribution(file string) (string, bool, error) {
+	if _, err := os.Stat(file); os.IsNotExist(err) {
+		return "", false, nil
+	} else if err != nil {
+		return "", false, fmt.Errorf("unable to stat %s\n%w", file, err)
+	}
+
+	p, err := properties.LoadFile(file, properties.UTF8)
+	if err != nil {
+		return "", false, fmt.Errorf("unable to read %s\n%w", file, err)
+	}
+
+	raw, ok := p.Get("distributionUrl")
+	if !ok {
+		return "", false, nil
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", false, fmt.Errorf("unable to parse distributionUrl %s of %s\n%w", raw, file, err)
+	} else if u.Scheme == "file" {
+		return "", false, nil
+	}
+
+	base := path.Base(u.Path)
+	return strings.TrimSuffix(base, path.Ext(base)), true, nil
+}
@@ -0,0 +1,127 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package native_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testOffline(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		appPath string
+		command string
+		home    string
+	)
+
+	it.Before(func() {
+		var err error
+		appPath, err = ioutil.TempDir("", "offline-application")
+		Expect(err).NotTo(HaveOccurred())
+		home, err = ioutil.TempDir("", "offline-home")
+		Expect(err).NotTo(HaveOccurred())
+
+		command = filepath.Join(home, "native-image")
+		Expect(ioutil.WriteFile(command, []byte{}, 0755)).To(Succeed())
+
+		Expect(os.Setenv("MAVEN_USER_HOME", filepath.Join(home, ".m2"))).To(Succeed())
+	})
+
+	it.After(func() {
+		Expect(os.Unsetenv("MAVEN_USER_HOME")).To(Succeed())
+		Expect(os.RemoveAll(appPath)).To(Succeed())
+		Expect(os.RemoveAll(home)).To(Succeed())
+	})
+
+	it("requires nothing when native-image is available", func() {
+		Expect(native.Offline{Command: command, InstallComponent: true}.Missing()).To(BeEmpty())
+	})
+
+	it("requires the component archive when native-image is missing", func() {
+		missing, err := native.Offline{Command: filepath.Join(home, "missing"), InstallComponent: true}.Missing()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(missing).To(HaveLen(1))
+		Expect(missing[0].Name).To(Equal("native-image component"))
+		Expect(missing[0].Provide).To(ContainSubstring("native-image-component"))
+	})
+
+	it("requires nothing when the component archive is bound or not installed", func() {
+		Expect(native.Offline{Command: filepath.Join(home, "missing"), InstallComponent: true, ComponentArchive: "/bindings/svm.jar"}.Missing()).
+			To(BeEmpty())
+		Expect(native.Offline{Command: filepath.Join(home, "missing")}.Missing()).To(BeEmpty())
+	})
+
+	context("build tools", func() {
+		var tool *native.BuildTool
+
+		it.Before(func() {
+			Expect(os.MkdirAll(filepath.Join(appPath, ".mvn", "wrapper"), 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(appPath, ".mvn", "wrapper", "maven-wrapper.properties"),
+				[]byte("distributionUrl=https\\://repo.maven.apache.org/maven2/org/apache/maven/apache-maven/3.8.6/apache-maven-3.8.6-bin.zip\n"), 0644)).
+				To(Succeed())
+
+			tool = &native.BuildTool{Name: native.BuildToolMaven}
+		})
+
+		it("requires the distribution and the dependencies of the wrapper", func() {
+			missing, err := native.Offline{ApplicationPath: appPath, BuildTool: tool, Command: command}.Missing()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(missing).To(Equal([]native.OfflineArtifact{
+				{
+					Name:    "maven distribution apache-maven-3.8.6-bin",
+					Provide: "pre-seed " + filepath.Join(home, ".m2", "wrapper", "dists", "apache-maven-3.8.6-bin") + ", or set the distributionUrl of .mvn/wrapper/maven-wrapper.properties to a file: URL",
+				},
+				{
+					Name:    "maven dependencies, plugins and GraalVM reachability metadata",
+					Provide: "pre-seed " + filepath.Join(home, ".m2", "repository") + ", or set $MAVEN_USER_HOME to a cache holding them",
+				},
+			}))
+		})
+
+		it("requires nothing when the caches are pre-seeded", func() {
+			Expect(os.MkdirAll(filepath.Join(home, ".m2", "wrapper", "dists", "apache-maven-3.8.6-bin"), 0755)).To(Succeed())
+			Expect(os.MkdirAll(filepath.Join(home, ".m2", "repository"), 0755)).To(Succeed())
+
+			Expect(native.Offline{ApplicationPath: appPath, BuildTool: tool, Command: command}.Missing()).To(BeEmpty())
+		})
+
+		it("does not require a local distribution", func() {
+			Expect(ioutil.WriteFile(filepath.Join(appPath, ".mvn", "wrapper", "maven-wrapper.properties"),
+				[]byte("distributionUrl=file:///opt/apache-maven-3.8.6-bin.zip\n"), 0644)).To(Succeed())
+			Expect(os.MkdirAll(filepath.Join(home, ".m2", "repository"), 0755)).To(Succeed())
+
+			Expect(native.Offline{ApplicationPath: appPath, BuildTool: tool, Command: command}.Missing()).To(BeEmpty())
+		})
+	})
+
+	it("lists every missing artifact", func() {
+		Expect(native.OfflineError{Missing: []native.OfflineArtifact{
+			{Name: "a", Provide: "provide a"},
+			{Name: "b", Provide: "provide b"},
+		}}.Error()).To(Equal("$BP_NATIVE_IMAGE_OFFLINE is set, but the build requires artifacts it would download:\n  * a: provide a\n  * b: provide b"))
+	})
+}
@@ -0,0 +1,177 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"context"
+
+	"github.com/magiconair/properties"
+	"github.com/paketo-buildpacks/libpak/bard"
+	"github.com/paketo-buildpacks/libpak/effect"
+)
+
+// ClasspathStrategy selects how the application is put on the native-image classpath
+type ClasspathStrategy string
+
+const (
+	// ClasspathAuto builds an exploded JAR if the application has a manifest, and a JAR file otherwise
+	ClasspathAuto ClasspathStrategy = ""
+	// ClasspathExplodedJar builds the application directory as an exploded JAR
+	ClasspathExplodedJar ClasspathStrategy = "exploded-jar"
+	// ClasspathJar builds the JAR file matching the JAR file pattern
+	ClasspathJar ClasspathStrategy = "jar"
+	// ClasspathDirectory builds a plain directory of classes and a library directory with the start class
+	ClasspathDirectory ClasspathStrategy = "directory"
+)
+
+// Option configures a NativeImage created with New
+type Option func(*NativeImage)
+
+// New creates a NativeImage contributor, so that other buildpacks and tools can embed it. Options are applied in
+// order to a contributor running the native-image command with the ProcessGroupExecutor.
+func New(options ...Option) NativeImage {
+	n := NativeImage{
+		Cache:      true,
+		Command:    DefaultCommand,
+		Compressor: CompressorNone,
+		Executor:   ProcessGroupExecutor{},
+	}
+
+	for _, o := range options {
+		o(&n)
+	}
+
+	return n
+}
+
+// WithApplicationPath sets the path of the application to build
+func WithApplicationPath(path string) Option {
+	return func(n *NativeImage) {
+		n.ApplicationPath = path
+	}
+}
+
+// WithArguments sets the native-image arguments configured by the end user, split like a shell would
+func WithArguments(arguments string) Option {
+	return func(n *NativeImage) {
+		n.Arguments = arguments
+	}
+}
+
+// WithArgumentsFile sets a file holding the native-image arguments configured by the end user
+func WithArgumentsFile(file string) Option {
+	return func(n *NativeImage) {
+		n.ArgumentsFile = file
+	}
+}
+
+// WithArgumentResolver replaces the ArgumentResolver resolving the gathered native-image arguments
+func WithArgumentResolver(resolver Resolver) Option {
+	return func(n *NativeImage) {
+		n.Resolver = resolver
+	}
+}
+
+// WithCache sets whether the native image layer is cached, so that an unchanged application is not compiled again.
+// Caching trades the storage of the layer for the time of the build.
+func WithCache(cache bool) Option {
+	return func(n *NativeImage) {
+		n.Cache = cache
+	}
+}
+
+// WithClasspathStrategy selects how the application is put on the classpath, and the JAR file pattern matching the
+// JAR file to build
+func WithClasspathStrategy(strategy ClasspathStrategy, jarFilePattern string) Option {
+	return func(n *NativeImage) {
+		n.ClasspathStrategy = strategy
+		n.JarFilePattern = jarFilePattern
+	}
+}
+
+// WithClasspathResolver replaces the ManifestClasspathResolver resolving the classpath of an exploded JAR when
+// $CLASSPATH is not set
+func WithClasspathResolver(resolver ClasspathResolver) Option {
+	return func(n *NativeImage) {
+		n.ClasspathResolver = resolver
+	}
+}
+
+// WithCommand sets the native-image command, e.g. the absolute path of a native-image outside $JAVA_HOME
+func WithCommand(command string) Option {
+	return func(n *NativeImage) {
+		n.Command = command
+	}
+}
+
+// WithCompressor sets the compression method of the binary
+func WithCompressor(compressor string) Option {
+	return func(n *NativeImage) {
+		n.Compressor = compressor
+	}
+}
+
+// WithContext sets the context whose cancellation kills the running command and aborts the contribution. Executors
+// implementing ContextExecutor are cancelled cooperatively.
+func WithContext(ctx context.Context) Option {
+	return func(n *NativeImage) {
+		n.Context = ctx
+	}
+}
+
+// WithExecutor sets the executor running native-image and the auxiliary commands
+func WithExecutor(executor effect.Executor) Option {
+	return func(n *NativeImage) {
+		n.Executor = executor
+	}
+}
+
+// WithLibDirectory sets the library directory of a plain directory application, relative to the application path
+func WithLibDirectory(directory string) Option {
+	return func(n *NativeImage) {
+		n.LibDirectory = directory
+	}
+}
+
+// WithLogger sets the logger
+func WithLogger(logger bard.Logger) Option {
+	return func(n *NativeImage) {
+		n.Logger = logger
+	}
+}
+
+// WithManifest sets the manifest of the application
+func WithManifest(manifest *properties.Properties) Option {
+	return func(n *NativeImage) {
+		n.Manifest = manifest
+	}
+}
+
+// WithStackID sets the stack the binary runs on, which selects static linking on the Tiny stack
+func WithStackID(stackID string) Option {
+	return func(n *NativeImage) {
+		n.StackID = stackID
+	}
+}
+
+// WithStartClass sets the class to start, which a plain directory application requires and which overrides the
+// Start-Class and Main-Class of an exploded JAR
+func WithStartClass(startClass string) Option {
+	return func(n *NativeImage) {
+		n.StartClass = startClass
+	}
+}
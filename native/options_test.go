@@ -0,0 +1,135 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/buildpacks/libcnb"
+	"github.com/magiconair/properties"
+	. "github.com/onsi/gomega"
+	"github.com/paketo-buildpacks/libpak"
+	"github.com/paketo-buildpacks/libpak/bard"
+	"github.com/paketo-buildpacks/libpak/effect/mocks"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+type recordingResolver struct {
+	resolved []string
+}
+
+func (t *recordingResolver) Resolve(arguments []string) ([]string, error) {
+	t.resolved = arguments
+	return append(arguments, "--test-resolved"), nil
+}
+
+func (t *recordingResolver) ReportOverrides(before []string, after []string) {}
+
+func testOptions(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		appPath string
+	)
+
+	it.Before(func() {
+		var err error
+
+		appPath, err = ioutil.TempDir("", "options")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(appPath)).To(Succeed())
+	})
+
+	it("creates a contributor with defaults", func() {
+		n := native.New()
+
+		Expect(n.Command).To(Equal("native-image"))
+		Expect(n.Compressor).To(Equal("none"))
+		Expect(n.Executor).To(Equal(native.ProcessGroupExecutor{}))
+		Expect(n.ClasspathStrategy).To(Equal(native.ClasspathAuto))
+		Expect(n.Cache).To(BeTrue())
+	})
+
+	it("applies options", func() {
+		executor := &mocks.Executor{}
+		manifest := properties.NewProperties()
+
+		n := native.New(
+			native.WithApplicationPath(appPath),
+			native.WithArguments("--no-fallback"),
+			native.WithArgumentsFile("/args.txt"),
+			native.WithClasspathStrategy(native.ClasspathJar, "target/*.jar"),
+			native.WithCommand("/opt/mandrel/bin/native-image"),
+			native.WithCompressor("upx"),
+			native.WithExecutor(executor),
+			native.WithManifest(manifest),
+			native.WithStackID(libpak.JammyTinyStackID),
+		)
+
+		Expect(n.ApplicationPath).To(Equal(appPath))
+		Expect(n.Arguments).To(Equal("--no-fallback"))
+		Expect(n.ArgumentsFile).To(Equal("/args.txt"))
+		Expect(n.ClasspathStrategy).To(Equal(native.ClasspathJar))
+		Expect(n.JarFilePattern).To(Equal("target/*.jar"))
+		Expect(n.Command).To(Equal("/opt/mandrel/bin/native-image"))
+		Expect(n.Compressor).To(Equal("upx"))
+		Expect(n.Executor).To(BeIdenticalTo(executor))
+		Expect(n.Manifest).To(BeIdenticalTo(manifest))
+		Expect(n.StackID).To(Equal(libpak.JammyTinyStackID))
+	})
+
+	it("uses the argument resolver", func() {
+		resolver := &recordingResolver{}
+
+		Expect(ioutil.WriteFile(filepath.Join(appPath, "test.jar"), []byte{}, 0644)).To(Succeed())
+
+		n := native.New(
+			native.WithApplicationPath(appPath),
+			native.WithArguments("--no-fallback"),
+			native.WithArgumentResolver(resolver),
+			native.WithClasspathStrategy(native.ClasspathJar, "*.jar"),
+			native.WithLogger(bard.NewLogger(io.Discard)),
+		)
+
+		arguments, _, err := n.ProcessArguments(libcnb.Layer{Path: appPath})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resolver.resolved).To(ContainElement("--no-fallback"))
+		Expect(arguments).To(ContainElement("--test-resolved"))
+		Expect(arguments).To(ContainElement("-jar"))
+	})
+
+	it("builds an exploded JAR without a manifest", func() {
+		n := native.New(
+			native.WithApplicationPath(appPath),
+			native.WithClasspathStrategy(native.ClasspathExplodedJar, ""),
+			native.WithLogger(bard.NewLogger(io.Discard)),
+			native.WithManifest(properties.NewProperties()),
+		)
+
+		_, _, err := n.ProcessArguments(libcnb.Layer{Path: appPath})
+		Expect(err).To(MatchError(ContainSubstring("unable to append exploded-jar directory arguments")))
+	})
+}
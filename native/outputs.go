@@ -0,0 +1,78 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/paketo-buildpacks/libpak/sherpa"
+)
+
+// CopyOutputs copies the files in the native image layer whose path relative to the layer matches one of the glob
+// patterns, such as *.so, into the application, keeping their relative paths. Returns the relative paths of the
+// copied files.
+func CopyOutputs(layerPath string, appPath string, patterns []string) ([]string, error) {
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+
+	var expressions []*regexp.Regexp
+	for _, p := range patterns {
+		expressions = append(expressions, regexp.MustCompile("^(?:"+ResourceGlobToRegex(p)+")$"))
+	}
+
+	var copied []string
+	if err := filepath.Walk(layerPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(layerPath, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if !matchesAny(expressions, rel) {
+			return nil
+		}
+
+		in, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("unable to open %s\n%w", path, err)
+		}
+		defer in.Close()
+
+		if err := sherpa.CopyFile(in, filepath.Join(appPath, filepath.FromSlash(rel))); err != nil {
+			return err
+		}
+
+		copied = append(copied, rel)
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("unable to copy outputs from %s\n%w", layerPath, err)
+	}
+
+	return copied, nil
+}
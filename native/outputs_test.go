@@ -0,0 +1,76 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testOutputs(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		appPath   string
+		layerPath string
+	)
+
+	it.Before(func() {
+		var err error
+
+		appPath, err = ioutil.TempDir("", "outputs-application")
+		Expect(err).NotTo(HaveOccurred())
+
+		layerPath, err = ioutil.TempDir("", "outputs-layer")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(os.MkdirAll(filepath.Join(layerPath, "lib"), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(layerPath, "test-start-class"), []byte{}, 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(layerPath, "libawt.so"), []byte("awt"), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(layerPath, "lib", "libfontmanager.so"), []byte{}, 0644)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(layerPath, "provenance.json"), []byte{}, 0644)).To(Succeed())
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(appPath)).To(Succeed())
+		Expect(os.RemoveAll(layerPath)).To(Succeed())
+	})
+
+	it("copies nothing by default", func() {
+		Expect(native.CopyOutputs(layerPath, appPath, nil)).To(BeEmpty())
+		Expect(ioutil.ReadDir(appPath)).To(BeEmpty())
+	})
+
+	it("copies matching files keeping their paths and modes", func() {
+		Expect(native.CopyOutputs(layerPath, appPath, []string{"**/*.so"})).
+			To(Equal([]string{"lib/libfontmanager.so", "libawt.so"}))
+
+		Expect(ioutil.ReadFile(filepath.Join(appPath, "libawt.so"))).To(Equal([]byte("awt")))
+		fi, err := os.Stat(filepath.Join(appPath, "libawt.so"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fi.Mode().Perm()).To(Equal(os.FileMode(0755)))
+		Expect(filepath.Join(appPath, "lib", "libfontmanager.so")).To(BeARegularFile())
+		Expect(filepath.Join(appPath, "provenance.json")).NotTo(BeAnExistingFile())
+	})
+}
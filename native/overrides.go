@@ -0,0 +1,73 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/buildpacks/libcnb"
+	"github.com/paketo-buildpacks/libpak/bindings"
+)
+
+// OverridesDirectory is the directory of hand-written native-image configuration in the application
+const OverridesDirectory = "META-INF/native-image-overrides"
+
+// ConfigurationBindingType is the type of bindings holding hand-written native-image configuration
+const ConfigurationBindingType = "native-image-configuration"
+
+// FindConfigurationOverrides returns the directories of hand-written reflect, resource, proxy, jni and serialization
+// configuration, from the application and from bindings. The application directory is looked for at the root of the
+// application and in BOOT-INF/classes.
+func FindConfigurationOverrides(appPath string, binds libcnb.Bindings) ([]string, error) {
+	var dirs []string
+
+	for _, d := range []string{
+		filepath.Join(appPath, OverridesDirectory),
+		filepath.Join(appPath, "BOOT-INF", "classes", OverridesDirectory),
+	} {
+		if fi, err := os.Stat(d); err == nil && fi.IsDir() {
+			dirs = append(dirs, d)
+		} else if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("unable to stat %s\n%w", d, err)
+		}
+	}
+
+	for _, b := range bindings.Resolve(binds, bindings.OfType(ConfigurationBindingType)) {
+		dirs = append(dirs, b.Path)
+	}
+
+	return dirs, nil
+}
+
+// ConfigurationDirectoryArguments adds directories of native-image configuration. native-image merges the
+// configuration in them with the configuration generated into META-INF/native-image on the classpath, with the same
+// semantics as native-image-configure, so hand-written fixes survive the configuration being regenerated.
+type ConfigurationDirectoryArguments struct {
+	Directories []string
+}
+
+// Configure appends -H:ConfigurationFileDirectories to inputArgs when there are directories
+func (c ConfigurationDirectoryArguments) Configure(inputArgs []string) ([]string, string, error) {
+	if len(c.Directories) > 0 {
+		inputArgs = append(inputArgs, fmt.Sprintf("-H:ConfigurationFileDirectories=%s", strings.Join(c.Directories, ",")))
+	}
+
+	return inputArgs, "", nil
+}
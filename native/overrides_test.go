@@ -0,0 +1,80 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/buildpacks/libcnb"
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testOverrides(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		appPath string
+	)
+
+	it.Before(func() {
+		var err error
+
+		appPath, err = ioutil.TempDir("", "overrides-application")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(appPath)).To(Succeed())
+	})
+
+	it("finds overrides in the application and bindings", func() {
+		Expect(os.MkdirAll(filepath.Join(appPath, "META-INF", "native-image-overrides"), 0755)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(appPath, "BOOT-INF", "classes", "META-INF", "native-image-overrides"), 0755)).To(Succeed())
+
+		dirs, err := native.FindConfigurationOverrides(appPath, libcnb.Bindings{
+			{Name: "overrides", Type: "native-image-configuration", Path: "/bindings/overrides"},
+			{Name: "other", Type: "maven", Path: "/bindings/maven"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dirs).To(Equal([]string{
+			filepath.Join(appPath, "META-INF", "native-image-overrides"),
+			filepath.Join(appPath, "BOOT-INF", "classes", "META-INF", "native-image-overrides"),
+			"/bindings/overrides",
+		}))
+	})
+
+	it("finds no overrides", func() {
+		Expect(native.FindConfigurationOverrides(appPath, nil)).To(BeEmpty())
+	})
+
+	it("adds configuration directories", func() {
+		Expect(native.ConfigurationDirectoryArguments{Directories: []string{"/a", "/b"}}.Configure([]string{"test"})).
+			To(Equal([]string{"test", "-H:ConfigurationFileDirectories=/a,/b"}))
+	})
+
+	it("does not add arguments without directories", func() {
+		args, _, err := native.ConfigurationDirectoryArguments{}.Configure([]string{"test"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(args).To(Equal([]string{"test"}))
+	})
+}
@@ -0,0 +1,35 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// Windows is the operating system name of Windows builders
+const Windows = "windows"
+
+// BinaryName returns the file name of the binary native-image writes for -H:Name=name on goos. On Windows,
+// native-image adds an .exe extension.
+func BinaryName(name string, goos string) string {
+	if goos == Windows && !strings.EqualFold(filepath.Ext(name), ".exe") {
+		return name + ".exe"
+	}
+
+	return name
+}
@@ -0,0 +1,95 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"archive/zip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testPlatform(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		appPath string
+	)
+
+	it.Before(func() {
+		var err error
+
+		appPath, err = ioutil.TempDir("", "platform-application")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(appPath)).To(Succeed())
+	})
+
+	context("BinaryName", func() {
+		it("adds an extension on Windows", func() {
+			Expect(native.BinaryName("com.example.App", "windows")).To(Equal("com.example.App.exe"))
+			Expect(native.BinaryName("app.EXE", "windows")).To(Equal("app.EXE"))
+		})
+
+		it("keeps the name on other operating systems", func() {
+			Expect(native.BinaryName("com.example.App", "linux")).To(Equal("com.example.App"))
+		})
+	})
+
+	context("NewManifest", func() {
+		it("reads a manifest with Windows line endings and continuation lines", func() {
+			Expect(os.MkdirAll(filepath.Join(appPath, "META-INF"), 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(appPath, "META-INF", "MANIFEST.MF"),
+				[]byte("Start-Class: com.example.Applic\r\n ation\r\nClass-Path: a.jar\r\n"), 0644)).To(Succeed())
+
+			m, err := native.NewManifest(appPath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(m.MustGet("Start-Class")).To(Equal("com.example.Application"))
+			Expect(m.MustGet("Class-Path")).To(Equal("a.jar"))
+		})
+
+		it("returns empty properties without a manifest", func() {
+			m, err := native.NewManifest(appPath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(m.Len()).To(BeZero())
+		})
+
+		it("reads the manifest of a JAR", func() {
+			out, err := os.Create(filepath.Join(appPath, "app.jar"))
+			Expect(err).NotTo(HaveOccurred())
+			z := zip.NewWriter(out)
+			w, err := z.Create("META-INF/MANIFEST.MF")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = w.Write([]byte("Main-Class: com.example.Main\n"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(z.Close()).To(Succeed())
+			Expect(out.Close()).To(Succeed())
+
+			m, err := native.NewManifestFromJAR(filepath.Join(appPath, "app.jar"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(m.MustGet("Main-Class")).To(Equal("com.example.Main"))
+		})
+	})
+}
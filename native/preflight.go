@@ -0,0 +1,92 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/magiconair/properties"
+	"github.com/paketo-buildpacks/libpak/bard"
+)
+
+// springBootPathAttributes are the manifest attributes of Spring Boot holding paths in the application
+var springBootPathAttributes = []string{
+	"Spring-Boot-Classes",
+	"Spring-Boot-Lib",
+	"Spring-Boot-Classpath-Index",
+	"Spring-Boot-Layers-Index",
+}
+
+// Preflight checks an application before it is compiled
+type Preflight struct {
+	ApplicationPath string
+	Classpath       []string
+	Logger          bard.Logger
+
+	// Manifest is the manifest of an exploded JAR, whose Spring Boot attributes must point at paths in the application.
+	// Nil skips the check.
+	Manifest *properties.Properties
+
+	// StartClass is the class that must be on the classpath. Empty skips the check.
+	StartClass string
+
+	// Warnings collects the classpath entries that do not exist, which native-image ignores like the JVM does
+	Warnings *Warnings
+}
+
+// Check returns a PreflightError listing all problems found: Spring Boot manifest attributes pointing at missing paths
+// and a start class that is not on the classpath. Missing classpath entries, such as stale $CLASSPATH entries or
+// optional Class-Path entries of thin JARs, are only warned about.
+func (p Preflight) Check() error {
+	var problems []string
+
+	if p.Manifest != nil {
+		for _, a := range springBootPathAttributes {
+			if v, ok := p.Manifest.Get(a); ok {
+				if _, err := os.Stat(filepath.Join(p.ApplicationPath, v)); os.IsNotExist(err) {
+					problems = append(problems, fmt.Sprintf("manifest attribute %s points at %s, which does not exist", a, v))
+				} else if err != nil {
+					return fmt.Errorf("unable to stat %s\n%w", v, err)
+				}
+			}
+		}
+	}
+
+	for _, entry := range p.Classpath {
+		if _, err := os.Stat(entry); os.IsNotExist(err) {
+			p.Warnings.Warn(p.Logger, fmt.Sprintf("Classpath entry %s does not exist and is ignored", entry))
+		} else if err != nil {
+			return fmt.Errorf("unable to stat %s\n%w", entry, err)
+		}
+	}
+
+	if p.StartClass != "" {
+		if err := CheckStartClass(p.Classpath, p.StartClass); errors.As(err, &StartClassNotFoundError{}) {
+			problems = append(problems, err.Error())
+		} else if err != nil {
+			return err
+		}
+	}
+
+	if len(problems) > 0 {
+		return PreflightError{Problems: problems}
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package native_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/magiconair/properties"
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testPreflight(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		appPath  string
+		manifest *properties.Properties
+	)
+
+	it.Before(func() {
+		var err error
+		appPath, err = ioutil.TempDir("", "preflight")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(os.MkdirAll(filepath.Join(appPath, "BOOT-INF", "classes", "com", "example"), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(appPath, "BOOT-INF", "classes", "com", "example", "App.class"), []byte{}, 0644)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(appPath, "BOOT-INF", "lib"), 0755)).To(Succeed())
+
+		manifest = properties.NewProperties()
+		_, _, err = manifest.Set("Spring-Boot-Classes", "BOOT-INF/classes/")
+		Expect(err).NotTo(HaveOccurred())
+		_, _, err = manifest.Set("Spring-Boot-Lib", "BOOT-INF/lib/")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(appPath)).To(Succeed())
+	})
+
+	it("passes a consistent application", func() {
+		Expect(native.Preflight{
+			ApplicationPath: appPath,
+			Classpath:       []string{filepath.Join(appPath, "BOOT-INF", "classes")},
+			Manifest:        manifest,
+			StartClass:      "com.example.App",
+		}.Check()).To(Succeed())
+	})
+
+	it("reports all problems", func() {
+		_, _, err := manifest.Set("Spring-Boot-Classpath-Index", "BOOT-INF/classpath.idx")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(native.Preflight{
+			ApplicationPath: appPath,
+			Classpath:       []string{filepath.Join(appPath, "BOOT-INF", "classes")},
+			Manifest:        manifest,
+			StartClass:      "com.example.Ap",
+		}.Check()).To(MatchError(native.PreflightError{Problems: []string{
+			"manifest attribute Spring-Boot-Classpath-Index points at BOOT-INF/classpath.idx, which does not exist",
+			"start class com.example.Ap is not on the classpath, check the Start-Class of the manifest or $BP_NATIVE_IMAGE_START_CLASS",
+		}}))
+	})
+
+	it("warns about missing classpath entries", func() {
+		warnings := &native.Warnings{}
+
+		Expect(native.Preflight{
+			ApplicationPath: appPath,
+			Classpath:       []string{filepath.Join(appPath, "BOOT-INF", "classes"), filepath.Join(appPath, "BOOT-INF", "lib", "missing.jar")},
+			Manifest:        manifest,
+			StartClass:      "com.example.App",
+			Warnings:        warnings,
+		}.Check()).To(Succeed())
+
+		Expect(warnings.Messages).To(Equal([]string{
+			"Classpath entry " + filepath.Join(appPath, "BOOT-INF", "lib", "missing.jar") + " does not exist and is ignored",
+		}))
+	})
+
+	it("skips the manifest and start class when not set", func() {
+		Expect(native.Preflight{ApplicationPath: appPath, Classpath: []string{appPath}}.Check()).To(Succeed())
+	})
+
+	it("describes the problems", func() {
+		Expect(native.PreflightError{Problems: []string{"first", "second"}}).
+			To(MatchError("unable to build the native image:\n  * first\n  * second"))
+	})
+}
@@ -0,0 +1,121 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// DefaultStaticContent are the locations Spring MVC serves static content from, preserved by default so that
+// applications serving these files from disk keep working
+var DefaultStaticContent = []string{
+	"BOOT-INF/classes/static/**",
+	"BOOT-INF/classes/public/**",
+	"BOOT-INF/classes/META-INF/resources/**",
+	"META-INF/resources/**",
+}
+
+// ParseStaticContentPatterns parses the static content to preserve, either true for DefaultStaticContent, false or a
+// comma separated list of glob patterns replacing the defaults
+func ParseStaticContentPatterns(value string) []string {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "true":
+		return append([]string{}, DefaultStaticContent...)
+	case "false":
+		return nil
+	}
+
+	return ParseResourcePatterns(value)
+}
+
+// ParsePreservePatterns parses the application contents to preserve, either true to preserve everything, false or a
+// comma separated list of glob patterns such as static/**,templates/**
+func ParsePreservePatterns(value string) []string {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "false":
+		return nil
+	case "true":
+		return []string{"**"}
+	}
+
+	return ParseResourcePatterns(value)
+}
+
+// RemoveApplication removes the contents of the application, except for the files and directories whose path
+// relative to the application matches one of the preserve glob patterns. Directories left empty are removed.
+func RemoveApplication(appPath string, preserve []string) error {
+	var patterns []*regexp.Regexp
+	for _, p := range preserve {
+		patterns = append(patterns, regexp.MustCompile("^(?:"+ResourceGlobToRegex(p)+")$"))
+	}
+
+	_, err := removeExcept(appPath, "", patterns)
+	return err
+}
+
+// removeExcept removes the children of dir that do not match patterns and returns whether any child was kept
+func removeExcept(dir string, rel string, patterns []*regexp.Regexp) (bool, error) {
+	cs, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return false, fmt.Errorf("unable to list children of %s\n%w", dir, err)
+	}
+
+	kept := false
+	for _, c := range cs {
+		file, name := filepath.Join(dir, c.Name()), c.Name()
+		if rel != "" {
+			name = rel + "/" + c.Name()
+		}
+
+		if matchesAny(patterns, name) {
+			kept = true
+			continue
+		}
+
+		if c.IsDir() && len(patterns) > 0 {
+			keptChild, err := removeExcept(file, name, patterns)
+			if err != nil {
+				return false, err
+			}
+			if keptChild {
+				kept = true
+				continue
+			}
+		}
+
+		if err := os.RemoveAll(file); err != nil {
+			return false, fmt.Errorf("unable to remove %s\n%w", file, err)
+		}
+	}
+
+	return kept, nil
+}
+
+func matchesAny(patterns []*regexp.Regexp, name string) bool {
+	for _, p := range patterns {
+		if p.MatchString(name) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,109 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testPreserve(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		appPath string
+	)
+
+	it.Before(func() {
+		var err error
+
+		appPath, err = ioutil.TempDir("", "preserve-application")
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, f := range []string{
+			"BOOT-INF/classes/com/example/App.class",
+			"BOOT-INF/classes/static/index.html",
+			"BOOT-INF/lib/spring-boot-3.1.0.jar",
+			"static/css/site.css",
+			"templates/index.html",
+			"application.yml",
+		} {
+			Expect(os.MkdirAll(filepath.Dir(filepath.Join(appPath, f)), 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(appPath, f), []byte{}, 0644)).To(Succeed())
+		}
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(appPath)).To(Succeed())
+	})
+
+	it("parses patterns", func() {
+		Expect(native.ParsePreservePatterns("")).To(BeEmpty())
+		Expect(native.ParsePreservePatterns("false")).To(BeEmpty())
+		Expect(native.ParsePreservePatterns("TRUE")).To(Equal([]string{"**"}))
+		Expect(native.ParsePreservePatterns("static/**, templates")).To(Equal([]string{"static/**", "templates"}))
+	})
+
+	it("parses static content patterns", func() {
+		Expect(native.ParseStaticContentPatterns("")).To(Equal(native.DefaultStaticContent))
+		Expect(native.ParseStaticContentPatterns("true")).To(Equal(native.DefaultStaticContent))
+		Expect(native.ParseStaticContentPatterns("false")).To(BeEmpty())
+		Expect(native.ParseStaticContentPatterns("BOOT-INF/classes/assets/**")).To(Equal([]string{"BOOT-INF/classes/assets/**"}))
+	})
+
+	it("preserves static content", func() {
+		Expect(os.MkdirAll(filepath.Join(appPath, "BOOT-INF", "classes", "static", "css"), 0755)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(appPath, "BOOT-INF", "classes", "com"), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(appPath, "BOOT-INF", "classes", "static", "css", "site.css"), []byte{}, 0644)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(appPath, "BOOT-INF", "classes", "com", "Application.class"), []byte{}, 0644)).To(Succeed())
+
+		Expect(native.RemoveApplication(appPath, native.DefaultStaticContent)).To(Succeed())
+
+		Expect(filepath.Join(appPath, "BOOT-INF", "classes", "static", "css", "site.css")).To(BeARegularFile())
+		Expect(filepath.Join(appPath, "BOOT-INF", "classes", "com")).NotTo(BeADirectory())
+	})
+
+	it("removes everything", func() {
+		Expect(native.RemoveApplication(appPath, nil)).To(Succeed())
+
+		Expect(ioutil.ReadDir(appPath)).To(BeEmpty())
+	})
+
+	it("preserves matching files and directories", func() {
+		Expect(native.RemoveApplication(appPath, []string{"**/static/**", "templates", "*.yml"})).To(Succeed())
+
+		Expect(filepath.Join(appPath, "BOOT-INF", "classes", "static", "index.html")).To(BeARegularFile())
+		Expect(filepath.Join(appPath, "static", "css", "site.css")).To(BeARegularFile())
+		Expect(filepath.Join(appPath, "templates", "index.html")).To(BeARegularFile())
+		Expect(filepath.Join(appPath, "application.yml")).To(BeARegularFile())
+		Expect(filepath.Join(appPath, "BOOT-INF", "classes", "com")).NotTo(BeAnExistingFile())
+		Expect(filepath.Join(appPath, "BOOT-INF", "lib")).NotTo(BeAnExistingFile())
+	})
+
+	it("preserves everything", func() {
+		Expect(native.RemoveApplication(appPath, []string{"**"})).To(Succeed())
+
+		Expect(filepath.Join(appPath, "BOOT-INF", "lib", "spring-boot-3.1.0.jar")).To(BeARegularFile())
+	})
+}
@@ -0,0 +1,74 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mattn/go-shellwords"
+)
+
+// ProcessType is an additional process type running the native image with its own arguments
+type ProcessType struct {
+	Type      string
+	Arguments []string
+}
+
+// ParseProcessTypes parses a semicolon separated list of process types, each optionally followed by = and the
+// arguments of the native image, e.g. worker=--spring.profiles.active=worker;batch
+func ParseProcessTypes(value string) ([]ProcessType, error) {
+	var types []ProcessType
+
+	for _, entry := range strings.Split(value, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.SplitN(entry, "=", 2)
+		t := ProcessType{Type: strings.TrimSpace(parts[0])}
+		if !processTypePattern.MatchString(t.Type) {
+			return nil, fmt.Errorf("invalid process type %q, expected type or type=arguments", entry)
+		}
+
+		if len(parts) == 2 {
+			args, err := ParseLaunchArguments(parts[1])
+			if err != nil {
+				return nil, err
+			}
+			t.Arguments = args
+		}
+
+		types = append(types, t)
+	}
+
+	return types, nil
+}
+
+// ParseLaunchArguments parses the arguments passed to the native image when it is launched
+func ParseLaunchArguments(value string) ([]string, error) {
+	args, err := shellwords.Parse(value)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse arguments from %s\n%w", value, err)
+	}
+
+	if len(args) == 0 {
+		return nil, nil
+	}
+	return args, nil
+}
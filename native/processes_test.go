@@ -0,0 +1,49 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testProcesses(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+	)
+
+	it("parses process types with and without arguments", func() {
+		Expect(native.ParseProcessTypes(` worker=--spring.profiles.active=worker --name="a b"; batch ;`)).To(Equal([]native.ProcessType{
+			{Type: "worker", Arguments: []string{"--spring.profiles.active=worker", "--name=a b"}},
+			{Type: "batch"},
+		}))
+	})
+
+	it("fails on invalid process types", func() {
+		_, err := native.ParseProcessTypes("my worker")
+		Expect(err).To(MatchError(`invalid process type "my worker", expected type or type=arguments`))
+	})
+
+	it("parses launch arguments", func() {
+		Expect(native.ParseLaunchArguments("--server.port=8081 -Dx='y z'")).To(Equal([]string{"--server.port=8081", "-Dx=y z"}))
+		Expect(native.ParseLaunchArguments("")).To(BeNil())
+	})
+}
@@ -0,0 +1,133 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/buildpacks/libcnb"
+	"github.com/mattn/go-shellwords"
+	"github.com/paketo-buildpacks/libpak/bard"
+)
+
+// ProcfileName is the name of the Procfile in the application
+const ProcfileName = "Procfile"
+
+// ProcfileEntry is a process type and its command in a Procfile
+type ProcfileEntry struct {
+	Type    string
+	Command string
+}
+
+// ReadProcfile returns the entries of the Procfile in the application, in order. Returns nil if the application does
+// not have a Procfile.
+func ReadProcfile(appPath string) ([]ProcfileEntry, error) {
+	file := filepath.Join(appPath, ProcfileName)
+	in, err := os.Open(file)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("unable to open %s\n%w", file, err)
+	}
+	defer in.Close()
+
+	var entries []ProcfileEntry
+	s := bufio.NewScanner(in)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 || !processTypePattern.MatchString(strings.TrimSpace(parts[0])) {
+			return nil, fmt.Errorf("invalid Procfile entry %q, expected type: command", line)
+		}
+
+		entries = append(entries, ProcfileEntry{Type: strings.TrimSpace(parts[0]), Command: strings.TrimSpace(parts[1])})
+	}
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read %s\n%w", file, err)
+	}
+
+	return entries, nil
+}
+
+// ProcfileProcess translates a Procfile entry into a process. A java invocation is replaced by the native binary,
+// keeping the system properties and heap and stack sizes, which native images accept at runtime, and the arguments
+// of the application. Other commands are kept as they are. Commands referring to environment variables are run by a
+// shell, which the image must then provide.
+func ProcfileProcess(entry ProcfileEntry, binary string, logger bard.Logger) (libcnb.Process, error) {
+	p := shellwords.NewParser()
+	tokens, err := p.Parse(entry.Command)
+	if err != nil {
+		return libcnb.Process{}, fmt.Errorf("unable to parse Procfile command %s\n%w", entry.Command, err)
+	}
+
+	if len(tokens) == 0 || (tokens[0] != "java" && !strings.HasSuffix(tokens[0], "/java")) {
+		return libcnb.Process{Type: entry.Type, Command: entry.Command}, nil
+	}
+
+	var arguments []string
+	for i := 1; i < len(tokens); i++ {
+		t := tokens[i]
+
+		// the main class or JAR ends the options of the JVM, everything following is for the application
+		if t == "-jar" {
+			i++
+		}
+		if t == "-jar" || !strings.HasPrefix(t, "-") {
+			if i+1 < len(tokens) {
+				arguments = append(arguments, tokens[i+1:]...)
+			}
+			break
+		}
+
+		switch {
+		case t == "-cp" || t == "-classpath" || t == "--class-path":
+			i++
+		case strings.HasPrefix(t, "-D"), strings.HasPrefix(t, "-Xmx"), strings.HasPrefix(t, "-Xms"), strings.HasPrefix(t, "-Xss"):
+			arguments = append(arguments, t)
+		default:
+			logger.Bodyf("Ignoring JVM option %s of Procfile process type %s", t, entry.Type)
+		}
+	}
+
+	for _, a := range arguments {
+		if strings.Contains(a, "$") {
+			return libcnb.Process{Type: entry.Type, Command: strings.Join(append([]string{binary}, quote(arguments)...), " ")}, nil
+		}
+	}
+
+	return libcnb.Process{Type: entry.Type, Command: binary, Arguments: arguments, Direct: true}, nil
+}
+
+// quote quotes arguments containing whitespace for a shell, leaving environment variable references expandable
+func quote(arguments []string) []string {
+	var quoted []string
+	for _, a := range arguments {
+		if strings.ContainsAny(a, " \t") {
+			a = `"` + strings.ReplaceAll(a, `"`, `\"`) + `"`
+		}
+		quoted = append(quoted, a)
+	}
+	return quoted
+}
@@ -0,0 +1,120 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/buildpacks/libcnb"
+	. "github.com/onsi/gomega"
+	"github.com/paketo-buildpacks/libpak/bard"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testProcfile(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		appPath string
+		log     *bytes.Buffer
+		logger  bard.Logger
+	)
+
+	it.Before(func() {
+		var err error
+
+		appPath, err = ioutil.TempDir("", "procfile-application")
+		Expect(err).NotTo(HaveOccurred())
+
+		log = &bytes.Buffer{}
+		logger = bard.NewLogger(log)
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(appPath)).To(Succeed())
+	})
+
+	it("reads Procfile entries in order", func() {
+		Expect(ioutil.WriteFile(filepath.Join(appPath, "Procfile"), []byte(`
+# comment
+web: java -jar app.jar
+worker:  java -cp app.jar com.example.Worker
+`), 0644)).To(Succeed())
+
+		Expect(native.ReadProcfile(appPath)).To(Equal([]native.ProcfileEntry{
+			{Type: "web", Command: "java -jar app.jar"},
+			{Type: "worker", Command: "java -cp app.jar com.example.Worker"},
+		}))
+	})
+
+	it("reads no entries without a Procfile", func() {
+		Expect(native.ReadProcfile(appPath)).To(BeNil())
+	})
+
+	it("fails on invalid entries", func() {
+		Expect(ioutil.WriteFile(filepath.Join(appPath, "Procfile"), []byte("java -jar app.jar\n"), 0644)).To(Succeed())
+
+		_, err := native.ReadProcfile(appPath)
+		Expect(err).To(MatchError(ContainSubstring("invalid Procfile entry")))
+	})
+
+	it("replaces java -jar with the binary", func() {
+		Expect(native.ProcfileProcess(native.ProcfileEntry{
+			Type:    "web",
+			Command: "java -Xmx512m -XX:+UseG1GC -Dspring.profiles.active=prod -jar target/app.jar --server.port=8081",
+		}, "/workspace/app", logger)).To(Equal(libcnb.Process{
+			Type:      "web",
+			Command:   "/workspace/app",
+			Arguments: []string{"-Xmx512m", "-Dspring.profiles.active=prod", "--server.port=8081"},
+			Direct:    true,
+		}))
+		Expect(log.String()).To(ContainSubstring("Ignoring JVM option -XX:+UseG1GC of Procfile process type web"))
+	})
+
+	it("replaces java with a main class with the binary", func() {
+		Expect(native.ProcfileProcess(native.ProcfileEntry{
+			Type:    "worker",
+			Command: "/usr/bin/java -cp app.jar:lib/* com.example.Worker --queue jobs",
+		}, "/workspace/app", logger)).To(Equal(libcnb.Process{
+			Type:      "worker",
+			Command:   "/workspace/app",
+			Arguments: []string{"--queue", "jobs"},
+			Direct:    true,
+		}))
+	})
+
+	it("runs commands referring to environment variables with a shell", func() {
+		Expect(native.ProcfileProcess(native.ProcfileEntry{
+			Type:    "web",
+			Command: `java -jar app.jar --server.port=$PORT --name="a b"`,
+		}, "/workspace/app", logger)).To(Equal(libcnb.Process{
+			Type:    "web",
+			Command: `/workspace/app --server.port=$PORT "--name=a b"`,
+		}))
+	})
+
+	it("keeps other commands", func() {
+		Expect(native.ProcfileProcess(native.ProcfileEntry{Type: "release", Command: "./migrate.sh --all"}, "/workspace/app", logger)).
+			To(Equal(libcnb.Process{Type: "release", Command: "./migrate.sh --all"}))
+	})
+}
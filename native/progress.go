@@ -0,0 +1,121 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/paketo-buildpacks/libpak/bard"
+)
+
+// phasePattern matches a native-image progress line, e.g. `[2/8] Performing analysis...  [****]  (20.1s @ 1.20GB)`
+var phasePattern = regexp.MustCompile(`^\[(\d+)/(\d+)\]\s+(.+?)\.\.\.(?:.*\((\d+(?:\.\d+)?)s @ [\d.]+GB\))?`)
+
+// Phase is a single step of a native-image build
+type Phase struct {
+	Index    int
+	Total    int
+	Name     string
+	Duration time.Duration
+}
+
+// PhaseWriter parses native-image output line by line, logging progress phases with their elapsed time and passing
+// all other lines through to the body of the logger.
+type PhaseWriter struct {
+	Logger bard.Logger
+	Phases []Phase
+
+	buf  bytes.Buffer
+	last time.Time
+	now  func() time.Time
+}
+
+// NewPhaseWriter creates a PhaseWriter that starts timing immediately
+func NewPhaseWriter(logger bard.Logger) *PhaseWriter {
+	return &PhaseWriter{Logger: logger, last: time.Now(), now: time.Now}
+}
+
+func (p *PhaseWriter) Write(b []byte) (int, error) {
+	p.buf.Write(b)
+
+	for {
+		i := bytes.IndexByte(p.buf.Bytes(), '\n')
+		if i < 0 {
+			break
+		}
+		line := string(p.buf.Next(i + 1))
+		p.line(strings.TrimRight(line, "\r\n"))
+	}
+
+	return len(b), nil
+}
+
+// Flush processes any output that was not terminated by a newline
+func (p *PhaseWriter) Flush() {
+	if p.buf.Len() > 0 {
+		p.line(p.buf.String())
+		p.buf.Reset()
+	}
+}
+
+// Summary logs a table of all phases seen and the total time spent in them
+func (p *PhaseWriter) Summary() {
+	if len(p.Phases) == 0 {
+		return
+	}
+
+	width := 0
+	for _, ph := range p.Phases {
+		if len(ph.Name) > width {
+			width = len(ph.Name)
+		}
+	}
+
+	var total time.Duration
+	p.Logger.Header("Native Image phases:")
+	for _, ph := range p.Phases {
+		total += ph.Duration
+		p.Logger.Bodyf("[%d/%d] %-*s %8s", ph.Index, ph.Total, width, ph.Name, ph.Duration.Round(100*time.Millisecond))
+	}
+	p.Logger.Bodyf("      %-*s %8s", width, "Total", total.Round(100*time.Millisecond))
+}
+
+func (p *PhaseWriter) line(line string) {
+	m := phasePattern.FindStringSubmatch(line)
+	if m == nil {
+		if strings.TrimSpace(line) != "" {
+			p.Logger.Body(line)
+		}
+		return
+	}
+
+	now := p.now()
+	ph := Phase{Name: m[3], Duration: now.Sub(p.last)}
+	ph.Index, _ = strconv.Atoi(m[1])
+	ph.Total, _ = strconv.Atoi(m[2])
+	if s, err := strconv.ParseFloat(m[4], 64); err == nil {
+		ph.Duration = time.Duration(s * float64(time.Second))
+	}
+	p.last = now
+
+	p.Phases = append(p.Phases, ph)
+	p.Logger.Bodyf("[%d/%d] %s (%s)", ph.Index, ph.Total, ph.Name, ph.Duration.Round(100*time.Millisecond))
+}
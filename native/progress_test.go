@@ -0,0 +1,79 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/paketo-buildpacks/libpak/bard"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testProgress(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		out    bytes.Buffer
+		writer *native.PhaseWriter
+	)
+
+	it.Before(func() {
+		out.Reset()
+		writer = native.NewPhaseWriter(bard.NewLogger(&out))
+	})
+
+	it("parses phases split across writes", func() {
+		_, err := writer.Write([]byte("GraalVM Native Image: Generating 'app' (executable)...\n[1/7] Initializing...   "))
+		Expect(err).NotTo(HaveOccurred())
+		_, err = writer.Write([]byte("  (3.5s @ 0.20GB)\n Version info: 'GraalVM 22.3.0 Java 17 CE'\n"))
+		Expect(err).NotTo(HaveOccurred())
+		_, err = writer.Write([]byte("[2/7] Performing analysis...  [*******]    (20.1s @ 1.20GB)\n"))
+		Expect(err).NotTo(HaveOccurred())
+		writer.Flush()
+
+		Expect(writer.Phases).To(Equal([]native.Phase{
+			{Index: 1, Total: 7, Name: "Initializing", Duration: 3500 * time.Millisecond},
+			{Index: 2, Total: 7, Name: "Performing analysis", Duration: 20100 * time.Millisecond},
+		}))
+		Expect(out.String()).To(ContainSubstring("[1/7] Initializing (3.5s)"))
+		Expect(out.String()).To(ContainSubstring("Version info: 'GraalVM 22.3.0 Java 17 CE'"))
+	})
+
+	it("prints a summary table", func() {
+		_, err := writer.Write([]byte("[1/2] Initializing... (1.0s @ 0.20GB)\n[2/2] Creating image... (2.0s @ 0.20GB)"))
+		Expect(err).NotTo(HaveOccurred())
+		writer.Flush()
+		writer.Summary()
+
+		Expect(writer.Phases).To(HaveLen(2))
+		Expect(out.String()).To(ContainSubstring("Native Image phases:"))
+		Expect(out.String()).To(MatchRegexp(`Total\s+3s`))
+	})
+
+	it("does not print a summary without phases", func() {
+		_, err := writer.Write([]byte("some other output\n"))
+		Expect(err).NotTo(HaveOccurred())
+		writer.Summary()
+
+		Expect(out.String()).NotTo(ContainSubstring("Native Image phases:"))
+	})
+}
@@ -0,0 +1,59 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mattn/go-shellwords"
+)
+
+// ParseSystemProperties parses whitespace separated key=value pairs, quoted like shell words when values contain
+// whitespace, e.g. spring.profiles.active=prod,cloud spring.native.remove-yaml-support=true
+func ParseSystemProperties(value string) ([]string, error) {
+	pairs, err := shellwords.Parse(value)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse system properties from %s\n%w", value, err)
+	}
+
+	var properties []string
+	for _, p := range pairs {
+		p = strings.TrimPrefix(p, "-D")
+		if strings.SplitN(p, "=", 2)[0] == "" {
+			return nil, fmt.Errorf("invalid system property %q, expected key=value", p)
+		}
+		properties = append(properties, p)
+	}
+
+	return properties, nil
+}
+
+// SystemPropertyArguments sets system properties at image build time, which is when frameworks such as Spring read
+// properties like spring.profiles.active to decide what is compiled into the image
+type SystemPropertyArguments struct {
+	Properties []string
+}
+
+// Configure appends a -D argument for each system property to inputArgs
+func (s SystemPropertyArguments) Configure(inputArgs []string) ([]string, string, error) {
+	for _, p := range s.Properties {
+		inputArgs = append(inputArgs, "-D"+p)
+	}
+
+	return inputArgs, "", nil
+}
@@ -0,0 +1,47 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testSystemProperties(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+	)
+
+	it("parses system properties", func() {
+		Expect(native.ParseSystemProperties(`-Da=1 b="two words" c=`)).To(Equal([]string{"a=1", "b=two words", "c="}))
+		Expect(native.ParseSystemProperties("")).To(BeEmpty())
+	})
+
+	it("fails on a missing key", func() {
+		_, err := native.ParseSystemProperties("=1")
+		Expect(err).To(MatchError(`invalid system property "=1", expected key=value`))
+	})
+
+	it("adds system property arguments", func() {
+		Expect(native.SystemPropertyArguments{Properties: []string{"spring.profiles.active=prod", "b=two words"}}.Configure([]string{"test"})).
+			To(Equal([]string{"test", "-Dspring.profiles.active=prod", "-Db=two words"}))
+	})
+}
@@ -0,0 +1,63 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultWebURLProtocols are the URL protocols enabled when a Spring web framework is on the classpath
+var DefaultWebURLProtocols = []string{"https"}
+
+// springWebCoordinates are the coordinates of the Spring web frameworks, whose clients commonly call HTTPS endpoints
+var springWebCoordinates = []string{
+	"org.springframework:spring-web",
+	"org.springframework:spring-webflux",
+}
+
+// FindSpringWeb returns the first Spring web framework artifact in the dependencies of the classpath
+func FindSpringWeb(dependencies Dependencies) (Artifact, bool) {
+	return dependencies.Find(springWebCoordinates...)
+}
+
+// ParseURLProtocols parses a comma separated list of URL protocols
+func ParseURLProtocols(value string) []string {
+	var protocols []string
+
+	for _, p := range strings.Split(value, ",") {
+		if p = strings.ToLower(strings.TrimSpace(p)); p != "" {
+			protocols = append(protocols, p)
+		}
+	}
+
+	return protocols
+}
+
+// URLProtocolArguments enables URL protocols, which native-image otherwise leaves out of the image
+type URLProtocolArguments struct {
+	Protocols []string
+}
+
+// Configure appends --enable-url-protocols to inputArgs when there are protocols
+func (u URLProtocolArguments) Configure(inputArgs []string) ([]string, string, error) {
+	if len(u.Protocols) > 0 {
+		inputArgs = append(inputArgs, fmt.Sprintf("--enable-url-protocols=%s", strings.Join(u.Protocols, ",")))
+	}
+
+	return inputArgs, "", nil
+}
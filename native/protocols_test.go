@@ -0,0 +1,66 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testProtocols(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+	)
+
+	it("finds Spring web frameworks", func() {
+		dependencies, err := native.DetectDependencies(native.NewDependencyDetector(), "/workspace", []string{
+			"BOOT-INF/lib/spring-core-6.0.9.jar",
+			"BOOT-INF/lib/spring-webflux-6.0.9.jar",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		a, ok := native.FindSpringWeb(dependencies)
+		Expect(ok).To(BeTrue())
+		Expect(a.ArtifactID).To(Equal("spring-webflux"))
+	})
+
+	it("does not find other artifacts", func() {
+		dependencies, err := native.DetectDependencies(native.NewDependencyDetector(), "/workspace", []string{
+			"BOOT-INF/lib/spring-webmvc-extras-1.0.0.jar",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		_, ok := native.FindSpringWeb(dependencies)
+		Expect(ok).To(BeFalse())
+	})
+
+	it("parses protocols", func() {
+		Expect(native.ParseURLProtocols(" HTTPS,http, ")).To(Equal([]string{"https", "http"}))
+		Expect(native.ParseURLProtocols("")).To(BeEmpty())
+	})
+
+	it("enables protocols", func() {
+		Expect(native.URLProtocolArguments{Protocols: []string{"http", "https"}}.Configure([]string{"test"})).
+			To(Equal([]string{"test", "--enable-url-protocols=http,https"}))
+
+		args, _, err := native.URLProtocolArguments{}.Configure([]string{"test"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(args).To(Equal([]string{"test"}))
+	})
+}
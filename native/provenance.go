@@ -0,0 +1,143 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/paketo-buildpacks/libpak/sherpa"
+)
+
+// ArgumentsRecord is the name of the file in the native image layer holding the redacted native-image arguments
+const ArgumentsRecord = "native-image-arguments.txt"
+
+// Redacted replaces the value of arguments that look like they hold a secret
+const Redacted = "[REDACTED]"
+
+// secretPattern matches the names of options and properties that are likely to hold a secret
+var secretPattern = regexp.MustCompile(`(?i)(password|passwd|secret|token|credential|api[-_.]?key|private[-_.]?key)`)
+
+// RedactArguments returns a copy of native-image arguments with the values of secret options and properties, such as
+// -Dspring.datasource.password=..., replaced
+func RedactArguments(arguments []string) []string {
+	redacted := make([]string, len(arguments))
+
+	for i, a := range arguments {
+		if parts := strings.SplitN(a, "=", 2); len(parts) == 2 && secretPattern.MatchString(parts[0]) {
+			a = parts[0] + "=" + Redacted
+		}
+		redacted[i] = a
+	}
+
+	return redacted
+}
+
+// ProvenanceFile is the name of the file in the native image layer holding the provenance statement of the binary
+const ProvenanceFile = "provenance.json"
+
+// ProvenanceMetadataKey is the key of the binary checksum and provenance reference in the native image layer metadata
+const ProvenanceMetadataKey = "provenance"
+
+const (
+	StatementType       = "https://in-toto.io/Statement/v0.1"
+	ProvenancePredicate = "https://slsa.dev/provenance/v0.2"
+	ProvenanceBuildType = "https://paketo.io/native-image/build@v1"
+	ApplicationMaterial = "application"
+	NativeImageMaterial = "native-image"
+)
+
+// Statement is an in-toto statement with a SLSA provenance predicate
+type Statement struct {
+	Type          string     `json:"_type"`
+	Subject       []Subject  `json:"subject"`
+	PredicateType string     `json:"predicateType"`
+	Predicate     Provenance `json:"predicate"`
+}
+
+// Subject is an artifact the statement is about
+type Subject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+// Provenance describes how a binary was produced
+type Provenance struct {
+	Builder    Builder    `json:"builder"`
+	BuildType  string     `json:"buildType"`
+	Invocation Invocation `json:"invocation"`
+	Materials  []Subject  `json:"materials"`
+}
+
+// Builder identifies the buildpack that produced a binary
+type Builder struct {
+	ID string `json:"id"`
+}
+
+// Invocation holds the parameters native-image was invoked with
+type Invocation struct {
+	Parameters map[string]interface{} `json:"parameters"`
+}
+
+// NewStatement creates the provenance statement of a binary. The arguments are redacted before they are recorded.
+func NewStatement(binary string, binaryDigest string, builder string, arguments []string, inputsDigest string, versionDigest string) Statement {
+	return Statement{
+		Type:          StatementType,
+		Subject:       []Subject{{Name: binary, Digest: map[string]string{"sha256": binaryDigest}}},
+		PredicateType: ProvenancePredicate,
+		Predicate: Provenance{
+			Builder:    Builder{ID: builder},
+			BuildType:  ProvenanceBuildType,
+			Invocation: Invocation{Parameters: map[string]interface{}{"arguments": RedactArguments(arguments)}},
+			Materials: []Subject{
+				{Name: ApplicationMaterial, Digest: map[string]string{"sha256": inputsDigest}},
+				{Name: NativeImageMaterial, Digest: map[string]string{"sha256": versionDigest}},
+			},
+		},
+	}
+}
+
+// InputsDigest returns the SHA-256 digest of the listing of the application files a binary is built from
+func InputsDigest(files []sherpa.FileEntry) string {
+	h := sha256.New()
+	for _, f := range files {
+		_, _ = fmt.Fprintf(h, "%s %s %s\n", f.Path, f.Mode, f.SHA256)
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// FileDigest returns the SHA-256 digest of a file
+func FileDigest(file string) (string, error) {
+	in, err := os.Open(file)
+	if err != nil {
+		return "", fmt.Errorf("unable to open %s\n%w", file, err)
+	}
+	defer in.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, in); err != nil {
+		return "", fmt.Errorf("unable to read %s\n%w", file, err)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
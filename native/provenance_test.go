@@ -0,0 +1,84 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/paketo-buildpacks/libpak/sherpa"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testProvenance(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+	)
+
+	it("redacts secret values", func() {
+		Expect(native.RedactArguments([]string{
+			"-Dspring.datasource.password=hunter2",
+			"-J-Dgithub.token=abc",
+			"--initialize-at-build-time=com.example.SecretHolder",
+			"-H:Name=/layers/native-image/app",
+			"-cp",
+		})).To(Equal([]string{
+			"-Dspring.datasource.password=[REDACTED]",
+			"-J-Dgithub.token=[REDACTED]",
+			"--initialize-at-build-time=com.example.SecretHolder",
+			"-H:Name=/layers/native-image/app",
+			"-cp",
+		}))
+	})
+
+	it("does not modify its input", func() {
+		in := []string{"-Dapi.key=abc"}
+		Expect(native.RedactArguments(in)).To(Equal([]string{"-Dapi.key=[REDACTED]"}))
+		Expect(in).To(Equal([]string{"-Dapi.key=abc"}))
+	})
+
+	it("creates a provenance statement", func() {
+		s := native.NewStatement("app", "binary-digest", "test-id@1.0.0", []string{"-Dtoken=abc"}, "inputs-digest", "version-digest")
+
+		Expect(s.Type).To(Equal("https://in-toto.io/Statement/v0.1"))
+		Expect(s.Subject).To(Equal([]native.Subject{{Name: "app", Digest: map[string]string{"sha256": "binary-digest"}}}))
+		Expect(s.Predicate.Builder.ID).To(Equal("test-id@1.0.0"))
+		Expect(s.Predicate.Invocation.Parameters).To(HaveKeyWithValue("arguments", []string{"-Dtoken=[REDACTED]"}))
+		Expect(s.Predicate.Materials).To(ConsistOf(
+			native.Subject{Name: "application", Digest: map[string]string{"sha256": "inputs-digest"}},
+			native.Subject{Name: "native-image", Digest: map[string]string{"sha256": "version-digest"}},
+		))
+	})
+
+	it("digests inputs", func() {
+		a := native.InputsDigest([]sherpa.FileEntry{{Path: "a", Mode: "0644", SHA256: "1"}})
+		b := native.InputsDigest([]sherpa.FileEntry{{Path: "a", Mode: "0755", SHA256: "1"}})
+		Expect(a).NotTo(Equal(b))
+		Expect(a).To(HaveLen(64))
+	})
+
+	it("digests files", func() {
+		f := filepath.Join(t.TempDir(), "binary")
+		Expect(ioutil.WriteFile(f, []byte("test"), 0644)).To(Succeed())
+
+		Expect(native.FileDigest(f)).To(Equal("9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"))
+	})
+}
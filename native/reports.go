@@ -0,0 +1,80 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/paketo-buildpacks/libpak/sherpa"
+)
+
+// ReportsDirectory is the directory, relative to the directory native-image runs in, it writes reports to
+const ReportsDirectory = "reports"
+
+// AnalysisReportArguments requests the reports of the points-to analysis, which show why classes and methods are
+// reachable and so what makes up the size of an image
+type AnalysisReportArguments struct {
+	Enabled bool
+
+	// ForbiddenTypes are types whose reachability native-image reports, with the path making them reachable
+	ForbiddenTypes []string
+}
+
+// Configure appends the analysis report arguments to inputArgs as requested
+func (a AnalysisReportArguments) Configure(inputArgs []string) ([]string, string, error) {
+	if a.Enabled {
+		inputArgs = append(inputArgs, "-H:+PrintAnalysisCallTree", "-H:+PrintAnalysisStatistics")
+	}
+
+	if len(a.ForbiddenTypes) > 0 {
+		inputArgs = append(inputArgs, fmt.Sprintf("-H:ReportAnalysisForbiddenType=%s", strings.Join(a.ForbiddenTypes, ",")))
+	}
+
+	return inputArgs, "", nil
+}
+
+// CollectReports moves the reports native-image wrote into the native image layer to the diagnostics layer, so that
+// they can be extracted from the cache. Returns where the reports are, which is the native image layer if there is no
+// diagnostics layer.
+func CollectReports(layerPath string, diagnosticsPath string) (string, error) {
+	src := filepath.Join(layerPath, ReportsDirectory)
+	if _, err := os.Stat(src); os.IsNotExist(err) {
+		return "", nil
+	} else if err != nil {
+		return "", fmt.Errorf("unable to stat %s\n%w", src, err)
+	}
+
+	if diagnosticsPath == "" {
+		return src, nil
+	}
+
+	dst := filepath.Join(diagnosticsPath, ReportsDirectory)
+	if err := os.RemoveAll(dst); err != nil {
+		return "", fmt.Errorf("unable to remove %s\n%w", dst, err)
+	}
+	if err := sherpa.CopyDir(src, dst); err != nil {
+		return "", fmt.Errorf("unable to copy %s to %s\n%w", src, dst, err)
+	}
+	if err := os.RemoveAll(src); err != nil {
+		return "", fmt.Errorf("unable to remove %s\n%w", src, err)
+	}
+
+	return dst, nil
+}
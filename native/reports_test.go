@@ -0,0 +1,96 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testReports(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		layerPath       string
+		diagnosticsPath string
+	)
+
+	it.Before(func() {
+		var err error
+
+		layerPath, err = ioutil.TempDir("", "reports-layer")
+		Expect(err).NotTo(HaveOccurred())
+
+		diagnosticsPath, err = ioutil.TempDir("", "reports-diagnostics")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(layerPath)).To(Succeed())
+		Expect(os.RemoveAll(diagnosticsPath)).To(Succeed())
+	})
+
+	context("AnalysisReportArguments", func() {
+		it("does nothing when disabled", func() {
+			args, _, err := native.AnalysisReportArguments{}.Configure([]string{"one"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(args).To(Equal([]string{"one"}))
+		})
+
+		it("requests analysis reports and forbidden types", func() {
+			args, _, err := native.AnalysisReportArguments{
+				Enabled:        true,
+				ForbiddenTypes: []string{"java.awt.Toolkit", "javax.swing.JFrame"},
+			}.Configure([]string{"one"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(args).To(Equal([]string{
+				"one",
+				"-H:+PrintAnalysisCallTree",
+				"-H:+PrintAnalysisStatistics",
+				"-H:ReportAnalysisForbiddenType=java.awt.Toolkit,javax.swing.JFrame",
+			}))
+		})
+	})
+
+	context("CollectReports", func() {
+		it("moves reports to the diagnostics layer", func() {
+			Expect(os.MkdirAll(filepath.Join(layerPath, "reports"), 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(layerPath, "reports", "call_tree_app.txt"), []byte{}, 0644)).To(Succeed())
+
+			Expect(native.CollectReports(layerPath, diagnosticsPath)).To(Equal(filepath.Join(diagnosticsPath, "reports")))
+			Expect(filepath.Join(diagnosticsPath, "reports", "call_tree_app.txt")).To(BeARegularFile())
+			Expect(filepath.Join(layerPath, "reports")).NotTo(BeADirectory())
+		})
+
+		it("leaves reports in the layer without a diagnostics layer", func() {
+			Expect(os.MkdirAll(filepath.Join(layerPath, "reports"), 0755)).To(Succeed())
+
+			Expect(native.CollectReports(layerPath, "")).To(Equal(filepath.Join(layerPath, "reports")))
+		})
+
+		it("returns nothing without reports", func() {
+			Expect(native.CollectReports(layerPath, diagnosticsPath)).To(BeEmpty())
+		})
+	})
+}
@@ -0,0 +1,61 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SourceDateEpoch is the environment variable holding the timestamp of reproducible builds, in seconds since the Unix
+// epoch. See https://reproducible-builds.org/specs/source-date-epoch/.
+const SourceDateEpoch = "SOURCE_DATE_EPOCH"
+
+// DefaultSourceDateEpoch is the timestamp used when SOURCE_DATE_EPOCH is not set, matching the timestamp the
+// lifecycle gives image layers.
+var DefaultSourceDateEpoch = time.Date(1980, time.January, 1, 0, 0, 1, 0, time.UTC)
+
+// ParseSourceDateEpoch parses a SOURCE_DATE_EPOCH value, returning DefaultSourceDateEpoch if it is empty
+func ParseSourceDateEpoch(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return DefaultSourceDateEpoch, nil
+	}
+
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unable to parse $%s value %q as seconds since the epoch\n%w", SourceDateEpoch, value, err)
+	}
+
+	return time.Unix(seconds, 0).UTC(), nil
+}
+
+// replaceName returns a copy of arguments with the value of -H:Name replaced by name
+func replaceName(arguments []string, name string) []string {
+	replaced := make([]string, len(arguments))
+
+	for i, a := range arguments {
+		if strings.HasPrefix(a, "-H:Name=") {
+			a = fmt.Sprintf("-H:Name=%s", name)
+		}
+		replaced[i] = a
+	}
+
+	return replaced
+}
@@ -0,0 +1,46 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testReproducible(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+	)
+
+	it("parses SOURCE_DATE_EPOCH", func() {
+		Expect(native.ParseSourceDateEpoch("1700000000")).To(Equal(time.Date(2023, time.November, 14, 22, 13, 20, 0, time.UTC)))
+	})
+
+	it("defaults SOURCE_DATE_EPOCH", func() {
+		Expect(native.ParseSourceDateEpoch("")).To(Equal(native.DefaultSourceDateEpoch))
+	})
+
+	it("fails to parse an invalid SOURCE_DATE_EPOCH", func() {
+		_, err := native.ParseSourceDateEpoch("yesterday")
+		Expect(err).To(MatchError(ContainSubstring(`unable to parse $SOURCE_DATE_EPOCH value "yesterday"`)))
+	})
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"fmt"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+const (
+	// VersionSourceSpringNative is the source of a native-image-builder version derived from Spring Native
+	VersionSourceSpringNative = "spring-native"
+)
+
+// BuilderRequirement returns the version constraint on the native-image-builder and its source, either the configured
+// version or the GraalVM versions the compatibility table lists for the Spring Native release on the classpath. Fails
+// if the table enforces a range the configured version is not in, so that the incompatibility is reported before
+// compiling.
+func BuilderRequirement(table []Compatibility, configured string, springNative *Artifact) (string, string, error) {
+	var (
+		supported string
+		enforced  bool
+	)
+	if springNative != nil {
+		if c, ok := FindCompatibility(table, *springNative); ok {
+			supported, enforced = c.GraalVM, c.Action == CompatibilityFail
+		}
+	}
+
+	if configured == "" {
+		if supported == "" {
+			return "", "", nil
+		}
+		return supported, VersionSourceSpringNative, nil
+	}
+
+	if enforced {
+		// configured constraints such as 22.* are left to the provider of the native-image-builder
+		if v, err := semver.NewVersion(configured); err == nil {
+			c, err := semver.NewConstraint(supported)
+			if err != nil {
+				return "", "", fmt.Errorf("unable to parse constraint %q\n%w", supported, err)
+			}
+
+			if !c.Check(v) {
+				return "", "", fmt.Errorf("GraalVM %s configured with $%s is not supported by %s %s, which requires GraalVM %s",
+					configured, ConfigNativeImageVersion, springNative.ArtifactID, springNative.Version, supported)
+			}
+		}
+	}
+
+	return configured, ConfigNativeImageVersion, nil
+}
@@ -0,0 +1,76 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testRequirement(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		springNative = native.Artifact{ArtifactID: "spring-native", Version: "0.11.2"}
+	)
+
+	context("BuilderRequirement", func() {
+		it("does not constrain the version", func() {
+			v, s, err := native.BuilderRequirement(native.DefaultCompatibility, "", nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(v).To(BeEmpty())
+			Expect(s).To(BeEmpty())
+		})
+
+		it("derives the version from Spring Native", func() {
+			v, s, err := native.BuilderRequirement(native.DefaultCompatibility, "", &springNative)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(v).To(Equal(">=21.3.0, <22.2.0"))
+			Expect(s).To(Equal("spring-native"))
+		})
+
+		it("uses the configured version", func() {
+			v, s, err := native.BuilderRequirement(native.DefaultCompatibility, "22.1.0", &springNative)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(v).To(Equal("22.1.0"))
+			Expect(s).To(Equal("BP_NATIVE_IMAGE_VERSION"))
+		})
+
+		it("uses a configured constraint", func() {
+			v, _, err := native.BuilderRequirement(native.DefaultCompatibility, "22.*", &springNative)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(v).To(Equal("22.*"))
+		})
+
+		it("does not fail if the compatibility table only warns", func() {
+			v, _, err := native.BuilderRequirement([]native.Compatibility{
+				{SpringNative: "~0.11.0", GraalVM: ">=21.3.0, <22.2.0", Action: "warn"},
+			}, "22.3.1", &springNative)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(v).To(Equal("22.3.1"))
+		})
+
+		it("fails if Spring Native does not support the configured version", func() {
+			_, _, err := native.BuilderRequirement(native.DefaultCompatibility, "22.3.1", &springNative)
+			Expect(err).To(MatchError("GraalVM 22.3.1 configured with $BP_NATIVE_IMAGE_VERSION is not supported by spring-native 0.11.2, which requires GraalVM >=21.3.0, <22.2.0"))
+		})
+	})
+}
@@ -0,0 +1,144 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/paketo-buildpacks/libpak/bard"
+)
+
+// singleValuedArguments are the native-image options that take a single value, so that a later occurrence replaces
+// an earlier one. Every other option may be repeated and is only de-duplicated.
+var singleValuedArguments = []string{
+	"--gc",
+	"--libc",
+	"--parallelism",
+	"-march",
+	"-H:Class",
+	"-H:Kind",
+	"-H:Name",
+	"-H:Path",
+	"-O",
+	"-R:MaxHeapSize",
+	"-R:MaximumHeapSizePercent",
+	"-J-Xms",
+	"-J-Xmx",
+	"-J-Xss",
+}
+
+// exclusiveArguments are groups of native-image options of which at most one may be set
+var exclusiveArguments = [][]string{
+	{"--auto-fallback", "--force-fallback", "--no-fallback"},
+}
+
+// Resolver resolves the gathered native-image arguments and reports the arguments replaced by the configuration of
+// the end user
+type Resolver interface {
+	Resolve(arguments []string) ([]string, error)
+	ReportOverrides(before []string, after []string)
+}
+
+// ArgumentResolver resolves the native-image arguments gathered from the defaults, native-image.properties of
+// libraries and the configuration of the end user, where the last occurrence of an option wins
+type ArgumentResolver struct {
+	Logger bard.Logger
+}
+
+// Resolve removes duplicate arguments, removes options that are replaced by a later occurrence with a different value
+// and reports the winner, and fails when mutually exclusive options are set
+func (r ArgumentResolver) Resolve(arguments []string) ([]string, error) {
+	last := map[string]int{}
+	for i, arg := range arguments {
+		if key, ok := argumentKey(arg); ok {
+			last[key] = i
+		}
+	}
+
+	var resolved []string
+	for i, arg := range arguments {
+		if key, ok := argumentKey(arg); ok && last[key] != i {
+			if winner := arguments[last[key]]; winner != arg {
+				r.Logger.Bodyf("Using native-image argument %s instead of %s", winner, arg)
+			}
+			continue
+		}
+
+		resolved = append(resolved, arg)
+	}
+
+	for _, group := range exclusiveArguments {
+		var set []string
+		for _, g := range group {
+			if containsString(resolved, g) {
+				set = append(set, g)
+			}
+		}
+
+		if len(set) > 1 {
+			return nil, fmt.Errorf("conflicting native-image arguments %s, only one of them may be set", strings.Join(set, " and "))
+		}
+	}
+
+	return resolved, nil
+}
+
+// ReportOverrides reports the arguments that were replaced because the end user configured the same option
+func (r ArgumentResolver) ReportOverrides(before []string, after []string) {
+	for _, arg := range before {
+		if !containsString(after, arg) {
+			r.Logger.Bodyf("Using $%s instead of native-image argument %s", ConfigNativeImageArgs, arg)
+		}
+	}
+}
+
+// argumentKey returns the key identifying an option, so that arguments with the same key replace each other. Options
+// that are neither single valued, boolean -H options nor system properties are identified by the whole argument, so
+// that only exact duplicates are removed. Arguments that are not options, such as the values of -cp, have no key.
+func argumentKey(arg string) (string, bool) {
+	if !strings.HasPrefix(arg, "-") {
+		return "", false
+	}
+
+	if strings.HasPrefix(arg, "-H:+") || strings.HasPrefix(arg, "-H:-") {
+		return "-H:" + arg[4:], true
+	}
+
+	// system properties are single valued per property
+	if strings.HasPrefix(arg, "-D") {
+		return strings.SplitN(arg, "=", 2)[0], true
+	}
+
+	for _, s := range singleValuedArguments {
+		if strings.HasPrefix(arg, s+"=") || (strings.HasPrefix(arg, s) && isSizeOrLevel(arg[len(s):])) {
+			return s, true
+		}
+	}
+
+	return arg, true
+}
+
+// isSizeOrLevel returns true for the values of options such as -O2 and -J-Xmx4g, which are not separated by =
+func isSizeOrLevel(value string) bool {
+	if value == "" {
+		return false
+	}
+
+	_, ok := parseMemorySize(value)
+	return ok || value == "b" || value == "s"
+}
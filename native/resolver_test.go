@@ -0,0 +1,78 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/paketo-buildpacks/libpak/bard"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testResolver(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		log      *bytes.Buffer
+		resolver native.ArgumentResolver
+	)
+
+	it.Before(func() {
+		log = &bytes.Buffer{}
+		resolver = native.ArgumentResolver{Logger: bard.NewLogger(log)}
+	})
+
+	it("removes duplicates", func() {
+		Expect(resolver.Resolve([]string{"--no-fallback", "-H:IncludeResources=a", "--no-fallback", "-H:IncludeResources=b"})).
+			To(Equal([]string{"-H:IncludeResources=a", "--no-fallback", "-H:IncludeResources=b"}))
+		Expect(log.String()).To(BeEmpty())
+	})
+
+	it("keeps the last value of single valued options", func() {
+		Expect(resolver.Resolve([]string{"--gc=serial", "-J-Xmx8g", "-O1", "-H:-AddAllCharsets", "-Da=1", "--gc=G1", "-J-Xmx4g", "-Ob", "-H:+AddAllCharsets", "-Da=2"})).
+			To(Equal([]string{"--gc=G1", "-J-Xmx4g", "-Ob", "-H:+AddAllCharsets", "-Da=2"}))
+		Expect(log.String()).To(ContainSubstring("Using native-image argument --gc=G1 instead of --gc=serial"))
+		Expect(log.String()).To(ContainSubstring("Using native-image argument -J-Xmx4g instead of -J-Xmx8g"))
+		Expect(log.String()).To(ContainSubstring("Using native-image argument -Ob instead of -O1"))
+		Expect(log.String()).To(ContainSubstring("Using native-image argument -H:+AddAllCharsets instead of -H:-AddAllCharsets"))
+		Expect(log.String()).To(ContainSubstring("Using native-image argument -Da=2 instead of -Da=1"))
+	})
+
+	it("does not confuse options sharing a prefix", func() {
+		Expect(resolver.Resolve([]string{"-O2", "-Ob", "-H:Name=a", "-H:NameSuffix=b"})).
+			To(Equal([]string{"-Ob", "-H:Name=a", "-H:NameSuffix=b"}))
+	})
+
+	it("keeps arguments that are not options", func() {
+		Expect(resolver.Resolve([]string{"@argfile", "@argfile"})).To(Equal([]string{"@argfile", "@argfile"}))
+	})
+
+	it("fails on mutually exclusive options", func() {
+		_, err := resolver.Resolve([]string{"--no-fallback", "--force-fallback"})
+		Expect(err).To(MatchError("conflicting native-image arguments --force-fallback and --no-fallback, only one of them may be set"))
+	})
+
+	it("reports arguments replaced by the end user", func() {
+		resolver.ReportOverrides([]string{"--gc=serial", "-ea"}, []string{"-ea", "--gc=G1"})
+		Expect(log.String()).To(ContainSubstring("Using $BP_NATIVE_IMAGE_BUILD_ARGUMENTS instead of native-image argument --gc=serial"))
+		Expect(log.String()).NotTo(ContainSubstring("-ea"))
+	})
+}
@@ -0,0 +1,72 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ParseResourcePatterns parses a comma or whitespace separated list of glob patterns
+func ParseResourcePatterns(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n'
+	})
+}
+
+// ResourceGlobToRegex translates a resource glob pattern into the regular expression expected by
+// -H:IncludeResources. native-image matches the expression against the whole resource path, which never has a leading
+// slash. '**' matches across directories, '*' and '?' match within a single path segment, and every other character
+// is matched literally.
+func ResourceGlobToRegex(glob string) string {
+	glob = strings.TrimLeft(glob, "/")
+
+	var b strings.Builder
+	for i := 0; i < len(glob); i++ {
+		switch {
+		case strings.HasPrefix(glob[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case glob[i] == '*':
+			b.WriteString("[^/]*")
+		case glob[i] == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		}
+	}
+
+	return b.String()
+}
+
+// ResourceArguments includes the resources matching glob patterns in the native image
+type ResourceArguments struct {
+	Patterns []string
+}
+
+// Configure appends a -H:IncludeResources argument to inputArgs for each pattern
+func (r ResourceArguments) Configure(inputArgs []string) ([]string, string, error) {
+	for _, p := range r.Patterns {
+		inputArgs = append(inputArgs, fmt.Sprintf("-H:IncludeResources=%s", ResourceGlobToRegex(p)))
+	}
+
+	return inputArgs, "", nil
+}
@@ -0,0 +1,63 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"regexp"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testResources(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+	)
+
+	it("parses comma and whitespace separated patterns", func() {
+		Expect(native.ParseResourcePatterns(" static/**, *.properties  messages_?.txt,")).
+			To(Equal([]string{"static/**", "*.properties", "messages_?.txt"}))
+		Expect(native.ParseResourcePatterns("")).To(BeEmpty())
+	})
+
+	it("translates globs into escaped regular expressions", func() {
+		Expect(native.ResourceGlobToRegex("static/**")).To(Equal(`static/.*`))
+		Expect(native.ResourceGlobToRegex("**/*.properties")).To(Equal(`(?:.*/)?[^/]*\.properties`))
+		Expect(native.ResourceGlobToRegex("/messages_?.txt")).To(Equal(`messages_[^/]\.txt`))
+		Expect(native.ResourceGlobToRegex("a+b(1)[2]{3}$.json")).To(Equal(`a\+b\(1\)\[2\]\{3\}\$\.json`))
+	})
+
+	it("matches resource paths", func() {
+		matches := func(glob string, path string) bool {
+			return regexp.MustCompile("^(?:" + native.ResourceGlobToRegex(glob) + ")$").MatchString(path)
+		}
+
+		Expect(matches("**/*.properties", "application.properties")).To(BeTrue())
+		Expect(matches("**/*.properties", "config/dev/application.properties")).To(BeTrue())
+		Expect(matches("*.properties", "config/application.properties")).To(BeFalse())
+		Expect(matches("static/**", "static/css/site.css")).To(BeTrue())
+		Expect(matches("a.txt", "abtxt")).To(BeFalse())
+	})
+
+	it("adds an argument for each pattern", func() {
+		Expect(native.ResourceArguments{Patterns: []string{"static/**", "*.xml"}}.Configure([]string{"test"})).
+			To(Equal([]string{"test", `-H:IncludeResources=static/.*`, `-H:IncludeResources=[^/]*\.xml`}))
+	})
+}
@@ -0,0 +1,124 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"debug/elf"
+	"fmt"
+	"strings"
+
+	"github.com/paketo-buildpacks/libpak"
+	"github.com/paketo-buildpacks/libpak/bard"
+)
+
+// JammyStaticStackID is the ID of the jammy static stack, whose run image has no C library
+const JammyStaticStackID = "io.buildpacks.stacks.jammy.static"
+
+// tinyLibraries are the shared libraries of the tiny run images, which provide glibc and nothing else
+var tinyLibraries = []string{
+	"ld-linux-aarch64.so.1",
+	"ld-linux-x86-64.so.2",
+	"libc.so.6",
+	"libdl.so.2",
+	"libm.so.6",
+	"libnss_dns.so.2",
+	"libnss_files.so.2",
+	"libpthread.so.0",
+	"libresolv.so.2",
+	"librt.so.1",
+	"libutil.so.1",
+}
+
+// Linkage is the dynamic linking of an ELF binary
+type Linkage struct {
+	// Interpreter is the dynamic loader of the binary, empty for a static binary
+	Interpreter string
+
+	// Libraries are the shared libraries the binary needs
+	Libraries []string
+}
+
+// ReadLinkage reads the dynamic loader and shared libraries of an ELF binary, like ldd does without running it
+func ReadLinkage(file string) (Linkage, error) {
+	f, err := elf.Open(file)
+	if err != nil {
+		return Linkage{}, fmt.Errorf("unable to open %s\n%w", file, err)
+	}
+	defer f.Close()
+
+	var linkage Linkage
+	for _, p := range f.Progs {
+		if p.Type != elf.PT_INTERP {
+			continue
+		}
+
+		b := make([]byte, p.Filesz)
+		if _, err := p.ReadAt(b, 0); err != nil {
+			return Linkage{}, fmt.Errorf("unable to read interpreter of %s\n%w", file, err)
+		}
+		linkage.Interpreter = strings.TrimRight(string(b), "\x00")
+	}
+
+	if linkage.Libraries, err = f.ImportedLibraries(); err != nil {
+		return Linkage{}, fmt.Errorf("unable to read shared libraries of %s\n%w", file, err)
+	}
+
+	return linkage, nil
+}
+
+// CheckRunImage returns the reasons a binary with linkage cannot run on the run image of the stack. Stacks other than
+// tiny and static are assumed to provide the libraries of a full distribution.
+func CheckRunImage(stackID string, linkage Linkage) []string {
+	var problems []string
+
+	switch stackID {
+	case JammyStaticStackID:
+		if linkage.Interpreter != "" || len(linkage.Libraries) > 0 {
+			problems = append(problems, fmt.Sprintf("the binary is dynamically linked, but the run image of %s has no C library", stackID))
+		}
+	case libpak.BionicTinyStackID, libpak.JammyTinyStackID:
+		for _, l := range linkage.Libraries {
+			if !containsString(tinyLibraries, l) {
+				problems = append(problems, fmt.Sprintf("the binary needs %s, which is not in the run image of %s", l, stackID))
+			}
+		}
+	}
+
+	return problems
+}
+
+// VerifyRunImage checks that the binary can run on the run image of the stack, failing or warning as action requests
+func VerifyRunImage(logger bard.Logger, file string, stackID string, action string) error {
+	linkage, err := ReadLinkage(file)
+	if err != nil {
+		return err
+	}
+
+	problems := CheckRunImage(stackID, linkage)
+	if len(problems) == 0 {
+		return nil
+	}
+
+	if action == CheckFail {
+		return fmt.Errorf("native image is not compatible with the run image\n%s", strings.Join(problems, "\n"))
+	}
+
+	for _, p := range problems {
+		warn(logger, p)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/paketo-buildpacks/libpak"
+	"github.com/paketo-buildpacks/libpak/bard"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testRunImage(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		dynamic = native.Linkage{
+			Interpreter: "/lib64/ld-linux-x86-64.so.2",
+			Libraries:   []string{"libz.so.1", "libc.so.6"},
+		}
+	)
+
+	context("CheckRunImage", func() {
+		it("accepts a static binary on the static stack", func() {
+			Expect(native.CheckRunImage(native.JammyStaticStackID, native.Linkage{})).To(BeEmpty())
+		})
+
+		it("rejects a dynamic binary on the static stack", func() {
+			Expect(native.CheckRunImage(native.JammyStaticStackID, dynamic)).
+				To(ConsistOf(ContainSubstring("dynamically linked")))
+		})
+
+		it("rejects libraries other than glibc on the tiny stack", func() {
+			Expect(native.CheckRunImage(libpak.JammyTinyStackID, dynamic)).
+				To(ConsistOf(ContainSubstring("needs libz.so.1")))
+		})
+
+		it("accepts any library on a full stack", func() {
+			Expect(native.CheckRunImage(libpak.JammyStackID, dynamic)).To(BeEmpty())
+		})
+	})
+
+	context("ReadLinkage", func() {
+		it("fails for a file that is not an ELF binary", func() {
+			dir, err := ioutil.TempDir("", "run-image")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.RemoveAll(dir)
+			Expect(ioutil.WriteFile(filepath.Join(dir, "binary"), []byte("#!/bin/sh"), 0755)).To(Succeed())
+
+			_, err = native.ReadLinkage(filepath.Join(dir, "binary"))
+			Expect(err).To(HaveOccurred())
+		})
+
+		it("reads the linkage of a dynamic binary", func() {
+			if runtime.GOOS != "linux" {
+				return
+			}
+			if _, err := os.Stat("/bin/ls"); err != nil {
+				return
+			}
+
+			linkage, err := native.ReadLinkage("/bin/ls")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(linkage.Interpreter).NotTo(BeEmpty())
+			Expect(linkage.Libraries).To(ContainElement("libc.so.6"))
+		})
+	})
+
+	context("VerifyRunImage", func() {
+		it("warns or fails on an incompatible binary", func() {
+			if runtime.GOOS != "linux" {
+				return
+			}
+			if _, err := os.Stat("/bin/ls"); err != nil {
+				return
+			}
+
+			b := &bytes.Buffer{}
+			Expect(native.VerifyRunImage(bard.NewLogger(b), "/bin/ls", native.JammyStaticStackID, native.CheckWarn)).To(Succeed())
+			Expect(b.String()).To(ContainSubstring("dynamically linked"))
+
+			Expect(native.VerifyRunImage(bard.NewLogger(b), "/bin/ls", native.JammyStaticStackID, native.CheckFail)).
+				To(MatchError(ContainSubstring("not compatible with the run image")))
+		})
+	})
+}
@@ -0,0 +1,124 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/buildpacks/libcnb"
+	"github.com/mattn/go-shellwords"
+	"github.com/paketo-buildpacks/libpak/bard"
+)
+
+// RuntimeOptionsVariable is the environment variable the runtime options are written to at launch
+const RuntimeOptionsVariable = "NATIVE_IMAGE_RUNTIME_OPTIONS"
+
+// RuntimeOptions is an exec.d helper translating the JVM options of $JAVA_TOOL_OPTIONS that native images support,
+// and the native image options of $BPL_NATIVE_IMAGE_OPTS, into the runtime options of the native image.
+type RuntimeOptions struct {
+	Logger bard.Logger
+}
+
+func (r RuntimeOptions) Execute() (map[string]string, error) {
+	var options []string
+
+	if s, ok := os.LookupEnv("JAVA_TOOL_OPTIONS"); ok {
+		tokens, err := shellwords.Parse(s)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse $JAVA_TOOL_OPTIONS\n%w", err)
+		}
+
+		for _, t := range tokens {
+			o, ok := TranslateRuntimeOption(t)
+			if !ok {
+				r.Logger.Debugf("Ignoring JVM option %s, which native images do not support", t)
+				continue
+			}
+			options = append(options, o)
+		}
+	}
+
+	if s, ok := os.LookupEnv("BPL_NATIVE_IMAGE_OPTS"); ok {
+		tokens, err := shellwords.Parse(s)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse $BPL_NATIVE_IMAGE_OPTS\n%w", err)
+		}
+
+		// native image options are passed through, so that options unknown to the JVM can be used
+		for _, t := range tokens {
+			if o, ok := TranslateRuntimeOption(t); ok {
+				t = o
+			}
+			options = append(options, t)
+		}
+	}
+
+	if len(options) == 0 {
+		return nil, nil
+	}
+
+	r.Logger.Infof("Adding runtime options to $%s", RuntimeOptionsVariable)
+	return map[string]string{RuntimeOptionsVariable: strings.Join(options, " ")}, nil
+}
+
+// TranslateRuntimeOption translates a JVM option into the equivalent runtime option of a native image. Returns false
+// if native images do not support the option.
+func TranslateRuntimeOption(option string) (string, bool) {
+	switch {
+	case strings.HasPrefix(option, "-Xmx") && strings.HasSuffix(option, "%"):
+		return heapPercent(strings.TrimSuffix(strings.TrimPrefix(option, "-Xmx"), "%"))
+	case strings.HasPrefix(option, "-XX:MaxRAMPercentage="):
+		return heapPercent(strings.TrimPrefix(option, "-XX:MaxRAMPercentage="))
+	case strings.HasPrefix(option, "-XX:ThreadStackSize="):
+		// the JVM expects the thread stack size in kilobytes
+		s := strings.TrimPrefix(option, "-XX:ThreadStackSize=")
+		if _, err := strconv.ParseUint(s, 10, 64); err != nil {
+			return "", false
+		}
+		return fmt.Sprintf("-Xss%sk", s), true
+	case strings.HasPrefix(option, "-Xmx"), strings.HasPrefix(option, "-Xms"), strings.HasPrefix(option, "-Xmn"),
+		strings.HasPrefix(option, "-Xss"), strings.HasPrefix(option, "-D"):
+		return option, true
+	default:
+		return "", false
+	}
+}
+
+// heapPercent returns the maximum heap size option of a native image for a percentage of the physical memory
+func heapPercent(s string) (string, bool) {
+	p, err := strconv.ParseFloat(s, 64)
+	if err != nil || p <= 0 || p > 100 {
+		return "", false
+	}
+	return fmt.Sprintf("-XX:MaximumHeapSizePercent=%d", int(p)), true
+}
+
+// RuntimeOptionsProcess runs a process of the native image with a shell, passing the runtime options written by the
+// runtime options helper ahead of its arguments. Other processes are returned as they are.
+func RuntimeOptionsProcess(process libcnb.Process, binary string) libcnb.Process {
+	if process.Command != binary || !process.Direct {
+		return process
+	}
+
+	process.Command = strings.Join(append([]string{binary, "$" + RuntimeOptionsVariable}, quote(process.Arguments)...), " ")
+	process.Arguments = nil
+	process.Direct = false
+	return process
+}
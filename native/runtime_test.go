@@ -0,0 +1,89 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/buildpacks/libcnb"
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testRuntimeOptions(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		r native.RuntimeOptions
+	)
+
+	it.After(func() {
+		Expect(os.Unsetenv("JAVA_TOOL_OPTIONS")).To(Succeed())
+		Expect(os.Unsetenv("BPL_NATIVE_IMAGE_OPTS")).To(Succeed())
+	})
+
+	it("does not set options without configuration", func() {
+		Expect(r.Execute()).To(BeNil())
+	})
+
+	it("translates $JAVA_TOOL_OPTIONS", func() {
+		Expect(os.Setenv("JAVA_TOOL_OPTIONS", "-XX:MaxRAMPercentage=75.0 -XX:ThreadStackSize=512 -Dtest=\"a b\" -XX:+UseG1GC -javaagent:agent.jar")).
+			To(Succeed())
+
+		Expect(r.Execute()).To(Equal(map[string]string{
+			"NATIVE_IMAGE_RUNTIME_OPTIONS": "-XX:MaximumHeapSizePercent=75 -Xss512k -Dtest=a b",
+		}))
+	})
+
+	it("passes $BPL_NATIVE_IMAGE_OPTS after $JAVA_TOOL_OPTIONS", func() {
+		Expect(os.Setenv("JAVA_TOOL_OPTIONS", "-Xmx1G")).To(Succeed())
+		Expect(os.Setenv("BPL_NATIVE_IMAGE_OPTS", "-Xmx50% -XX:+PrintGC")).To(Succeed())
+
+		Expect(r.Execute()).To(Equal(map[string]string{
+			"NATIVE_IMAGE_RUNTIME_OPTIONS": "-Xmx1G -XX:MaximumHeapSizePercent=50 -XX:+PrintGC",
+		}))
+	})
+
+	context("TranslateRuntimeOption", func() {
+		it("rejects invalid percentages", func() {
+			_, ok := native.TranslateRuntimeOption("-Xmx150%")
+			Expect(ok).To(BeFalse())
+		})
+
+		it("rejects invalid stack sizes", func() {
+			_, ok := native.TranslateRuntimeOption("-XX:ThreadStackSize=1M")
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	context("RuntimeOptionsProcess", func() {
+		it("runs the native image with a shell", func() {
+			Expect(native.RuntimeOptionsProcess(
+				libcnb.Process{Type: "web", Command: "/workspace/app", Arguments: []string{"--name=a b"}, Direct: true, Default: true},
+				"/workspace/app")).
+				To(Equal(libcnb.Process{Type: "web", Command: `/workspace/app $NATIVE_IMAGE_RUNTIME_OPTIONS "--name=a b"`, Default: true}))
+		})
+
+		it("keeps other processes", func() {
+			p := libcnb.Process{Type: "helper", Command: "/workspace/helper", Direct: true}
+			Expect(native.RuntimeOptionsProcess(p, "/workspace/app")).To(Equal(p))
+		})
+	})
+}
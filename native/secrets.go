@@ -0,0 +1,101 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package native
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/buildpacks/libcnb"
+	"github.com/paketo-buildpacks/libpak/bindings"
+)
+
+// BuildSecretsBindingType is the type of bindings holding credentials native-image needs at compile time, for example
+// for substitutions or metadata downloads
+const BuildSecretsBindingType = "native-image-build-secrets"
+
+// BuildSecretsEnv is the environment variable the directories of the build secrets bindings are passed to
+// native-image in, for secrets read as files
+const BuildSecretsEnv = "NATIVE_IMAGE_BUILD_SECRETS"
+
+var environmentName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// BuildSecrets are the contents of native-image-build-secrets bindings. They are only passed to the processes of the
+// build and never written to a layer, the layer metadata or the log.
+type BuildSecrets struct {
+	Paths   []string
+	Secrets map[string]string
+}
+
+// FindBuildSecrets returns the secrets of the bindings of type native-image-build-secrets. Returns nil if there is no
+// such binding.
+func FindBuildSecrets(binds libcnb.Bindings) (*BuildSecrets, error) {
+	bs := bindings.Resolve(binds, bindings.OfType(BuildSecretsBindingType))
+	if len(bs) == 0 {
+		return nil, nil
+	}
+	sort.Slice(bs, func(i, j int) bool { return bs[i].Name < bs[j].Name })
+
+	s := &BuildSecrets{Secrets: map[string]string{}}
+	for _, b := range bs {
+		s.Paths = append(s.Paths, b.Path)
+
+		for k, v := range b.Secret {
+			if !environmentName.MatchString(k) {
+				continue
+			}
+			if _, ok := s.Secrets[k]; ok {
+				return nil, fmt.Errorf("secret %s of binding %s is also set by another binding of type %s",
+					k, b.Name, BuildSecretsBindingType)
+			}
+			s.Secrets[k] = strings.TrimSpace(v)
+		}
+	}
+
+	return s, nil
+}
+
+// Names returns the sorted names of the secrets passed as environment variables
+func (s BuildSecrets) Names() []string {
+	var names []string
+	for k := range s.Secrets {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Environment adds the secrets whose keys are valid environment variable names, and the directories of the bindings,
+// to the native-image process environment. A nil env inherits the build environment.
+func (s BuildSecrets) Environment(env []string) []string {
+	if env == nil {
+		env = os.Environ()
+	}
+
+	env = append([]string{}, env...)
+	for _, k := range s.Names() {
+		env = append(env, fmt.Sprintf("%s=%s", k, s.Secrets[k]))
+	}
+	if len(s.Paths) > 0 {
+		env = append(env, fmt.Sprintf("%s=%s", BuildSecretsEnv, strings.Join(s.Paths, string(filepath.ListSeparator))))
+	}
+
+	return env
+}
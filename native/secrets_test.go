@@ -0,0 +1,72 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package native_test
+
+import (
+	"testing"
+
+	"github.com/buildpacks/libcnb"
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testSecrets(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+	)
+
+	context("FindBuildSecrets", func() {
+		it("reads the secrets of the bindings", func() {
+			Expect(native.FindBuildSecrets(libcnb.Bindings{
+				{Name: "b", Path: "/bindings/b", Type: "native-image-build-secrets", Secret: map[string]string{"REPO_TOKEN": "token\n", "settings.xml": "<settings/>"}},
+				{Name: "a", Path: "/bindings/a", Type: "native-image-build-secrets", Secret: map[string]string{"REPO_USER": "user"}},
+				{Name: "other", Path: "/bindings/other", Type: "other", Secret: map[string]string{"OTHER": "other"}},
+			})).To(Equal(&native.BuildSecrets{
+				Paths:   []string{"/bindings/a", "/bindings/b"},
+				Secrets: map[string]string{"REPO_TOKEN": "token", "REPO_USER": "user"},
+			}))
+		})
+
+		it("fails when bindings set the same secret", func() {
+			_, err := native.FindBuildSecrets(libcnb.Bindings{
+				{Name: "a", Type: "native-image-build-secrets", Secret: map[string]string{"REPO_TOKEN": "a"}},
+				{Name: "b", Type: "native-image-build-secrets", Secret: map[string]string{"REPO_TOKEN": "b"}},
+			})
+			Expect(err).To(MatchError("secret REPO_TOKEN of binding b is also set by another binding of type native-image-build-secrets"))
+		})
+
+		it("returns nil without a binding", func() {
+			Expect(native.FindBuildSecrets(nil)).To(BeNil())
+		})
+	})
+
+	it("adds the secrets to the environment", func() {
+		secrets := native.BuildSecrets{
+			Paths:   []string{"/bindings/a", "/bindings/b"},
+			Secrets: map[string]string{"REPO_USER": "user", "REPO_TOKEN": "token"},
+		}
+
+		Expect(secrets.Environment([]string{"PATH=/bin"})).To(Equal([]string{
+			"PATH=/bin",
+			"REPO_TOKEN=token",
+			"REPO_USER=user",
+			"NATIVE_IMAGE_BUILD_SECRETS=/bindings/a:/bindings/b",
+		}))
+		Expect(secrets.Environment(nil)).To(ContainElement("REPO_TOKEN=token"))
+	})
+}
@@ -0,0 +1,86 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"fmt"
+	"strings"
+)
+
+// securityProviderAliases are short names of commonly used JCA providers
+var securityProviderAliases = map[string]string{
+	"bc":           "org.bouncycastle.jce.provider.BouncyCastleProvider",
+	"bouncycastle": "org.bouncycastle.jce.provider.BouncyCastleProvider",
+	"bcfips":       "org.bouncycastle.jcajce.provider.BouncyCastleFipsProvider",
+	"bcjsse":       "org.bouncycastle.jsse.provider.BouncyCastleJsseProvider",
+}
+
+// BouncyCastleInitialization are the class initialization arguments BouncyCastle providers need. The provider is
+// registered at build time, but its random number generators must be seeded at run time.
+var BouncyCastleInitialization = []string{
+	"--initialize-at-build-time=org.bouncycastle",
+	"--initialize-at-run-time=org.bouncycastle.jcajce.provider.drbg.DRBG$Default,org.bouncycastle.jcajce.provider.drbg.DRBG$NonceAndIV",
+}
+
+// ParseSecurityProviders parses a comma separated list of JCA provider class names or aliases such as bc
+func ParseSecurityProviders(value string) []string {
+	var providers []string
+
+	for _, p := range strings.Split(value, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+
+		if c, ok := securityProviderAliases[strings.ToLower(p)]; ok {
+			p = c
+		}
+		providers = append(providers, p)
+	}
+
+	return providers
+}
+
+// SecurityArguments enables security services and registers additional JCA providers, without which TLS and token
+// handling commonly fail at runtime
+type SecurityArguments struct {
+	AllServices bool
+	Providers   []string
+}
+
+// Configure appends --enable-all-security-services, -H:AdditionalSecurityProviders and the class initialization the
+// providers need to inputArgs as requested
+func (s SecurityArguments) Configure(inputArgs []string) ([]string, string, error) {
+	if s.AllServices {
+		inputArgs = append(inputArgs, "--enable-all-security-services")
+	}
+
+	if len(s.Providers) == 0 {
+		return inputArgs, "", nil
+	}
+
+	inputArgs = append(inputArgs, fmt.Sprintf("-H:AdditionalSecurityProviders=%s", strings.Join(s.Providers, ",")))
+
+	for _, p := range s.Providers {
+		if strings.HasPrefix(p, "org.bouncycastle.") {
+			inputArgs = append(inputArgs, BouncyCastleInitialization...)
+			break
+		}
+	}
+
+	return inputArgs, "", nil
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testSecurity(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+	)
+
+	it("parses providers and aliases", func() {
+		Expect(native.ParseSecurityProviders("BC, com.example.Provider,")).To(Equal([]string{
+			"org.bouncycastle.jce.provider.BouncyCastleProvider",
+			"com.example.Provider",
+		}))
+	})
+
+	it("does nothing by default", func() {
+		args, _, err := native.SecurityArguments{}.Configure([]string{"test"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(args).To(Equal([]string{"test"}))
+	})
+
+	it("enables all security services", func() {
+		args, _, err := native.SecurityArguments{AllServices: true}.Configure([]string{"test"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(args).To(Equal([]string{"test", "--enable-all-security-services"}))
+	})
+
+	it("registers providers", func() {
+		args, _, err := native.SecurityArguments{Providers: []string{"com.example.Provider"}}.Configure([]string{"test"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(args).To(Equal([]string{"test", "-H:AdditionalSecurityProviders=com.example.Provider"}))
+	})
+
+	it("initializes BouncyCastle once", func() {
+		args, _, err := native.SecurityArguments{Providers: []string{
+			"org.bouncycastle.jce.provider.BouncyCastleProvider",
+			"org.bouncycastle.jsse.provider.BouncyCastleJsseProvider",
+		}}.Configure(nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(args).To(Equal(append([]string{
+			"-H:AdditionalSecurityProviders=org.bouncycastle.jce.provider.BouncyCastleProvider,org.bouncycastle.jsse.provider.BouncyCastleJsseProvider",
+		}, native.BouncyCastleInitialization...)))
+	})
+}
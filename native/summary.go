@@ -0,0 +1,155 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// SummaryFile is the name of the machine-readable build summary in the native image layer
+const SummaryFile = "native-build-summary.json"
+
+var (
+	// heapPattern matches the heap in use at the end of a phase, e.g. `(20.1s @ 1.20GB)`
+	heapPattern = regexp.MustCompile(`\(\d+(?:\.\d+)?s @ (\d+(?:\.\d+)?)GB\)`)
+
+	// resourcesPattern matches the resources in the image heap, e.g. `1.20MB (1.50%) for 218 resources`
+	resourcesPattern = regexp.MustCompile(`for\s+([\d,]+) resources`)
+
+	// reflectionPattern matches the elements registered for reflection, e.g.
+	// `2,101 classes, 32 fields, and 1,073 methods registered for reflection`
+	reflectionPattern = regexp.MustCompile(`([\d,]+) classes,\s+([\d,]+) fields, and\s+([\d,]+) methods registered for reflection`)
+)
+
+// Summary is a machine-readable summary of a native-image build, so that CI pipelines can assert on size and time
+// budgets
+type Summary struct {
+	Binary          string         `json:"binary"`
+	GraalVMVersion  string         `json:"graalvm-version"`
+	DurationSeconds float64        `json:"duration-seconds"`
+	Phases          []SummaryPhase `json:"phases"`
+	PeakHeapBytes   int64          `json:"peak-heap-bytes"`
+	PeakRSSBytes    int64          `json:"peak-rss-bytes"`
+	BinarySizeBytes int64          `json:"binary-size-bytes"`
+	Resources       int64          `json:"resources"`
+	Reflection      Reflection     `json:"reflection"`
+	Warnings        []string       `json:"warnings"`
+
+	// Violations are the phases that took longer than their thresholds
+	Violations []ThresholdViolation `json:"threshold-violations"`
+}
+
+// SummaryPhase is the duration of a single step of a native-image build
+type SummaryPhase struct {
+	Name            string  `json:"name"`
+	DurationSeconds float64 `json:"duration-seconds"`
+}
+
+// Reflection are the numbers of elements registered for reflection
+type Reflection struct {
+	Classes int64 `json:"classes"`
+	Fields  int64 `json:"fields"`
+	Methods int64 `json:"methods"`
+}
+
+// NewSummary creates the summary of a build from its metrics, its phases and the output of native-image. Figures
+// native-image does not report, such as those of releases before GraalVM 22, are zero.
+func NewSummary(binary string, metrics Metrics, phases []Phase, output string) Summary {
+	s := Summary{
+		Binary:          binary,
+		GraalVMVersion:  metrics.GraalVMVersion,
+		DurationSeconds: metrics.Duration.Seconds(),
+		Phases:          []SummaryPhase{},
+		Warnings:        []string{},
+		Violations:      []ThresholdViolation{},
+		PeakRSSBytes:    metrics.PeakRSS,
+		BinarySizeBytes: metrics.BinarySize,
+	}
+
+	for _, p := range phases {
+		s.Phases = append(s.Phases, SummaryPhase{Name: p.Name, DurationSeconds: p.Duration.Seconds()})
+	}
+
+	for _, m := range heapPattern.FindAllStringSubmatch(output, -1) {
+		if gb, err := strconv.ParseFloat(m[1], 64); err == nil && int64(gb*1024*1024*1024) > s.PeakHeapBytes {
+			s.PeakHeapBytes = int64(gb * 1024 * 1024 * 1024)
+		}
+	}
+
+	if m := resourcesPattern.FindStringSubmatch(output); m != nil {
+		s.Resources = parseCount(m[1])
+	}
+
+	if m := reflectionPattern.FindStringSubmatch(output); m != nil {
+		s.Reflection = Reflection{Classes: parseCount(m[1]), Fields: parseCount(m[2]), Methods: parseCount(m[3])}
+	}
+
+	return s
+}
+
+// WriteTo writes the summary as JSON to path, creating its parent directories
+func (s Summary) WriteTo(path string) error {
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to encode build summary\n%w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("unable to create %s\n%w", filepath.Dir(path), err)
+	}
+
+	if err := ioutil.WriteFile(path, append(b, '\n'), 0644); err != nil {
+		return fmt.Errorf("unable to write %s\n%w", path, err)
+	}
+
+	return nil
+}
+
+// parseCount parses a number with thousands separators, e.g. 1,073
+func parseCount(s string) int64 {
+	v, _ := strconv.ParseInt(strings.ReplaceAll(s, ",", ""), 10, 64)
+	return v
+}
+
+// CopySummary copies the build summary of the native image layer to path, for platforms that collect it from a
+// location of their choice. A layer without a summary, contributed by an earlier version of the buildpack, is ignored.
+func CopySummary(layerPath string, path string) error {
+	src := filepath.Join(layerPath, SummaryFile)
+	b, err := ioutil.ReadFile(src)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("unable to read %s\n%w", src, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("unable to create %s\n%w", filepath.Dir(path), err)
+	}
+
+	if err := ioutil.WriteFile(path, b, 0644); err != nil {
+		return fmt.Errorf("unable to write %s\n%w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testSummary(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		dir string
+	)
+
+	it.Before(func() {
+		var err error
+
+		dir, err = ioutil.TempDir("", "summary")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	it("summarizes the output of native-image", func() {
+		output := `[1/7] Initializing...                                            (3.5s @ 0.20GB)
+[2/7] Performing analysis...  [*******]                         (20.1s @ 1.50GB)
+   2,101 classes,    32 fields, and 1,073 methods registered for reflection
+[6/7] Creating image...                                         (2.1s @ 1.25GB)
+   1.20MB (1.50%) for    218 resources
+`
+		summary := native.NewSummary("app", native.Metrics{
+			Duration:       30 * time.Second,
+			PeakRSS:        1024,
+			BinarySize:     2048,
+			GraalVMVersion: "GraalVM 22.3.0 Java 17 CE",
+		}, []native.Phase{
+			{Index: 1, Total: 7, Name: "Initializing", Duration: 3500 * time.Millisecond},
+			{Index: 2, Total: 7, Name: "Performing analysis", Duration: 20100 * time.Millisecond},
+		}, output)
+
+		Expect(summary).To(Equal(native.Summary{
+			Binary:          "app",
+			GraalVMVersion:  "GraalVM 22.3.0 Java 17 CE",
+			DurationSeconds: 30,
+			Phases: []native.SummaryPhase{
+				{Name: "Initializing", DurationSeconds: 3.5},
+				{Name: "Performing analysis", DurationSeconds: 20.1},
+			},
+			PeakHeapBytes:   int64(1.5 * 1024 * 1024 * 1024),
+			PeakRSSBytes:    1024,
+			BinarySizeBytes: 2048,
+			Resources:       218,
+			Reflection:      native.Reflection{Classes: 2101, Fields: 32, Methods: 1073},
+			Warnings:        []string{},
+			Violations:      []native.ThresholdViolation{},
+		}))
+	})
+
+	it("leaves figures that are not reported at zero", func() {
+		summary := native.NewSummary("app", native.Metrics{}, nil, "")
+		Expect(summary.Phases).To(BeEmpty())
+		Expect(summary.Resources).To(BeZero())
+		Expect(summary.Reflection).To(Equal(native.Reflection{}))
+	})
+
+	it("writes and copies the summary", func() {
+		layer := filepath.Join(dir, "layer")
+		Expect(native.Summary{Binary: "app"}.WriteTo(filepath.Join(layer, "native-build-summary.json"))).To(Succeed())
+
+		path := filepath.Join(dir, "reports", "summary.json")
+		Expect(native.CopySummary(layer, path)).To(Succeed())
+
+		data, err := ioutil.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		var summary map[string]interface{}
+		Expect(json.Unmarshal(data, &summary)).To(Succeed())
+		Expect(summary["binary"]).To(Equal("app"))
+		Expect(summary["phases"]).To(BeNil())
+	})
+
+	it("ignores a layer without a summary", func() {
+		path := filepath.Join(dir, "summary.json")
+		Expect(native.CopySummary(filepath.Join(dir, "layer"), path)).To(Succeed())
+		Expect(path).NotTo(BeAnExistingFile())
+	})
+}
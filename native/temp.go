@@ -0,0 +1,195 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/buildpacks/libcnb"
+	"github.com/paketo-buildpacks/libpak/bard"
+)
+
+// Heuristics for estimating the disk space of a build, taken from the binaries and temporary files of Spring Boot
+// applications. Like the estimate of the build they are deliberately pessimistic.
+const (
+	baseDisk            = int64(256 * 1024 * 1024)
+	diskByClasspathByte = 4
+)
+
+// TempDirectoryLayer is the value of $BP_NATIVE_IMAGE_TEMP_DIRECTORY placing the temporary files of native-image in
+// its layer
+const TempDirectoryLayer = "layer"
+
+// TempDirectoryName is the directory of the native-image layer holding temporary files
+const TempDirectoryName = "tmp"
+
+// ParseTempDirectory parses the directory native-image writes temporary files to, either layer or an absolute path.
+// Empty keeps the temporary directory of the build environment.
+func ParseTempDirectory(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == TempDirectoryLayer || filepath.IsAbs(value) {
+		return value, nil
+	}
+
+	return "", fmt.Errorf("unknown $%s %q, must be %s or an absolute path", ConfigNativeImageTempDirectory, value, TempDirectoryLayer)
+}
+
+// ParseMinDiskSpace parses the free disk space a build requires, such as 10g
+func ParseMinDiskSpace(value string) (int64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+
+	size, ok := parseMemorySize(value)
+	if !ok || size < 0 {
+		return 0, fmt.Errorf("unable to parse $%s value %q as a memory size", ConfigNativeImageMinDiskSpace, value)
+	}
+
+	return size, nil
+}
+
+// TempSpace is where native-image writes its temporary files, which can fill up a small /tmp, and the free disk space
+// a build requires
+type TempSpace struct {
+	// Directory is layer, an absolute path or empty for the temporary directory of the build environment
+	Directory string
+
+	// Keep keeps the temporary files after the build instead of removing them
+	Keep bool
+
+	// MinFree is the free disk space required in the layer and the temporary directory, or zero for no minimum
+	MinFree int64
+
+	// CheckEstimate fails builds when the free disk space is below the disk space estimated from the classpath
+	CheckEstimate bool
+}
+
+// Create creates a temporary directory for a build, in layer or in the configured directory, returning it and a
+// function removing it unless it is kept. Returns an empty directory when none is configured.
+func (t TempSpace) Create(layer libcnb.Layer) (string, func(), error) {
+	base := t.Directory
+	switch base {
+	case "":
+		return "", func() {}, nil
+	case TempDirectoryLayer:
+		base = filepath.Join(layer.Path, TempDirectoryName)
+	}
+
+	if err := os.MkdirAll(base, 0755); err != nil {
+		return "", nil, fmt.Errorf("unable to create %s\n%w", base, err)
+	}
+
+	dir, err := ioutil.TempDir(base, "native-image")
+	if err != nil {
+		return "", nil, fmt.Errorf("unable to create temporary directory in %s\n%w", base, err)
+	}
+
+	if t.Keep {
+		return dir, func() {}, nil
+	}
+	return dir, func() {
+		_ = os.RemoveAll(dir)
+		if t.Directory == TempDirectoryLayer {
+			_ = os.Remove(base)
+		}
+	}, nil
+}
+
+// Check logs the free disk space of paths, failing if it is below the configured minimum or, when checked, below the
+// estimated disk space of the build. Paths whose free space is unknown are skipped.
+func (t TempSpace) Check(logger bard.Logger, estimated int64, paths ...string) error {
+	seen := map[string]bool{}
+	for _, p := range paths {
+		if p == "" || seen[p] {
+			continue
+		}
+		seen[p] = true
+
+		free, ok := freeSpace(p)
+		if !ok {
+			continue
+		}
+		logger.Bodyf("Free disk space in %s: %s", p, formatBytes(free))
+
+		if t.MinFree > 0 && free < t.MinFree {
+			return fmt.Errorf("free disk space in %s of %s is below the %s set with $%s, point $%s at a larger volume",
+				p, formatBytes(free), formatBytes(t.MinFree), ConfigNativeImageMinDiskSpace, ConfigNativeImageTempDirectory)
+		}
+		if t.CheckEstimate && free < estimated {
+			return fmt.Errorf("free disk space in %s of %s is below the estimated %s the build needs, point $%s at a larger volume or set $%s to false",
+				p, formatBytes(free), formatBytes(estimated), ConfigNativeImageTempDirectory, ConfigNativeImageDiskCheck)
+		}
+	}
+
+	return nil
+}
+
+// MeasureClasspath returns the size in bytes of the classpath entries, which may be JARs or directories
+func MeasureClasspath(classpath []string) (int64, error) {
+	var size int64
+
+	for _, entry := range classpath {
+		if err := filepath.Walk(entry, func(_ string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.Mode().IsRegular() {
+				size += info.Size()
+			}
+			return nil
+		}); os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return 0, fmt.Errorf("unable to walk %s\n%w", entry, err)
+		}
+	}
+
+	return size, nil
+}
+
+// EstimateDiskSpace estimates the disk space a build of a classpath of size bytes needs for the binary and the
+// temporary files of native-image
+func EstimateDiskSpace(size int64) int64 {
+	return baseDisk + size*diskByClasspathByte
+}
+
+// TempEnvironment points $TMPDIR and the java.io.tmpdir system property of native-image at dir. A nil env inherits
+// the build environment.
+func TempEnvironment(env []string, dir string) []string {
+	if dir == "" {
+		return env
+	}
+
+	if env == nil {
+		env = os.Environ()
+	}
+
+	var result []string
+	for _, e := range env {
+		if !strings.HasPrefix(e, "TMPDIR=") {
+			result = append(result, e)
+		}
+	}
+	result = append(result, fmt.Sprintf("TMPDIR=%s", dir))
+
+	return JavaToolOptions(result, []string{fmt.Sprintf("-Djava.io.tmpdir=%s", dir)})
+}
@@ -0,0 +1,167 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package native_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/buildpacks/libcnb"
+	. "github.com/onsi/gomega"
+	"github.com/paketo-buildpacks/libpak/bard"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testTemp(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		layer libcnb.Layer
+	)
+
+	it.Before(func() {
+		var err error
+		layer.Path, err = ioutil.TempDir("", "temp-layer")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(layer.Path)).To(Succeed())
+	})
+
+	context("ParseTempDirectory", func() {
+		it("parses layer and absolute paths", func() {
+			Expect(native.ParseTempDirectory("")).To(BeEmpty())
+			Expect(native.ParseTempDirectory(" layer ")).To(Equal("layer"))
+			Expect(native.ParseTempDirectory("/workspace/tmp")).To(Equal("/workspace/tmp"))
+		})
+
+		it("fails on relative paths", func() {
+			_, err := native.ParseTempDirectory("tmp")
+			Expect(err).To(MatchError(`unknown $BP_NATIVE_IMAGE_TEMP_DIRECTORY "tmp", must be layer or an absolute path`))
+		})
+	})
+
+	context("ParseMinDiskSpace", func() {
+		it("parses memory sizes", func() {
+			Expect(native.ParseMinDiskSpace("")).To(BeZero())
+			Expect(native.ParseMinDiskSpace("10g")).To(Equal(int64(10 * 1024 * 1024 * 1024)))
+		})
+
+		it("fails on invalid sizes", func() {
+			_, err := native.ParseMinDiskSpace("lots")
+			Expect(err).To(MatchError(`unable to parse $BP_NATIVE_IMAGE_MIN_DISK_SPACE value "lots" as a memory size`))
+		})
+	})
+
+	context("Create", func() {
+		it("does nothing without a directory", func() {
+			dir, cleanup, err := native.TempSpace{}.Create(layer)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dir).To(BeEmpty())
+			cleanup()
+		})
+
+		it("creates and removes a directory in the layer", func() {
+			dir, cleanup, err := native.TempSpace{Directory: "layer"}.Create(layer)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(filepath.Dir(dir)).To(Equal(filepath.Join(layer.Path, "tmp")))
+			Expect(dir).To(BeADirectory())
+
+			cleanup()
+			Expect(filepath.Join(layer.Path, "tmp")).NotTo(BeAnExistingFile())
+		})
+
+		it("creates a directory in an absolute path", func() {
+			base := filepath.Join(layer.Path, "scratch")
+
+			dir, cleanup, err := native.TempSpace{Directory: base}.Create(layer)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(filepath.Dir(dir)).To(Equal(base))
+
+			cleanup()
+			Expect(dir).NotTo(BeAnExistingFile())
+			Expect(base).To(BeADirectory())
+		})
+
+		it("keeps the directory", func() {
+			dir, cleanup, err := native.TempSpace{Directory: "layer", Keep: true}.Create(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			cleanup()
+			Expect(dir).To(BeADirectory())
+		})
+	})
+
+	context("Check", func() {
+		it("logs the free disk space", func() {
+			b := &bytes.Buffer{}
+
+			Expect(native.TempSpace{}.Check(bard.NewLogger(b), 0, layer.Path, layer.Path)).To(Succeed())
+			Expect(b.String()).To(ContainSubstring("Free disk space in " + layer.Path))
+		})
+
+		it("fails below the minimum", func() {
+			err := native.TempSpace{MinFree: 1 << 60}.Check(bard.NewLogger(ioutil.Discard), 0, layer.Path)
+			Expect(err).To(MatchError(ContainSubstring("is below the 1073741824.0 GB set with $BP_NATIVE_IMAGE_MIN_DISK_SPACE")))
+		})
+
+		it("fails below the estimated disk space", func() {
+			err := native.TempSpace{CheckEstimate: true}.Check(bard.NewLogger(ioutil.Discard), 1<<60, layer.Path)
+			Expect(err).To(MatchError(ContainSubstring("is below the estimated 1073741824.0 GB the build needs")))
+			Expect(err).To(MatchError(ContainSubstring("set $BP_NATIVE_IMAGE_DISK_SPACE_CHECK to false")))
+		})
+
+		it("ignores the estimate unless checked", func() {
+			Expect(native.TempSpace{}.Check(bard.NewLogger(ioutil.Discard), 1<<60, layer.Path)).To(Succeed())
+		})
+
+		it("skips missing paths", func() {
+			Expect(native.TempSpace{MinFree: 1 << 60}.Check(bard.NewLogger(ioutil.Discard), 0, filepath.Join(layer.Path, "missing"))).To(Succeed())
+		})
+	})
+
+	it("measures the classpath", func() {
+		Expect(os.MkdirAll(filepath.Join(layer.Path, "classes", "com"), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(layer.Path, "classes", "com", "A.class"), make([]byte, 100), 0644)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(layer.Path, "lib.jar"), make([]byte, 50), 0644)).To(Succeed())
+
+		Expect(native.MeasureClasspath([]string{
+			filepath.Join(layer.Path, "classes"),
+			filepath.Join(layer.Path, "lib.jar"),
+			filepath.Join(layer.Path, "missing.jar"),
+		})).To(Equal(int64(150)))
+	})
+
+	it("estimates the disk space from the classpath", func() {
+		Expect(native.EstimateDiskSpace(0)).To(Equal(int64(256 * 1024 * 1024)))
+		Expect(native.EstimateDiskSpace(100 * 1024 * 1024)).To(Equal(int64(656 * 1024 * 1024)))
+	})
+
+	it("points the temporary directory at dir", func() {
+		Expect(native.TempEnvironment([]string{"PATH=/bin", "TMPDIR=/tmp"}, "/layer/tmp")).To(Equal([]string{
+			"PATH=/bin",
+			"TMPDIR=/layer/tmp",
+			"JAVA_TOOL_OPTIONS=-Djava.io.tmpdir=/layer/tmp",
+		}))
+		Expect(native.TempEnvironment(nil, "")).To(BeNil())
+	})
+}
@@ -0,0 +1,103 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PhaseTotal is the name of the threshold on the total duration of the native-image build
+const PhaseTotal = "total"
+
+// PhaseThreshold is the longest a native-image phase, or the whole build, is expected to take. Phase is matched
+// case-insensitively against the phase names native-image reports, e.g. analysis matches Performing analysis.
+type PhaseThreshold struct {
+	Phase    string
+	Duration time.Duration
+}
+
+// ThresholdViolation is a phase, or the whole build, that took longer than its threshold
+type ThresholdViolation struct {
+	Phase            string  `json:"phase"`
+	DurationSeconds  float64 `json:"duration-seconds"`
+	ThresholdSeconds float64 `json:"threshold-seconds"`
+}
+
+func (v ThresholdViolation) String() string {
+	return fmt.Sprintf("%s took %s, longer than its threshold of %s", v.Phase,
+		time.Duration(v.DurationSeconds*float64(time.Second)).Round(100*time.Millisecond),
+		time.Duration(v.ThresholdSeconds*float64(time.Second)))
+}
+
+// ParsePhaseThresholds parses a comma separated list of phase=duration thresholds, e.g. analysis=10m,total=30m
+func ParsePhaseThresholds(value string) ([]PhaseThreshold, error) {
+	var thresholds []PhaseThreshold
+
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, fmt.Errorf("invalid phase threshold %q, expected phase=duration", entry)
+		}
+
+		d, err := time.ParseDuration(strings.TrimSpace(parts[1]))
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid phase threshold %q, expected a positive duration such as 10m", entry)
+		}
+
+		thresholds = append(thresholds, PhaseThreshold{Phase: strings.ToLower(strings.TrimSpace(parts[0])), Duration: d})
+	}
+
+	return thresholds, nil
+}
+
+// CheckPhaseThresholds returns the phases, and the whole build of duration total, that took longer than their
+// thresholds
+func CheckPhaseThresholds(thresholds []PhaseThreshold, phases []Phase, total time.Duration) []ThresholdViolation {
+	var violations []ThresholdViolation
+
+	for _, t := range thresholds {
+		if t.Phase == PhaseTotal {
+			if total > t.Duration {
+				violations = append(violations, ThresholdViolation{
+					Phase:            PhaseTotal,
+					DurationSeconds:  total.Seconds(),
+					ThresholdSeconds: t.Duration.Seconds(),
+				})
+			}
+			continue
+		}
+
+		for _, p := range phases {
+			if strings.Contains(strings.ToLower(p.Name), t.Phase) && p.Duration > t.Duration {
+				violations = append(violations, ThresholdViolation{
+					Phase:            p.Name,
+					DurationSeconds:  p.Duration.Seconds(),
+					ThresholdSeconds: t.Duration.Seconds(),
+				})
+			}
+		}
+	}
+
+	return violations
+}
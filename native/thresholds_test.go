@@ -0,0 +1,80 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package native_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testThresholds(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+	)
+
+	context("ParsePhaseThresholds", func() {
+		it("parses thresholds", func() {
+			Expect(native.ParsePhaseThresholds(" Analysis=10m, ,total=30m ")).To(Equal([]native.PhaseThreshold{
+				{Phase: "analysis", Duration: 10 * time.Minute},
+				{Phase: "total", Duration: 30 * time.Minute},
+			}))
+		})
+
+		it("fails on invalid thresholds", func() {
+			_, err := native.ParsePhaseThresholds("analysis")
+			Expect(err).To(MatchError(`invalid phase threshold "analysis", expected phase=duration`))
+
+			_, err = native.ParsePhaseThresholds("analysis=long")
+			Expect(err).To(MatchError(`invalid phase threshold "analysis=long", expected a positive duration such as 10m`))
+		})
+	})
+
+	context("CheckPhaseThresholds", func() {
+		phases := []native.Phase{
+			{Index: 1, Total: 8, Name: "Initializing", Duration: 5 * time.Second},
+			{Index: 2, Total: 8, Name: "Performing analysis", Duration: 12 * time.Minute},
+		}
+
+		it("returns the phases exceeding their thresholds", func() {
+			Expect(native.CheckPhaseThresholds([]native.PhaseThreshold{
+				{Phase: "analysis", Duration: 10 * time.Minute},
+				{Phase: "initializing", Duration: time.Minute},
+				{Phase: "total", Duration: 15 * time.Minute},
+			}, phases, 20*time.Minute)).To(Equal([]native.ThresholdViolation{
+				{Phase: "Performing analysis", DurationSeconds: 720, ThresholdSeconds: 600},
+				{Phase: "total", DurationSeconds: 1200, ThresholdSeconds: 900},
+			}))
+		})
+
+		it("returns nothing within the thresholds", func() {
+			Expect(native.CheckPhaseThresholds([]native.PhaseThreshold{{Phase: "total", Duration: time.Hour}}, phases, 20*time.Minute)).
+				To(BeEmpty())
+			Expect(native.CheckPhaseThresholds(nil, phases, 20*time.Minute)).To(BeEmpty())
+		})
+	})
+
+	it("describes violations", func() {
+		v := native.ThresholdViolation{Phase: "Performing analysis", DurationSeconds: 720.04, ThresholdSeconds: 600}
+		Expect(v.String()).To(Equal("Performing analysis took 12m0s, longer than its threshold of 10m0s"))
+		Expect(native.PhaseThresholdError{Violations: []native.ThresholdViolation{v}}).
+			To(MatchError("native-image exceeded the thresholds set with $BP_NATIVE_IMAGE_PHASE_THRESHOLDS: Performing analysis took 12m0s, longer than its threshold of 10m0s"))
+	})
+}
@@ -0,0 +1,117 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/paketo-buildpacks/libpak/bard"
+	"github.com/paketo-buildpacks/libpak/effect"
+)
+
+// TracingAgentDirectory is the directory in the native image layer the tracing agent writes configuration to
+const TracingAgentDirectory = "agent-configuration"
+
+// DefaultTracingAgentDuration is how long the application runs with the tracing agent by default
+const DefaultTracingAgentDuration = 30 * time.Second
+
+// tracingAgentGracePeriod is how long the application is given to shut down, and the agent to write its
+// configuration, after being asked to terminate
+const tracingAgentGracePeriod = 30 * time.Second
+
+// TracingAgent runs the application on the JVM with the native-image tracing agent to generate configuration
+type TracingAgent struct {
+
+	// Duration is the longest the application runs for
+	Duration time.Duration
+
+	// ReadyURL stops the application as soon as it responds successfully, if set
+	ReadyURL string
+}
+
+// Arguments returns the JVM arguments for running the application with the tracing agent writing to dir. The
+// application is either a classpath and main class, or a JAR when startClass is empty.
+func (TracingAgent) Arguments(dir string, classpath string, startClass string) []string {
+	arguments := []string{fmt.Sprintf("-agentlib:native-image-agent=config-output-dir=%s", dir)}
+
+	if startClass == "" {
+		return append(arguments, "-jar", classpath)
+	}
+
+	return append(arguments, "-cp", classpath, startClass)
+}
+
+// Run runs the application until the duration has elapsed or it is ready, and then asks it to terminate so that the
+// agent writes its configuration. An application that exits by itself is not an error unless it fails.
+func (t TracingAgent) Run(ctx context.Context, executor effect.Executor, logger bard.Logger, execution effect.Execution) error {
+	if p, ok := executor.(ProcessGroupExecutor); ok {
+		p.GracePeriod = tracingAgentGracePeriod
+		executor = p
+	}
+
+	duration := t.Duration
+	if duration <= 0 {
+		duration = DefaultTracingAgentDuration
+	}
+
+	run, cancel := context.WithTimeout(ctx, duration)
+	defer cancel()
+
+	if t.ReadyURL != "" {
+		go func() {
+			if WaitReady(run, t.ReadyURL, time.Second) {
+				logger.Bodyf("Application is ready at %s", t.ReadyURL)
+				cancel()
+			}
+		}()
+	}
+
+	logger.Bodyf("Running application with the native-image tracing agent for up to %s", duration)
+	err := executeContext(run, executor, execution)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	} else if err != nil && run.Err() == nil {
+		return fmt.Errorf("application failed while running with the tracing agent\n%w", err)
+	}
+
+	return nil
+}
+
+// WaitReady polls url every interval until it responds with a 2xx status, returning false if ctx is done first
+func WaitReady(ctx context.Context, url string, interval time.Duration) bool {
+	client := http.Client{Timeout: interval}
+
+	for {
+		if req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil); err == nil {
+			if resp, err := client.Do(req); err == nil {
+				resp.Body.Close()
+				if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+					return true
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,95 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	gocontext "context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/paketo-buildpacks/libpak/bard"
+	"github.com/paketo-buildpacks/libpak/effect"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testTracing(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+	)
+
+	it("runs a main class with the agent", func() {
+		Expect(native.TracingAgent{}.Arguments("/layer/agent", "/workspace", "com.example.Main")).To(Equal([]string{
+			"-agentlib:native-image-agent=config-output-dir=/layer/agent",
+			"-cp", "/workspace",
+			"com.example.Main",
+		}))
+	})
+
+	it("runs a JAR with the agent", func() {
+		Expect(native.TracingAgent{}.Arguments("/layer/agent", "/workspace/app.jar", "")).To(Equal([]string{
+			"-agentlib:native-image-agent=config-output-dir=/layer/agent",
+			"-jar", "/workspace/app.jar",
+		}))
+	})
+
+	it("stops the application when it is ready", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		start := time.Now()
+		err := native.TracingAgent{Duration: time.Minute, ReadyURL: server.URL}.Run(gocontext.Background(), native.ProcessGroupExecutor{}, bard.NewLogger(io.Discard), effect.Execution{
+			Command: "sleep",
+			Args:    []string{"60"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(time.Since(start)).To(BeNumerically("<", 30*time.Second))
+	})
+
+	it("stops the application after the duration", func() {
+		err := native.TracingAgent{Duration: 100 * time.Millisecond}.Run(gocontext.Background(), native.ProcessGroupExecutor{}, bard.NewLogger(io.Discard), effect.Execution{
+			Command: "sleep",
+			Args:    []string{"60"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	it("fails when the application fails", func() {
+		err := native.TracingAgent{Duration: time.Minute}.Run(gocontext.Background(), native.ProcessGroupExecutor{}, bard.NewLogger(io.Discard), effect.Execution{
+			Command: "false",
+		})
+		Expect(err).To(MatchError(ContainSubstring("application failed while running with the tracing agent")))
+	})
+
+	it("does not wait for a URL that never becomes ready", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		ctx, cancel := gocontext.WithTimeout(gocontext.Background(), 50*time.Millisecond)
+		defer cancel()
+		Expect(native.WaitReady(ctx, server.URL, 10*time.Millisecond)).To(BeFalse())
+	})
+}
@@ -0,0 +1,71 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/paketo-buildpacks/libpak/sherpa"
+)
+
+// trainingArtifactPatterns match the class data sharing archives and AOT caches written by JVM training runs, such as
+// the application.jsa of Spring Boot 3.3 CDS support
+var trainingArtifactPatterns = []string{"*.jsa", "*.aot"}
+
+// IsTrainingArtifact returns true if the file is a JVM training run artifact
+func IsTrainingArtifact(file string) bool {
+	for _, p := range trainingArtifactPatterns {
+		if ok, _ := filepath.Match(p, filepath.Base(file)); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// FindTrainingArtifacts returns the JVM training run artifacts in the application, or in a directory directly below
+// it as created by extracting a Spring Boot JAR
+func FindTrainingArtifacts(appPath string) ([]string, error) {
+	var artifacts []string
+
+	for _, p := range trainingArtifactPatterns {
+		for _, pattern := range []string{filepath.Join(appPath, p), filepath.Join(appPath, "*", p)} {
+			matches, err := filepath.Glob(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("unable to find training artifacts with %s\n%w", pattern, err)
+			}
+			artifacts = append(artifacts, matches...)
+		}
+	}
+
+	return artifacts, nil
+}
+
+// withoutTrainingArtifacts removes JVM training run artifacts from a file listing. They do not take part in building
+// a native image, so they must not cause it to be rebuilt.
+func withoutTrainingArtifacts(files []sherpa.FileEntry) []sherpa.FileEntry {
+	var filtered []sherpa.FileEntry
+
+	for _, f := range files {
+		if !IsTrainingArtifact(f.Path) {
+			filtered = append(filtered, f)
+		}
+	}
+
+	return filtered
+}
@@ -0,0 +1,66 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testTraining(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		appPath string
+	)
+
+	it.Before(func() {
+		var err error
+
+		appPath, err = ioutil.TempDir("", "training-application")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(appPath)).To(Succeed())
+	})
+
+	it("identifies training artifacts", func() {
+		Expect(native.IsTrainingArtifact("/workspace/application.jsa")).To(BeTrue())
+		Expect(native.IsTrainingArtifact("/workspace/app.aot")).To(BeTrue())
+		Expect(native.IsTrainingArtifact("/workspace/application.jar")).To(BeFalse())
+	})
+
+	it("finds training artifacts", func() {
+		Expect(os.MkdirAll(filepath.Join(appPath, "application"), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(appPath, "application.jsa"), []byte{}, 0644)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(appPath, "application", "app.aot"), []byte{}, 0644)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(appPath, "application", "application.jar"), []byte{}, 0644)).To(Succeed())
+
+		Expect(native.FindTrainingArtifacts(appPath)).To(ConsistOf(
+			filepath.Join(appPath, "application.jsa"),
+			filepath.Join(appPath, "application", "app.aot"),
+		))
+	})
+}
@@ -0,0 +1,100 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/buildpacks/libcnb"
+)
+
+// UsageStatisticsFile is the name of the file in the usage statistics layer holding the counters
+const UsageStatisticsFile = "usage-statistics.json"
+
+// UsageStatisticsCounters are anonymous counters of the native-image builds that used a cache. They never include
+// application names, arguments or paths.
+type UsageStatisticsCounters struct {
+	Builds                int64   `json:"builds"`
+	CacheHits             int64   `json:"cache-hits"`
+	Compiles              int64   `json:"compiles"`
+	TotalCompileSeconds   float64 `json:"total-compile-seconds"`
+	AverageCompileSeconds float64 `json:"average-compile-seconds"`
+}
+
+// Record counts a build, either as a cache hit or as a compilation taking duration
+func (u *UsageStatisticsCounters) Record(rebuilt bool, duration time.Duration) {
+	u.Builds++
+
+	if !rebuilt {
+		u.CacheHits++
+		return
+	}
+
+	u.Compiles++
+	u.TotalCompileSeconds += duration.Seconds()
+	u.AverageCompileSeconds = u.TotalCompileSeconds / float64(u.Compiles)
+}
+
+// UsageStatistics contributes a cache layer holding local usage counters, so that platform operators can read them
+// from the cache volume for capacity planning. Nothing is sent over the network. The counters are taken from Metrics,
+// which is filled in when the native image layer, contributed before this layer, is contributed.
+type UsageStatistics struct {
+	Metrics *Metrics
+}
+
+func (u UsageStatistics) Contribute(layer libcnb.Layer) (libcnb.Layer, error) {
+	if err := os.MkdirAll(layer.Path, 0755); err != nil {
+		return libcnb.Layer{}, fmt.Errorf("unable to create %s\n%w", layer.Path, err)
+	}
+
+	file := filepath.Join(layer.Path, UsageStatisticsFile)
+
+	var counters UsageStatisticsCounters
+	if b, err := ioutil.ReadFile(file); err == nil {
+		if err := json.Unmarshal(b, &counters); err != nil {
+			// a corrupt file only loses the history, it must not fail the build
+			counters = UsageStatisticsCounters{}
+		}
+	} else if !os.IsNotExist(err) {
+		return libcnb.Layer{}, fmt.Errorf("unable to read %s\n%w", file, err)
+	}
+
+	if u.Metrics != nil {
+		counters.Record(u.Metrics.Rebuilt, u.Metrics.Duration)
+	}
+
+	b, err := json.MarshalIndent(counters, "", "  ")
+	if err != nil {
+		return libcnb.Layer{}, fmt.Errorf("unable to encode usage statistics\n%w", err)
+	}
+
+	if err := ioutil.WriteFile(file, b, 0644); err != nil {
+		return libcnb.Layer{}, fmt.Errorf("unable to write %s\n%w", file, err)
+	}
+
+	layer.Cache = true
+	return layer, nil
+}
+
+func (UsageStatistics) Name() string {
+	return "usage-statistics"
+}
@@ -0,0 +1,95 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package native_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/buildpacks/libcnb"
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testUsageStatistics(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		ctx libcnb.BuildContext
+	)
+
+	it.Before(func() {
+		var err error
+
+		ctx.Layers.Path, err = ioutil.TempDir("", "usage-statistics-layers")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(ctx.Layers.Path)).To(Succeed())
+	})
+
+	read := func(layer libcnb.Layer) native.UsageStatisticsCounters {
+		b, err := ioutil.ReadFile(filepath.Join(layer.Path, "usage-statistics.json"))
+		Expect(err).NotTo(HaveOccurred())
+
+		var counters native.UsageStatisticsCounters
+		Expect(json.Unmarshal(b, &counters)).To(Succeed())
+		return counters
+	}
+
+	it("counts builds across contributions", func() {
+		layer, err := ctx.Layers.Layer("usage-statistics")
+		Expect(err).NotTo(HaveOccurred())
+
+		layer, err = native.UsageStatistics{Metrics: &native.Metrics{Rebuilt: true, Duration: 100 * time.Second}}.Contribute(layer)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(layer.Cache).To(BeTrue())
+		Expect(layer.Launch).To(BeFalse())
+
+		_, err = native.UsageStatistics{Metrics: &native.Metrics{Duration: 100 * time.Second}}.Contribute(layer)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = native.UsageStatistics{Metrics: &native.Metrics{Rebuilt: true, Duration: 200 * time.Second}}.Contribute(layer)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(read(layer)).To(Equal(native.UsageStatisticsCounters{
+			Builds:                3,
+			CacheHits:             1,
+			Compiles:              2,
+			TotalCompileSeconds:   300,
+			AverageCompileSeconds: 150,
+		}))
+	})
+
+	it("starts over from a corrupt file", func() {
+		layer, err := ctx.Layers.Layer("usage-statistics")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.MkdirAll(layer.Path, 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(layer.Path, "usage-statistics.json"), []byte("{"), 0644)).To(Succeed())
+
+		layer, err = native.UsageStatistics{Metrics: &native.Metrics{}}.Contribute(layer)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(read(layer)).To(Equal(native.UsageStatisticsCounters{Builds: 1, CacheHits: 1}))
+	})
+}
@@ -0,0 +1,105 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package native
+
+import (
+	"bufio"
+	"sort"
+	"strings"
+
+	"github.com/paketo-buildpacks/libpak"
+	"github.com/paketo-buildpacks/libpak/bard"
+)
+
+// ConfigurationPrefix is the prefix of the names of the configurations of the buildpack
+const ConfigurationPrefix = "BP_NATIVE_IMAGE"
+
+// Warnings collects the non-fatal findings of a build, so that they are repeated in a summary at the end of the build
+// where they are not lost in the output of native-image. The zero value is ready to use and a nil Warnings only logs.
+type Warnings struct {
+	Messages []string
+}
+
+// Warn logs a warning and adds it to the findings
+func (w *Warnings) Warn(logger bard.Logger, msg string) {
+	warn(logger, msg)
+	w.Add(msg)
+}
+
+// Add adds findings that have already been logged, skipping duplicates
+func (w *Warnings) Add(messages ...string) {
+	if w == nil {
+		return
+	}
+
+	for _, m := range messages {
+		if !containsString(w.Messages, m) {
+			w.Messages = append(w.Messages, m)
+		}
+	}
+}
+
+// Log prints the findings in a WARNINGS section, if there are any
+func (w *Warnings) Log(logger bard.Logger) {
+	if w == nil || len(w.Messages) == 0 {
+		return
+	}
+
+	logger.Header("WARNINGS")
+	for _, m := range w.Messages {
+		logger.Bodyf("* %s", m)
+	}
+}
+
+// FindOutputWarnings returns the warnings native-image prints, e.g. `Warning: Image 'demo' is a fallback image that
+// requires a JDK for execution (use --no-fallback to suppress fallback image generation).`
+func FindOutputWarnings(output string) []string {
+	var warnings []string
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if m := strings.TrimSpace(strings.TrimPrefix(line, "Warning:")); m != line && m != "" && !containsString(warnings, m) {
+			warnings = append(warnings, m)
+		}
+	}
+
+	return warnings
+}
+
+// UnknownConfigurations returns the names of the variables in environ that look like configurations of the buildpack
+// but are neither declared nor deprecated configurations, which are most likely misspelt
+func UnknownConfigurations(environ []string, configurations []libpak.BuildpackConfiguration, deprecated []DeprecatedConfiguration) []string {
+	known := map[string]bool{}
+	for _, c := range configurations {
+		known[c.Name] = true
+	}
+	for _, d := range deprecated {
+		known[d.Name] = true
+	}
+
+	var unknown []string
+	for _, e := range environ {
+		name := strings.SplitN(e, "=", 2)[0]
+		if strings.HasPrefix(name, ConfigurationPrefix+"_") && !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+
+	return unknown
+}
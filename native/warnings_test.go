@@ -0,0 +1,84 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package native_test
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/paketo-buildpacks/libpak"
+	"github.com/paketo-buildpacks/libpak/bard"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/native-image/v5/native"
+)
+
+func testWarnings(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+	)
+
+	context("Warnings", func() {
+		it("logs and collects warnings once", func() {
+			out := &bytes.Buffer{}
+			logger := bard.NewLogger(out)
+			warnings := &native.Warnings{}
+
+			warnings.Warn(logger, "first")
+			warnings.Add("second", "first")
+			Expect(warnings.Messages).To(Equal([]string{"first", "second"}))
+
+			warnings.Log(logger)
+			Expect(out.String()).To(ContainSubstring("WARNINGS"))
+			Expect(out.String()).To(ContainSubstring("* first"))
+			Expect(out.String()).To(ContainSubstring("* second"))
+		})
+
+		it("only logs without a collector", func() {
+			out := &bytes.Buffer{}
+			var warnings *native.Warnings
+
+			warnings.Warn(bard.NewLogger(out), "first")
+			warnings.Log(bard.NewLogger(out))
+			Expect(out.String()).To(ContainSubstring("first"))
+			Expect(out.String()).NotTo(ContainSubstring("WARNINGS"))
+		})
+	})
+
+	context("FindOutputWarnings", func() {
+		it("finds the warnings of native-image", func() {
+			Expect(native.FindOutputWarnings(`[1/7] Initializing...
+Warning: Image 'demo' is a fallback image that requires a JDK for execution (use --no-fallback to suppress fallback image generation).
+  Warning: Could not resolve class com.example.Missing for reflection configuration.
+Warning: Image 'demo' is a fallback image that requires a JDK for execution (use --no-fallback to suppress fallback image generation).
+`)).To(Equal([]string{
+				"Image 'demo' is a fallback image that requires a JDK for execution (use --no-fallback to suppress fallback image generation).",
+				"Could not resolve class com.example.Missing for reflection configuration.",
+			}))
+		})
+	})
+
+	context("UnknownConfigurations", func() {
+		it("finds unknown configurations of the buildpack", func() {
+			Expect(native.UnknownConfigurations(
+				[]string{"BP_NATIVE_IMAGE=true", "BP_NATIVE_IMAGE_BUILD_ARGUMNETS=--verbose", "BP_JVM_VERSION=17", "BP_BOOT_NATIVE_IMAGE=1"},
+				[]libpak.BuildpackConfiguration{{Name: "BP_NATIVE_IMAGE"}, {Name: "BP_NATIVE_IMAGE_BUILD_ARGUMENTS"}},
+				native.DeprecatedConfigurations,
+			)).To(Equal([]string{"BP_NATIVE_IMAGE_BUILD_ARGUMNETS"}))
+		})
+	})
+}
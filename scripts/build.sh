@@ -3,12 +3,14 @@
 set -euo pipefail
 
 GOOS="linux" go build -ldflags='-s -w' -o bin/main github.com/paketo-buildpacks/native-image/v5/cmd/main
+GOOS="linux" go build -ldflags='-s -w' -o bin/helper github.com/paketo-buildpacks/native-image/v5/cmd/helper
 
 if [ "${STRIP:-false}" != "false" ]; then
-  strip bin/main
+  strip bin/helper bin/main
 fi
 
 if [ "${COMPRESS:-none}" != "none" ]; then
+  $COMPRESS bin/helper
   $COMPRESS bin/main
 fi
 